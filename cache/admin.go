@@ -0,0 +1,222 @@
+package cache
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AdminConfig 管理接口配置
+type AdminConfig struct {
+	// 管理的缓存，nil 表示全局缓存
+	Cache Cache
+
+	// 删除类操作需要的 Bearer Token，为空时禁用删除类操作
+	Token string
+}
+
+// AdminOption 选项函数
+type AdminOption func(*AdminConfig)
+
+// WithAdminCache 设置管理的缓存
+func WithAdminCache(c Cache) AdminOption {
+	return func(cfg *AdminConfig) {
+		cfg.Cache = c
+	}
+}
+
+// WithAdminToken 设置删除类操作的 Bearer Token
+func WithAdminToken(token string) AdminOption {
+	return func(cfg *AdminConfig) {
+		cfg.Token = token
+	}
+}
+
+// AdminHandler 返回用于运维排查的 HTTP 管理接口，挂载到子路径时配合 http.StripPrefix 使用：
+//
+//	GET    /ping                  可用性检查，不可用时返回 503，可用作就绪探针
+//	GET    /stats                 命中统计与热点 key（开启 EnableHotKeys 时）
+//	GET    /keys?pattern=x&limit=n 列出匹配的 key（默认最多 1000 个，不返回值）
+//	GET    /keys/{key}            key 元数据：是否存在、剩余 TTL（不返回值）
+//	DELETE /keys/{key}            删除 key（需要 Token）
+//	POST   /clear?prefix=xxx      按前缀删除（需要 Token）
+//	POST   /clear?all=true        清空缓存（需要 Token）
+func AdminHandler(opts ...AdminOption) http.Handler {
+	var config AdminConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+	a := &admin{config: config}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /ping", a.ping)
+	mux.HandleFunc("GET /stats", a.stats)
+	mux.HandleFunc("GET /keys", a.keys)
+	mux.HandleFunc("GET /keys/{key...}", a.inspect)
+	mux.HandleFunc("DELETE /keys/{key...}", a.authorized(a.delete))
+	mux.HandleFunc("POST /clear", a.authorized(a.clear))
+	return mux
+}
+
+type admin struct {
+	config AdminConfig
+}
+
+func (a *admin) cache() Cache {
+	if a.config.Cache != nil {
+		return a.config.Cache
+	}
+	return current()
+}
+
+func (a *admin) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if a.config.Token == "" {
+			writeAdminJSON(w, http.StatusForbidden, map[string]any{"error": "write operations are disabled"})
+			return
+		}
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(a.config.Token)) != 1 {
+			writeAdminJSON(w, http.StatusUnauthorized, map[string]any{"error": "unauthorized"})
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (a *admin) ping(w http.ResponseWriter, r *http.Request) {
+	c := a.cache()
+	if c == nil {
+		writeAdminJSON(w, http.StatusServiceUnavailable, map[string]any{"error": ErrNoGlobal.Error()})
+		return
+	}
+
+	if err := c.Ping(r.Context()); err != nil {
+		writeAdminJSON(w, http.StatusServiceUnavailable, map[string]any{"ok": false, "error": err.Error()})
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, map[string]any{"ok": true})
+}
+
+func (a *admin) stats(w http.ResponseWriter, r *http.Request) {
+	c := a.cache()
+	if c == nil {
+		writeAdminJSON(w, http.StatusServiceUnavailable, map[string]any{"error": ErrNoGlobal.Error()})
+		return
+	}
+
+	s := c.Stats()
+	resp := map[string]any{
+		"hits":      s.Hits,
+		"misses":    s.Misses,
+		"sets":      s.Sets,
+		"deletes":   s.Deletes,
+		"hit_ratio": s.HitRatio(),
+	}
+	if a.config.Cache == nil {
+		if hot := currentHotKeys(); hot != nil {
+			resp["hot_keys"] = hot.TopKeys(20)
+		}
+	}
+	writeAdminJSON(w, http.StatusOK, resp)
+}
+
+// defaultAdminKeysLimit /keys 未指定 limit 时返回的最大 key 数
+const defaultAdminKeysLimit = 1000
+
+func (a *admin) keys(w http.ResponseWriter, r *http.Request) {
+	c := a.cache()
+	if c == nil {
+		writeAdminJSON(w, http.StatusServiceUnavailable, map[string]any{"error": ErrNoGlobal.Error()})
+		return
+	}
+
+	q := r.URL.Query()
+	limit := defaultAdminKeysLimit
+	if s := q.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			writeAdminJSON(w, http.StatusBadRequest, map[string]any{"error": "limit must be a positive integer"})
+			return
+		}
+		limit = n
+	}
+
+	keys, err := c.Keys(q.Get("pattern"), limit)
+	if err != nil {
+		writeAdminJSON(w, http.StatusInternalServerError, map[string]any{"error": err.Error()})
+		return
+	}
+	if keys == nil {
+		keys = []string{}
+	}
+	sort.Strings(keys)
+	writeAdminJSON(w, http.StatusOK, map[string]any{"keys": keys, "count": len(keys), "truncated": len(keys) >= limit})
+}
+
+func (a *admin) inspect(w http.ResponseWriter, r *http.Request) {
+	c := a.cache()
+	if c == nil {
+		writeAdminJSON(w, http.StatusServiceUnavailable, map[string]any{"error": ErrNoGlobal.Error()})
+		return
+	}
+
+	key := r.PathValue("key")
+	resp := map[string]any{"key": key, "exists": c.Exists(key)}
+	if ttl, ok := c.TTL(key); ok {
+		resp["ttl_ms"] = ttl.Milliseconds()
+	}
+	writeAdminJSON(w, http.StatusOK, resp)
+}
+
+func (a *admin) delete(w http.ResponseWriter, r *http.Request) {
+	c := a.cache()
+	if c == nil {
+		writeAdminJSON(w, http.StatusServiceUnavailable, map[string]any{"error": ErrNoGlobal.Error()})
+		return
+	}
+
+	key := r.PathValue("key")
+	c.Delete(key)
+	writeAdminJSON(w, http.StatusOK, map[string]any{"key": key, "deleted": true})
+}
+
+func (a *admin) clear(w http.ResponseWriter, r *http.Request) {
+	c := a.cache()
+	if c == nil {
+		writeAdminJSON(w, http.StatusServiceUnavailable, map[string]any{"error": ErrNoGlobal.Error()})
+		return
+	}
+
+	q := r.URL.Query()
+	if prefix := q.Get("prefix"); prefix != "" {
+		writeAdminJSON(w, http.StatusOK, map[string]any{"prefix": prefix, "deleted": c.DeleteByPrefix(prefix)})
+		return
+	}
+	if q.Get("all") == "true" {
+		c.Clear()
+		writeAdminJSON(w, http.StatusOK, map[string]any{"cleared": true})
+		return
+	}
+	writeAdminJSON(w, http.StatusBadRequest, map[string]any{"error": "prefix or all=true is required"})
+}
+
+// unwrap 去掉 Tracked 等包装，返回底层适配器
+func unwrap(c Cache) Cache {
+	for {
+		u, ok := c.(interface{ Unwrap() Cache })
+		if !ok {
+			return c
+		}
+		c = u.Unwrap()
+	}
+}
+
+func writeAdminJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
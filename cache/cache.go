@@ -1,9 +1,9 @@
 package cache
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"reflect"
 	"sync"
 	"time"
 )
@@ -12,10 +12,35 @@ type Cache interface {
 	Get(key string) (any, error)
 	Set(key string, value any, ttl time.Duration)
 	Delete(key string)
+	// DeleteByPrefix 删除以 prefix 开头的 key，返回删除的条目数；如清理 "session:" 下的全部会话
+	DeleteByPrefix(prefix string) int
+	// Keys 列出匹配 pattern（Redis 风格通配，为空表示全部，见 MatchPattern）的未过期 key，供运维与调试查看缓存内容；
+	// limit > 0 时最多返回 limit 个。返回顺序不保证，遍历期间写入的 key 可能不会出现在结果中
+	Keys(pattern string, limit int) ([]string, error)
 	Clear()
 	TTL(key string) (time.Duration, bool)
 	Exists(key string) bool
 	Stats() Stats
+	// CompareAndSwap 当前值与 old 相等（old 为 nil 表示 key 不存在）时写入 new，返回是否写入
+	CompareAndSwap(key string, old, new any, ttl time.Duration) (bool, error)
+	// GetDel 读取并删除 key，不存在时返回 ErrNotFound
+	GetDel(key string) (any, error)
+	// GetSet 写入 value 并返回旧值，旧值不存在时返回 ErrNotFound（value 仍会写入）
+	GetSet(key string, value any, ttl time.Duration) (any, error)
+
+	// 以下为带 context 的版本，遵循调用方的超时与取消；不带 context 的方法等价于传入 context.Background()
+	GetCtx(ctx context.Context, key string) (any, error)
+	SetCtx(ctx context.Context, key string, value any, ttl time.Duration) error
+	DeleteCtx(ctx context.Context, key string) error
+	TTLCtx(ctx context.Context, key string) (time.Duration, bool, error)
+	ExistsCtx(ctx context.Context, key string) (bool, error)
+	CompareAndSwapCtx(ctx context.Context, key string, old, new any, ttl time.Duration) (bool, error)
+	GetDelCtx(ctx context.Context, key string) (any, error)
+	GetSetCtx(ctx context.Context, key string, value any, ttl time.Duration) (any, error)
+
+	// Ping 检查缓存是否可用（Redis 连通、文件目录可写等），用于就绪探针；可用时返回 nil
+	Ping(ctx context.Context) error
+
 	Close() error
 	Start(config any) error
 }
@@ -31,38 +56,139 @@ const (
 	AdapterMemory = "memory"
 	AdapterRedis  = "redis"
 	AdapterFile   = "file"
+	AdapterTiered = "tiered"
 )
 
 var (
-	global Cache
-	once   sync.Once
+	globalMu sync.RWMutex
+	global   Cache
+
+	namedMu sync.RWMutex
+	named   = make(map[string]Cache)
 )
 
 var (
 	ErrNoGlobal     = errors.New("cache: global instance is nil")
+	ErrInitialized  = errors.New("cache: global instance already initialized")
 	ErrNotFound     = errors.New("cache: not found")
 	ErrTypeMismatch = errors.New("cache: type mismatch")
 	ErrDecode       = errors.New("cache: decode failed")
+
+	ErrQuotaExceeded = errors.New("cache: namespace quota exceeded")
 )
 
-func Init(adapterName string, config ...any) (err error) {
+// Init 初始化全局实例，已初始化时返回 ErrInitialized；Start 失败时保持未初始化，可再次调用。
+// 需要替换已有实例时使用 Reconfigure，或先调用 Shutdown
+func Init(adapterName string, config ...any) error {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+
+	if global != nil {
+		return ErrInitialized
+	}
+	c, err := newInstance(adapterName, config...)
+	if err != nil {
+		return err
+	}
+	global = c
+	return nil
+}
+
+// Reconfigure 使用新的适配器与配置替换全局实例，新实例启动成功后关闭旧实例；
+// 启动失败时保留旧实例。未初始化时返回 ErrNoGlobal
+func Reconfigure(adapterName string, config ...any) error {
+	c, err := newInstance(adapterName, config...)
+	if err != nil {
+		return err
+	}
+
+	globalMu.Lock()
+	old := global
+	if old != nil {
+		global = c
+		globalHotKeys = nil
+	}
+	globalMu.Unlock()
+
+	if old == nil {
+		_ = c.Close()
+		return ErrNoGlobal
+	}
+	return old.Close()
+}
+
+// Shutdown 关闭全局实例并重置为未初始化，之后可再次调用 Init；未初始化时不做任何事
+func Shutdown() error {
+	globalMu.Lock()
+	c := global
+	global = nil
+	globalHotKeys = nil
+	globalMu.Unlock()
+
+	if c == nil {
+		return nil
+	}
+	return c.Close()
+}
+
+func newInstance(adapterName string, config ...any) (Cache, error) {
+	adaptersMu.RLock()
 	instanceFunc, ok := adapters[adapterName]
+	adaptersMu.RUnlock()
+
 	if !ok {
-		err = fmt.Errorf("cache: unknown adapter name %q (forgot to import?)", adapterName)
-		return
+		return nil, fmt.Errorf("cache: unknown adapter name %q (forgot to import?)", adapterName)
+	}
+
+	var cfg any
+	if len(config) > 0 {
+		cfg = config[0]
 	}
-	once.Do(func() {
-		global = instanceFunc()
-		err = global.Start(config[0])
-	})
+	c := instanceFunc()
+	if err := c.Start(cfg); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// current 返回全局实例，未初始化时返回 nil
+func current() Cache {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+	return global
+}
+
+// InitNamed 初始化命名缓存实例，可与全局实例同时使用（如内存缓存保存热点配置、Redis 缓存保存共享数据）。
+// 同名实例已存在时关闭旧实例后替换
+func InitNamed(name string, adapterName string, config any) error {
+	c, err := newInstance(adapterName, config)
 	if err != nil {
-		global = nil
+		return err
 	}
-	return
+
+	namedMu.Lock()
+	old := named[name]
+	named[name] = c
+	namedMu.Unlock()
+
+	if old != nil {
+		_ = old.Close()
+	}
+	return nil
+}
+
+// Use 返回命名缓存实例，未初始化时返回 nil
+func Use(name string) Cache {
+	namedMu.RLock()
+	defer namedMu.RUnlock()
+	return named[name]
 }
 
 func SetGlobal(cache Cache) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
 	global = cache
+	globalHotKeys = nil
 }
 
 func Register(name string, adapter Instance) {
@@ -79,68 +205,119 @@ func Register(name string, adapter Instance) {
 
 func Get[T any](key string) (T, error) {
 	var zero T
-	if global == nil {
+	c := current()
+	if c == nil {
 		return zero, ErrNoGlobal
 	}
 
-	v, err := global.Get(key)
-	if err != nil {
-		return zero, err
-	}
-
-	if tv, ok := v.(T); ok {
-		return tv, nil
-	}
-
-	target := reflect.TypeOf((*T)(nil)).Elem()
-	if v != nil {
-		rv := reflect.ValueOf(v)
-		if rv.Type().ConvertibleTo(target) {
-			return rv.Convert(target).Interface().(T), nil
-		}
-	}
-
-	return zero, fmt.Errorf("%w: value type %T", ErrTypeMismatch, v)
+	return getAs[T](c, key)
 }
 
 func Set[T any](key string, value T, ttl time.Duration) {
-	if global == nil {
+	c := current()
+	if c == nil {
 		return
 	}
-	global.Set(key, value, ttl)
+	c.Set(key, value, ttl)
 }
 
 func Delete(key string) {
-	if global == nil {
+	c := current()
+	if c == nil {
 		return
 	}
-	global.Delete(key)
+	c.Delete(key)
 }
 
 func Exists(key string) bool {
-	if global == nil {
+	c := current()
+	if c == nil {
 		return false
 	}
-	return global.Exists(key)
+	return c.Exists(key)
 }
 
 func TTL(key string) (time.Duration, bool) {
-	if global == nil {
+	c := current()
+	if c == nil {
 		return 0, false
 	}
-	return global.TTL(key)
+	return c.TTL(key)
+}
+
+func CompareAndSwap(key string, old, new any, ttl time.Duration) (bool, error) {
+	c := current()
+	if c == nil {
+		return false, ErrNoGlobal
+	}
+	return c.CompareAndSwap(key, old, new, ttl)
+}
+
+// DeleteByPrefix 使用全局缓存按前缀删除，返回删除的条目数
+func DeleteByPrefix(prefix string) int {
+	c := current()
+	if c == nil {
+		return 0
+	}
+	return c.DeleteByPrefix(prefix)
+}
+
+// GetDel 使用全局缓存读取并删除 key，适用于一次性令牌等场景
+func GetDel[T any](key string) (T, error) {
+	var zero T
+	c := current()
+	if c == nil {
+		return zero, ErrNoGlobal
+	}
+	v, err := c.GetDel(key)
+	if err != nil {
+		return zero, err
+	}
+	return asType[T](v)
+}
+
+// GetSet 使用全局缓存写入 value 并返回旧值
+func GetSet[T any](key string, value T, ttl time.Duration) (T, error) {
+	var zero T
+	c := current()
+	if c == nil {
+		return zero, ErrNoGlobal
+	}
+	v, err := c.GetSet(key, value, ttl)
+	if err != nil {
+		return zero, err
+	}
+	return asType[T](v)
 }
 
 func GetStats() Stats {
-	if global == nil {
+	c := current()
+	if c == nil {
 		return Stats{}
 	}
-	return global.Stats()
+	return c.Stats()
 }
 
+// Ping 检查全局实例是否可用，未初始化时返回 ErrNoGlobal
+func Ping(ctx context.Context) error {
+	c := current()
+	if c == nil {
+		return ErrNoGlobal
+	}
+	return c.Ping(ctx)
+}
+
+// Close 关闭全局实例与所有命名实例，之后可重新 Init / InitNamed
 func Close() error {
-	if global == nil {
-		return nil
+	errs := []error{Shutdown()}
+
+	namedMu.Lock()
+	instances := named
+	named = make(map[string]Cache)
+	namedMu.Unlock()
+
+	for _, c := range instances {
+		errs = append(errs, c.Close())
 	}
-	return global.Close()
+	return errors.Join(errs...)
 }
@@ -0,0 +1,447 @@
+package cachetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jiajia556/tool-box/cache"
+)
+
+// Factory 创建一个已启动且为空的缓存实例，每个用例调用一次；
+// 共享存储（如 Redis）的适配器应使用独立的 Prefix 或库，避免用例之间互相影响
+type Factory func(t *testing.T) cache.Cache
+
+// Config 一致性测试配置
+type Config struct {
+	// 过期相关用例使用的 TTL，默认 300ms；存储的过期精度较粗（如按秒）时应调大
+	TTL time.Duration
+
+	// 并发用例的 goroutine 数，默认 8
+	Concurrency int
+
+	// 大值用例的值大小（字节），默认 1 MiB
+	LargeValueSize int
+
+	// 是否检查 Stats 计数，统计不精确的适配器（如多级缓存按层计数）可关闭
+	CheckStats bool
+}
+
+// Option 选项函数
+type Option func(*Config)
+
+// WithTTL 设置过期用例的 TTL
+func WithTTL(ttl time.Duration) Option {
+	return func(c *Config) {
+		c.TTL = ttl
+	}
+}
+
+// WithConcurrency 设置并发用例的 goroutine 数
+func WithConcurrency(n int) Option {
+	return func(c *Config) {
+		c.Concurrency = n
+	}
+}
+
+// WithLargeValueSize 设置大值用例的值大小
+func WithLargeValueSize(n int) Option {
+	return func(c *Config) {
+		c.LargeValueSize = n
+	}
+}
+
+// WithStats 设置是否检查 Stats 计数
+func WithStats(check bool) Option {
+	return func(c *Config) {
+		c.CheckStats = check
+	}
+}
+
+// DefaultConfig 默认配置
+func DefaultConfig() Config {
+	return Config{
+		TTL:            300 * time.Millisecond,
+		Concurrency:    8,
+		LargeValueSize: 1 << 20,
+		CheckStats:     true,
+	}
+}
+
+// RunConformance 对 factory 创建的缓存运行一致性测试，内置适配器与第三方适配器使用同一套用例验证语义：
+//
+//	func TestConformance(t *testing.T) {
+//		cachetest.RunConformance(t, func(t *testing.T) cache.Cache {
+//			c := mycache.New()
+//			if err := c.Start(cfg); err != nil {
+//				t.Fatal(err)
+//			}
+//			t.Cleanup(func() { _ = c.Close() })
+//			return c
+//		})
+//	}
+//
+// 值通过 cache.Scan 读回比较，因此 JSON 编码与直接保存原始值的适配器都适用
+func RunConformance(t *testing.T, factory Factory, opts ...Option) {
+	config := DefaultConfig()
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	s := &suite{factory: factory, config: config}
+	t.Run("Ping", s.ping)
+	t.Run("Basic", s.basic)
+	t.Run("Overwrite", s.overwrite)
+	t.Run("NoExpiry", s.noExpiry)
+	t.Run("TTL", s.ttl)
+	t.Run("ExpiryBoundary", s.expiryBoundary)
+	t.Run("Atomic", s.atomic)
+	t.Run("Prefix", s.prefix)
+	t.Run("Clear", s.clear)
+	t.Run("Context", s.context)
+	t.Run("Concurrent", s.concurrent)
+	t.Run("LargeValue", s.largeValue)
+	if config.CheckStats {
+		t.Run("Stats", s.stats)
+	}
+}
+
+// RedisAddr 返回环境变量 REDIS_ADDR 指定的 Redis 地址，未设置时跳过测试
+func RedisAddr(t testing.TB) string {
+	t.Helper()
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_ADDR not set, skipping integration test")
+	}
+	return addr
+}
+
+type suite struct {
+	factory Factory
+	config  Config
+}
+
+type record struct {
+	Name  string   `json:"name"`
+	Count int      `json:"count"`
+	Tags  []string `json:"tags"`
+}
+
+// mustScan 读取 key 并与 want 比较
+func mustScan[T comparable](t *testing.T, c cache.Cache, key string, want T) {
+	t.Helper()
+	var got T
+	if err := cache.Scan(c, key, &got); err != nil {
+		t.Fatalf("Scan(%q): %v", key, err)
+	}
+	if got != want {
+		t.Fatalf("Scan(%q) = %v, want %v", key, got, want)
+	}
+}
+
+func mustMiss(t *testing.T, c cache.Cache, key string) {
+	t.Helper()
+	if _, err := c.Get(key); !errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("Get(%q) err = %v, want ErrNotFound", key, err)
+	}
+	if c.Exists(key) {
+		t.Fatalf("Exists(%q) = true, want false", key)
+	}
+}
+
+// ping 启动后的缓存可用，ctx 已取消时返回错误
+func (s *suite) ping(t *testing.T) {
+	c := s.factory(t)
+
+	if err := c.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := c.Ping(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Ping(canceled) = %v, want context.Canceled", err)
+	}
+}
+
+func (s *suite) basic(t *testing.T) {
+	c := s.factory(t)
+
+	mustMiss(t, c, "missing")
+
+	c.Set("str", "hello", 0)
+	c.Set("int", 42, 0)
+	c.Set("struct", record{Name: "a", Count: 2, Tags: []string{"x", "y"}}, 0)
+
+	mustScan(t, c, "str", "hello")
+	mustScan(t, c, "int", 42)
+	var r record
+	if err := cache.Scan(c, "struct", &r); err != nil {
+		t.Fatalf("Scan(struct): %v", err)
+	}
+	if r.Name != "a" || r.Count != 2 || strings.Join(r.Tags, ",") != "x,y" {
+		t.Fatalf("Scan(struct) = %+v", r)
+	}
+	if !c.Exists("str") {
+		t.Fatal("Exists(str) = false after Set")
+	}
+
+	c.Delete("str")
+	mustMiss(t, c, "str")
+	// 删除不存在的 key 不报错
+	c.Delete("str")
+}
+
+func (s *suite) overwrite(t *testing.T) {
+	c := s.factory(t)
+
+	c.Set("k", "v1", 0)
+	c.Set("k", "v2", 0)
+	mustScan(t, c, "k", "v2")
+
+	// 覆盖写入使用新的 TTL
+	c.Set("k", "v3", s.config.TTL)
+	if _, ok := c.TTL("k"); !ok {
+		t.Fatal("TTL after overwrite with ttl: ok = false")
+	}
+	c.Set("k", "v4", 0)
+	if _, ok := c.TTL("k"); ok {
+		t.Fatal("TTL after overwrite without ttl: ok = true")
+	}
+}
+
+func (s *suite) noExpiry(t *testing.T) {
+	c := s.factory(t)
+
+	c.Set("k", "v", 0)
+	if d, ok := c.TTL("k"); ok {
+		t.Fatalf("TTL of key without expiry = %v, true; want ok = false", d)
+	}
+	if _, ok := c.TTL("missing"); ok {
+		t.Fatal("TTL of missing key: ok = true")
+	}
+	time.Sleep(s.config.TTL)
+	mustScan(t, c, "k", "v")
+}
+
+func (s *suite) ttl(t *testing.T) {
+	c := s.factory(t)
+
+	c.Set("k", "v", s.config.TTL)
+	d, ok := c.TTL("k")
+	if !ok || d <= 0 || d > s.config.TTL {
+		t.Fatalf("TTL = %v, %v; want (0, %v]", d, ok, s.config.TTL)
+	}
+
+	time.Sleep(s.config.TTL + s.config.TTL/2)
+	mustMiss(t, c, "k")
+	if _, ok := c.TTL("k"); ok {
+		t.Fatal("TTL of expired key: ok = true")
+	}
+	if keys, err := c.Keys("k", 0); err != nil || len(keys) != 0 {
+		t.Fatalf("Keys after expiry = %v, %v; want none", keys, err)
+	}
+}
+
+// expiryBoundary 过期前一刻仍可读，过期后立即不可读
+func (s *suite) expiryBoundary(t *testing.T) {
+	c := s.factory(t)
+
+	c.Set("k", "v", s.config.TTL)
+	time.Sleep(s.config.TTL * 2 / 3)
+	mustScan(t, c, "k", "v")
+	time.Sleep(s.config.TTL * 2 / 3)
+	mustMiss(t, c, "k")
+
+	// 过期的 key 可以重新写入
+	c.Set("k", "v2", 0)
+	mustScan(t, c, "k", "v2")
+}
+
+func (s *suite) atomic(t *testing.T) {
+	c := s.factory(t)
+
+	// old 为 nil 表示 key 不存在
+	if ok, err := c.CompareAndSwap("cas", nil, "a", 0); err != nil || !ok {
+		t.Fatalf("CAS on missing key = %v, %v; want true", ok, err)
+	}
+	if ok, err := c.CompareAndSwap("cas", nil, "b", 0); err != nil || ok {
+		t.Fatalf("CAS with nil old on existing key = %v, %v; want false", ok, err)
+	}
+	if ok, err := c.CompareAndSwap("cas", "x", "b", 0); err != nil || ok {
+		t.Fatalf("CAS with wrong old = %v, %v; want false", ok, err)
+	}
+	if ok, err := c.CompareAndSwap("cas", "a", "b", 0); err != nil || !ok {
+		t.Fatalf("CAS with matching old = %v, %v; want true", ok, err)
+	}
+	mustScan(t, c, "cas", "b")
+
+	if _, err := c.GetSet("gs", "first", 0); !errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("GetSet on missing key err = %v, want ErrNotFound", err)
+	}
+	mustScan(t, c, "gs", "first")
+	if old, err := c.GetSet("gs", "second", 0); err != nil || fmt.Sprint(old) != "first" {
+		t.Fatalf("GetSet = %v, %v; want first", old, err)
+	}
+	mustScan(t, c, "gs", "second")
+
+	if old, err := c.GetDel("gs"); err != nil || fmt.Sprint(old) != "second" {
+		t.Fatalf("GetDel = %v, %v; want second", old, err)
+	}
+	mustMiss(t, c, "gs")
+	if _, err := c.GetDel("gs"); !errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("GetDel on missing key err = %v, want ErrNotFound", err)
+	}
+}
+
+func (s *suite) prefix(t *testing.T) {
+	c := s.factory(t)
+
+	for _, key := range []string{"user:1", "user:2", "user:3", "order:1"} {
+		c.Set(key, key, 0)
+	}
+
+	keys, err := c.Keys("user:*", 0)
+	if err != nil {
+		t.Fatalf("Keys: %v", err)
+	}
+	sort.Strings(keys)
+	if strings.Join(keys, ",") != "user:1,user:2,user:3" {
+		t.Fatalf("Keys(user:*) = %v", keys)
+	}
+	if keys, err := c.Keys("user:*", 2); err != nil || len(keys) != 2 {
+		t.Fatalf("Keys(user:*, 2) = %v, %v; want 2 keys", keys, err)
+	}
+
+	if n := c.DeleteByPrefix("user:"); n != 3 {
+		t.Fatalf("DeleteByPrefix = %d, want 3", n)
+	}
+	mustMiss(t, c, "user:1")
+	mustScan(t, c, "order:1", "order:1")
+}
+
+func (s *suite) clear(t *testing.T) {
+	c := s.factory(t)
+
+	c.Set("a", 1, 0)
+	c.Set("b", 2, s.config.TTL)
+	c.Clear()
+	mustMiss(t, c, "a")
+	mustMiss(t, c, "b")
+
+	c.Set("a", 3, 0)
+	mustScan(t, c, "a", 3)
+}
+
+// context 已取消的 ctx 使 Ctx 版本返回错误且不执行写入
+func (s *suite) context(t *testing.T) {
+	c := s.factory(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := c.SetCtx(ctx, "k", "v", 0); err == nil {
+		t.Fatal("SetCtx with canceled ctx: err = nil")
+	}
+	mustMiss(t, c, "k")
+
+	c.Set("k", "v", 0)
+	if _, err := c.GetCtx(ctx, "k"); err == nil {
+		t.Fatal("GetCtx with canceled ctx: err = nil")
+	}
+	if err := c.DeleteCtx(ctx, "k"); err == nil {
+		t.Fatal("DeleteCtx with canceled ctx: err = nil")
+	}
+	mustScan(t, c, "k", "v")
+
+	if v, err := c.GetCtx(context.Background(), "k"); err != nil || fmt.Sprint(v) != "v" {
+		t.Fatalf("GetCtx = %v, %v; want v", v, err)
+	}
+}
+
+// concurrent 并发读写不同与相同的 key，结束后每个 key 的值完整
+func (s *suite) concurrent(t *testing.T) {
+	c := s.factory(t)
+	const perWorker = 50
+
+	var wg sync.WaitGroup
+	for w := 0; w < s.config.Concurrency; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				c.Set(fmt.Sprintf("w%d:%d", w, i), i, 0)
+				c.Set("shared", w, 0)
+				_, _ = c.Get("shared")
+				_, _ = c.Get(fmt.Sprintf("w%d:%d", w, i/2))
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	for w := 0; w < s.config.Concurrency; w++ {
+		for i := 0; i < perWorker; i++ {
+			mustScan(t, c, fmt.Sprintf("w%d:%d", w, i), i)
+		}
+	}
+	var shared int
+	if err := cache.Scan(c, "shared", &shared); err != nil || shared < 0 || shared >= s.config.Concurrency {
+		t.Fatalf("shared = %d, %v; want a value written by one of the workers", shared, err)
+	}
+}
+
+func (s *suite) largeValue(t *testing.T) {
+	c := s.factory(t)
+
+	b := make([]byte, s.config.LargeValueSize)
+	for i := range b {
+		b[i] = 'a' + byte(i%26)
+	}
+	want := string(b)
+	c.Set("large", want, 0)
+
+	var got string
+	if err := cache.Scan(c, "large", &got); err != nil {
+		t.Fatalf("Scan(large): %v", err)
+	}
+	if got != want {
+		t.Fatalf("large value corrupted: len %d, want %d", len(got), len(want))
+	}
+}
+
+// stats 按增量检查命中、未命中、写入与删除计数
+func (s *suite) stats(t *testing.T) {
+	c := s.factory(t)
+	before := c.Stats()
+
+	c.Set("k", "v", 0)
+	_, _ = c.Get("k")
+	_, _ = c.Get("k")
+	_, _ = c.Get("missing")
+	c.Delete("k")
+
+	after := c.Stats()
+	got := cache.Stats{
+		Hits:    after.Hits - before.Hits,
+		Misses:  after.Misses - before.Misses,
+		Sets:    after.Sets - before.Sets,
+		Deletes: after.Deletes - before.Deletes,
+	}
+	want := cache.Stats{Hits: 2, Misses: 1, Sets: 1, Deletes: 1}
+	if got != want {
+		t.Fatalf("Stats delta = %+v, want %+v", got, want)
+	}
+
+	// 实现了 StatsResetter 的适配器重置后计数归零
+	if r, ok := c.(cache.StatsResetter); ok {
+		r.ResetStats()
+		_, _ = c.Get("missing")
+		if s := c.Stats(); s != (cache.Stats{Misses: 1}) || s.HitRatio() != 0 {
+			t.Fatalf("Stats after ResetStats = %+v, want only 1 miss", s)
+		}
+	}
+}
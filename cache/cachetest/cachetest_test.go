@@ -0,0 +1,106 @@
+package cachetest
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/jiajia556/tool-box/cache"
+	"github.com/jiajia556/tool-box/cache/file"
+	"github.com/jiajia556/tool-box/cache/memory"
+	"github.com/jiajia556/tool-box/cache/redis"
+	"github.com/jiajia556/tool-box/cache/tiered"
+)
+
+func start(t *testing.T, c cache.Cache, config any) cache.Cache {
+	t.Helper()
+	if err := c.Start(config); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func TestMemory(t *testing.T) {
+	RunConformance(t, func(t *testing.T) cache.Cache {
+		return start(t, memory.NewMemoryCache(), memory.Options{})
+	})
+}
+
+func TestMemoryNative(t *testing.T) {
+	RunConformance(t, func(t *testing.T) cache.Cache {
+		return start(t, memory.NewMemoryCache(), memory.Options{Native: true, DeepCopy: true})
+	})
+}
+
+func TestFile(t *testing.T) {
+	RunConformance(t, func(t *testing.T) cache.Cache {
+		return start(t, file.NewFileCache(), file.Options{Dir: t.TempDir()})
+	})
+}
+
+func TestRedis(t *testing.T) {
+	addr := RedisAddr(t)
+	RunConformance(t, func(t *testing.T) cache.Cache {
+		c := start(t, redis.NewRedisCache(), redis.Options{Addr: addr, Prefix: "cachetest:" + uuid.NewString()})
+		t.Cleanup(c.Clear)
+		return c
+	})
+}
+
+// TestRedisWriteBatch 开启写缓冲；缓冲的写入在刷新时才计入统计，不检查 Stats
+func TestRedisWriteBatch(t *testing.T) {
+	addr := RedisAddr(t)
+	RunConformance(t, func(t *testing.T) cache.Cache {
+		c := start(t, redis.NewRedisCache(), redis.Options{
+			Addr:           addr,
+			Prefix:         "cachetest:" + uuid.NewString(),
+			WriteBatchSize: 64,
+		})
+		t.Cleanup(c.Clear)
+		return c
+	}, WithStats(false))
+}
+
+// TestRedisCluster 需要设置 REDIS_CLUSTER_ADDRS（逗号分隔的集群节点地址）
+func TestRedisCluster(t *testing.T) {
+	addrs := os.Getenv("REDIS_CLUSTER_ADDRS")
+	if addrs == "" {
+		t.Skip("REDIS_CLUSTER_ADDRS not set, skipping integration test")
+	}
+	RunConformance(t, func(t *testing.T) cache.Cache {
+		c := start(t, redis.NewRedisCache(), redis.Options{Addrs: strings.Split(addrs, ","), Prefix: "cachetest:" + uuid.NewString()})
+		t.Cleanup(c.Clear)
+		return c
+	})
+}
+
+// TestRedisSentinel 需要设置 REDIS_SENTINEL_ADDRS（逗号分隔的哨兵地址）与 REDIS_MASTER_NAME
+func TestRedisSentinel(t *testing.T) {
+	addrs, master := os.Getenv("REDIS_SENTINEL_ADDRS"), os.Getenv("REDIS_MASTER_NAME")
+	if addrs == "" || master == "" {
+		t.Skip("REDIS_SENTINEL_ADDRS or REDIS_MASTER_NAME not set, skipping integration test")
+	}
+	RunConformance(t, func(t *testing.T) cache.Cache {
+		c := start(t, redis.NewRedisCache(), redis.Options{
+			MasterName:    master,
+			SentinelAddrs: strings.Split(addrs, ","),
+			Prefix:        "cachetest:" + uuid.NewString(),
+		})
+		t.Cleanup(c.Clear)
+		return c
+	})
+}
+
+func TestTiered(t *testing.T) {
+	addr := RedisAddr(t)
+	RunConformance(t, func(t *testing.T) cache.Cache {
+		c := start(t, tiered.NewTieredCache(), tiered.Options{
+			L2: redis.Options{Addr: addr, Prefix: "cachetest:" + uuid.NewString()},
+		})
+		t.Cleanup(c.Clear)
+		return c
+	}, WithStats(false))
+}
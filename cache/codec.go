@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Codec 缓存值的编解码器，redis / file 适配器可通过 Options.Codec 选择，默认 JSONCodec。
+// 可按此接口接入 msgpack、protobuf 等第三方编码。
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+
+	// Unmarshal 解码到 v（指针），v 为 *any 时由编解码器决定返回的类型
+	Unmarshal(data []byte, v any) error
+}
+
+var (
+	// JSONCodec 使用 encoding/json，结构体读回为 map[string]any、数字读回为 float64
+	JSONCodec Codec = jsonCodec{}
+
+	// GobCodec 使用 encoding/gob，体积更小且保留原类型；自定义类型需先 gob.Register
+	GobCodec Codec = gobCodec{}
+)
+
+// CodecOrJSON 返回 c，c 为 nil 时返回 JSONCodec
+func CodecOrJSON(c Codec) Codec {
+	if c == nil {
+		return JSONCodec
+	}
+	return c
+}
+
+// IsJSONCodec 判断 c 是否为默认的 JSON 编解码器（nil 也视为 JSON）
+func IsJSONCodec(c Codec) bool {
+	_, ok := CodecOrJSON(c).(jsonCodec)
+	return ok
+}
+
+// ToJSON 将 c 编码的值转换为 JSON，供适配器实现 RawGetter
+func ToJSON(c Codec, data []byte) ([]byte, error) {
+	if IsJSONCodec(c) {
+		return data, nil
+	}
+	var v any
+	if err := c.Unmarshal(data, &v); err != nil {
+		return nil, ErrDecode
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, ErrDecode
+	}
+	return b, nil
+}
+
+// EqualEncodedWith 同 EqualEncoded，stored 由 c 编码
+func EqualEncodedWith(c Codec, stored []byte, v any) bool {
+	if len(stored) == 0 || IsJSONCodec(c) {
+		return EqualEncoded(stored, v)
+	}
+	b, err := ToJSON(c, stored)
+	if err != nil {
+		return false
+	}
+	return EqualEncoded(b, v)
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+type gobCodec struct{}
+
+// Marshal 以接口值编码，解码时无需知道具体类型
+func (gobCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte, v any) error {
+	var decoded any
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&decoded); err != nil {
+		return err
+	}
+
+	dv := reflect.ValueOf(v)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() {
+		return fmt.Errorf("cache: gob unmarshal into non-pointer %T", v)
+	}
+	if decoded == nil {
+		dv.Elem().SetZero()
+		return nil
+	}
+	if sv := reflect.ValueOf(decoded); sv.Type().AssignableTo(dv.Elem().Type()) {
+		dv.Elem().Set(sv)
+		return nil
+	}
+	return fmt.Errorf("%w: cannot assign %T to %s", ErrTypeMismatch, decoded, dv.Elem().Type())
+}
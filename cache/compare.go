@@ -0,0 +1,29 @@
+package cache
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// EqualEncoded 判断已编码的缓存值与 v 是否相等，供适配器实现 CompareAndSwap。
+// 比较前双方都经过 JSON 解码，因此 Get 读回的通用结构可以直接作为 old 传入；
+// stored 为空表示 key 不存在，此时仅当 v 为 nil 时相等。
+func EqualEncoded(stored []byte, v any) bool {
+	if len(stored) == 0 {
+		return v == nil
+	}
+	if v == nil {
+		return false
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return false
+	}
+
+	var a, c any
+	if json.Unmarshal(stored, &a) != nil || json.Unmarshal(b, &c) != nil {
+		return false
+	}
+	return reflect.DeepEqual(a, c)
+}
@@ -0,0 +1,362 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ConsistencyConfig 读己之写配置
+type ConsistencyConfig struct {
+	// 写入后校验副本读取结果的时间窗口，应大于副本的最大复制延迟，默认 5s；
+	// 超过窗口的 key 直接读副本
+	Window time.Duration
+
+	// 本地记录的最大 key 数，超出时丢弃最早的记录（这些 key 在窗口内可能读到旧值），默认 10000
+	MaxKeys int
+}
+
+// ConsistencyOption 选项函数
+type ConsistencyOption func(*ConsistencyConfig)
+
+// WithConsistencyWindow 设置校验窗口
+func WithConsistencyWindow(d time.Duration) ConsistencyOption {
+	return func(c *ConsistencyConfig) {
+		c.Window = d
+	}
+}
+
+// WithConsistencyMaxKeys 设置本地记录的最大 key 数
+func WithConsistencyMaxKeys(n int) ConsistencyOption {
+	return func(c *ConsistencyConfig) {
+		c.MaxKeys = n
+	}
+}
+
+type rywEntry struct {
+	key     string
+	digest  uint64 // 写入值的摘要
+	deleted bool   // 最近一次写入是删除
+	expire  time.Time
+}
+
+// ReadYourWrites 读写分离时的读己之写包装：写入走主库，读取走副本，
+// 本进程写入的 key 在 Window 内读取时校验副本返回的值与最近一次写入一致（删除的 key 应不存在），
+// 不一致说明副本复制延迟，改为从主库读取。
+// 只保证本进程写入的 key，其他进程的写入仍可能在复制延迟内读到旧值。
+//
+//	c := cache.NewReadYourWrites(primary, replica, cache.WithConsistencyWindow(2*time.Second))
+type ReadYourWrites struct {
+	primary, replica Cache
+	config           ConsistencyConfig
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // 按写入时间排列，最早的在前
+	// DeleteByPrefix / Clear 后无法逐个记录，在此之前的所有读取都走主库
+	primaryUntil time.Time
+
+	staleReads atomic.Uint64
+}
+
+// NewReadYourWrites 创建写入 primary、读取 replica 的缓存
+func NewReadYourWrites(primary, replica Cache, opts ...ConsistencyOption) *ReadYourWrites {
+	config := ConsistencyConfig{Window: 5 * time.Second, MaxKeys: 10000}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return &ReadYourWrites{
+		primary: primary,
+		replica: replica,
+		config:  config,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Unwrap 返回主库
+func (c *ReadYourWrites) Unwrap() Cache {
+	return c.primary
+}
+
+// StaleReads 返回副本读到旧值后改读主库的次数
+func (c *ReadYourWrites) StaleReads() uint64 {
+	return c.staleReads.Load()
+}
+
+// record 记录 key 的最近一次写入，value 为写入的值，deleted 表示删除
+func (c *ReadYourWrites) record(key string, value any, deleted bool) {
+	e := &rywEntry{key: key, deleted: deleted}
+	if !deleted {
+		b, err := json.Marshal(value)
+		if err != nil {
+			c.forget(key)
+			return
+		}
+		e.digest = digestJSON(b)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	e.expire = now.Add(c.config.Window)
+	if el, ok := c.entries[key]; ok {
+		c.order.Remove(el)
+	}
+	c.entries[key] = c.order.PushBack(e)
+
+	for el := c.order.Front(); el != nil; el = c.order.Front() {
+		old := el.Value.(*rywEntry)
+		if now.Before(old.expire) && (c.config.MaxKeys <= 0 || len(c.entries) <= c.config.MaxKeys) {
+			break
+		}
+		c.order.Remove(el)
+		delete(c.entries, old.key)
+	}
+}
+
+// forget 删除 key 的记录，写入结果未知时调用，之后读取不再校验
+func (c *ReadYourWrites) forget(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		c.order.Remove(el)
+		delete(c.entries, key)
+	}
+}
+
+// pending 返回 key 在窗口内的写入记录；ok 为 false 时可直接读副本。
+// 处于 DeleteByPrefix / Clear 之后的窗口时返回 nil, true，表示应读主库
+func (c *ReadYourWrites) pending(key string) (*rywEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(c.primaryUntil) {
+		return nil, true
+	}
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*rywEntry)
+	if !now.Before(e.expire) {
+		return nil, false
+	}
+	return e, true
+}
+
+// fresh 判断副本读取的结果是否不旧于 e 记录的写入
+func fresh(e *rywEntry, raw []byte, err error) bool {
+	if e == nil {
+		return false
+	}
+	if e.deleted {
+		return errors.Is(err, ErrNotFound)
+	}
+	return err == nil && digestJSON(raw) == e.digest
+}
+
+// digestJSON 计算 JSON 的摘要，先解码再编码以消除 map 顺序、空白等差异
+func digestJSON(b []byte) uint64 {
+	var v any
+	if err := json.Unmarshal(b, &v); err == nil {
+		if nb, err := json.Marshal(v); err == nil {
+			b = nb
+		}
+	}
+	h := fnv.New64a()
+	_, _ = h.Write(b)
+	return h.Sum64()
+}
+
+// readPrimary 是否应直接读主库：窗口内的 key 先读副本校验，不一致时计入 StaleReads
+func (c *ReadYourWrites) readPrimary(key string) bool {
+	e, ok := c.pending(key)
+	if !ok {
+		return false
+	}
+	if e == nil {
+		return true
+	}
+	raw, err := getRaw(c.replica, key)
+	if fresh(e, raw, err) {
+		return false
+	}
+	c.staleReads.Add(1)
+	return true
+}
+
+func (c *ReadYourWrites) Get(key string) (any, error) {
+	return c.GetCtx(context.Background(), key)
+}
+
+func (c *ReadYourWrites) GetCtx(ctx context.Context, key string) (any, error) {
+	if c.readPrimary(key) {
+		return c.primary.GetCtx(ctx, key)
+	}
+	return c.replica.GetCtx(ctx, key)
+}
+
+func (c *ReadYourWrites) GetRaw(key string) ([]byte, error) {
+	if c.readPrimary(key) {
+		return getRaw(c.primary, key)
+	}
+	return getRaw(c.replica, key)
+}
+
+func (c *ReadYourWrites) Set(key string, value any, ttl time.Duration) {
+	_ = c.SetCtx(context.Background(), key, value, ttl)
+}
+
+func (c *ReadYourWrites) SetCtx(ctx context.Context, key string, value any, ttl time.Duration) error {
+	if err := c.primary.SetCtx(ctx, key, value, ttl); err != nil {
+		c.forget(key)
+		return err
+	}
+	c.record(key, value, false)
+	return nil
+}
+
+func (c *ReadYourWrites) Delete(key string) {
+	_ = c.DeleteCtx(context.Background(), key)
+}
+
+func (c *ReadYourWrites) DeleteCtx(ctx context.Context, key string) error {
+	if err := c.primary.DeleteCtx(ctx, key); err != nil {
+		c.forget(key)
+		return err
+	}
+	c.record(key, nil, true)
+	return nil
+}
+
+// DeleteByPrefix 在主库中删除，之后 Window 内的读取都走主库
+func (c *ReadYourWrites) DeleteByPrefix(prefix string) int {
+	n := c.primary.DeleteByPrefix(prefix)
+	c.readPrimaryFor(func(key string) bool { return strings.HasPrefix(key, prefix) })
+	return n
+}
+
+// Clear 清空主库，之后 Window 内的读取都走主库
+func (c *ReadYourWrites) Clear() {
+	c.primary.Clear()
+	c.readPrimaryFor(func(string) bool { return true })
+}
+
+// readPrimaryFor 删除 match 的 key 的记录，并在 Window 内让所有读取走主库
+func (c *ReadYourWrites) readPrimaryFor(match func(key string) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, el := range c.entries {
+		if match(key) {
+			c.order.Remove(el)
+			delete(c.entries, key)
+		}
+	}
+	c.primaryUntil = time.Now().Add(c.config.Window)
+}
+
+// Keys 列出主库中的 key
+func (c *ReadYourWrites) Keys(pattern string, limit int) ([]string, error) {
+	return c.primary.Keys(pattern, limit)
+}
+
+func (c *ReadYourWrites) TTL(key string) (time.Duration, bool) {
+	ttl, ok, _ := c.TTLCtx(context.Background(), key)
+	return ttl, ok
+}
+
+// TTLCtx 窗口内写入过的 key 读主库
+func (c *ReadYourWrites) TTLCtx(ctx context.Context, key string) (time.Duration, bool, error) {
+	if _, ok := c.pending(key); ok {
+		return c.primary.TTLCtx(ctx, key)
+	}
+	return c.replica.TTLCtx(ctx, key)
+}
+
+func (c *ReadYourWrites) Exists(key string) bool {
+	ok, _ := c.ExistsCtx(context.Background(), key)
+	return ok
+}
+
+// ExistsCtx 窗口内写入过的 key 读主库
+func (c *ReadYourWrites) ExistsCtx(ctx context.Context, key string) (bool, error) {
+	if _, ok := c.pending(key); ok {
+		return c.primary.ExistsCtx(ctx, key)
+	}
+	return c.replica.ExistsCtx(ctx, key)
+}
+
+// Stats 返回副本的统计（读取主要发生在副本）
+func (c *ReadYourWrites) Stats() Stats {
+	return c.replica.Stats()
+}
+
+// CompareAndSwap 在主库上比较并写入
+func (c *ReadYourWrites) CompareAndSwap(key string, old, new any, ttl time.Duration) (bool, error) {
+	return c.CompareAndSwapCtx(context.Background(), key, old, new, ttl)
+}
+
+func (c *ReadYourWrites) CompareAndSwapCtx(ctx context.Context, key string, old, new any, ttl time.Duration) (bool, error) {
+	ok, err := c.primary.CompareAndSwapCtx(ctx, key, old, new, ttl)
+	if err != nil {
+		c.forget(key)
+		return ok, err
+	}
+	if ok {
+		c.record(key, new, false)
+	}
+	return ok, nil
+}
+
+func (c *ReadYourWrites) GetDel(key string) (any, error) {
+	return c.GetDelCtx(context.Background(), key)
+}
+
+func (c *ReadYourWrites) GetDelCtx(ctx context.Context, key string) (any, error) {
+	v, err := c.primary.GetDelCtx(ctx, key)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		c.forget(key)
+		return v, err
+	}
+	c.record(key, nil, true)
+	return v, err
+}
+
+func (c *ReadYourWrites) GetSet(key string, value any, ttl time.Duration) (any, error) {
+	return c.GetSetCtx(context.Background(), key, value, ttl)
+}
+
+func (c *ReadYourWrites) GetSetCtx(ctx context.Context, key string, value any, ttl time.Duration) (any, error) {
+	v, err := c.primary.GetSetCtx(ctx, key, value, ttl)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		c.forget(key)
+		return v, err
+	}
+	c.record(key, value, false)
+	return v, err
+}
+
+// Ping 检查主库与副本均可用
+func (c *ReadYourWrites) Ping(ctx context.Context) error {
+	return errors.Join(c.primary.Ping(ctx), c.replica.Ping(ctx))
+}
+
+// Close 不关闭主库与副本，二者由创建方负责关闭
+func (c *ReadYourWrites) Close() error {
+	return nil
+}
+
+// Start 基于已启动的缓存创建，无需启动
+func (c *ReadYourWrites) Start(config any) error {
+	return nil
+}
@@ -0,0 +1,246 @@
+package cache
+
+import (
+	"expvar"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Describer 可描述自身的 Cache，供 DebugHandler 输出适配器名称、配置与条目数
+type Describer interface {
+	Describe() Description
+}
+
+// Description 适配器描述
+type Description struct {
+	// 适配器名称，如 "memory"、"redis"
+	Adapter string `json:"adapter"`
+
+	// 配置，应经过 MaskSecrets 脱敏
+	Config any `json:"config,omitempty"`
+
+	// 条目数，<0 表示未知（如 Redis 共享实例无法廉价统计前缀下的 key 数）
+	Items int `json:"items"`
+}
+
+// DebugInfo 缓存实例的调试快照
+type DebugInfo struct {
+	Adapter  string     `json:"adapter"`
+	Wrappers []string   `json:"wrappers,omitempty"`
+	Config   any        `json:"config,omitempty"`
+	Items    *int       `json:"items,omitempty"`
+	Stats    DebugStats `json:"stats"`
+	TopKeys  []KeyCount `json:"top_keys,omitempty"`
+}
+
+// DebugStats 命中统计与命中率，字段名与 AdminHandler 的 /stats 一致
+type DebugStats struct {
+	Hits     uint64  `json:"hits"`
+	Misses   uint64  `json:"misses"`
+	Sets     uint64  `json:"sets"`
+	Deletes  uint64  `json:"deletes"`
+	HitRatio float64 `json:"hit_ratio"`
+}
+
+// Debug 返回 c 的调试快照：Tracked 等包装的类型记录在 Wrappers 中，适配器信息取自最内层实现的 Describer，
+// 统计取自 c 本身
+func Debug(c Cache) DebugInfo {
+	s := c.Stats()
+	info := DebugInfo{Stats: DebugStats{
+		Hits:     s.Hits,
+		Misses:   s.Misses,
+		Sets:     s.Sets,
+		Deletes:  s.Deletes,
+		HitRatio: s.HitRatio(),
+	}}
+	for {
+		u, ok := c.(interface{ Unwrap() Cache })
+		if !ok {
+			break
+		}
+		info.Wrappers = append(info.Wrappers, fmt.Sprintf("%T", c))
+		c = u.Unwrap()
+	}
+
+	d, ok := c.(Describer)
+	if !ok {
+		info.Adapter = fmt.Sprintf("%T", c)
+		return info
+	}
+	desc := d.Describe()
+	info.Adapter = desc.Adapter
+	info.Config = desc.Config
+	if desc.Items >= 0 {
+		info.Items = &desc.Items
+	}
+	return info
+}
+
+// debugTopKeys DebugHandler 输出的热点 key 数
+const debugTopKeys = 20
+
+// DebugHandler 返回只读的调试接口，以 JSON 输出全局缓存（或 WithAdminCache 指定的缓存）
+// 与全部命名实例的适配器、脱敏后的配置、条目数、命中统计与热点 key，用于线上快速排查。
+// 与 AdminHandler 共用选项，Token 不使用；接口不返回任何 key 的值，但配置与热点 key 仍可能敏感，
+// 应只在内网或鉴权后暴露
+func DebugHandler(opts ...AdminOption) http.Handler {
+	var config AdminConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeAdminJSON(w, http.StatusOK, debugSnapshot(config.Cache))
+	})
+}
+
+// PublishExpvar 将调试快照以 name 发布到 expvar（/debug/vars）；name 已发布时 panic，与 expvar.Publish 一致
+func PublishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return debugSnapshot(nil)
+	}))
+}
+
+func debugSnapshot(c Cache) map[string]any {
+	resp := map[string]any{"time": time.Now().Format(time.RFC3339)}
+	if c != nil {
+		resp["cache"] = Debug(c)
+		return resp
+	}
+
+	if c = current(); c != nil {
+		info := Debug(c)
+		if hot := currentHotKeys(); hot != nil {
+			info.TopKeys = hot.TopKeys(debugTopKeys)
+		}
+		resp["global"] = info
+	} else {
+		resp["global"] = nil
+	}
+
+	namedMu.RLock()
+	names := make([]string, 0, len(named))
+	for name := range named {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	instances := make(map[string]DebugInfo, len(names))
+	for _, name := range names {
+		instances[name] = Debug(named[name])
+	}
+	namedMu.RUnlock()
+	if len(instances) > 0 {
+		resp["named"] = instances
+	}
+	return resp
+}
+
+// secretMask 脱敏后的占位值
+const secretMask = "******"
+
+// sensitiveNames 字段名（小写、去掉下划线后）包含这些词时脱敏
+var sensitiveNames = []string{"password", "passwd", "secret", "token", "credential", "privatekey", "accesskey", "apikey"}
+
+func sensitive(name string) bool {
+	name = strings.ToLower(strings.ReplaceAll(name, "_", ""))
+	for _, s := range sensitiveNames {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaskSecrets 将配置转换为便于 JSON 输出的 map，password、secret、token 等字段的非空值替换为 "******"；
+// 字段名优先取 json tag，tag 为 "-" 的字段与函数、通道等不可序列化的值按类型名输出，
+// time.Duration 输出为 "1m30s" 形式
+func MaskSecrets(config any) any {
+	if config == nil {
+		return nil
+	}
+	return maskValue(reflect.ValueOf(config))
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func maskValue(v reflect.Value) any {
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String()
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return maskValue(v.Elem())
+	case reflect.Struct:
+		out := make(map[string]any, v.NumField())
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			fv := v.Field(i)
+			if name == "-" {
+				// 不参与序列化的字段（如 Codec、Fallback）只输出类型
+				if !fv.IsZero() {
+					out[f.Name] = typeName(fv)
+				}
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			out[name] = maskField(name, fv)
+		}
+		return out
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return typeName(v)
+		}
+		out := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			k := iter.Key().String()
+			out[k] = maskField(k, iter.Value())
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			return fmt.Sprintf("<%d bytes>", v.Len())
+		}
+		out := make([]any, v.Len())
+		for i := range out {
+			out[i] = maskValue(v.Index(i))
+		}
+		return out
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		if v.IsNil() {
+			return nil
+		}
+		return typeName(v)
+	}
+	return v.Interface()
+}
+
+func maskField(name string, v reflect.Value) any {
+	if sensitive(name) {
+		if v.IsZero() {
+			return ""
+		}
+		return secretMask
+	}
+	return maskValue(v)
+}
+
+func typeName(v reflect.Value) string {
+	if v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v.Type().String()
+}
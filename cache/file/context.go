@@ -0,0 +1,89 @@
+package file
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// 带 context 的版本：文件缓存的读写较快，仅在执行前检查 ctx 是否已取消或到期
+
+func (f *FileCache) GetCtx(ctx context.Context, key string) (any, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return f.Get(key)
+}
+
+func (f *FileCache) SetCtx(ctx context.Context, key string, value any, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	f.Set(key, value, ttl)
+	return nil
+}
+
+func (f *FileCache) DeleteCtx(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	f.Delete(key)
+	return nil
+}
+
+func (f *FileCache) TTLCtx(ctx context.Context, key string) (time.Duration, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, false, err
+	}
+	d, ok := f.TTL(key)
+	return d, ok, nil
+}
+
+func (f *FileCache) ExistsCtx(ctx context.Context, key string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return f.Exists(key), nil
+}
+
+func (f *FileCache) CompareAndSwapCtx(ctx context.Context, key string, old, new any, ttl time.Duration) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return f.CompareAndSwap(key, old, new, ttl)
+}
+
+func (f *FileCache) GetDelCtx(ctx context.Context, key string) (any, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return f.GetDel(key)
+}
+
+func (f *FileCache) GetSetCtx(ctx context.Context, key string, value any, ttl time.Duration) (any, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return f.GetSet(key, value, ttl)
+}
+
+// Ping 在缓存目录中创建并删除一个临时文件，检查目录存在且可写
+func (f *FileCache) Ping(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(f.dir, ".ping-*")
+	if err != nil {
+		return fmt.Errorf("file cache: ping: %w", err)
+	}
+	name := tmp.Name()
+	err = tmp.Close()
+	if rmErr := os.Remove(name); err == nil {
+		err = rmErr
+	}
+	if err != nil {
+		return fmt.Errorf("file cache: ping: %w", err)
+	}
+	return nil
+}
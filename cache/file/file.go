@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -12,18 +13,31 @@ import (
 )
 
 type fileItem struct {
-	Value      json.RawMessage `json:"value"`
+	Value      json.RawMessage `json:"value,omitempty"` // JSON 编码的值直接内嵌
+	Data       []byte          `json:"data,omitempty"`  // 其他编解码器的编码结果（base64）
 	Expiration time.Time       `json:"expiration"`
 }
 
+// payload 返回编码后的值
+func (item fileItem) payload() []byte {
+	if item.Data != nil {
+		return item.Data
+	}
+	return item.Value
+}
+
 type FileCache struct {
 	dir   string
+	codec cache.Codec
 	mu    sync.RWMutex
 	stats cache.Stats
 }
 
 type Options struct {
 	Dir string `json:"dir"`
+
+	// 值的编解码器，nil 表示 cache.JSONCodec
+	Codec cache.Codec `json:"-"`
 }
 
 // NewFileCache create new file cache
@@ -41,25 +55,83 @@ func (f *FileCache) ensureDir() error {
 }
 
 func (f *FileCache) Get(key string) (any, error) {
+	start := cache.HookStart()
+	b, err := f.read(key)
+	var v any
+	if err == nil {
+		v, err = f.decode(b)
+	}
+	cache.Observe(cache.AdapterFile, "get", key, start, err)
+	return v, err
+}
+
+// GetRaw 返回 JSON 编码的值，由调用方解码到具体类型；使用其他编解码器时先转换为 JSON
+func (f *FileCache) GetRaw(key string) ([]byte, error) {
+	start := cache.HookStart()
+	b, err := f.read(key)
+	if err == nil {
+		b, err = cache.ToJSON(f.codec, b)
+	}
+	cache.Observe(cache.AdapterFile, "get", key, start, err)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// StoresNative 实现 cache.NativeCache：使用非 JSON 编解码器时 Get 的结果可直接断言类型
+func (f *FileCache) StoresNative() bool {
+	return !cache.IsJSONCodec(f.codec)
+}
+
+// encode 按编解码器生成条目：JSON 直接内嵌到文件中，其他编码保存在 data 字段
+func (f *FileCache) encode(value any, ttl time.Duration) (fileItem, error) {
+	b, err := f.codec.Marshal(value)
+	if err != nil {
+		return fileItem{}, err
+	}
+
+	var item fileItem
+	if cache.IsJSONCodec(f.codec) {
+		item.Value = b
+	} else {
+		item.Data = b
+	}
+	if ttl > 0 {
+		item.Expiration = time.Now().Add(ttl)
+	}
+	return item, nil
+}
+
+func (f *FileCache) decode(b []byte) (any, error) {
+	var v any
+	if err := f.codec.Unmarshal(b, &v); err != nil {
+		return nil, cache.ErrDecode
+	}
+	return v, nil
+}
+
+// read 返回未过期条目编码后的值
+func (f *FileCache) read(key string) ([]byte, error) {
 	f.mu.RLock()
 	defer f.mu.RUnlock()
 
 	filePath := f.getFilePath(key)
 	data, err := os.ReadFile(filePath)
 	if err != nil {
-		f.stats.Misses++
+		f.stats.Miss()
 		return nil, cache.ErrNotFound
 	}
 
 	var item fileItem
 	if err := json.Unmarshal(data, &item); err != nil {
-		f.stats.Misses++
+		f.stats.Miss()
 		return nil, cache.ErrDecode
 	}
 
 	// 检查是否过期
 	if !item.Expiration.IsZero() && time.Now().After(item.Expiration) {
-		f.stats.Misses++
+		f.stats.Miss()
 		// 异步删除过期文件
 		go func() {
 			_ = os.Remove(filePath)
@@ -67,55 +139,147 @@ func (f *FileCache) Get(key string) (any, error) {
 		return nil, cache.ErrNotFound
 	}
 
-	var v any
-	if err := json.Unmarshal(item.Value, &v); err != nil {
-		f.stats.Misses++
-		return nil, cache.ErrDecode
-	}
-
-	f.stats.Hits++
-	return v, nil
+	f.stats.Hit()
+	return item.payload(), nil
 }
 
 func (f *FileCache) Set(key string, value any, ttl time.Duration) {
+	start := cache.HookStart()
+	err := f.set(key, value, ttl)
+	cache.Observe(cache.AdapterFile, "set", key, start, err)
+}
+
+func (f *FileCache) set(key string, value any, ttl time.Duration) error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	b, err := json.Marshal(value)
+	item, err := f.encode(value, ttl)
 	if err != nil {
-		return
+		return err
 	}
 
-	var expiration time.Time
-	if ttl > 0 {
-		expiration = time.Now().Add(ttl)
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
 	}
 
-	item := fileItem{
-		Value:      b,
-		Expiration: expiration,
+	filePath := f.getFilePath(key)
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		return err
 	}
 
-	data, err := json.Marshal(item)
+	f.stats.Set()
+	return nil
+}
+
+// CompareAndSwap 仅保证单进程内的原子性
+func (f *FileCache) CompareAndSwap(key string, old, new any, ttl time.Duration) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	item, err := f.encode(new, ttl)
 	if err != nil {
-		return
+		return false, err
 	}
 
 	filePath := f.getFilePath(key)
+	var current []byte
+	if data, err := os.ReadFile(filePath); err == nil {
+		var cur fileItem
+		if err := json.Unmarshal(data, &cur); err == nil &&
+			(cur.Expiration.IsZero() || time.Now().Before(cur.Expiration)) {
+			current = cur.payload()
+		}
+	}
+	if !cache.EqualEncodedWith(f.codec, current, old) {
+		return false, nil
+	}
+
+	data, err := json.Marshal(item)
+	if err != nil {
+		return false, err
+	}
 	if err := os.WriteFile(filePath, data, 0644); err != nil {
-		return
+		return false, err
+	}
+
+	f.stats.Set()
+	return true, nil
+}
+
+// GetDel 仅保证单进程内的原子性
+func (f *FileCache) GetDel(key string) (any, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	old, err := f.current(key)
+	if err != nil {
+		return nil, err
+	}
+	_ = os.Remove(f.getFilePath(key))
+	f.stats.Delete()
+	return old, nil
+}
+
+// GetSet 仅保证单进程内的原子性
+func (f *FileCache) GetSet(key string, value any, ttl time.Duration) (any, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	item, err := f.encode(value, ttl)
+	if err != nil {
+		return nil, err
 	}
 
-	f.stats.Sets++
+	old, oldErr := f.current(key)
+
+	data, err := json.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(f.getFilePath(key), data, 0644); err != nil {
+		return nil, err
+	}
+	f.stats.Set()
+	return old, oldErr
+}
+
+// current 返回未过期的当前值并计入命中统计，调用方需持有写锁
+func (f *FileCache) current(key string) (any, error) {
+	data, err := os.ReadFile(f.getFilePath(key))
+	if err != nil {
+		f.stats.Miss()
+		return nil, cache.ErrNotFound
+	}
+
+	var item fileItem
+	if err := json.Unmarshal(data, &item); err != nil {
+		f.stats.Miss()
+		return nil, cache.ErrDecode
+	}
+	if !item.Expiration.IsZero() && time.Now().After(item.Expiration) {
+		f.stats.Miss()
+		return nil, cache.ErrNotFound
+	}
+
+	v, err := f.decode(item.payload())
+	if err != nil {
+		f.stats.Miss()
+		return nil, err
+	}
+	f.stats.Hit()
+	return v, nil
 }
 
 func (f *FileCache) Delete(key string) {
+	start := cache.HookStart()
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
 	filePath := f.getFilePath(key)
 	_ = os.Remove(filePath)
-	f.stats.Deletes++
+	f.stats.Delete()
+	cache.Observe(cache.AdapterFile, "delete", key, start, nil)
 }
 
 func (f *FileCache) Clear() {
@@ -134,6 +298,70 @@ func (f *FileCache) Clear() {
 	}
 }
 
+// DeleteByPrefix 按文件名匹配 "<prefix>*.cache.json"
+func (f *FileCache) DeleteByPrefix(prefix string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return 0
+	}
+
+	n := 0
+	for _, entry := range entries {
+		key, ok := strings.CutSuffix(entry.Name(), ".cache.json")
+		if entry.IsDir() || !ok || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if os.Remove(filepath.Join(f.dir, entry.Name())) == nil {
+			n++
+		}
+	}
+	f.stats.AddDeletes(uint64(n))
+	return n
+}
+
+// Keys 按文件名还原 key，读取文件跳过已过期的条目；目录不存在时返回空
+func (f *FileCache) Keys(pattern string, limit int) ([]string, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	var keys []string
+	for _, entry := range entries {
+		if limit > 0 && len(keys) >= limit {
+			break
+		}
+		key, ok := strings.CutSuffix(entry.Name(), ".cache.json")
+		if entry.IsDir() || !ok || !cache.MatchPattern(pattern, key) {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(f.dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		var item fileItem
+		if err := json.Unmarshal(data, &item); err != nil {
+			continue
+		}
+		if !item.Expiration.IsZero() && now.After(item.Expiration) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
 func (f *FileCache) Exists(key string) bool {
 	f.mu.RLock()
 	defer f.mu.RUnlock()
@@ -185,10 +413,31 @@ func (f *FileCache) TTL(key string) (time.Duration, bool) {
 }
 
 func (f *FileCache) Stats() cache.Stats {
-	f.mu.RLock()
-	defer f.mu.RUnlock()
+	return f.stats.Snapshot()
+}
 
-	return f.stats
+func (f *FileCache) ResetStats() {
+	f.stats.Reset()
+}
+
+// Describe 实现 cache.Describer，条目数为目录中的缓存文件数，包含尚未清理的过期条目；读取目录失败时为 -1
+func (f *FileCache) Describe() cache.Description {
+	desc := cache.Description{
+		Adapter: cache.AdapterFile,
+		Config:  cache.MaskSecrets(Options{Dir: f.dir, Codec: f.codec}),
+		Items:   -1,
+	}
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return desc
+	}
+	desc.Items = 0
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".cache.json") {
+			desc.Items++
+		}
+	}
+	return desc
 }
 
 func (f *FileCache) Close() error {
@@ -207,6 +456,7 @@ func (f *FileCache) Start(config any) error {
 	}
 
 	f.dir = opts.Dir
+	f.codec = cache.CodecOrJSON(opts.Codec)
 
 	if err := f.ensureDir(); err != nil {
 		return fmt.Errorf("file cache: failed to create cache directory: %w", err)
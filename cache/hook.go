@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EventType 缓存操作事件类型
+type EventType string
+
+const (
+	EventHit    EventType = "hit"    // 读取命中
+	EventMiss   EventType = "miss"   // 读取未命中
+	EventSet    EventType = "set"    // 写入成功
+	EventDelete EventType = "delete" // 删除
+	EventEvict  EventType = "evict"  // 因容量限制被淘汰或过期后被后台清理（仅内存缓存）
+	EventError  EventType = "error"  // 操作失败，Err 为失败原因
+)
+
+// Event 缓存操作事件
+type Event struct {
+	Type    EventType
+	Op      string // 触发事件的操作："get"、"set"、"delete"、"evict"、"expire"
+	Adapter string // 适配器名称，如 AdapterMemory
+	Key     string
+	Latency time.Duration // 操作耗时，淘汰事件为 0
+	Err     error
+}
+
+// Hook 接收缓存操作事件，用于上报指标、记录 tracing span 等。
+// OnEvent 在缓存操作的 goroutine 中同步调用（内存缓存还持有内部锁），不能阻塞，也不能操作同一缓存
+type Hook interface {
+	OnEvent(e Event)
+}
+
+// HookFunc 函数形式的 Hook
+type HookFunc func(e Event)
+
+func (f HookFunc) OnEvent(e Event) { f(e) }
+
+var (
+	hooksMu  sync.Mutex
+	hooks    atomic.Pointer[[]Hook]
+	hasHooks atomic.Bool
+)
+
+// RegisterHook 注册 Hook，对所有适配器的 Get / GetRaw / Set / Delete 生效；
+// 内存缓存的 CompareAndSwap / GetDel / GetSet / DeleteByPrefix / Clear、淘汰与过期清理同样会通知
+func RegisterHook(h Hook) {
+	if h == nil {
+		panic("cache: RegisterHook hook is nil")
+	}
+
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+
+	var list []Hook
+	if p := hooks.Load(); p != nil {
+		list = append(list, *p...)
+	}
+	list = append(list, h)
+	hooks.Store(&list)
+	hasHooks.Store(true)
+}
+
+// HookStart 返回操作的开始时间，供适配器传给 Observe；未注册 Hook 时返回零值，省去取时间的开销
+func HookStart() time.Time {
+	if !hasHooks.Load() {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// Observe 供适配器在操作结束后调用，按 op 与 err 生成事件并通知所有 Hook：
+// get 操作 err 为 nil 时为命中、ErrNotFound 为未命中；其他操作 err 为 nil 时按 op 生成事件；
+// 其余错误为 EventError
+func Observe(adapter, op, key string, start time.Time, err error) {
+	if !hasHooks.Load() {
+		return
+	}
+
+	e := Event{Op: op, Adapter: adapter, Key: key, Err: err}
+	if !start.IsZero() {
+		e.Latency = time.Since(start)
+	}
+	switch {
+	case op == "get" && err == nil:
+		e.Type = EventHit
+	case op == "get" && errors.Is(err, ErrNotFound):
+		e.Type, e.Err = EventMiss, nil
+	case err != nil:
+		e.Type = EventError
+	default:
+		e.Type = EventType(op)
+	}
+	emit(e)
+}
+
+// ObserveEvict 供适配器在淘汰条目时调用
+func ObserveEvict(adapter, key string) {
+	if !hasHooks.Load() {
+		return
+	}
+	emit(Event{Type: EventEvict, Op: "evict", Adapter: adapter, Key: key})
+}
+
+// ObserveExpire 供适配器在后台清理过期条目时调用，事件类型为 EventEvict、Op 为 "expire"
+func ObserveExpire(adapter, key string) {
+	if !hasHooks.Load() {
+		return
+	}
+	emit(Event{Type: EventEvict, Op: "expire", Adapter: adapter, Key: key})
+}
+
+func emit(e Event) {
+	p := hooks.Load()
+	if p == nil {
+		return
+	}
+	for _, h := range *p {
+		h.OnEvent(e)
+	}
+}
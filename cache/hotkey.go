@@ -0,0 +1,259 @@
+package cache
+
+import (
+	"context"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+)
+
+// HotKeyConfig 热点 key 统计配置
+type HotKeyConfig struct {
+	// 采样率 (0, 1]，每次访问以该概率计数
+	SampleRate float64
+
+	// 统计窗口，每个窗口结束时计数减半，使统计偏向近期访问
+	Window time.Duration
+
+	// 最多跟踪的 key 数量，达到上限后新 key 在下一个窗口前不再计入
+	MaxKeys int
+
+	// 单个窗口内估算访问次数达到该值时触发 OnHotKey，0 表示不告警
+	Threshold uint64
+
+	// 热点 key 回调，每个 key 每个窗口最多触发一次；在 Record 调用方的 goroutine 中同步执行
+	OnHotKey func(key string, count uint64)
+}
+
+// HotKeyOption 选项函数
+type HotKeyOption func(*HotKeyConfig)
+
+// WithSampleRate 设置采样率
+func WithSampleRate(rate float64) HotKeyOption {
+	return func(c *HotKeyConfig) {
+		c.SampleRate = rate
+	}
+}
+
+// WithHotKeyWindow 设置统计窗口
+func WithHotKeyWindow(d time.Duration) HotKeyOption {
+	return func(c *HotKeyConfig) {
+		c.Window = d
+	}
+}
+
+// WithMaxKeys 设置最多跟踪的 key 数量
+func WithMaxKeys(n int) HotKeyOption {
+	return func(c *HotKeyConfig) {
+		c.MaxKeys = n
+	}
+}
+
+// WithHotKeyAlert 设置告警阈值与回调
+func WithHotKeyAlert(threshold uint64, fn func(key string, count uint64)) HotKeyOption {
+	return func(c *HotKeyConfig) {
+		c.Threshold = threshold
+		c.OnHotKey = fn
+	}
+}
+
+// DefaultHotKeyConfig 默认配置：10% 采样，1 分钟窗口，最多跟踪 10000 个 key
+func DefaultHotKeyConfig() HotKeyConfig {
+	return HotKeyConfig{
+		SampleRate: 0.1,
+		Window:     time.Minute,
+		MaxKeys:    10000,
+	}
+}
+
+// KeyCount key 及其估算访问次数
+type KeyCount struct {
+	Key   string
+	Count uint64
+}
+
+// HotKeys 采样统计每个 key 的访问次数，用于找出热点 key
+type HotKeys struct {
+	config HotKeyConfig
+
+	mu        sync.Mutex
+	counts    map[string]uint64
+	alerted   map[string]struct{}
+	lastDecay time.Time
+}
+
+// NewHotKeys 创建热点 key 统计
+func NewHotKeys(opts ...HotKeyOption) *HotKeys {
+	config := DefaultHotKeyConfig()
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.SampleRate <= 0 || config.SampleRate > 1 {
+		config.SampleRate = 1
+	}
+	if config.Window <= 0 {
+		config.Window = time.Minute
+	}
+	return &HotKeys{
+		config:    config,
+		counts:    make(map[string]uint64),
+		alerted:   make(map[string]struct{}),
+		lastDecay: time.Now(),
+	}
+}
+
+// Record 记录一次访问
+func (h *HotKeys) Record(key string) {
+	if h.config.SampleRate < 1 && rand.Float64() >= h.config.SampleRate {
+		return
+	}
+
+	h.mu.Lock()
+	h.decayLocked()
+
+	c, ok := h.counts[key]
+	if !ok && h.config.MaxKeys > 0 && len(h.counts) >= h.config.MaxKeys {
+		h.mu.Unlock()
+		return
+	}
+	c++
+	h.counts[key] = c
+
+	var fire bool
+	estimate := h.estimate(c)
+	if h.config.Threshold > 0 && h.config.OnHotKey != nil && estimate >= h.config.Threshold {
+		if _, done := h.alerted[key]; !done {
+			h.alerted[key] = struct{}{}
+			fire = true
+		}
+	}
+	h.mu.Unlock()
+
+	if fire {
+		h.config.OnHotKey(key, estimate)
+	}
+}
+
+// TopKeys 返回估算访问次数最多的 n 个 key，按次数降序
+func (h *HotKeys) TopKeys(n int) []KeyCount {
+	h.mu.Lock()
+	h.decayLocked()
+	out := make([]KeyCount, 0, len(h.counts))
+	for k, c := range h.counts {
+		out = append(out, KeyCount{Key: k, Count: h.estimate(c)})
+	}
+	h.mu.Unlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Key < out[j].Key
+	})
+	if n > 0 && len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+// Reset 清空统计
+func (h *HotKeys) Reset() {
+	h.mu.Lock()
+	h.counts = make(map[string]uint64)
+	h.alerted = make(map[string]struct{})
+	h.lastDecay = time.Now()
+	h.mu.Unlock()
+}
+
+func (h *HotKeys) estimate(c uint64) uint64 {
+	return uint64(float64(c) / h.config.SampleRate)
+}
+
+// decayLocked 每经过一个窗口计数减半，并移除归零的 key
+func (h *HotKeys) decayLocked() {
+	now := time.Now()
+	for now.Sub(h.lastDecay) >= h.config.Window {
+		h.lastDecay = h.lastDecay.Add(h.config.Window)
+		for k, c := range h.counts {
+			if c /= 2; c == 0 {
+				delete(h.counts, k)
+			} else {
+				h.counts[k] = c
+			}
+		}
+		h.alerted = make(map[string]struct{})
+		if len(h.counts) == 0 {
+			h.lastDecay = now
+			break
+		}
+	}
+}
+
+// trackedCache 在读取时记录访问的 Cache 包装
+type trackedCache struct {
+	Cache
+	hot *HotKeys
+}
+
+// Tracked 包装 c，每次 Get 都会记录到 hot
+func Tracked(c Cache, hot *HotKeys) Cache {
+	return &trackedCache{Cache: c, hot: hot}
+}
+
+// Unwrap 返回被包装的 Cache
+func (t *trackedCache) Unwrap() Cache {
+	return t.Cache
+}
+
+func (t *trackedCache) Get(key string) (any, error) {
+	t.hot.Record(key)
+	return t.Cache.Get(key)
+}
+
+func (t *trackedCache) GetCtx(ctx context.Context, key string) (any, error) {
+	t.hot.Record(key)
+	return t.Cache.GetCtx(ctx, key)
+}
+
+func (t *trackedCache) GetRaw(key string) ([]byte, error) {
+	t.hot.Record(key)
+	return getRaw(t.Cache, key)
+}
+
+// globalHotKeys 全局缓存的热点 key 统计，与 global 一起由 globalMu 保护；
+// 全局实例被 Shutdown、Reconfigure 或 SetGlobal 替换时重置为 nil
+var globalHotKeys *HotKeys
+
+// EnableHotKeys 为全局缓存开启热点 key 统计；重复调用时以新的选项替换统计，不会重复包装
+func EnableHotKeys(opts ...HotKeyOption) error {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+
+	if global == nil {
+		return ErrNoGlobal
+	}
+	c := global
+	if t, ok := c.(*trackedCache); ok && globalHotKeys != nil && t.hot == globalHotKeys {
+		c = t.Cache
+	}
+	globalHotKeys = NewHotKeys(opts...)
+	global = Tracked(c, globalHotKeys)
+	return nil
+}
+
+// currentHotKeys 返回全局缓存的热点 key 统计，未开启时返回 nil
+func currentHotKeys() *HotKeys {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+	return globalHotKeys
+}
+
+// TopKeys 返回全局缓存访问最多的 n 个 key，未开启统计时返回 nil
+func TopKeys(n int) []KeyCount {
+	hot := currentHotKeys()
+	if hot == nil {
+		return nil
+	}
+	return hot.TopKeys(n)
+}
@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Keys 使用全局缓存列出匹配 pattern 的 key，pattern 与 limit 的含义见 Cache.Keys
+func Keys(pattern string, limit int) ([]string, error) {
+	c := current()
+	if c == nil {
+		return nil, ErrNoGlobal
+	}
+	return c.Keys(pattern, limit)
+}
+
+var patternEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
+// EscapePattern 转义 s 中的通配符，使其在 pattern 中按字面匹配，如拼接命名空间前缀
+func EscapePattern(s string) string {
+	return patternEscaper.Replace(s)
+}
+
+// MatchPattern 按 Redis KEYS / SCAN MATCH 的通配规则判断 key 是否匹配 pattern：
+//   - * 匹配任意长度（包括空）的字符，? 匹配单个字符
+//   - [abc] 匹配其中任一字符，[a-z] 匹配范围，[^a] 取反
+//   - \ 转义下一个字符
+//
+// pattern 为空时匹配所有 key；未闭合的 [ 按字面字符处理
+func MatchPattern(pattern, key string) bool {
+	if pattern == "" {
+		return true
+	}
+	return matchPattern(pattern, key)
+}
+
+// matchPattern 使用贪心双指针匹配：遇到 * 时记录位置，后续失配时回到最近的 * 让它多吞一个字符，
+// 只需回溯到最近的 *，耗时与 len(pattern)*len(s) 成正比
+func matchPattern(pattern, s string) bool {
+	px, sx := 0, 0
+	starPx, starSx := -1, 0
+	for sx < len(s) {
+		if px < len(pattern) && pattern[px] == '*' {
+			starPx, starSx = px, sx
+			px++
+			continue
+		}
+		if px < len(pattern) {
+			if np, ns, ok := matchOne(pattern, px, s, sx); ok {
+				px, sx = np, ns
+				continue
+			}
+		}
+		if starPx < 0 {
+			return false
+		}
+		_, n := utf8.DecodeRuneInString(s[starSx:])
+		starSx += n
+		px, sx = starPx+1, starSx
+	}
+	for px < len(pattern) && pattern[px] == '*' {
+		px++
+	}
+	return px == len(pattern)
+}
+
+// matchOne 用 pattern[px:] 开头的单个非 * 元素匹配 s[sx:] 开头的字符，返回匹配后的新位置
+func matchOne(pattern string, px int, s string, sx int) (int, int, bool) {
+	switch pattern[px] {
+	case '?':
+		_, n := utf8.DecodeRuneInString(s[sx:])
+		return px + 1, sx + n, true
+	case '[':
+		r, n := utf8.DecodeRuneInString(s[sx:])
+		matched, rest, ok := matchClass(pattern[px+1:], r)
+		if !ok {
+			// 未闭合的 [ 按字面字符处理
+			return px + 1, sx + 1, s[sx] == '['
+		}
+		return len(pattern) - len(rest), sx + n, matched
+	case '\\':
+		if px+1 < len(pattern) {
+			px++
+		}
+	}
+	return px + 1, sx + 1, s[sx] == pattern[px]
+}
+
+// matchClass 匹配 [ 之后的字符集合，返回是否匹配与 ] 之后的 pattern；未找到 ] 时 ok 为 false
+func matchClass(p string, r rune) (matched bool, rest string, ok bool) {
+	negate := false
+	if len(p) > 0 && p[0] == '^' {
+		negate = true
+		p = p[1:]
+	}
+
+	for len(p) > 0 {
+		if p[0] == ']' {
+			return matched != negate, p[1:], true
+		}
+		if p[0] == '\\' && len(p) > 1 {
+			p = p[1:]
+		}
+		lo, n := utf8.DecodeRuneInString(p)
+		p = p[n:]
+		hi := lo
+		if len(p) > 1 && p[0] == '-' && p[1] != ']' {
+			p = p[1:]
+			if p[0] == '\\' && len(p) > 1 {
+				p = p[1:]
+			}
+			hi, n = utf8.DecodeRuneInString(p)
+			p = p[n:]
+		}
+		if lo <= r && r <= hi {
+			matched = true
+		}
+	}
+	return false, "", false
+}
@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// MultiLoader 批量加载函数，参数为未命中的 key，返回能加载到的值；未返回的 key 视为不存在
+type MultiLoader func(missing []string) (map[string]any, error)
+
+var loadGroup singleflight.Group
+
+// LoadMulti 批量读取缓存，未命中的 key 合并为一次 loader 调用并回填缓存。
+// 并发的相同未命中批次只会调用一次 loader；返回值只包含命中或加载到的 key。
+func LoadMulti(c Cache, keys []string, loader MultiLoader, ttl time.Duration) (map[string]any, error) {
+	result := make(map[string]any, len(keys))
+	var missing []string
+	seen := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		if v, err := c.Get(key); err == nil {
+			result[key] = v
+			continue
+		}
+		missing = append(missing, key)
+	}
+	if len(missing) == 0 || loader == nil {
+		return result, nil
+	}
+
+	// 以排序后的未命中 key 作为去重依据，不同 Cache 实例互不干扰
+	sorted := append([]string(nil), missing...)
+	sort.Strings(sorted)
+	sfKey := fmt.Sprintf("%p\x00%s", c, strings.Join(sorted, "\x00"))
+
+	v, err, _ := loadGroup.Do(sfKey, func() (any, error) {
+		loaded, err := loader(missing)
+		if err != nil {
+			return nil, err
+		}
+		for key, val := range loaded {
+			c.Set(key, val, ttl)
+		}
+		return loaded, nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	for key, val := range v.(map[string]any) {
+		if _, ok := seen[key]; ok {
+			result[key] = val
+		}
+	}
+	return result, nil
+}
+
+// GetMultiOrLoad 使用全局缓存批量读取，未命中部分通过 loader 加载并回填
+func GetMultiOrLoad(keys []string, loader MultiLoader, ttl time.Duration) (map[string]any, error) {
+	c := current()
+	if c == nil {
+		return nil, ErrNoGlobal
+	}
+	return LoadMulti(c, keys, loader, ttl)
+}
+
+// Load 读取 c 中的 key 并转换为 T，未命中时调用 loader 加载并以 ttl 写回缓存。
+// 同一 Cache 的同一 key 并发未命中时 loader 只执行一次，其余调用共享结果，避免缓存击穿；
+// loader 返回错误时不写缓存。
+func Load[T any](c Cache, key string, loader func() (T, error), ttl time.Duration) (T, error) {
+	if v, err := getAs[T](c, key); err == nil {
+		return v, nil
+	}
+
+	v, err, _ := loadGroup.Do(fmt.Sprintf("%p\x01%s", c, key), func() (any, error) {
+		val, err := loader()
+		if err != nil {
+			return nil, err
+		}
+		c.Set(key, val, ttl)
+		return val, nil
+	})
+	var zero T
+	if err != nil {
+		return zero, err
+	}
+	// loader 可能返回 nil 接口；同一 key 的不同 T 的并发调用共享结果时类型可能不一致
+	if v == nil {
+		return zero, nil
+	}
+	t, ok := v.(T)
+	if !ok {
+		return zero, ErrTypeMismatch
+	}
+	return t, nil
+}
+
+// GetOrSet 使用全局缓存读取 key，未命中时通过 loader 加载并回填，见 Load
+func GetOrSet[T any](key string, loader func() (T, error), ttl time.Duration) (T, error) {
+	c := current()
+	if c == nil {
+		var zero T
+		return zero, ErrNoGlobal
+	}
+	return Load(c, key, loader, ttl)
+}
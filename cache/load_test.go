@@ -0,0 +1,65 @@
+package cache_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jiajia556/tool-box/cache"
+	"github.com/jiajia556/tool-box/cache/memory"
+)
+
+func newMemory(t *testing.T) cache.Cache {
+	t.Helper()
+	c := memory.NewMemoryCache()
+	if err := c.Start(memory.Options{}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func TestLoad_NilResult(t *testing.T) {
+	c := newMemory(t)
+
+	v, err := cache.Load[any](c, "k", func() (any, error) { return nil, nil }, time.Minute)
+	if err != nil || v != nil {
+		t.Fatalf("Load = %v, %v; want nil, nil", v, err)
+	}
+
+	e, err := cache.Load[error](c, "e", func() (error, error) { return nil, nil }, time.Minute)
+	if err != nil || e != nil {
+		t.Fatalf("Load = %v, %v; want nil, nil", e, err)
+	}
+}
+
+func TestLoad_TypeMismatch(t *testing.T) {
+	c := newMemory(t)
+
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, _ = cache.Load(c, "k", func() (string, error) {
+			<-release
+			return "v", nil
+		}, time.Minute)
+	}()
+	// 等待首个调用进入 loader，第二个调用与其共享同一次加载
+	time.Sleep(50 * time.Millisecond)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := cache.Load(c, "k", func() (int, error) { return 1, nil }, time.Minute)
+		done <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if err := <-done; !errors.Is(err, cache.ErrTypeMismatch) {
+		t.Fatalf("expected ErrTypeMismatch, got %v", err)
+	}
+}
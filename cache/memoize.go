@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// memoKeyLimit 默认 key 超过该长度时改用摘要
+const memoKeyLimit = 128
+
+// Memoize 为 fn 加上缓存：结果以 "name:" + keyFn(req) 为 key 写入全局缓存，ttl 过期前相同请求直接返回缓存结果，
+// 并发的相同请求只执行一次 fn。keyFn 为 nil 时按 req 的 JSON 编码生成 key（过长时取 SHA-1）。
+// fn 返回错误时不缓存；全局缓存未初始化时直接调用 fn。
+//
+//	var getUser = cache.Memoize("user", repo.GetUser, 10*time.Minute, func(id int64) string {
+//		return strconv.FormatInt(id, 10)
+//	})
+//	u, err := getUser(ctx, 42)
+func Memoize[Req, Resp any](name string, fn func(context.Context, Req) (Resp, error), ttl time.Duration, keyFn func(Req) string) func(context.Context, Req) (Resp, error) {
+	if keyFn == nil {
+		keyFn = memoKey[Req]
+	}
+	return func(ctx context.Context, req Req) (Resp, error) {
+		c := current()
+		if c == nil {
+			return fn(ctx, req)
+		}
+		// 合并执行时结果被多个调用方共享，不随首个调用方的取消而失败
+		shared := context.WithoutCancel(ctx)
+		return Load(c, name+":"+keyFn(req), func() (Resp, error) {
+			return fn(shared, req)
+		}, ttl)
+	}
+}
+
+func memoKey[Req any](req Req) string {
+	b, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Sprintf("%v", req)
+	}
+	if len(b) <= memoKeyLimit {
+		return string(b)
+	}
+	sum := sha1.Sum(b)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,31 @@
+package cache_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jiajia556/tool-box/cache"
+)
+
+func TestMemoize_NilInterfaceResult(t *testing.T) {
+	cache.SetGlobal(newMemory(t))
+	t.Cleanup(func() { cache.SetGlobal(nil) })
+
+	calls := 0
+	find := cache.Memoize("stringer", func(ctx context.Context, id int) (fmt.Stringer, error) {
+		calls++
+		return nil, nil
+	}, time.Minute, nil)
+
+	for i := 0; i < 2; i++ {
+		v, err := find(context.Background(), 1)
+		if err != nil || v != nil {
+			t.Fatalf("call %d = %v, %v; want nil, nil", i, v, err)
+		}
+	}
+	if calls == 0 {
+		t.Fatal("fn was never called")
+	}
+}
@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"context"
+	"time"
+)
+
+// 带 context 的版本：内存缓存不涉及网络 I/O，仅在执行前检查 ctx 是否已取消或到期
+
+func (m *MemoryCache) GetCtx(ctx context.Context, key string) (any, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.Get(key)
+}
+
+func (m *MemoryCache) SetCtx(ctx context.Context, key string, value any, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.Set(key, value, ttl)
+	return nil
+}
+
+func (m *MemoryCache) DeleteCtx(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.Delete(key)
+	return nil
+}
+
+func (m *MemoryCache) TTLCtx(ctx context.Context, key string) (time.Duration, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, false, err
+	}
+	d, ok := m.TTL(key)
+	return d, ok, nil
+}
+
+func (m *MemoryCache) ExistsCtx(ctx context.Context, key string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return m.Exists(key), nil
+}
+
+func (m *MemoryCache) CompareAndSwapCtx(ctx context.Context, key string, old, new any, ttl time.Duration) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return m.CompareAndSwap(key, old, new, ttl)
+}
+
+func (m *MemoryCache) GetDelCtx(ctx context.Context, key string) (any, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.GetDel(key)
+}
+
+func (m *MemoryCache) GetSetCtx(ctx context.Context, key string, value any, ttl time.Duration) (any, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.GetSet(key, value, ttl)
+}
+
+// Ping 内存缓存始终可用，仅检查 ctx
+func (m *MemoryCache) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
@@ -0,0 +1,81 @@
+package memory
+
+import "reflect"
+
+// deepCopy 深拷贝 v，保持原类型；指针、切片、map 与接口递归复制，循环引用按原结构复制。
+// 结构体的未导出字段无法通过反射设置，保持浅拷贝；通道与函数原样共享。
+func deepCopy(v any) any {
+	if v == nil {
+		return nil
+	}
+	src := reflect.ValueOf(v)
+	dst := reflect.New(src.Type()).Elem()
+	copyValue(dst, src, make(map[uintptr]reflect.Value))
+	return dst.Interface()
+}
+
+func copyValue(dst, src reflect.Value, seen map[uintptr]reflect.Value) {
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+		if p, ok := seen[src.Pointer()]; ok && p.Type() == src.Type() {
+			dst.Set(p)
+			return
+		}
+		p := reflect.New(src.Type().Elem())
+		seen[src.Pointer()] = p
+		copyValue(p.Elem(), src.Elem(), seen)
+		dst.Set(p)
+
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		elem := src.Elem()
+		c := reflect.New(elem.Type()).Elem()
+		copyValue(c, elem, seen)
+		dst.Set(c)
+
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		s := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			copyValue(s.Index(i), src.Index(i), seen)
+		}
+		dst.Set(s)
+
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			copyValue(dst.Index(i), src.Index(i), seen)
+		}
+
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		m := reflect.MakeMapWithSize(src.Type(), src.Len())
+		iter := src.MapRange()
+		for iter.Next() {
+			v := reflect.New(src.Type().Elem()).Elem()
+			copyValue(v, iter.Value(), seen)
+			m.SetMapIndex(iter.Key(), v)
+		}
+		dst.Set(m)
+
+	case reflect.Struct:
+		// 先整体赋值带上未导出字段，再深拷贝可导出字段
+		dst.Set(src)
+		for i := 0; i < src.NumField(); i++ {
+			if dst.Field(i).CanSet() {
+				copyValue(dst.Field(i), src.Field(i), seen)
+			}
+		}
+
+	default:
+		dst.Set(src)
+	}
+}
@@ -0,0 +1,331 @@
+package memory
+
+import (
+	"container/list"
+	"sort"
+)
+
+// evictionStrategy 淘汰策略，记录 key 的访问情况并在超出容量时选出淘汰的 key。
+// 由 MemoryCache 串行调用，实现无需加锁；新增策略只需在 strategies 中注册。
+type evictionStrategy interface {
+	// Add 记录新写入的 key，超出容量时返回需要淘汰的 key
+	Add(key string) (victim string, evict bool)
+	// Access 记录对已有 key 的读取或覆盖写入
+	Access(key string)
+	// Remove 移除被删除的 key
+	Remove(key string)
+	// Evict 按策略选出并移除一个不等于 exclude 的 key，用于按字节数淘汰
+	Evict(exclude string) (victim string, evict bool)
+	// Reset 清空全部记录
+	Reset()
+}
+
+// strategies 按名称注册的淘汰策略，参数为容量（>0）
+var strategies = map[string]func(capacity int) evictionStrategy{
+	"lru": newLRU,
+	"lfu": newLFU,
+	"arc": newARC,
+}
+
+// lru 淘汰最久未使用的 key
+type lru struct {
+	capacity int
+	order    *list.List // 最近使用的在前
+	elems    map[string]*list.Element
+}
+
+func newLRU(capacity int) evictionStrategy {
+	return &lru{capacity: capacity, order: list.New(), elems: make(map[string]*list.Element)}
+}
+
+func (s *lru) Add(key string) (string, bool) {
+	if el, ok := s.elems[key]; ok {
+		s.order.MoveToFront(el)
+		return "", false
+	}
+	s.elems[key] = s.order.PushFront(key)
+	if s.order.Len() <= s.capacity {
+		return "", false
+	}
+	victim := s.order.Remove(s.order.Back()).(string)
+	delete(s.elems, victim)
+	return victim, true
+}
+
+func (s *lru) Access(key string) {
+	if el, ok := s.elems[key]; ok {
+		s.order.MoveToFront(el)
+	}
+}
+
+func (s *lru) Remove(key string) {
+	if el, ok := s.elems[key]; ok {
+		s.order.Remove(el)
+		delete(s.elems, key)
+	}
+}
+
+func (s *lru) Evict(exclude string) (string, bool) {
+	for el := s.order.Back(); el != nil; el = el.Prev() {
+		if key := el.Value.(string); key != exclude {
+			s.Remove(key)
+			return key, true
+		}
+	}
+	return "", false
+}
+
+func (s *lru) Reset() {
+	s.order.Init()
+	s.elems = make(map[string]*list.Element)
+}
+
+// lfu 淘汰访问次数最少的 key，次数相同时淘汰最久未使用的
+type lfu struct {
+	capacity int
+	entries  map[string]*lfuEntry
+	buckets  map[int]*list.List // 访问次数 -> key 列表，最近使用的在前
+	minFreq  int
+}
+
+type lfuEntry struct {
+	freq int
+	el   *list.Element
+}
+
+func newLFU(capacity int) evictionStrategy {
+	return &lfu{capacity: capacity, entries: make(map[string]*lfuEntry), buckets: make(map[int]*list.List)}
+}
+
+func (s *lfu) Add(key string) (victim string, evict bool) {
+	if _, ok := s.entries[key]; ok {
+		s.Access(key)
+		return "", false
+	}
+	// 先淘汰再写入，避免新 key 因次数最少被立即淘汰
+	if len(s.entries) >= s.capacity {
+		victim, evict = s.Evict("")
+	}
+	s.entries[key] = &lfuEntry{freq: 1, el: s.bucket(1).PushFront(key)}
+	s.minFreq = 1
+	return victim, evict
+}
+
+func (s *lfu) Access(key string) {
+	e, ok := s.entries[key]
+	if !ok {
+		return
+	}
+	s.unlink(key, e)
+	if e.freq == s.minFreq && s.buckets[e.freq] == nil {
+		s.minFreq++
+	}
+	e.freq++
+	e.el = s.bucket(e.freq).PushFront(key)
+}
+
+func (s *lfu) Remove(key string) {
+	if e, ok := s.entries[key]; ok {
+		s.unlink(key, e)
+		delete(s.entries, key)
+	}
+}
+
+func (s *lfu) Reset() {
+	s.entries = make(map[string]*lfuEntry)
+	s.buckets = make(map[int]*list.List)
+	s.minFreq = 0
+}
+
+func (s *lfu) Evict(exclude string) (string, bool) {
+	// 通常在最小次数的列表中即可找到；Remove 可能已清空该列表，或其中只有 exclude
+	if key, ok := s.evictFrom(s.buckets[s.minFreq], exclude); ok {
+		return key, true
+	}
+	freqs := make([]int, 0, len(s.buckets))
+	for freq := range s.buckets {
+		freqs = append(freqs, freq)
+	}
+	sort.Ints(freqs)
+	for _, freq := range freqs {
+		if key, ok := s.evictFrom(s.buckets[freq], exclude); ok {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// evictFrom 移除列表中最久未使用且不等于 exclude 的 key
+func (s *lfu) evictFrom(l *list.List, exclude string) (string, bool) {
+	if l == nil {
+		return "", false
+	}
+	for el := l.Back(); el != nil; el = el.Prev() {
+		if key := el.Value.(string); key != exclude {
+			s.Remove(key)
+			return key, true
+		}
+	}
+	return "", false
+}
+
+func (s *lfu) bucket(freq int) *list.List {
+	l, ok := s.buckets[freq]
+	if !ok {
+		l = list.New()
+		s.buckets[freq] = l
+	}
+	return l
+}
+
+// unlink 从次数列表中移除，列表为空时删除
+func (s *lfu) unlink(key string, e *lfuEntry) {
+	l := s.buckets[e.freq]
+	l.Remove(e.el)
+	if l.Len() == 0 {
+		delete(s.buckets, e.freq)
+	}
+}
+
+// arc 自适应替换缓存（Adaptive Replacement Cache）：
+// t1 保存只访问过一次的 key，t2 保存多次访问的 key，b1 / b2 记录二者最近淘汰的 key（不含值），
+// 根据 b1 / b2 的命中自动调整 t1 的目标大小 p，在偏向新近与偏向频率的访问模式间自适应。
+type arc struct {
+	capacity       int
+	p              int
+	t1, t2, b1, b2 *list.List // 最近使用的在前
+	elems          map[string]*arcElem
+}
+
+type arcElem struct {
+	in *list.List
+	el *list.Element
+}
+
+func newARC(capacity int) evictionStrategy {
+	s := &arc{capacity: capacity}
+	s.Reset()
+	return s
+}
+
+func (s *arc) Add(key string) (victim string, evict bool) {
+	e, ok := s.elems[key]
+	switch {
+	case ok && (e.in == s.t1 || e.in == s.t2):
+		s.Access(key)
+		return "", false
+	case ok && e.in == s.b1:
+		s.p = min(s.capacity, s.p+max(s.b2.Len()/s.b1.Len(), 1))
+		victim, evict = s.replace(false)
+		s.move(key, s.t2)
+	case ok && e.in == s.b2:
+		s.p = max(0, s.p-max(s.b1.Len()/s.b2.Len(), 1))
+		victim, evict = s.replace(true)
+		s.move(key, s.t2)
+	default:
+		if l1 := s.t1.Len() + s.b1.Len(); l1 >= s.capacity {
+			if s.t1.Len() < s.capacity {
+				s.drop(s.b1)
+				victim, evict = s.replace(false)
+			} else {
+				victim, evict = s.drop(s.t1), true
+			}
+		} else if total := l1 + s.t2.Len() + s.b2.Len(); total >= s.capacity {
+			if total >= 2*s.capacity {
+				s.drop(s.b2)
+			}
+			victim, evict = s.replace(false)
+		}
+		s.move(key, s.t1)
+	}
+	s.trim(s.b1)
+	s.trim(s.b2)
+	return victim, evict
+}
+
+func (s *arc) Access(key string) {
+	if e, ok := s.elems[key]; ok && (e.in == s.t1 || e.in == s.t2) {
+		s.move(key, s.t2)
+	}
+}
+
+func (s *arc) Remove(key string) {
+	if e, ok := s.elems[key]; ok {
+		e.in.Remove(e.el)
+		delete(s.elems, key)
+	}
+}
+
+// Evict 按 p 从 t1 或 t2 淘汰，同样记入 b1 / b2；缓存因字节数缩小时淘汰记录随之收缩
+func (s *arc) Evict(exclude string) (string, bool) {
+	lists := []*list.List{s.t2, s.t1}
+	if s.t1.Len() > s.p || s.t2.Len() == 0 {
+		lists = []*list.List{s.t1, s.t2}
+	}
+	for _, l := range lists {
+		for el := l.Back(); el != nil; el = el.Prev() {
+			key := el.Value.(string)
+			if key == exclude {
+				continue
+			}
+			if l == s.t1 {
+				s.move(key, s.b1)
+			} else {
+				s.move(key, s.b2)
+			}
+			limit := max(min(s.capacity, s.t1.Len()+s.t2.Len()), 1)
+			s.trimTo(s.b1, limit)
+			s.trimTo(s.b2, limit)
+			return key, true
+		}
+	}
+	return "", false
+}
+
+func (s *arc) Reset() {
+	s.p = 0
+	s.t1, s.t2, s.b1, s.b2 = list.New(), list.New(), list.New(), list.New()
+	s.elems = make(map[string]*arcElem)
+}
+
+// replace 缓存已满时将 t1 或 t2 最久未使用的 key 移入对应的淘汰记录，返回该 key
+func (s *arc) replace(inB2 bool) (string, bool) {
+	if s.t1.Len()+s.t2.Len() < s.capacity {
+		return "", false
+	}
+	if s.t1.Len() > 0 && (s.t1.Len() > s.p || (inB2 && s.t1.Len() == s.p) || s.t2.Len() == 0) {
+		key := s.t1.Back().Value.(string)
+		s.move(key, s.b1)
+		return key, true
+	}
+	key := s.t2.Back().Value.(string)
+	s.move(key, s.b2)
+	return key, true
+}
+
+// move 将 key 移到列表 to 的最前面
+func (s *arc) move(key string, to *list.List) {
+	s.Remove(key)
+	s.elems[key] = &arcElem{in: to, el: to.PushFront(key)}
+}
+
+// drop 移除列表中最久未使用的 key 并返回
+func (s *arc) drop(l *list.List) string {
+	if l.Len() == 0 {
+		return ""
+	}
+	key := l.Back().Value.(string)
+	s.Remove(key)
+	return key
+}
+
+// trim 限制淘汰记录的长度，删除 key 后 t1 / t2 变小可能使其超出
+func (s *arc) trim(l *list.List) {
+	s.trimTo(l, s.capacity)
+}
+
+func (s *arc) trimTo(l *list.List, n int) {
+	for l.Len() > n {
+		s.drop(l)
+	}
+}
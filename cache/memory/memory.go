@@ -2,90 +2,448 @@ package memory
 
 import (
 	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/jiajia556/tool-box/cache"
+	"github.com/jiajia556/tool-box/utils"
 )
 
+// Options 内存缓存配置选项
+type Options struct {
+	// 时间源，nil 表示使用系统时间；测试中可传入 utils.FakeClock 控制过期
+	Clock utils.Clock
+
+	// 最大条目数，超出时按 Policy 淘汰；<=0 表示不限制
+	MaxEntries int
+
+	// 最大字节数（按 key 与 JSON 编码后的值的长度估算），超出时按 Policy 淘汰；<=0 表示不限制。
+	// 单个条目超过该值时不写入，不计入写入统计，并以淘汰事件通知 Hook
+	MaxBytes int64
+
+	// 淘汰策略："lru"（默认）、"lfu" 或 "arc"
+	Policy string
+
+	// 清理过期条目的间隔，<=0 表示不清理（过期条目只在读取时跳过，不会释放内存）
+	CleanupInterval time.Duration
+
+	// 直接保存写入的值而不是 JSON 编码：Get 返回原类型（结构体不会变成 map[string]any），
+	// 同时省去编解码开销。此时只在设置 MaxBytes 时才编码以估算大小
+	Native bool
+
+	// Native 模式下写入与读取时深拷贝值，避免调用方修改切片、map、指针指向的数据影响缓存；
+	// 结构体的未导出字段为浅拷贝
+	DeepCopy bool
+}
+
 type item struct {
 	Value      json.RawMessage
+	Native     any  // Native 模式下保存的值
+	IsNative   bool // 值保存在 Native 中
 	Expiration time.Time
+	size       int64 // 值的估算大小
 }
 
 type MemoryCache struct {
 	mu    sync.RWMutex
 	items map[string]*item
 	stats cache.Stats
+	clock utils.Clock
+	bytes int64 // 当前条目的估算大小
+
+	maxBytes int64
+	native   bool
+	deepCopy bool
+	opts     Options // Start 时的配置，供 Describe 输出
+
+	// 淘汰策略，未限制条目数时为 nil；读取只持有读锁，因此单独加锁
+	evictMu sync.Mutex
+	evict   evictionStrategy
+
+	// 关闭后台清理
+	stopJanitor chan struct{}
 }
 
 // NewMemoryCache 创建内存缓存实例。
 func NewMemoryCache() cache.Cache {
 	return &MemoryCache{
 		items: make(map[string]*item),
+		clock: utils.RealClock,
 	}
 }
 
 func (m *MemoryCache) Get(key string) (any, error) {
+	start := cache.HookStart()
+	v, err := m.get(key)
+	cache.Observe(cache.AdapterMemory, "get", key, start, err)
+	return v, err
+}
+
+func (m *MemoryCache) get(key string) (any, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	item, err := m.lookup(key)
+	if err != nil {
+		return nil, err
+	}
+	return m.decode(item)
+}
+
+// GetRaw 返回 JSON 编码的值，由调用方解码到具体类型
+func (m *MemoryCache) GetRaw(key string) ([]byte, error) {
+	start := cache.HookStart()
+	b, err := m.getRaw(key)
+	cache.Observe(cache.AdapterMemory, "get", key, start, err)
+	return b, err
+}
+
+func (m *MemoryCache) getRaw(key string) ([]byte, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	item, err := m.lookup(key)
+	if err != nil {
+		return nil, err
+	}
+	if item.IsNative {
+		b, err := json.Marshal(item.Native)
+		if err != nil {
+			return nil, cache.ErrDecode
+		}
+		return b, nil
+	}
+	return append([]byte(nil), item.Value...), nil
+}
+
+// StoresNative 实现 cache.NativeCache，Native 模式下 Get[T] 直接断言类型
+func (m *MemoryCache) StoresNative() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.native
+}
+
+// lookup 返回未过期的条目并计入命中统计，调用方需持有读锁或写锁
+func (m *MemoryCache) lookup(key string) (*item, error) {
 	item, ok := m.items[key]
 	if !ok {
-		m.stats.Misses++
+		m.stats.Miss()
 		return nil, cache.ErrNotFound
 	}
 
-	if !item.Expiration.IsZero() && time.Now().After(item.Expiration) {
-		m.stats.Misses++
+	if !item.Expiration.IsZero() && m.clock.Now().After(item.Expiration) {
+		m.stats.Miss()
 		return nil, cache.ErrNotFound
 	}
 
+	m.stats.Hit()
+	m.touch(key)
+	return item, nil
+}
+
+// newItem 按当前模式创建条目，调用方需持有写锁
+func (m *MemoryCache) newItem(value any, ttl time.Duration) (*item, error) {
+	it := &item{}
+	if ttl > 0 {
+		it.Expiration = m.clock.Now().Add(ttl)
+	}
+
+	if !m.native {
+		b, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		it.Value, it.size = b, int64(len(b))
+		return it, nil
+	}
+
+	if m.deepCopy {
+		value = deepCopy(value)
+	}
+	it.Native, it.IsNative = value, true
+	if m.maxBytes > 0 {
+		b, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		it.size = int64(len(b))
+	}
+	return it, nil
+}
+
+// decode 返回条目的值：Native 条目按需深拷贝，JSON 条目解码
+func (m *MemoryCache) decode(it *item) (any, error) {
+	if it.IsNative {
+		if m.deepCopy {
+			return deepCopy(it.Native), nil
+		}
+		return it.Native, nil
+	}
+
 	var v any
-	if err := json.Unmarshal(item.Value, &v); err != nil {
-		m.stats.Misses++
+	if err := json.Unmarshal(it.Value, &v); err != nil {
 		return nil, cache.ErrDecode
 	}
-
-	m.stats.Hits++
 	return v, nil
 }
 
 func (m *MemoryCache) Set(key string, value any, ttl time.Duration) {
+	start := cache.HookStart()
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	b, err := json.Marshal(value)
+	it, err := m.newItem(value, ttl)
 	if err != nil {
+		cache.Observe(cache.AdapterMemory, "set", key, start, err)
+		return
+	}
+	if !m.store(key, it) {
 		return
 	}
 
-	var expiration time.Time
-	if ttl > 0 {
-		expiration = time.Now().Add(ttl)
+	m.stats.Set()
+	cache.Observe(cache.AdapterMemory, "set", key, start, nil)
+}
+
+func (m *MemoryCache) CompareAndSwap(key string, old, new any, ttl time.Duration) (bool, error) {
+	start := cache.HookStart()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	it, err := m.newItem(new, ttl)
+	if err != nil {
+		cache.Observe(cache.AdapterMemory, "set", key, start, err)
+		return false, err
+	}
+
+	var current json.RawMessage
+	if item, ok := m.items[key]; ok && (item.Expiration.IsZero() || m.clock.Now().Before(item.Expiration)) {
+		if item.IsNative {
+			if current, err = json.Marshal(item.Native); err != nil {
+				cache.Observe(cache.AdapterMemory, "set", key, start, cache.ErrDecode)
+				return false, cache.ErrDecode
+			}
+		} else {
+			current = item.Value
+		}
+	}
+	if !cache.EqualEncoded(current, old) {
+		return false, nil
+	}
+
+	// 超出字节数预算未写入时视为未交换
+	if !m.store(key, it) {
+		return false, nil
+	}
+	m.stats.Set()
+	cache.Observe(cache.AdapterMemory, "set", key, start, nil)
+	return true, nil
+}
+
+// GetDel 读取时按 get 通知命中或未命中，删除后再通知 delete
+func (m *MemoryCache) GetDel(key string) (any, error) {
+	start := cache.HookStart()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	old, err := m.current(key)
+	cache.Observe(cache.AdapterMemory, "get", key, start, err)
+	if err != nil {
+		return nil, err
+	}
+	m.remove(key)
+	m.stats.Delete()
+	cache.Observe(cache.AdapterMemory, "delete", key, start, nil)
+	return old, nil
+}
+
+// GetSet 读取旧值时按 get 通知命中或未命中，写入后再通知 set
+func (m *MemoryCache) GetSet(key string, value any, ttl time.Duration) (any, error) {
+	start := cache.HookStart()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	it, err := m.newItem(value, ttl)
+	if err != nil {
+		cache.Observe(cache.AdapterMemory, "set", key, start, err)
+		return nil, err
+	}
+
+	old, err := m.current(key)
+	cache.Observe(cache.AdapterMemory, "get", key, start, err)
+	if m.store(key, it) {
+		m.stats.Set()
+		cache.Observe(cache.AdapterMemory, "set", key, start, nil)
+	}
+	return old, err
+}
+
+// current 返回未过期的当前值并计入命中统计，调用方需持有写锁
+func (m *MemoryCache) current(key string) (any, error) {
+	item, err := m.lookup(key)
+	if err != nil {
+		return nil, err
+	}
+	return m.decode(item)
+}
+
+// store 写入条目，超出条目数或字节数时淘汰，返回是否写入；调用方需持有写锁
+func (m *MemoryCache) store(key string, it *item) bool {
+	size := itemSize(key, it)
+	if m.maxBytes > 0 && size > m.maxBytes {
+		// 单个条目超出预算时不写入，同时移除旧值以免读到过期数据；按淘汰通知 Hook
+		if _, ok := m.items[key]; ok {
+			m.remove(key)
+		}
+		cache.ObserveEvict(cache.AdapterMemory, key)
+		return false
+	}
+
+	old, exists := m.items[key]
+	if exists {
+		m.bytes -= itemSize(key, old)
+	}
+	m.items[key] = it
+	m.bytes += size
+	if m.evict == nil {
+		return true
+	}
+
+	m.evictMu.Lock()
+	defer m.evictMu.Unlock()
+	if exists {
+		m.evict.Access(key)
+	} else if victim, ok := m.evict.Add(key); ok {
+		m.deleteItem(victim)
+		cache.ObserveEvict(cache.AdapterMemory, victim)
+	}
+	m.evictBytesLocked(key)
+	return true
+}
+
+// evictBytesLocked 淘汰直到不超过字节数预算，不淘汰 keep；调用方需持有写锁与 evictMu
+func (m *MemoryCache) evictBytesLocked(keep string) {
+	for m.maxBytes > 0 && m.bytes > m.maxBytes {
+		victim, ok := m.evict.Evict(keep)
+		if !ok {
+			return
+		}
+		m.deleteItem(victim)
+		cache.ObserveEvict(cache.AdapterMemory, victim)
+	}
+}
+
+// deleteItem 删除条目并更新大小，不通知淘汰策略，调用方需持有写锁
+func (m *MemoryCache) deleteItem(key string) {
+	if it, ok := m.items[key]; ok {
+		m.bytes -= itemSize(key, it)
+		delete(m.items, key)
 	}
+}
+
+func itemSize(key string, it *item) int64 {
+	return int64(len(key)) + it.size
+}
+
+// Size 返回当前条目的估算字节数（key 与 JSON 编码后的值的长度之和）
+func (m *MemoryCache) Size() int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.bytes
+}
 
-	m.items[key] = &item{
-		Value:      b,
-		Expiration: expiration,
+// touch 记录命中，调用方需持有读锁或写锁
+func (m *MemoryCache) touch(key string) {
+	if m.evict == nil {
+		return
 	}
+	m.evictMu.Lock()
+	m.evict.Access(key)
+	m.evictMu.Unlock()
+}
 
-	m.stats.Sets++
+// remove 删除条目，调用方需持有写锁
+func (m *MemoryCache) remove(key string) {
+	m.deleteItem(key)
+	if m.evict != nil {
+		m.evictMu.Lock()
+		m.evict.Remove(key)
+		m.evictMu.Unlock()
+	}
+}
+
+// resetItems 清空条目，调用方需持有写锁
+func (m *MemoryCache) resetItems() {
+	m.items = make(map[string]*item)
+	m.bytes = 0
+	if m.evict != nil {
+		m.evictMu.Lock()
+		m.evict.Reset()
+		m.evictMu.Unlock()
+	}
 }
 
 func (m *MemoryCache) Delete(key string) {
+	start := cache.HookStart()
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	delete(m.items, key)
-	m.stats.Deletes++
+	m.remove(key)
+	m.stats.Delete()
+	cache.Observe(cache.AdapterMemory, "delete", key, start, nil)
 }
 
+// Clear 清空所有条目，每个被删除的 key 都会通知 delete
 func (m *MemoryCache) Clear() {
+	start := cache.HookStart()
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.items = make(map[string]*item)
+	items := m.items
+	m.resetItems()
+	for key := range items {
+		cache.Observe(cache.AdapterMemory, "delete", key, start, nil)
+	}
+}
+
+func (m *MemoryCache) DeleteByPrefix(prefix string) int {
+	start := cache.HookStart()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	n := 0
+	for key := range m.items {
+		if strings.HasPrefix(key, prefix) {
+			m.remove(key)
+			cache.Observe(cache.AdapterMemory, "delete", key, start, nil)
+			n++
+		}
+	}
+	m.stats.AddDeletes(uint64(n))
+	return n
+}
+
+func (m *MemoryCache) Keys(pattern string, limit int) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := m.clock.Now()
+	var keys []string
+	for key, item := range m.items {
+		if limit > 0 && len(keys) >= limit {
+			break
+		}
+		if !item.Expiration.IsZero() && now.After(item.Expiration) {
+			continue
+		}
+		if cache.MatchPattern(pattern, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
 }
 
 func (m *MemoryCache) Exists(key string) bool {
@@ -97,7 +455,7 @@ func (m *MemoryCache) Exists(key string) bool {
 		return false
 	}
 
-	if !item.Expiration.IsZero() && time.Now().After(item.Expiration) {
+	if !item.Expiration.IsZero() && m.clock.Now().After(item.Expiration) {
 		return false
 	}
 
@@ -117,7 +475,7 @@ func (m *MemoryCache) TTL(key string) (time.Duration, bool) {
 		return 0, false
 	}
 
-	ttl := time.Until(item.Expiration)
+	ttl := item.Expiration.Sub(m.clock.Now())
 	if ttl <= 0 {
 		return 0, false
 	}
@@ -126,25 +484,121 @@ func (m *MemoryCache) TTL(key string) (time.Duration, bool) {
 }
 
 func (m *MemoryCache) Stats() cache.Stats {
+	return m.stats.Snapshot()
+}
+
+func (m *MemoryCache) ResetStats() {
+	m.stats.Reset()
+}
+
+// Describe 实现 cache.Describer，条目数包含尚未清理的过期条目
+func (m *MemoryCache) Describe() cache.Description {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-
-	return m.stats
+	return cache.Description{Adapter: cache.AdapterMemory, Config: cache.MaskSecrets(m.opts), Items: len(m.items)}
 }
 
 func (m *MemoryCache) Close() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.items = make(map[string]*item)
+	m.stopJanitorLocked()
+	m.resetItems()
 	return nil
 }
 
 func (m *MemoryCache) Start(config any) error {
-	// 内存缓存不需要额外配置。
+	// 内存缓存只有可选的时间源与容量配置。
+	opts, ok := config.(Options)
+	if !ok {
+		return nil
+	}
+
+	var evict evictionStrategy
+	if opts.MaxEntries > 0 || opts.MaxBytes > 0 {
+		policy := opts.Policy
+		if policy == "" {
+			policy = "lru"
+		}
+		newStrategy, ok := strategies[policy]
+		if !ok {
+			return fmt.Errorf("cache: unknown eviction policy %q", opts.Policy)
+		}
+		// 只限制字节数时条目数不限，取一半避免策略内部计算溢出
+		capacity := opts.MaxEntries
+		if capacity <= 0 {
+			capacity = math.MaxInt / 2
+		}
+		evict = newStrategy(capacity)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.clock = utils.ClockOrReal(opts.Clock)
+	m.evict = evict
+	m.maxBytes = opts.MaxBytes
+	m.native = opts.Native
+	m.deepCopy = opts.DeepCopy
+	m.opts = opts
+	if evict != nil {
+		m.evictMu.Lock()
+		for key := range m.items {
+			if victim, ok := evict.Add(key); ok {
+				m.deleteItem(victim)
+				cache.ObserveEvict(cache.AdapterMemory, victim)
+			}
+		}
+		m.evictBytesLocked("")
+		m.evictMu.Unlock()
+	}
+
+	m.stopJanitorLocked()
+	if opts.CleanupInterval > 0 {
+		m.stopJanitor = make(chan struct{})
+		go m.janitor(m.clock.NewTicker(opts.CleanupInterval), m.stopJanitor)
+	}
 	return nil
 }
 
+// janitor 定期删除过期条目，直到 stop 关闭
+func (m *MemoryCache) janitor(ticker utils.Ticker, stop chan struct{}) {
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C():
+			m.deleteExpired()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// deleteExpired 删除所有过期条目并通知 expire，返回删除的数量
+func (m *MemoryCache) deleteExpired() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.clock.Now()
+	n := 0
+	for key, item := range m.items {
+		if !item.Expiration.IsZero() && now.After(item.Expiration) {
+			m.remove(key)
+			cache.ObserveExpire(cache.AdapterMemory, key)
+			n++
+		}
+	}
+	return n
+}
+
+// stopJanitorLocked 停止后台清理，调用方需持有写锁
+func (m *MemoryCache) stopJanitorLocked() {
+	if m.stopJanitor != nil {
+		close(m.stopJanitor)
+		m.stopJanitor = nil
+	}
+}
+
 func init() {
 	cache.Register("memory", NewMemoryCache)
 }
@@ -0,0 +1,275 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// MigrationConfig 缓存迁移配置
+type MigrationConfig struct {
+	// 旧 key 到新缓存中 key 的映射，nil 表示不变；用于迁移时修改 key 前缀
+	KeyMap func(key string) string
+
+	// 从旧缓存读到的值是否回填到新缓存（沿用剩余 TTL）
+	Backfill bool
+}
+
+// MigrationOption 选项函数
+type MigrationOption func(*MigrationConfig)
+
+// WithKeyMap 设置新缓存的 key 映射
+func WithKeyMap(fn func(key string) string) MigrationOption {
+	return func(c *MigrationConfig) {
+		c.KeyMap = fn
+	}
+}
+
+// WithBackfill 设置是否回填
+func WithBackfill(backfill bool) MigrationOption {
+	return func(c *MigrationConfig) {
+		c.Backfill = backfill
+	}
+}
+
+// Migration 不停机迁移缓存的双写包装：
+// 写入同时写新旧两个缓存，读取先读新缓存、未命中再读旧缓存（可选回填），
+// 待新缓存预热完成后调用 Cutover 切换为只读写新缓存，之后即可下线旧缓存。
+//
+//	m := cache.NewMigration(fileCache, redisCache, cache.WithBackfill(true))
+//	// ... 运行一段时间后
+//	m.Cutover()
+type Migration struct {
+	old, new Cache
+	config   MigrationConfig
+	cutover  atomic.Bool
+}
+
+// NewMigration 创建从 old 迁移到 new 的缓存
+func NewMigration(old, new Cache, opts ...MigrationOption) *Migration {
+	var config MigrationConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return &Migration{old: old, new: new, config: config}
+}
+
+// Cutover 切换为只读写新缓存，不可撤销：切换后旧缓存不再更新
+func (m *Migration) Cutover() {
+	m.cutover.Store(true)
+}
+
+// CutoverDone 返回是否已切换
+func (m *Migration) CutoverDone() bool {
+	return m.cutover.Load()
+}
+
+// Unwrap 返回新缓存
+func (m *Migration) Unwrap() Cache {
+	return m.new
+}
+
+func (m *Migration) key(key string) string {
+	if m.config.KeyMap == nil {
+		return key
+	}
+	return m.config.KeyMap(key)
+}
+
+// dual 是否仍处于双写阶段
+func (m *Migration) dual() bool {
+	return !m.cutover.Load()
+}
+
+func (m *Migration) Get(key string) (any, error) {
+	return m.GetCtx(context.Background(), key)
+}
+
+func (m *Migration) GetCtx(ctx context.Context, key string) (any, error) {
+	v, err := m.new.GetCtx(ctx, m.key(key))
+	if !m.dual() || !errors.Is(err, ErrNotFound) {
+		return v, err
+	}
+
+	v, err = m.old.GetCtx(ctx, key)
+	if err == nil && m.config.Backfill {
+		m.backfill(ctx, key, v)
+	}
+	return v, err
+}
+
+func (m *Migration) GetRaw(key string) ([]byte, error) {
+	b, err := getRaw(m.new, m.key(key))
+	if !m.dual() || !errors.Is(err, ErrNotFound) {
+		return b, err
+	}
+
+	b, err = getRaw(m.old, key)
+	if err == nil && m.config.Backfill {
+		m.backfill(context.Background(), key, json.RawMessage(b))
+	}
+	return b, err
+}
+
+// backfill 将旧缓存的值按剩余 TTL 写入新缓存
+func (m *Migration) backfill(ctx context.Context, key string, v any) {
+	// 没有过期时间时 TTL 返回 0，写入后同样不过期
+	ttl, _, _ := m.old.TTLCtx(ctx, key)
+	_ = m.new.SetCtx(ctx, m.key(key), v, ttl)
+}
+
+func (m *Migration) Set(key string, value any, ttl time.Duration) {
+	_ = m.SetCtx(context.Background(), key, value, ttl)
+}
+
+func (m *Migration) SetCtx(ctx context.Context, key string, value any, ttl time.Duration) error {
+	err := m.new.SetCtx(ctx, m.key(key), value, ttl)
+	if m.dual() {
+		err = errors.Join(err, m.old.SetCtx(ctx, key, value, ttl))
+	}
+	return err
+}
+
+func (m *Migration) Delete(key string) {
+	_ = m.DeleteCtx(context.Background(), key)
+}
+
+func (m *Migration) DeleteCtx(ctx context.Context, key string) error {
+	err := m.new.DeleteCtx(ctx, m.key(key))
+	if m.dual() {
+		err = errors.Join(err, m.old.DeleteCtx(ctx, key))
+	}
+	return err
+}
+
+// DeleteByPrefix 新缓存中按 KeyMap 映射后的前缀删除，适用于只替换 key 前缀的映射；返回新缓存删除的数量
+func (m *Migration) DeleteByPrefix(prefix string) int {
+	n := m.new.DeleteByPrefix(m.key(prefix))
+	if m.dual() {
+		m.old.DeleteByPrefix(prefix)
+	}
+	return n
+}
+
+// Keys 列出新缓存中匹配的 key（即 KeyMap 映射后的 key），pattern 按 KeyMap 映射，适用于只替换 key 前缀的映射
+func (m *Migration) Keys(pattern string, limit int) ([]string, error) {
+	return m.new.Keys(m.key(pattern), limit)
+}
+
+func (m *Migration) Clear() {
+	m.new.Clear()
+	if m.dual() {
+		m.old.Clear()
+	}
+}
+
+func (m *Migration) TTL(key string) (time.Duration, bool) {
+	ttl, ok, _ := m.TTLCtx(context.Background(), key)
+	return ttl, ok
+}
+
+func (m *Migration) TTLCtx(ctx context.Context, key string) (time.Duration, bool, error) {
+	nk := m.key(key)
+	ttl, ok, err := m.new.TTLCtx(ctx, nk)
+	if err != nil || ok || !m.dual() {
+		return ttl, ok, err
+	}
+	if exists, err := m.new.ExistsCtx(ctx, nk); err != nil || exists {
+		return 0, false, err
+	}
+	return m.old.TTLCtx(ctx, key)
+}
+
+func (m *Migration) Exists(key string) bool {
+	ok, _ := m.ExistsCtx(context.Background(), key)
+	return ok
+}
+
+func (m *Migration) ExistsCtx(ctx context.Context, key string) (bool, error) {
+	ok, err := m.new.ExistsCtx(ctx, m.key(key))
+	if err != nil || ok || !m.dual() {
+		return ok, err
+	}
+	return m.old.ExistsCtx(ctx, key)
+}
+
+// Stats 返回新缓存的统计
+func (m *Migration) Stats() Stats {
+	return m.new.Stats()
+}
+
+// CompareAndSwap 双写阶段新缓存缺少该 key 时先从旧缓存复制，再在新缓存上比较，成功后同步写入旧缓存
+func (m *Migration) CompareAndSwap(key string, old, new any, ttl time.Duration) (bool, error) {
+	return m.CompareAndSwapCtx(context.Background(), key, old, new, ttl)
+}
+
+func (m *Migration) CompareAndSwapCtx(ctx context.Context, key string, old, new any, ttl time.Duration) (bool, error) {
+	nk := m.key(key)
+	if m.dual() {
+		if exists, err := m.new.ExistsCtx(ctx, nk); err == nil && !exists {
+			if v, err := getRaw(m.old, key); err == nil {
+				m.backfill(ctx, key, json.RawMessage(v))
+			}
+		}
+	}
+
+	ok, err := m.new.CompareAndSwapCtx(ctx, nk, old, new, ttl)
+	if ok && m.dual() {
+		_ = m.old.SetCtx(ctx, key, new, ttl)
+	}
+	return ok, err
+}
+
+func (m *Migration) GetDel(key string) (any, error) {
+	return m.GetDelCtx(context.Background(), key)
+}
+
+func (m *Migration) GetDelCtx(ctx context.Context, key string) (any, error) {
+	v, err := m.new.GetDelCtx(ctx, m.key(key))
+	if !m.dual() {
+		return v, err
+	}
+
+	ov, oerr := m.old.GetDelCtx(ctx, key)
+	if errors.Is(err, ErrNotFound) {
+		return ov, oerr
+	}
+	return v, err
+}
+
+func (m *Migration) GetSet(key string, value any, ttl time.Duration) (any, error) {
+	return m.GetSetCtx(context.Background(), key, value, ttl)
+}
+
+func (m *Migration) GetSetCtx(ctx context.Context, key string, value any, ttl time.Duration) (any, error) {
+	v, err := m.new.GetSetCtx(ctx, m.key(key), value, ttl)
+	if !m.dual() {
+		return v, err
+	}
+
+	ov, oerr := m.old.GetSetCtx(ctx, key, value, ttl)
+	if errors.Is(err, ErrNotFound) {
+		return ov, oerr
+	}
+	return v, err
+}
+
+// Ping 检查新旧缓存均可用；切换后不再读写旧缓存，只检查新缓存
+func (m *Migration) Ping(ctx context.Context) error {
+	if !m.dual() {
+		return m.new.Ping(ctx)
+	}
+	return errors.Join(m.old.Ping(ctx), m.new.Ping(ctx))
+}
+
+// Close 不关闭新旧缓存，二者由创建方负责关闭
+func (m *Migration) Close() error {
+	return nil
+}
+
+// Start 迁移基于已启动的缓存创建，无需启动
+func (m *Migration) Start(config any) error {
+	return nil
+}
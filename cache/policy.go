@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ExpirePolicy 单个条目的软/硬过期时间
+type ExpirePolicy struct {
+	// 软过期：超过后仍可读取，但应触发刷新
+	SoftTTL time.Duration
+
+	// 硬过期：超过后条目不可用；小于 SoftTTL 时按 SoftTTL 处理
+	HardTTL time.Duration
+}
+
+// policyEntry 缓存中保存的值与软过期时间（毫秒时间戳）
+type policyEntry struct {
+	Value  any   `json:"v"`
+	Expire int64 `json:"e"`
+}
+
+// SetEntry 按过期策略写入 c，需配合 GetEntry 读取
+func SetEntry(c Cache, key string, value any, p ExpirePolicy) {
+	hard := p.HardTTL
+	if hard < p.SoftTTL {
+		hard = p.SoftTTL
+	}
+	e := policyEntry{Value: value, Expire: time.Now().Add(p.SoftTTL).UnixMilli()}
+	c.Set(key, e, hard)
+}
+
+// GetEntry 读取 SetEntry 写入的条目，stale 表示已超过软过期时间
+func GetEntry(c Cache, key string) (value any, stale bool, err error) {
+	v, err := c.Get(key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	e, ok := v.(policyEntry)
+	if !ok {
+		// 各适配器读回的是 JSON 解码后的通用结构，需要重新解码
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, false, ErrDecode
+		}
+		if err := json.Unmarshal(b, &e); err != nil || e.Expire == 0 {
+			return nil, false, ErrDecode
+		}
+	}
+	return e.Value, time.Now().UnixMilli() >= e.Expire, nil
+}
+
+// SetWithPolicy 按过期策略写入全局缓存
+func SetWithPolicy(key string, value any, p ExpirePolicy) {
+	c := current()
+	if c == nil {
+		return
+	}
+	SetEntry(c, key, value, p)
+}
+
+// GetWithPolicy 从全局缓存读取 SetWithPolicy 写入的条目
+func GetWithPolicy(key string) (value any, stale bool, err error) {
+	c := current()
+	if c == nil {
+		return nil, false, ErrNoGlobal
+	}
+	return GetEntry(c, key)
+}
@@ -0,0 +1,459 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Overflow 命名空间超出配额时的处理方式
+type Overflow int
+
+const (
+	// OverflowReject 拒绝写入
+	OverflowReject Overflow = iota
+	// OverflowEvict 淘汰本命名空间内最久未使用的条目
+	OverflowEvict
+)
+
+// QuotaConfig 命名空间配额，<=0 表示不限制
+type QuotaConfig struct {
+	MaxEntries int
+	MaxBytes   int64 // 按 JSON 编码后的值大小计算
+	Overflow   Overflow
+}
+
+// QuotaOption 选项函数
+type QuotaOption func(*QuotaConfig)
+
+// WithQuotaEntries 设置条目数配额
+func WithQuotaEntries(n int) QuotaOption {
+	return func(c *QuotaConfig) {
+		c.MaxEntries = n
+	}
+}
+
+// WithQuotaBytes 设置字节数配额
+func WithQuotaBytes(n int64) QuotaOption {
+	return func(c *QuotaConfig) {
+		c.MaxBytes = n
+	}
+}
+
+// WithOverflow 设置超出配额时的处理方式
+func WithOverflow(o Overflow) QuotaOption {
+	return func(c *QuotaConfig) {
+		c.Overflow = o
+	}
+}
+
+// QuotaUsage 命名空间的用量
+type QuotaUsage struct {
+	Entries  int
+	Bytes    int64
+	Rejected uint64 // 因配额被拒绝的写入次数
+	Evicted  uint64 // 因配额被淘汰的条目数
+}
+
+type nsEntry struct {
+	key    string
+	size   int64
+	expire time.Time // 零值表示不过期
+	gen    uint64    // 每次写入递增，回滚时判断条目是否已被后续写入覆盖
+}
+
+// reservation 写入前占用的配额，写入失败时由 release 回滚
+type reservation struct {
+	key     string
+	gen     uint64
+	prev    *nsEntry // 写入前的条目，nil 表示原先不存在
+	victims []string // 为腾出配额需要从底层缓存删除的 key（不含前缀）
+}
+
+// Namespace 带配额的命名空间：key 自动加上 "name:" 前缀写入底层缓存，
+// 按配额限制本命名空间的条目数与字节数，使共享的内存 / Redis 实例中一个业务的 key 不会挤占其他业务。
+// 用量在当前进程内统计，多个进程共享 Redis 时每个进程分别限制各自写入的条目。
+type Namespace struct {
+	c      Cache
+	prefix string
+	config QuotaConfig
+
+	// mu 只保护用量统计，不在持有时访问底层缓存
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // 最久未使用的在前
+	bytes   int64
+	gen     uint64
+
+	rejected atomic.Uint64
+	evicted  atomic.Uint64
+}
+
+// NewNamespace 在 c 上创建命名空间 name
+func NewNamespace(c Cache, name string, opts ...QuotaOption) *Namespace {
+	var config QuotaConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return &Namespace{
+		c:       c,
+		prefix:  name + ":",
+		config:  config,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Unwrap 返回底层 Cache
+func (n *Namespace) Unwrap() Cache {
+	return n.c
+}
+
+// Usage 返回当前用量
+func (n *Namespace) Usage() QuotaUsage {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.pruneExpired(time.Now())
+	return QuotaUsage{
+		Entries:  len(n.entries),
+		Bytes:    n.bytes,
+		Rejected: n.rejected.Load(),
+		Evicted:  n.evicted.Load(),
+	}
+}
+
+func (n *Namespace) Get(key string) (any, error) {
+	return n.GetCtx(context.Background(), key)
+}
+
+func (n *Namespace) GetCtx(ctx context.Context, key string) (any, error) {
+	v, err := n.c.GetCtx(ctx, n.prefix+key)
+	n.touch(key, err)
+	return v, err
+}
+
+func (n *Namespace) GetRaw(key string) ([]byte, error) {
+	b, err := getRaw(n.c, n.prefix+key)
+	n.touch(key, err)
+	return b, err
+}
+
+// touch 读取后更新条目的使用顺序，已不存在的条目不再计入用量
+func (n *Namespace) touch(key string, err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if el, ok := n.entries[key]; ok {
+		if errors.Is(err, ErrNotFound) {
+			n.removeElement(el)
+		} else if err == nil {
+			n.order.MoveToBack(el)
+		}
+	}
+}
+
+// Set 写入，超出配额且为 OverflowReject 时丢弃写入；需要感知拒绝时使用 TrySet
+func (n *Namespace) Set(key string, value any, ttl time.Duration) {
+	_ = n.TrySet(key, value, ttl)
+}
+
+// TrySet 写入，超出配额且为 OverflowReject 时返回 ErrQuotaExceeded
+func (n *Namespace) TrySet(key string, value any, ttl time.Duration) error {
+	return n.SetCtx(context.Background(), key, value, ttl)
+}
+
+// SetCtx 同 TrySet
+func (n *Namespace) SetCtx(ctx context.Context, key string, value any, ttl time.Duration) error {
+	r, err := n.reserve(ctx, key, encodedSize(value), ttl)
+	if err != nil {
+		return err
+	}
+	if err := n.c.SetCtx(ctx, n.prefix+key, value, ttl); err != nil {
+		n.release(r)
+		return err
+	}
+	return nil
+}
+
+func (n *Namespace) Delete(key string) {
+	_ = n.DeleteCtx(context.Background(), key)
+}
+
+func (n *Namespace) DeleteCtx(ctx context.Context, key string) error {
+	err := n.c.DeleteCtx(ctx, n.prefix+key)
+
+	n.mu.Lock()
+	if el, ok := n.entries[key]; ok {
+		n.removeElement(el)
+	}
+	n.mu.Unlock()
+	return err
+}
+
+// Clear 只清空本命名空间的条目
+func (n *Namespace) Clear() {
+	n.c.DeleteByPrefix(n.prefix)
+
+	n.mu.Lock()
+	n.entries = make(map[string]*list.Element)
+	n.order.Init()
+	n.bytes = 0
+	n.mu.Unlock()
+}
+
+// DeleteByPrefix 删除本命名空间内以 prefix 开头的 key
+func (n *Namespace) DeleteByPrefix(prefix string) int {
+	deleted := n.c.DeleteByPrefix(n.prefix + prefix)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for key, el := range n.entries {
+		if strings.HasPrefix(key, prefix) {
+			n.removeElement(el)
+		}
+	}
+	return deleted
+}
+
+// Keys 列出本命名空间内匹配的 key，返回的 key 不含命名空间前缀
+func (n *Namespace) Keys(pattern string, limit int) ([]string, error) {
+	if pattern == "" {
+		pattern = "*"
+	}
+	keys, err := n.c.Keys(EscapePattern(n.prefix)+pattern, limit)
+	if err != nil {
+		return nil, err
+	}
+	for i, key := range keys {
+		keys[i] = strings.TrimPrefix(key, n.prefix)
+	}
+	return keys, nil
+}
+
+func (n *Namespace) TTL(key string) (time.Duration, bool) {
+	return n.c.TTL(n.prefix + key)
+}
+
+func (n *Namespace) TTLCtx(ctx context.Context, key string) (time.Duration, bool, error) {
+	return n.c.TTLCtx(ctx, n.prefix+key)
+}
+
+func (n *Namespace) Exists(key string) bool {
+	return n.c.Exists(n.prefix + key)
+}
+
+func (n *Namespace) ExistsCtx(ctx context.Context, key string) (bool, error) {
+	return n.c.ExistsCtx(ctx, n.prefix+key)
+}
+
+// Stats 返回底层缓存的统计
+func (n *Namespace) Stats() Stats {
+	return n.c.Stats()
+}
+
+func (n *Namespace) CompareAndSwap(key string, old, new any, ttl time.Duration) (bool, error) {
+	return n.CompareAndSwapCtx(context.Background(), key, old, new, ttl)
+}
+
+func (n *Namespace) CompareAndSwapCtx(ctx context.Context, key string, old, new any, ttl time.Duration) (bool, error) {
+	r, err := n.reserve(ctx, key, encodedSize(new), ttl)
+	if err != nil {
+		return false, err
+	}
+	ok, err := n.c.CompareAndSwapCtx(ctx, n.prefix+key, old, new, ttl)
+	if !ok {
+		n.release(r)
+	}
+	return ok, err
+}
+
+func (n *Namespace) GetDel(key string) (any, error) {
+	return n.GetDelCtx(context.Background(), key)
+}
+
+func (n *Namespace) GetDelCtx(ctx context.Context, key string) (any, error) {
+	v, err := n.c.GetDelCtx(ctx, n.prefix+key)
+
+	n.mu.Lock()
+	if el, ok := n.entries[key]; ok {
+		n.removeElement(el)
+	}
+	n.mu.Unlock()
+	return v, err
+}
+
+// GetSet 超出配额且为 OverflowReject 时不写入，返回 ErrQuotaExceeded
+func (n *Namespace) GetSet(key string, value any, ttl time.Duration) (any, error) {
+	return n.GetSetCtx(context.Background(), key, value, ttl)
+}
+
+func (n *Namespace) GetSetCtx(ctx context.Context, key string, value any, ttl time.Duration) (any, error) {
+	r, err := n.reserve(ctx, key, encodedSize(value), ttl)
+	if err != nil {
+		return nil, err
+	}
+	old, err := n.c.GetSetCtx(ctx, n.prefix+key, value, ttl)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		n.release(r)
+	}
+	return old, err
+}
+
+// Ping 检查底层缓存是否可用
+func (n *Namespace) Ping(ctx context.Context) error {
+	return n.c.Ping(ctx)
+}
+
+// Close 不关闭底层缓存，底层缓存由创建方负责关闭
+func (n *Namespace) Close() error {
+	return nil
+}
+
+// Start 命名空间基于已启动的缓存创建，无需启动
+func (n *Namespace) Start(config any) error {
+	return nil
+}
+
+// reserve 在锁内为写入 key 占用配额并计入用量，随后在锁外删除被淘汰的条目；
+// 写入底层缓存失败时调用方需调用 release 回滚
+func (n *Namespace) reserve(ctx context.Context, key string, size int64, ttl time.Duration) (*reservation, error) {
+	n.mu.Lock()
+	victims, err := n.makeRoom(key, size)
+	if err != nil {
+		n.mu.Unlock()
+		return nil, err
+	}
+	r := &reservation{key: key, victims: victims}
+	if el, ok := n.entries[key]; ok {
+		prev := *el.Value.(*nsEntry)
+		r.prev = &prev
+	}
+	r.gen = n.track(key, size, ttl)
+	n.mu.Unlock()
+
+	for _, victim := range r.victims {
+		_ = n.c.DeleteCtx(ctx, n.prefix+victim)
+	}
+	return r, nil
+}
+
+// release 回滚 reserve 计入的用量；条目已被之后的写入覆盖时不做任何事
+func (n *Namespace) release(r *reservation) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	el, ok := n.entries[r.key]
+	if !ok || el.Value.(*nsEntry).gen != r.gen {
+		return
+	}
+	if r.prev == nil {
+		n.removeElement(el)
+		return
+	}
+	e := el.Value.(*nsEntry)
+	n.bytes += r.prev.size - e.size
+	e.size, e.expire, e.gen = r.prev.size, r.prev.expire, r.prev.gen
+}
+
+// makeRoom 检查写入 key 后是否超出配额，OverflowEvict 时从用量中移除最久未使用的条目并返回它们，调用方需持有锁
+func (n *Namespace) makeRoom(key string, size int64) ([]string, error) {
+	if n.config.MaxEntries <= 0 && n.config.MaxBytes <= 0 {
+		return nil, nil
+	}
+	if n.config.MaxBytes > 0 && size > n.config.MaxBytes {
+		n.rejected.Add(1)
+		return nil, ErrQuotaExceeded
+	}
+
+	entries, bytes := n.usageAfter(key, size)
+	if !n.exceeds(entries, bytes) {
+		return nil, nil
+	}
+	n.pruneExpired(time.Now())
+	if entries, bytes = n.usageAfter(key, size); !n.exceeds(entries, bytes) {
+		return nil, nil
+	}
+	if n.config.Overflow != OverflowEvict {
+		n.rejected.Add(1)
+		return nil, ErrQuotaExceeded
+	}
+
+	var victims []string
+	for el := n.order.Front(); el != nil && n.exceeds(entries, bytes); {
+		next := el.Next()
+		e := el.Value.(*nsEntry)
+		if e.key != key {
+			victims = append(victims, e.key)
+			n.removeElement(el)
+			n.evicted.Add(1)
+			entries--
+			bytes -= e.size
+		}
+		el = next
+	}
+	return victims, nil
+}
+
+// usageAfter 返回写入 key 后的用量，覆盖已有 key 时扣除旧值
+func (n *Namespace) usageAfter(key string, size int64) (int, int64) {
+	entries, bytes := len(n.entries)+1, n.bytes+size
+	if el, ok := n.entries[key]; ok {
+		entries--
+		bytes -= el.Value.(*nsEntry).size
+	}
+	return entries, bytes
+}
+
+func (n *Namespace) exceeds(entries int, bytes int64) bool {
+	return n.config.MaxEntries > 0 && entries > n.config.MaxEntries ||
+		n.config.MaxBytes > 0 && bytes > n.config.MaxBytes
+}
+
+// track 记录写入后的用量并返回条目的新版本号，调用方需持有锁
+func (n *Namespace) track(key string, size int64, ttl time.Duration) uint64 {
+	var expire time.Time
+	if ttl > 0 {
+		expire = time.Now().Add(ttl)
+	}
+	n.gen++
+	if el, ok := n.entries[key]; ok {
+		e := el.Value.(*nsEntry)
+		n.bytes += size - e.size
+		e.size, e.expire, e.gen = size, expire, n.gen
+		n.order.MoveToBack(el)
+		return n.gen
+	}
+	n.entries[key] = n.order.PushBack(&nsEntry{key: key, size: size, expire: expire, gen: n.gen})
+	n.bytes += size
+	return n.gen
+}
+
+// pruneExpired 移除已过期条目的用量，调用方需持有锁
+func (n *Namespace) pruneExpired(now time.Time) {
+	for el := n.order.Front(); el != nil; {
+		next := el.Next()
+		if e := el.Value.(*nsEntry); !e.expire.IsZero() && now.After(e.expire) {
+			n.removeElement(el)
+		}
+		el = next
+	}
+}
+
+func (n *Namespace) removeElement(el *list.Element) {
+	e := n.order.Remove(el).(*nsEntry)
+	delete(n.entries, e.key)
+	n.bytes -= e.size
+}
+
+func encodedSize(v any) int64 {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return int64(len(b))
+}
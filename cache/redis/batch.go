@@ -0,0 +1,230 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/jiajia556/tool-box/cache"
+)
+
+// defaultWriteBatchInterval 写缓冲默认的刷新间隔
+const defaultWriteBatchInterval = 2 * time.Millisecond
+
+// writeOp 缓冲中的一次写入，同一 key 只保留最后一次
+type writeOp struct {
+	key   string
+	value []byte // 编码并压缩后的值，删除时为 nil
+	ttl   time.Duration
+	del   bool
+	raw   any // 原始值，写入降级缓存时使用
+}
+
+// writeBuffer 将 Set / Delete 合并为 pipeline 批量写入
+type writeBuffer struct {
+	r        *RedisCache
+	size     int
+	interval time.Duration
+
+	mu       sync.Mutex
+	pending  map[string]*writeOp
+	inflight map[string]*writeOp // 正在写入的批次，写入完成前 Get 仍从这里读取
+
+	// 串行执行批次，保证先缓冲的写入先到达 Redis
+	flushMu sync.Mutex
+
+	kick      chan struct{}
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newWriteBuffer(r *RedisCache, size int, interval time.Duration) *writeBuffer {
+	if interval <= 0 {
+		interval = defaultWriteBatchInterval
+	}
+	b := &writeBuffer{
+		r:        r,
+		size:     size,
+		interval: interval,
+		pending:  make(map[string]*writeOp),
+		kick:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go b.loop()
+	return b
+}
+
+func (b *writeBuffer) loop() {
+	defer close(b.done)
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-b.kick:
+		case <-b.stop:
+			return
+		}
+		_ = b.flush(context.Background())
+	}
+}
+
+// add 缓冲一次写入，达到 size 时通知后台立即刷新
+func (b *writeBuffer) add(op *writeOp) {
+	b.mu.Lock()
+	b.pending[op.key] = op
+	full := len(b.pending) >= b.size
+	b.mu.Unlock()
+
+	if full {
+		select {
+		case b.kick <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// lookup 返回 key 尚未写入 Redis 的最后一次写入
+func (b *writeBuffer) lookup(key string) (*writeOp, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if op, ok := b.pending[key]; ok {
+		return op, true
+	}
+	op, ok := b.inflight[key]
+	return op, ok
+}
+
+// flush 以 pipeline 写入缓冲中的全部操作，并等待之前的批次完成
+func (b *writeBuffer) flush(ctx context.Context) error {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	b.mu.Lock()
+	ops := b.pending
+	if len(ops) == 0 {
+		b.mu.Unlock()
+		return nil
+	}
+	b.pending = make(map[string]*writeOp, len(ops))
+	b.inflight = ops
+	b.mu.Unlock()
+
+	err := b.r.execBatch(ctx, ops)
+
+	b.mu.Lock()
+	b.inflight = nil
+	b.mu.Unlock()
+	return err
+}
+
+// close 停止后台刷新并写入剩余操作，可重复调用
+func (b *writeBuffer) close() error {
+	b.closeOnce.Do(func() {
+		close(b.stop)
+		<-b.done
+	})
+	return b.flush(context.Background())
+}
+
+// execBatch 以一个 pipeline 写入 ops；超时或熔断时改写到降级缓存，与 SetCtx / DeleteCtx 一致
+func (r *RedisCache) execBatch(ctx context.Context, pending map[string]*writeOp) error {
+	start := cache.HookStart()
+	// pipeline 的结果按命令顺序返回，先固定顺序
+	ops := make([]*writeOp, 0, len(pending))
+	for _, op := range pending {
+		ops = append(ops, op)
+	}
+
+	var (
+		cmds []redis.Cmder
+		err  error
+	)
+	opCtx, cancel, ok := r.begin(ctx)
+	if ok {
+		pipe := r.client.Pipeline()
+		for _, op := range ops {
+			if op.del {
+				pipe.Del(opCtx, r.key(op.key))
+			} else {
+				pipe.Set(opCtx, r.key(op.key), op.value, op.ttl)
+			}
+		}
+		cmds, err = pipe.Exec(opCtx)
+		cancel()
+	}
+	if !ok || r.timedOut(ctx, err) {
+		err = ErrTimeout
+	}
+
+	fb := r.fallback()
+	for i, op := range ops {
+		opErr := err
+		if !errors.Is(err, ErrTimeout) && i < len(cmds) {
+			opErr = cmds[i].Err()
+		}
+
+		name := "set"
+		if op.del {
+			name = "delete"
+		}
+		switch {
+		case op.del:
+			// 与 DeleteCtx 一致：无论 Redis 是否成功都删除降级缓存中的 key
+			if fb != nil {
+				_ = fb.DeleteCtx(ctx, op.key)
+			}
+			r.stats.Delete()
+		case errors.Is(opErr, ErrTimeout) && fb != nil:
+			if opErr = fb.SetCtx(ctx, op.key, op.raw, op.ttl); opErr == nil {
+				r.stats.Set()
+			}
+		case opErr == nil:
+			if fb != nil {
+				_ = fb.DeleteCtx(ctx, op.key)
+			}
+			r.stats.Set()
+		}
+		cache.Observe(cache.AdapterRedis, name, op.key, start, opErr)
+	}
+	if errors.Is(err, ErrTimeout) && fb != nil {
+		return nil
+	}
+	return err
+}
+
+// bufferSet 缓冲一次 Set
+func (r *RedisCache) bufferSet(key string, value any, ttl time.Duration) {
+	if ttl < 0 {
+		ttl = r.opts.DefaultTTL
+	}
+	b, err := r.encode(value)
+	if err != nil {
+		cache.Observe(cache.AdapterRedis, "set", key, cache.HookStart(), err)
+		return
+	}
+	r.batch.add(&writeOp{key: key, value: b, ttl: ttl, raw: value})
+}
+
+// settle 确保 key 缓冲中的写入已到达 Redis，供需要读取服务端状态或同步写入的操作在执行前调用
+func (r *RedisCache) settle(ctx context.Context, key string) {
+	if r.batch == nil {
+		return
+	}
+	if _, ok := r.batch.lookup(key); ok {
+		_ = r.batch.flush(ctx)
+	}
+}
+
+// Flush 立即写入写缓冲中的全部操作并等待完成，返回 Redis 的写入错误；未开启写缓冲时不做任何事
+func (r *RedisCache) Flush(ctx context.Context) error {
+	if r.batch == nil {
+		return nil
+	}
+	return r.batch.flush(ctx)
+}
@@ -0,0 +1,108 @@
+package redis
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Compressor 值压缩算法
+type Compressor interface {
+	Compress(data []byte) ([]byte, error)
+	Decompress(data []byte) ([]byte, error)
+}
+
+var (
+	compressorsMu sync.RWMutex
+	compressors   = map[string]Compressor{"gzip": gzipCompressor{}}
+)
+
+// RegisterCompressor 注册压缩算法，如 snappy、zstd；内置 "gzip"
+func RegisterCompressor(name string, c Compressor) {
+	compressorsMu.Lock()
+	defer compressorsMu.Unlock()
+
+	if c == nil {
+		panic("redis cache: RegisterCompressor compressor is nil")
+	}
+	if len(name) == 0 || len(name) > 255 {
+		panic("redis cache: RegisterCompressor invalid name " + name)
+	}
+	if _, ok := compressors[name]; ok {
+		panic("redis cache: RegisterCompressor called twice for " + name)
+	}
+	compressors[name] = c
+}
+
+func getCompressor(name string) (Compressor, bool) {
+	compressorsMu.RLock()
+	defer compressorsMu.RUnlock()
+	c, ok := compressors[name]
+	return c, ok
+}
+
+// compressMagic 压缩值的头部：magic + 1 字节算法名长度 + 算法名，之后为压缩数据。
+// 编码后的 JSON 不会以 0x00 开头，未压缩的值原样保存，读取时可与旧数据兼容。
+var compressMagic = []byte("\x00tbz")
+
+// pack 超过阈值时压缩编码后的值
+func (r *RedisCache) pack(b []byte) ([]byte, error) {
+	if r.compressor == nil || len(b) < r.opts.CompressThreshold {
+		return b, nil
+	}
+
+	compressed, err := r.compressor.Compress(b)
+	if err != nil {
+		return nil, err
+	}
+	name := r.opts.Compression
+	out := make([]byte, 0, len(compressMagic)+1+len(name)+len(compressed))
+	out = append(out, compressMagic...)
+	out = append(out, byte(len(name)))
+	out = append(out, name...)
+	return append(out, compressed...), nil
+}
+
+// unpack 按头部记录的算法解压，未压缩的值原样返回
+func unpack(b []byte) ([]byte, error) {
+	if !bytes.HasPrefix(b, compressMagic) || len(b) <= len(compressMagic) {
+		return b, nil
+	}
+
+	rest := b[len(compressMagic):]
+	n := int(rest[0])
+	if len(rest) < 1+n {
+		return nil, fmt.Errorf("redis cache: truncated compression header")
+	}
+	name := string(rest[1 : 1+n])
+	c, ok := getCompressor(name)
+	if !ok {
+		return nil, fmt.Errorf("redis cache: unknown compression %q", name)
+	}
+	return c.Decompress(rest[1+n:])
+}
+
+type gzipCompressor struct{}
+
+func (gzipCompressor) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gzipCompressor) Decompress(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
@@ -0,0 +1,167 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/jiajia556/tool-box/cache"
+)
+
+// ErrTimeout Redis 操作超时或熔断打开，且未配置 Fallback
+var ErrTimeout = errors.New("redis cache: operation timed out")
+
+// OpStats 超时与降级统计
+type OpStats struct {
+	Timeouts    uint64 // 超时的操作数
+	Fallbacks   uint64 // 降级到 Fallback（或按未命中处理）的操作数
+	CircuitOpen bool   // 熔断是否打开
+}
+
+// OpStats 返回超时与降级统计
+func (r *RedisCache) OpStats() OpStats {
+	r.breakerMu.Lock()
+	open := time.Now().Before(r.openUntil)
+	r.breakerMu.Unlock()
+
+	return OpStats{
+		Timeouts:    r.timeouts.Load(),
+		Fallbacks:   r.fallbacks.Load(),
+		CircuitOpen: open,
+	}
+}
+
+// begin 开始一次操作，基于调用方的 ctx 加上 OpTimeout；熔断打开时返回 ok=false，调用方直接降级
+func (r *RedisCache) begin(ctx context.Context) (opCtx context.Context, cancel context.CancelFunc, ok bool) {
+	if r.opts.BreakerThreshold > 0 {
+		r.breakerMu.Lock()
+		open := time.Now().Before(r.openUntil)
+		r.breakerMu.Unlock()
+		if open {
+			return nil, nil, false
+		}
+	}
+	if r.opts.OpTimeout <= 0 {
+		return ctx, func() {}, true
+	}
+	opCtx, cancel = context.WithTimeout(ctx, r.opts.OpTimeout)
+	return opCtx, cancel, true
+}
+
+// timedOut 记录操作结果并返回是否超时：连续超时达到 BreakerThreshold 时打开熔断 BreakerCooldown，
+// 冷却后的第一次操作成功则关闭熔断，再次超时则重新打开。
+// 调用方的 ctx 已取消或到期时不计入超时，由调用方直接返回 ctx.Err()
+func (r *RedisCache) timedOut(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	timeout := isTimeout(err)
+	if timeout {
+		r.timeouts.Add(1)
+	}
+	if r.opts.BreakerThreshold <= 0 {
+		return timeout
+	}
+
+	r.breakerMu.Lock()
+	defer r.breakerMu.Unlock()
+	if !timeout {
+		r.consecutive = 0
+		return false
+	}
+	r.consecutive++
+	if r.consecutive >= r.opts.BreakerThreshold {
+		cooldown := r.opts.BreakerCooldown
+		if cooldown <= 0 {
+			cooldown = 5 * time.Second
+		}
+		r.openUntil = time.Now().Add(cooldown)
+	}
+	return true
+}
+
+func isTimeout(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+// fallback 计入一次降级并返回 Fallback，未配置时返回 nil
+func (r *RedisCache) fallback() cache.Cache {
+	r.fallbacks.Add(1)
+	return r.opts.Fallback
+}
+
+// fallbackGet 降级读取，未配置 Fallback 时按未命中处理
+func (r *RedisCache) fallbackGet(ctx context.Context, key string) (any, error) {
+	fb := r.fallback()
+	if fb == nil {
+		r.stats.Miss()
+		return nil, cache.ErrNotFound
+	}
+	return fb.GetCtx(ctx, key)
+}
+
+// fallbackRaw 同 fallbackGet，返回 JSON 编码的值
+func (r *RedisCache) fallbackRaw(ctx context.Context, key string) ([]byte, error) {
+	fb := r.fallback()
+	if fb == nil {
+		r.stats.Miss()
+		return nil, cache.ErrNotFound
+	}
+	if rg, ok := fb.(cache.RawGetter); ok {
+		return rg.GetRaw(key)
+	}
+	v, err := fb.GetCtx(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, cache.ErrDecode
+	}
+	return b, nil
+}
+
+func (r *RedisCache) fallbackCAS(ctx context.Context, key string, old, new any, ttl time.Duration) (bool, error) {
+	if fb := r.fallback(); fb != nil {
+		return fb.CompareAndSwapCtx(ctx, key, old, new, ttl)
+	}
+	return false, ErrTimeout
+}
+
+func (r *RedisCache) fallbackGetDel(ctx context.Context, key string) (any, error) {
+	if fb := r.fallback(); fb != nil {
+		return fb.GetDelCtx(ctx, key)
+	}
+	r.stats.Miss()
+	return nil, cache.ErrNotFound
+}
+
+func (r *RedisCache) fallbackGetSet(ctx context.Context, key string, value any, ttl time.Duration) (any, error) {
+	if fb := r.fallback(); fb != nil {
+		return fb.GetSetCtx(ctx, key, value, ttl)
+	}
+	return nil, ErrTimeout
+}
+
+func (r *RedisCache) fallbackExists(ctx context.Context, key string) (bool, error) {
+	if fb := r.fallback(); fb != nil {
+		return fb.ExistsCtx(ctx, key)
+	}
+	return false, nil
+}
+
+func (r *RedisCache) fallbackTTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	if fb := r.fallback(); fb != nil {
+		return fb.TTLCtx(ctx, key)
+	}
+	return 0, false, nil
+}
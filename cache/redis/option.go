@@ -1,6 +1,10 @@
 package redis
 
-import "time"
+import (
+	"time"
+
+	"github.com/jiajia556/tool-box/cache"
+)
 
 type Options struct {
 	Addr     string `json:"addr"`
@@ -8,6 +12,59 @@ type Options struct {
 	Password string `json:"password"`
 	DB       int    `json:"db"`
 
+	// Redis Cluster 的种子节点地址，非空时使用集群模式，忽略 Addr 与 DB（集群只有 0 号库）；
+	// 不能与 Shards 同时设置
+	Addrs []string `json:"addrs"`
+
+	// 哨兵模式：主节点名称与哨兵地址，均非空时通过哨兵发现主节点，主从切换后自动连接新的主节点，忽略 Addr；
+	// 不能与 Shards、Addrs 同时设置
+	MasterName    string   `json:"master_name"`
+	SentinelAddrs []string `json:"sentinel_addrs"`
+
+	// 哨兵自身的认证密码，为空表示哨兵不需要认证；Username / Password 用于主节点
+	SentinelPassword string `json:"sentinel_password"`
+
+	// 分片模式：分片名到地址的映射，非空时忽略 Addr，key 按一致性哈希分布到各个独立的 Redis 实例，
+	// 无需部署 Redis Cluster 即可水平扩容。分片名参与哈希，替换地址时保持名称不变即可不迁移 key
+	Shards map[string]string `json:"shards"`
+
+	// 每个分片的虚拟节点数，默认 160；越大分布越均匀
+	VirtualNodes int `json:"virtual_nodes"`
+
+	// 分片健康检查间隔，默认 500ms
+	HeartbeatFrequency time.Duration `json:"heartbeat_frequency"`
+
 	DefaultTTL time.Duration `json:"default_ttl"`
 	Prefix     string        `json:"prefix"`
+
+	// 值的编解码器，nil 表示 cache.JSONCodec
+	Codec cache.Codec `json:"-"`
+
+	// 编码后超过该字节数的值压缩后写入，<=0 表示不压缩
+	CompressThreshold int `json:"compress_threshold"`
+
+	// 压缩算法，默认 "gzip"；其他算法需先通过 RegisterCompressor 注册
+	Compression string `json:"compression"`
+
+	// 单次操作的超时时间，<=0 表示不限制。超时后读取降级到 Fallback（未配置时按未命中处理），
+	// 写入改写到 Fallback，避免 Redis 变慢拖住请求
+	OpTimeout time.Duration `json:"op_timeout"`
+
+	// 超时或熔断时使用的降级缓存，如内存缓存；nil 表示不降级
+	Fallback cache.Cache `json:"-"`
+
+	// 连续超时达到该次数时打开熔断，冷却期内不再访问 Redis；<=0 表示不熔断
+	BreakerThreshold int `json:"breaker_threshold"`
+
+	// 熔断冷却时间，默认 5s
+	BreakerCooldown time.Duration `json:"breaker_cooldown"`
+
+	// 写缓冲的批量大小，>0 时开启写缓冲：Set / Delete 写入缓冲后立即返回，同一 key 的多次写入只保留最后一次，
+	// 缓冲达到该条数或每隔 WriteBatchInterval 以 pipeline 批量写入，减少写密集场景的往返次数。
+	// 本实例的 Get 能读到缓冲中的值；其他读写操作执行前先写入该 key 的缓冲，SetCtx 等返回错误的方法仍同步执行。
+	// 进程崩溃时缓冲中尚未写入的操作会丢失，Close 时写入剩余操作；<=0 表示不开启
+	WriteBatchSize int `json:"write_batch_size"`
+
+	// 写缓冲的刷新间隔，默认 2ms
+	WriteBatchInterval time.Duration `json:"write_batch_interval"`
 }
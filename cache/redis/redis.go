@@ -2,24 +2,36 @@ package redis
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/redis/go-redis/v9"
-	"golang.org/x/sync/singleflight"
 
 	"github.com/jiajia556/tool-box/cache"
 )
 
 type RedisCache struct {
-	client *redis.Client
-	opts   Options
-	stats  cache.Stats
-	sf     singleflight.Group
-	ctx    context.Context
+	client     client
+	opts       Options
+	compressor Compressor   // 未开启压缩时为 nil
+	batch      *writeBuffer // 未开启写缓冲时为 nil
+	stats      cache.Stats
+
+	// 超时降级与熔断
+	timeouts    atomic.Uint64
+	fallbacks   atomic.Uint64
+	breakerMu   sync.Mutex
+	consecutive int
+	openUntil   time.Time
 }
 
+// errFallback get 超时或熔断打开，调用方应降级
+var errFallback = errors.New("redis cache: fallback")
+
 // NewRedisCache 创建 Redis 缓存实例。
 func NewRedisCache() cache.Cache {
 	return &RedisCache{}
@@ -32,83 +44,467 @@ func (r *RedisCache) key(k string) string {
 	return r.opts.Prefix + ":" + k
 }
 
+func (r *RedisCache) codec() cache.Codec {
+	return cache.CodecOrJSON(r.opts.Codec)
+}
 
 func (r *RedisCache) Get(key string) (any, error) {
-	b, err := r.client.Get(r.ctx, r.key(key)).Bytes()
-	if err == redis.Nil {
-		r.stats.Misses++
-		return nil, cache.ErrNotFound
+	return r.GetCtx(context.Background(), key)
+}
+
+func (r *RedisCache) GetCtx(ctx context.Context, key string) (any, error) {
+	b, err := r.get(ctx, key)
+	if errors.Is(err, errFallback) {
+		return r.fallbackGet(ctx, key)
 	}
 	if err != nil {
-		r.stats.Misses++
-		return nil, cache.ErrNotFound
+		return nil, err
 	}
 
 	var v any
-	if err := json.Unmarshal(b, &v); err != nil {
-		r.stats.Misses++
+	if err := r.codec().Unmarshal(b, &v); err != nil {
 		return nil, cache.ErrDecode
 	}
-
-	r.stats.Hits++
 	return v, nil
 }
 
+// GetRaw 返回 JSON 编码的值，由调用方解码到具体类型；使用其他编解码器时先转换为 JSON
+func (r *RedisCache) GetRaw(key string) ([]byte, error) {
+	ctx := context.Background()
+	b, err := r.get(ctx, key)
+	if errors.Is(err, errFallback) {
+		return r.fallbackRaw(ctx, key)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cache.ToJSON(r.codec(), b)
+}
+
+// StoresNative 实现 cache.NativeCache：使用非 JSON 编解码器时 Get 的结果可直接断言类型
+func (r *RedisCache) StoresNative() bool {
+	return !cache.IsJSONCodec(r.opts.Codec)
+}
+
+// get 读取 key 并解压，返回编码后的值；超时或熔断时返回 errFallback
+func (r *RedisCache) get(ctx context.Context, key string) (b []byte, err error) {
+	start := cache.HookStart()
+	defer func() {
+		if errors.Is(err, errFallback) {
+			cache.Observe(cache.AdapterRedis, "get", key, start, ErrTimeout)
+			return
+		}
+		cache.Observe(cache.AdapterRedis, "get", key, start, err)
+	}()
+
+	if r.batch != nil {
+		if op, ok := r.batch.lookup(key); ok {
+			if op.del {
+				r.stats.Miss()
+				return nil, cache.ErrNotFound
+			}
+			r.stats.Hit()
+			if b, err = unpack(op.value); err != nil {
+				return nil, cache.ErrDecode
+			}
+			return b, nil
+		}
+	}
+
+	opCtx, cancel, ok := r.begin(ctx)
+	if !ok {
+		return nil, errFallback
+	}
+	defer cancel()
+
+	b, err = r.client.Get(opCtx, r.key(key)).Bytes()
+	if r.timedOut(ctx, err) {
+		return nil, errFallback
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err != nil {
+		r.stats.Miss()
+		return nil, cache.ErrNotFound
+	}
+
+	r.stats.Hit()
+	if b, err = unpack(b); err != nil {
+		return nil, cache.ErrDecode
+	}
+	return b, nil
+}
+
+// Set 开启写缓冲（WriteBatchSize > 0）时写入缓冲后立即返回，否则同步写入
 func (r *RedisCache) Set(key string, value any, ttl time.Duration) {
+	if r.batch != nil {
+		r.bufferSet(key, value, ttl)
+		return
+	}
+	_ = r.SetCtx(context.Background(), key, value, ttl)
+}
+
+func (r *RedisCache) SetCtx(ctx context.Context, key string, value any, ttl time.Duration) (err error) {
+	start := cache.HookStart()
+	defer func() { cache.Observe(cache.AdapterRedis, "set", key, start, err) }()
+
 	if ttl < 0 {
 		ttl = r.opts.DefaultTTL
 	}
 
-	b, err := json.Marshal(value)
+	b, err := r.encode(value)
 	if err != nil {
-		return
+		return err
 	}
 
-	if ttl > 0 {
-		_ = r.client.Set(r.ctx, r.key(key), b, ttl).Err()
-	} else {
-		_ = r.client.Set(r.ctx, r.key(key), b, 0).Err()
+	r.settle(ctx, key)
+	opCtx, cancel, ok := r.begin(ctx)
+	if ok {
+		err = r.client.Set(opCtx, r.key(key), b, ttl).Err()
+		cancel()
+	}
+	if !ok || r.timedOut(ctx, err) {
+		if fb := r.fallback(); fb != nil {
+			return fb.SetCtx(ctx, key, value, ttl)
+		}
+		return ErrTimeout
+	}
+	if err != nil {
+		return err
+	}
+	// 清除超时期间写入降级缓存的旧值，避免下次降级时读到
+	if r.opts.Fallback != nil {
+		_ = r.opts.Fallback.DeleteCtx(ctx, key)
+	}
+
+	r.stats.Set()
+	return nil
+}
+
+// CompareAndSwap 基于 WATCH/MULTI 实现，key 在比较后被其他客户端修改时返回 false
+func (r *RedisCache) CompareAndSwap(key string, old, new any, ttl time.Duration) (bool, error) {
+	return r.CompareAndSwapCtx(context.Background(), key, old, new, ttl)
+}
+
+func (r *RedisCache) CompareAndSwapCtx(ctx context.Context, key string, old, new any, ttl time.Duration) (bool, error) {
+	r.settle(ctx, key)
+	if ttl < 0 {
+		ttl = r.opts.DefaultTTL
+	}
+
+	b, err := r.encode(new)
+	if err != nil {
+		return false, err
+	}
+
+	opCtx, cancel, ok := r.begin(ctx)
+	if !ok {
+		return r.fallbackCAS(ctx, key, old, new, ttl)
+	}
+	defer cancel()
+
+	k := r.key(key)
+	swapped := false
+	err = r.client.Watch(opCtx, func(tx *redis.Tx) error {
+		current, err := tx.Get(opCtx, k).Bytes()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+		if current, err = unpack(current); err != nil {
+			return err
+		}
+		if !cache.EqualEncodedWith(r.codec(), current, old) {
+			return nil
+		}
+
+		_, err = tx.TxPipelined(opCtx, func(pipe redis.Pipeliner) error {
+			pipe.Set(opCtx, k, b, ttl)
+			return nil
+		})
+		if err == nil {
+			swapped = true
+		}
+		return err
+	}, k)
+	if r.timedOut(ctx, err) {
+		return r.fallbackCAS(ctx, key, old, new, ttl)
+	}
+	if err == redis.TxFailedErr {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if swapped {
+		r.stats.Set()
+	}
+	return swapped, nil
+}
+
+// GetDel 使用 GETDEL 命令（Redis 6.2+）
+func (r *RedisCache) GetDel(key string) (any, error) {
+	return r.GetDelCtx(context.Background(), key)
+}
+
+func (r *RedisCache) GetDelCtx(ctx context.Context, key string) (any, error) {
+	r.settle(ctx, key)
+	opCtx, cancel, ok := r.begin(ctx)
+	if !ok {
+		return r.fallbackGetDel(ctx, key)
+	}
+	defer cancel()
+
+	b, err := r.client.GetDel(opCtx, r.key(key)).Bytes()
+	if r.timedOut(ctx, err) {
+		return r.fallbackGetDel(ctx, key)
+	}
+	if r.opts.Fallback != nil {
+		_ = r.opts.Fallback.DeleteCtx(ctx, key)
+	}
+	if err == redis.Nil {
+		r.stats.Miss()
+		return nil, cache.ErrNotFound
+	}
+	if err != nil {
+		r.stats.Miss()
+		return nil, err
+	}
+	r.stats.Hit()
+	r.stats.Delete()
+	return r.decode(b)
+}
+
+// GetSet 使用 SET ... GET 命令（Redis 6.2+），可同时设置过期时间
+func (r *RedisCache) GetSet(key string, value any, ttl time.Duration) (any, error) {
+	return r.GetSetCtx(context.Background(), key, value, ttl)
+}
+
+func (r *RedisCache) GetSetCtx(ctx context.Context, key string, value any, ttl time.Duration) (any, error) {
+	r.settle(ctx, key)
+	if ttl < 0 {
+		ttl = r.opts.DefaultTTL
+	}
+
+	b, err := r.encode(value)
+	if err != nil {
+		return nil, err
+	}
+
+	opCtx, cancel, ok := r.begin(ctx)
+	if !ok {
+		return r.fallbackGetSet(ctx, key, value, ttl)
+	}
+	defer cancel()
+
+	old, err := r.client.SetArgs(opCtx, r.key(key), b, redis.SetArgs{Get: true, TTL: ttl}).Result()
+	if r.timedOut(ctx, err) {
+		return r.fallbackGetSet(ctx, key, value, ttl)
+	}
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+	r.stats.Set()
+	if err == redis.Nil {
+		r.stats.Miss()
+		return nil, cache.ErrNotFound
+	}
+	r.stats.Hit()
+	return r.decode([]byte(old))
+}
+
+// encode 编码并按需压缩
+func (r *RedisCache) encode(v any) ([]byte, error) {
+	b, err := r.codec().Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return r.pack(b)
+}
+
+// decode 解压并解码从 Redis 读出的值
+func (r *RedisCache) decode(b []byte) (any, error) {
+	b, err := unpack(b)
+	if err != nil {
+		return nil, cache.ErrDecode
 	}
 
-	r.stats.Sets++
+	var v any
+	if err := r.codec().Unmarshal(b, &v); err != nil {
+		return nil, cache.ErrDecode
+	}
+	return v, nil
 }
 
+// Delete 开启写缓冲时写入缓冲后立即返回，否则同步删除
 func (r *RedisCache) Delete(key string) {
-	_ = r.client.Del(r.ctx, r.key(key)).Err()
-	r.stats.Deletes++
+	if r.batch != nil {
+		r.batch.add(&writeOp{key: key, del: true})
+		return
+	}
+	_ = r.DeleteCtx(context.Background(), key)
 }
 
+// DeleteCtx 同时删除降级缓存中的 key；Redis 超时时返回 ErrTimeout
+func (r *RedisCache) DeleteCtx(ctx context.Context, key string) (err error) {
+	start := cache.HookStart()
+	defer func() { cache.Observe(cache.AdapterRedis, "delete", key, start, err) }()
+
+	r.settle(ctx, key)
+	if opCtx, cancel, ok := r.begin(ctx); ok {
+		err = r.client.Del(opCtx, r.key(key)).Err()
+		cancel()
+		if r.timedOut(ctx, err) {
+			err = ErrTimeout
+		}
+	} else {
+		err = ErrTimeout
+	}
+	if r.opts.Fallback != nil {
+		_ = r.opts.Fallback.DeleteCtx(ctx, key)
+	}
+	r.stats.Delete()
+	return err
+}
+
+// Clear 未设置 Prefix 时 FLUSHDB，否则在 Prefix 下 SCAN 并逐个 DEL；分片模式遍历所有分片，集群模式遍历所有主节点
 func (r *RedisCache) Clear() {
-	if r.opts.Prefix == "" {
-		_ = r.client.FlushDB(r.ctx).Err()
-		return
+	_ = r.Flush(context.Background())
+	_ = r.forEachNode(context.Background(), func(ctx context.Context, c *redis.Client) error {
+		if r.opts.Prefix == "" {
+			return c.FlushDB(ctx).Err()
+		}
+
+		iter := c.Scan(ctx, 0, cache.EscapePattern(r.key(""))+"*", 0).Iterator()
+		for iter.Next(ctx) {
+			_ = c.Del(ctx, iter.Val()).Err()
+		}
+		return iter.Err()
+	})
+}
+
+// DeleteByPrefix 在配置的 Prefix 下 SCAN 匹配的 key 并逐个 DEL，分片与集群模式下遍历所有节点
+func (r *RedisCache) DeleteByPrefix(prefix string) int {
+	_ = r.Flush(context.Background())
+	var n atomic.Int64
+	_ = r.forEachNode(context.Background(), func(ctx context.Context, c *redis.Client) error {
+		iter := c.Scan(ctx, 0, cache.EscapePattern(r.key(prefix))+"*", 0).Iterator()
+		for iter.Next(ctx) {
+			if deleted, err := c.Del(ctx, iter.Val()).Result(); err == nil {
+				n.Add(deleted)
+			}
+		}
+		return iter.Err()
+	})
+	r.stats.AddDeletes(uint64(n.Load()))
+	return int(n.Load())
+}
+
+// errKeysLimit Keys 已收集到 limit 个 key，停止 SCAN
+var errKeysLimit = errors.New("redis cache: keys limit reached")
+
+// Keys 在配置的 Prefix 下 SCAN 匹配的 key，返回去掉 Prefix 的 key；分片与集群模式下遍历所有节点
+func (r *RedisCache) Keys(pattern string, limit int) ([]string, error) {
+	if pattern == "" {
+		pattern = "*"
 	}
+	match := cache.EscapePattern(r.key("")) + pattern
+	_ = r.Flush(context.Background())
 
-	iter := r.client.Scan(r.ctx, 0, r.opts.Prefix+":*", 0).Iterator()
-	for iter.Next(r.ctx) {
-		_ = r.client.Del(r.ctx, iter.Val()).Err()
+	var (
+		mu   sync.Mutex
+		keys []string
+	)
+	err := r.forEachNode(context.Background(), func(ctx context.Context, c *redis.Client) error {
+		iter := c.Scan(ctx, 0, match, 0).Iterator()
+		for iter.Next(ctx) {
+			key := strings.TrimPrefix(iter.Val(), r.key(""))
+			mu.Lock()
+			if limit > 0 && len(keys) >= limit {
+				mu.Unlock()
+				return errKeysLimit
+			}
+			keys = append(keys, key)
+			mu.Unlock()
+		}
+		return iter.Err()
+	})
+	if err != nil && !errors.Is(err, errKeysLimit) {
+		return nil, err
 	}
+	return keys, nil
 }
 
 func (r *RedisCache) Exists(key string) bool {
-	n, err := r.client.Exists(r.ctx, r.key(key)).Result()
-	return err == nil && n > 0
+	ok, _ := r.ExistsCtx(context.Background(), key)
+	return ok
+}
+
+func (r *RedisCache) ExistsCtx(ctx context.Context, key string) (bool, error) {
+	r.settle(ctx, key)
+	opCtx, cancel, ok := r.begin(ctx)
+	if !ok {
+		return r.fallbackExists(ctx, key)
+	}
+	defer cancel()
+
+	n, err := r.client.Exists(opCtx, r.key(key)).Result()
+	if r.timedOut(ctx, err) {
+		return r.fallbackExists(ctx, key)
+	}
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
 }
 
 func (r *RedisCache) TTL(key string) (time.Duration, bool) {
-	d, err := r.client.TTL(r.ctx, r.key(key)).Result()
-	if err != nil || d <= 0 {
-		return 0, false
+	d, ok, _ := r.TTLCtx(context.Background(), key)
+	return d, ok
+}
+
+func (r *RedisCache) TTLCtx(ctx context.Context, key string) (time.Duration, bool, error) {
+	r.settle(ctx, key)
+	opCtx, cancel, ok := r.begin(ctx)
+	if !ok {
+		return r.fallbackTTL(ctx, key)
+	}
+	defer cancel()
+
+	d, err := r.client.TTL(opCtx, r.key(key)).Result()
+	if r.timedOut(ctx, err) {
+		return r.fallbackTTL(ctx, key)
+	}
+	if err != nil {
+		return 0, false, err
 	}
-	return d, true
+	if d <= 0 {
+		return 0, false, nil
+	}
+	return d, true, nil
 }
 
 func (r *RedisCache) Stats() cache.Stats {
-	return r.stats
+	return r.stats.Snapshot()
+}
+
+func (r *RedisCache) ResetStats() {
+	r.stats.Reset()
+}
+
+// Describe 实现 cache.Describer；实例可能与其他服务共享，不统计条目数
+func (r *RedisCache) Describe() cache.Description {
+	return cache.Description{Adapter: cache.AdapterRedis, Config: cache.MaskSecrets(r.opts), Items: -1}
 }
 
+// Close 写入写缓冲中剩余的操作后关闭连接
 func (r *RedisCache) Close() error {
-	return r.client.Close()
+	var err error
+	if r.batch != nil {
+		err = r.batch.close()
+	}
+	return errors.Join(err, r.client.Close())
 }
 
 func (r *RedisCache) Start(config any) error {
@@ -117,17 +513,68 @@ func (r *RedisCache) Start(config any) error {
 		return fmt.Errorf("redis cache: invalid config")
 	}
 	r.opts = opts
+	if opts.CompressThreshold > 0 {
+		if r.opts.Compression == "" {
+			r.opts.Compression = "gzip"
+		}
+		c, ok := getCompressor(r.opts.Compression)
+		if !ok {
+			return fmt.Errorf("redis cache: unknown compression %q", r.opts.Compression)
+		}
+		r.compressor = c
+	}
 
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     opts.Addr,
-		Username: opts.Username,
-		Password: opts.Password,
-		DB:       opts.DB,
-	})
+	sentinel := opts.MasterName != "" || len(opts.SentinelAddrs) > 0
+	if sentinel && (opts.MasterName == "" || len(opts.SentinelAddrs) == 0) {
+		return fmt.Errorf("redis cache: MasterName and SentinelAddrs must be set together")
+	}
+	modes := 0
+	for _, on := range []bool{len(opts.Shards) > 0, len(opts.Addrs) > 0, sentinel} {
+		if on {
+			modes++
+		}
+	}
+	if modes > 1 {
+		return fmt.Errorf("redis cache: Shards, Addrs and SentinelAddrs are mutually exclusive")
+	}
+	switch {
+	case len(opts.Shards) > 0:
+		r.client = newRing(opts)
+	case len(opts.Addrs) > 0:
+		r.client = newCluster(opts)
+	case sentinel:
+		r.client = newFailover(opts)
+	default:
+		r.client = redis.NewClient(&redis.Options{
+			Addr:     opts.Addr,
+			Username: opts.Username,
+			Password: opts.Password,
+			DB:       opts.DB,
+		})
+	}
 
-	r.client = rdb
-	r.ctx = context.Background()
+	if opts.WriteBatchSize > 0 {
+		r.batch = newWriteBuffer(r, opts.WriteBatchSize, opts.WriteBatchInterval)
+	}
+	return nil
+}
 
+// Ping 检查 Redis 连通性，不经过熔断与降级，反映 Redis 本身的状态。
+// 分片模式下不可用的分片已被移出哈希环，只要仍有可用分片且它们都能响应即视为可用
+func (r *RedisCache) Ping(ctx context.Context) error {
+	if r.client == nil {
+		return errors.New("redis cache: not started")
+	}
+	err := r.forEachNode(ctx, func(ctx context.Context, c *redis.Client) error {
+		return c.Ping(ctx).Err()
+	})
+	if ring, ok := r.client.(*redis.Ring); ok && err == nil {
+		// 全部分片不可用时 ForEachShard 不执行任何操作，由 Ring.Ping 报告
+		err = ring.Ping(ctx).Err()
+	}
+	if err != nil {
+		return fmt.Errorf("redis cache: ping: %w", err)
+	}
 	return nil
 }
 
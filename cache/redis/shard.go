@@ -0,0 +1,109 @@
+package redis
+
+import (
+	"context"
+	"hash/crc32"
+	"sort"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// client 单实例、分片与集群模式共用的命令接口，*redis.Client、*redis.Ring 与 *redis.ClusterClient 均已实现
+type client interface {
+	redis.Cmdable
+	Watch(ctx context.Context, fn func(*redis.Tx) error, keys ...string) error
+	Close() error
+}
+
+// newRing 创建分片客户端：key 按带虚拟节点的一致性哈希分布到 Shards，
+// 每 HeartbeatFrequency 检查各分片，不可用的分片暂时移出哈希环，其 key 落到其他分片上
+func newRing(opts Options) *redis.Ring {
+	vnodes := opts.VirtualNodes
+	if vnodes <= 0 {
+		vnodes = 160
+	}
+	return redis.NewRing(&redis.RingOptions{
+		Addrs:              opts.Shards,
+		Username:           opts.Username,
+		Password:           opts.Password,
+		DB:                 opts.DB,
+		HeartbeatFrequency: opts.HeartbeatFrequency,
+		NewConsistentHash: func(shards []string) redis.ConsistentHash {
+			return newHashRing(shards, vnodes)
+		},
+	})
+}
+
+// forEachNode 对每个节点执行 fn，用于 Clear / DeleteByPrefix 等需要遍历全部 key 的操作；
+// 集群模式下遍历所有主节点
+func (r *RedisCache) forEachNode(ctx context.Context, fn func(ctx context.Context, c *redis.Client) error) error {
+	switch c := r.client.(type) {
+	case *redis.Ring:
+		return c.ForEachShard(ctx, fn)
+	case *redis.ClusterClient:
+		return c.ForEachMaster(ctx, fn)
+	case *redis.Client:
+		return fn(ctx, c)
+	}
+	return nil
+}
+
+// newCluster 创建 Redis Cluster 客户端
+func newCluster(opts Options) *redis.ClusterClient {
+	return redis.NewClusterClient(&redis.ClusterOptions{
+		Addrs:    opts.Addrs,
+		Username: opts.Username,
+		Password: opts.Password,
+	})
+}
+
+// newFailover 创建哨兵模式客户端，主从切换后自动连接新的主节点
+func newFailover(opts Options) *redis.Client {
+	return redis.NewFailoverClient(&redis.FailoverOptions{
+		MasterName:       opts.MasterName,
+		SentinelAddrs:    opts.SentinelAddrs,
+		SentinelPassword: opts.SentinelPassword,
+		Username:         opts.Username,
+		Password:         opts.Password,
+		DB:               opts.DB,
+	})
+}
+
+// hashRing 带虚拟节点的一致性哈希（ketama 风格），增删分片时只有约 1/N 的 key 需要迁移
+type hashRing struct {
+	hashes []uint32
+	owners map[uint32]string
+}
+
+func newHashRing(shards []string, vnodes int) *hashRing {
+	h := &hashRing{
+		hashes: make([]uint32, 0, len(shards)*vnodes),
+		owners: make(map[uint32]string, len(shards)*vnodes),
+	}
+	for _, shard := range shards {
+		for i := 0; i < vnodes; i++ {
+			x := crc32.ChecksumIEEE([]byte(shard + "#" + strconv.Itoa(i)))
+			if _, ok := h.owners[x]; ok {
+				continue
+			}
+			h.owners[x] = shard
+			h.hashes = append(h.hashes, x)
+		}
+	}
+	sort.Slice(h.hashes, func(i, j int) bool { return h.hashes[i] < h.hashes[j] })
+	return h
+}
+
+// Get 返回 key 所在的分片，没有可用分片时返回空字符串
+func (h *hashRing) Get(key string) string {
+	if len(h.hashes) == 0 {
+		return ""
+	}
+	x := crc32.ChecksumIEEE([]byte(key))
+	i := sort.Search(len(h.hashes), func(i int) bool { return h.hashes[i] >= x })
+	if i == len(h.hashes) {
+		i = 0
+	}
+	return h.owners[h.hashes[i]]
+}
@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// RawGetter 可返回 JSON 编码值的 Cache，Scan / Get[T] 会优先使用，直接解码到调用方的类型
+type RawGetter interface {
+	GetRaw(key string) ([]byte, error)
+}
+
+// NativeCache 直接保存原始值（不经 JSON 编码）的 Cache，StoresNative 返回 true 时
+// Scan / Get[T] 先对 Get 的结果做类型断言，类型不符时再按 JSON 转换
+type NativeCache interface {
+	StoresNative() bool
+}
+
+// storesNative 判断 c（或其包装的底层缓存）是否直接保存原始值
+func storesNative(c Cache) bool {
+	nc, ok := unwrap(c).(NativeCache)
+	return ok && nc.StoresNative()
+}
+
+// getAs 读取 key 并转换为 T
+func getAs[T any](c Cache, key string) (T, error) {
+	if storesNative(c) {
+		v, err := c.Get(key)
+		if err != nil {
+			var zero T
+			return zero, err
+		}
+		return asType[T](v)
+	}
+
+	b, err := getRaw(c, key)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return decodeAs[T](b)
+}
+
+// getRaw 读取 key 的编码值，c 未实现 RawGetter 时将 Get 的结果重新编码
+func getRaw(c Cache, key string) ([]byte, error) {
+	if rg, ok := c.(RawGetter); ok {
+		return rg.GetRaw(key)
+	}
+	v, err := c.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, ErrDecode
+	}
+	return b, nil
+}
+
+// decodeAs 将编码值解码为 T
+func decodeAs[T any](b []byte) (T, error) {
+	var v T
+	if err := json.Unmarshal(b, &v); err != nil {
+		var zero T
+		return zero, fmt.Errorf("%w: %v", ErrTypeMismatch, err)
+	}
+	return v, nil
+}
+
+// asType 将 Get 系列返回的值（JSON 解码得到的 map、float64 等）转换为 T
+func asType[T any](v any) (T, error) {
+	if tv, ok := v.(T); ok {
+		return tv, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		var zero T
+		return zero, ErrDecode
+	}
+	return decodeAs[T](b)
+}
+
+// Scan 读取 c 中的 key 并按 JSON 解码到 dest（需为指针），结构体可以原样读回；
+// c 直接保存原始值且类型一致时直接赋值
+func Scan(c Cache, key string, dest any) error {
+	var b []byte
+	if storesNative(c) {
+		v, err := c.Get(key)
+		if err != nil {
+			return err
+		}
+		if dv := reflect.ValueOf(dest); dv.Kind() == reflect.Pointer && !dv.IsNil() && v != nil &&
+			reflect.TypeOf(v).AssignableTo(dv.Elem().Type()) {
+			dv.Elem().Set(reflect.ValueOf(v))
+			return nil
+		}
+		if b, err = json.Marshal(v); err != nil {
+			return ErrDecode
+		}
+	} else {
+		var err error
+		if b, err = getRaw(c, key); err != nil {
+			return err
+		}
+	}
+
+	if err := json.Unmarshal(b, dest); err != nil {
+		return fmt.Errorf("%w: %v", ErrTypeMismatch, err)
+	}
+	return nil
+}
+
+// GetScan 使用全局缓存读取 key 并解码到 dest，返回是否读取成功
+func GetScan(key string, dest any) bool {
+	c := current()
+	if c == nil {
+		return false
+	}
+	return Scan(c, key, dest) == nil
+}
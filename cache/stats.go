@@ -2,6 +2,8 @@ package cache
 
 import "sync/atomic"
 
+// Stats 缓存命中统计。适配器内部通过 Hit / Miss 等方法原子地更新计数，
+// 通过 Snapshot 读取，并发读写时计数准确
 type Stats struct {
 	Hits    uint64
 	Misses  uint64
@@ -9,7 +11,53 @@ type Stats struct {
 	Deletes uint64
 }
 
-func (s *Stats) hit()    { atomic.AddUint64(&s.Hits, 1) }
-func (s *Stats) miss()   { atomic.AddUint64(&s.Misses, 1) }
-func (s *Stats) set()    { atomic.AddUint64(&s.Sets, 1) }
-func (s *Stats) delete() { atomic.AddUint64(&s.Deletes, 1) }
+// StatsResetter 可重置统计的 Cache
+type StatsResetter interface {
+	ResetStats()
+}
+
+func (s *Stats) Hit()    { atomic.AddUint64(&s.Hits, 1) }
+func (s *Stats) Miss()   { atomic.AddUint64(&s.Misses, 1) }
+func (s *Stats) Set()    { atomic.AddUint64(&s.Sets, 1) }
+func (s *Stats) Delete() { atomic.AddUint64(&s.Deletes, 1) }
+
+// AddDeletes 增加 n 次删除，用于按前缀批量删除
+func (s *Stats) AddDeletes(n uint64) { atomic.AddUint64(&s.Deletes, n) }
+
+// Snapshot 原子地读取各项计数，返回当前统计的副本
+func (s *Stats) Snapshot() Stats {
+	return Stats{
+		Hits:    atomic.LoadUint64(&s.Hits),
+		Misses:  atomic.LoadUint64(&s.Misses),
+		Sets:    atomic.LoadUint64(&s.Sets),
+		Deletes: atomic.LoadUint64(&s.Deletes),
+	}
+}
+
+// Reset 将各项计数清零
+func (s *Stats) Reset() {
+	atomic.StoreUint64(&s.Hits, 0)
+	atomic.StoreUint64(&s.Misses, 0)
+	atomic.StoreUint64(&s.Sets, 0)
+	atomic.StoreUint64(&s.Deletes, 0)
+}
+
+// HitRatio 返回命中率 Hits / (Hits + Misses)，没有读取时返回 0
+func (s Stats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// ResetStats 重置全局缓存的统计，底层缓存未实现 StatsResetter 时不做任何事
+func ResetStats() {
+	c := current()
+	if c == nil {
+		return
+	}
+	if r, ok := unwrap(c).(StatsResetter); ok {
+		r.ResetStats()
+	}
+}
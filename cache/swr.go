@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Loader 单个 key 的加载函数
+type Loader func(key string) (any, error)
+
+// SWRConfig stale-while-revalidate 配置
+type SWRConfig struct {
+	// 新鲜期，超过后视为过期
+	TTL time.Duration
+
+	// 宽限期，过期后仍可返回旧值并在后台刷新；超过 TTL+Grace 后同步加载
+	Grace time.Duration
+
+	// 后台刷新失败回调
+	OnError func(key string, err error)
+}
+
+// SWROption 选项函数
+type SWROption func(*SWRConfig)
+
+// WithSWRTTL 设置新鲜期
+func WithSWRTTL(ttl time.Duration) SWROption {
+	return func(c *SWRConfig) {
+		c.TTL = ttl
+	}
+}
+
+// WithSWRGrace 设置宽限期
+func WithSWRGrace(grace time.Duration) SWROption {
+	return func(c *SWRConfig) {
+		c.Grace = grace
+	}
+}
+
+// WithSWROnError 设置后台刷新失败回调
+func WithSWROnError(fn func(key string, err error)) SWROption {
+	return func(c *SWRConfig) {
+		c.OnError = fn
+	}
+}
+
+// DefaultSWRConfig 默认配置
+func DefaultSWRConfig() SWRConfig {
+	return SWRConfig{
+		TTL:   time.Minute,
+		Grace: 10 * time.Second,
+	}
+}
+
+// SWR 在 Cache 之上提供 stale-while-revalidate 读取：
+// 过期后的宽限期内直接返回旧值，同时在后台通过 loader 刷新，避免过期瞬间的加载延迟尖刺。
+type SWR struct {
+	cache  Cache
+	loader Loader
+	config SWRConfig
+	sf     singleflight.Group
+}
+
+// NewSWR 创建 stale-while-revalidate 读取器
+func NewSWR(c Cache, loader Loader, opts ...SWROption) *SWR {
+	config := DefaultSWRConfig()
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return &SWR{cache: c, loader: loader, config: config}
+}
+
+// Get 读取 key：新鲜直接返回；宽限期内返回旧值并触发后台刷新；不存在时同步加载
+func (s *SWR) Get(key string) (any, error) {
+	if v, stale, err := GetEntry(s.cache, key); err == nil {
+		if stale {
+			s.refresh(key)
+		}
+		return v, nil
+	}
+
+	v, err, _ := s.sf.Do(key, func() (any, error) {
+		return s.load(key)
+	})
+	return v, err
+}
+
+// Set 写入 key 并重置新鲜期
+func (s *SWR) Set(key string, value any) {
+	SetEntry(s.cache, key, value, ExpirePolicy{SoftTTL: s.config.TTL, HardTTL: s.config.TTL + s.config.Grace})
+}
+
+// Delete 删除 key
+func (s *SWR) Delete(key string) {
+	s.cache.Delete(key)
+}
+
+func (s *SWR) load(key string) (any, error) {
+	v, err := s.loader(key)
+	if err != nil {
+		return nil, err
+	}
+	s.Set(key, v)
+	return v, nil
+}
+
+// refresh 后台刷新，同一 key 同时只有一个刷新在进行
+func (s *SWR) refresh(key string) {
+	ch := s.sf.DoChan(key, func() (any, error) {
+		return s.load(key)
+	})
+	go func() {
+		r := <-ch
+		if r.Err != nil && s.config.OnError != nil {
+			s.config.OnError(key, r.Err)
+		}
+	}()
+}
@@ -0,0 +1,283 @@
+package tiered
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jiajia556/tool-box/cache"
+	"github.com/jiajia556/tool-box/cache/memory"
+	"github.com/jiajia556/tool-box/cache/redis"
+)
+
+// TieredCache 两级缓存：进程内内存缓存（L1）在前，Redis（L2）在后。
+// 读取先查 L1，未命中再查 L2 并回填 L1；写入同时写两级，L1 使用较短的 TTL。
+// L1 不会收到其他进程的写入通知，L1TTL 即为跨进程可能读到旧值的最长时间。
+type TieredCache struct {
+	l1    cache.Cache
+	l2    cache.Cache
+	opts  Options
+	stats cache.Stats
+}
+
+type Options struct {
+	L1 memory.Options `json:"l1"`
+	L2 redis.Options  `json:"l2"`
+
+	// L1 条目的最长 TTL，写入的 TTL 更长或不过期时按 L1TTL 写入 L1；<=0 时使用 DefaultL1TTL
+	L1TTL time.Duration `json:"l1_ttl"`
+}
+
+// DefaultL1TTL 未配置 L1TTL 时 L1 条目的最长 TTL
+const DefaultL1TTL = time.Minute
+
+// NewTieredCache 创建两级缓存实例
+func NewTieredCache() cache.Cache {
+	return &TieredCache{}
+}
+
+// l1TTL 返回写入 L1 的 TTL
+func (t *TieredCache) l1TTL(ttl time.Duration) time.Duration {
+	if ttl <= 0 || ttl > t.opts.L1TTL {
+		return t.opts.L1TTL
+	}
+	return ttl
+}
+
+func (t *TieredCache) Get(key string) (any, error) {
+	return t.GetCtx(context.Background(), key)
+}
+
+func (t *TieredCache) GetCtx(ctx context.Context, key string) (any, error) {
+	if v, err := t.l1.GetCtx(ctx, key); err == nil {
+		t.stats.Hit()
+		return v, nil
+	}
+
+	v, err := t.l2.GetCtx(ctx, key)
+	if err != nil {
+		t.stats.Miss()
+		return nil, err
+	}
+	t.stats.Hit()
+	t.backfill(ctx, key, v)
+	return v, nil
+}
+
+// GetRaw 返回 JSON 编码的值，由调用方解码到具体类型
+func (t *TieredCache) GetRaw(key string) ([]byte, error) {
+	if b, err := getRaw(t.l1, key); err == nil {
+		t.stats.Hit()
+		return b, nil
+	}
+
+	b, err := getRaw(t.l2, key)
+	if err != nil {
+		t.stats.Miss()
+		return nil, err
+	}
+	t.stats.Hit()
+	// 原生存储的 L1 会原样保存 json.RawMessage，此时不回填
+	if nc, ok := t.l1.(cache.NativeCache); !ok || !nc.StoresNative() {
+		t.backfill(context.Background(), key, json.RawMessage(b))
+	}
+	return b, nil
+}
+
+func getRaw(c cache.Cache, key string) ([]byte, error) {
+	if rg, ok := c.(cache.RawGetter); ok {
+		return rg.GetRaw(key)
+	}
+	v, err := c.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// backfill 将 L2 读到的值写入 L1，TTL 不超过 L2 中的剩余时间
+func (t *TieredCache) backfill(ctx context.Context, key string, v any) {
+	ttl, ok, err := t.l2.TTLCtx(ctx, key)
+	if err != nil {
+		return
+	}
+	if !ok {
+		ttl = 0
+	}
+	_ = t.l1.SetCtx(ctx, key, v, t.l1TTL(ttl))
+}
+
+func (t *TieredCache) Set(key string, value any, ttl time.Duration) {
+	_ = t.SetCtx(context.Background(), key, value, ttl)
+}
+
+// SetCtx 先写 L2，成功后写 L1；写 L2 失败时删除 L1 中的旧值
+func (t *TieredCache) SetCtx(ctx context.Context, key string, value any, ttl time.Duration) error {
+	if err := t.l2.SetCtx(ctx, key, value, ttl); err != nil {
+		_ = t.l1.DeleteCtx(ctx, key)
+		return err
+	}
+	if ttl < 0 {
+		ttl = t.opts.L2.DefaultTTL
+	}
+	t.stats.Set()
+	return t.l1.SetCtx(ctx, key, value, t.l1TTL(ttl))
+}
+
+func (t *TieredCache) Delete(key string) {
+	_ = t.DeleteCtx(context.Background(), key)
+}
+
+func (t *TieredCache) DeleteCtx(ctx context.Context, key string) error {
+	err := t.l2.DeleteCtx(ctx, key)
+	_ = t.l1.DeleteCtx(ctx, key)
+	t.stats.Delete()
+	return err
+}
+
+func (t *TieredCache) Clear() {
+	t.l2.Clear()
+	t.l1.Clear()
+}
+
+// DeleteByPrefix 删除两级中以 prefix 开头的 key，返回 L2 删除的数量
+func (t *TieredCache) DeleteByPrefix(prefix string) int {
+	t.l1.DeleteByPrefix(prefix)
+	n := t.l2.DeleteByPrefix(prefix)
+	t.stats.AddDeletes(uint64(n))
+	return n
+}
+
+// Keys 列出 L2 中的 key，L1 只是 L2 的子集
+func (t *TieredCache) Keys(pattern string, limit int) ([]string, error) {
+	return t.l2.Keys(pattern, limit)
+}
+
+// TTL 返回 L2 中的剩余时间
+func (t *TieredCache) TTL(key string) (time.Duration, bool) {
+	return t.l2.TTL(key)
+}
+
+func (t *TieredCache) TTLCtx(ctx context.Context, key string) (time.Duration, bool, error) {
+	return t.l2.TTLCtx(ctx, key)
+}
+
+func (t *TieredCache) Exists(key string) bool {
+	ok, _ := t.ExistsCtx(context.Background(), key)
+	return ok
+}
+
+func (t *TieredCache) ExistsCtx(ctx context.Context, key string) (bool, error) {
+	if ok, err := t.l1.ExistsCtx(ctx, key); err == nil && ok {
+		return true, nil
+	}
+	return t.l2.ExistsCtx(ctx, key)
+}
+
+// Stats 返回两级合并的统计，L1 或 L2 命中均计为命中
+func (t *TieredCache) Stats() cache.Stats {
+	return t.stats.Snapshot()
+}
+
+// Describe 实现 cache.Describer，条目数取决于 L2，不统计
+func (t *TieredCache) Describe() cache.Description {
+	return cache.Description{Adapter: cache.AdapterTiered, Config: cache.MaskSecrets(t.opts), Items: -1}
+}
+
+// ResetStats 重置合并统计与两级各自的统计
+func (t *TieredCache) ResetStats() {
+	t.stats.Reset()
+	for _, c := range []cache.Cache{t.l1, t.l2} {
+		if r, ok := c.(cache.StatsResetter); ok {
+			r.ResetStats()
+		}
+	}
+}
+
+// CompareAndSwap 在 L2 上比较并写入，成功后更新 L1，失败时删除 L1 中可能过期的值
+func (t *TieredCache) CompareAndSwap(key string, old, new any, ttl time.Duration) (bool, error) {
+	return t.CompareAndSwapCtx(context.Background(), key, old, new, ttl)
+}
+
+func (t *TieredCache) CompareAndSwapCtx(ctx context.Context, key string, old, new any, ttl time.Duration) (bool, error) {
+	ok, err := t.l2.CompareAndSwapCtx(ctx, key, old, new, ttl)
+	if !ok {
+		_ = t.l1.DeleteCtx(ctx, key)
+		return ok, err
+	}
+	if ttl < 0 {
+		ttl = t.opts.L2.DefaultTTL
+	}
+	t.stats.Set()
+	_ = t.l1.SetCtx(ctx, key, new, t.l1TTL(ttl))
+	return true, err
+}
+
+func (t *TieredCache) GetDel(key string) (any, error) {
+	return t.GetDelCtx(context.Background(), key)
+}
+
+func (t *TieredCache) GetDelCtx(ctx context.Context, key string) (any, error) {
+	_ = t.l1.DeleteCtx(ctx, key)
+	v, err := t.l2.GetDelCtx(ctx, key)
+	if err == nil {
+		t.stats.Delete()
+	}
+	return v, err
+}
+
+func (t *TieredCache) GetSet(key string, value any, ttl time.Duration) (any, error) {
+	return t.GetSetCtx(context.Background(), key, value, ttl)
+}
+
+func (t *TieredCache) GetSetCtx(ctx context.Context, key string, value any, ttl time.Duration) (any, error) {
+	old, err := t.l2.GetSetCtx(ctx, key, value, ttl)
+	if err != nil && !errors.Is(err, cache.ErrNotFound) {
+		_ = t.l1.DeleteCtx(ctx, key)
+		return nil, err
+	}
+	if ttl < 0 {
+		ttl = t.opts.L2.DefaultTTL
+	}
+	t.stats.Set()
+	_ = t.l1.SetCtx(ctx, key, value, t.l1TTL(ttl))
+	return old, err
+}
+
+// Ping 检查两级缓存均可用
+func (t *TieredCache) Ping(ctx context.Context) error {
+	return errors.Join(t.l1.Ping(ctx), t.l2.Ping(ctx))
+}
+
+func (t *TieredCache) Close() error {
+	return errors.Join(t.l1.Close(), t.l2.Close())
+}
+
+func (t *TieredCache) Start(config any) error {
+	opts, ok := config.(Options)
+	if !ok {
+		return fmt.Errorf("tiered cache: invalid config")
+	}
+	if opts.L1TTL <= 0 {
+		opts.L1TTL = DefaultL1TTL
+	}
+	t.opts = opts
+
+	l1 := memory.NewMemoryCache()
+	if err := l1.Start(opts.L1); err != nil {
+		return fmt.Errorf("tiered cache: start l1: %w", err)
+	}
+	l2 := redis.NewRedisCache()
+	if err := l2.Start(opts.L2); err != nil {
+		_ = l1.Close()
+		return fmt.Errorf("tiered cache: start l2: %w", err)
+	}
+	t.l1, t.l2 = l1, l2
+	return nil
+}
+
+func init() {
+	cache.Register("tiered", NewTieredCache)
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/jiajia556/tool-box/utils"
+)
+
+// ByteSize 配置文件中的字节数，支持 "10MB"、"1.5GiB" 形式的字符串（按 1024 进制）或字节数
+type ByteSize int64
+
+// MarshalJSON 能无损表示时输出 "10 MB" 形式，否则输出字节数
+func (b ByteSize) MarshalJSON() ([]byte, error) {
+	s := utils.FormatBytes(int64(b))
+	if n, err := utils.ParseBytes(s); err == nil && n == int64(b) {
+		return json.Marshal(s)
+	}
+	return json.Marshal(int64(b))
+}
+
+func (b *ByteSize) UnmarshalJSON(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch x := v.(type) {
+	case float64:
+		*b = ByteSize(x)
+	case string:
+		parsed, err := utils.ParseBytes(x)
+		if err != nil {
+			return fmt.Errorf("config: invalid byte size %q: %w", x, err)
+		}
+		*b = ByteSize(parsed)
+	case nil:
+		*b = 0
+	default:
+		return fmt.Errorf("config: invalid byte size %s", data)
+	}
+	return nil
+}
+
+// Int64 返回字节数
+func (b ByteSize) Int64() int64 {
+	return int64(b)
+}
@@ -0,0 +1,352 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+var (
+	ErrNoGlobal      = errors.New("config: global instance is nil")
+	ErrInvalidTarget = errors.New("config: out must be a non-nil pointer")
+	ErrUnknownFormat = errors.New("config: unknown file format")
+)
+
+// Defaulter 绑定时在解码后调用，用于填充未配置字段的默认值
+type Defaulter interface {
+	SetDefaults()
+}
+
+// Validator 绑定时在填充默认值后调用，返回错误时绑定失败（热更新时保留旧值）
+type Validator interface {
+	Validate() error
+}
+
+type binding struct {
+	section string
+	typ     reflect.Type
+	raw     []byte
+
+	// store 在持锁时接收热更新解码出的新值（*T），notify 在释放锁后调用
+	store  func(v reflect.Value)
+	notify func(v reflect.Value)
+}
+
+// Store 配置存储：从 JSON / TOML 文件加载，按 section 绑定到结构体，支持热更新
+type Store struct {
+	path      string
+	resolvers map[string]SecretResolver
+
+	mu       sync.Mutex
+	data     map[string]any
+	modTime  time.Time
+	bindings []*binding
+}
+
+// Load 加载配置文件，格式由扩展名决定（.json / .toml）。
+// 字符串值中的 ${ENV} / ${ENV:-default} 会替换为环境变量，整值为 "vault:mount/path#key" 等
+// 已注册 scheme 的引用会替换为解析出的密钥；每次加载与 Reload 都会重新解析，
+// 同一引用在一次加载内只解析一次。
+func Load(path string, opts ...Option) (*Store, error) {
+	s := &Store{
+		path:      path,
+		resolvers: map[string]SecretResolver{"vault": envVaultResolver},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	data, modTime, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	s.data = data
+	s.modTime = modTime
+	return s, nil
+}
+
+func (s *Store) read() (map[string]any, time.Time, error) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("config: %w", err)
+	}
+	content, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("config: %w", err)
+	}
+
+	data := make(map[string]any)
+	switch strings.ToLower(filepath.Ext(s.path)) {
+	case ".json":
+		err = json.Unmarshal(content, &data)
+	case ".toml":
+		_, err = toml.Decode(string(content), &data)
+	default:
+		return nil, time.Time{}, fmt.Errorf("%w: %s", ErrUnknownFormat, s.path)
+	}
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("config: parse %s: %w", s.path, err)
+	}
+
+	sc := &secrets{ctx: context.Background(), resolvers: s.resolvers, cache: make(map[string]string)}
+	if _, err := sc.resolve(data, ""); err != nil {
+		return nil, time.Time{}, fmt.Errorf("config: resolve secrets in %s: %w", s.path, err)
+	}
+	return data, info.ModTime(), nil
+}
+
+// Bind 将 section（支持 "a.b" 形式的嵌套路径，空字符串表示整个文件）解码到 out，
+// 随后依次调用 SetDefaults 与 Validate。
+// out 只在 Bind 时写入，之后不会被修改；热更新时该 section 内容变化会解码为新值，
+// 以与 out 相同类型的指针传给 onChange。需要随时读取最新值时使用 BindValue。
+func (s *Store) Bind(section string, out any, onChange ...func(v any)) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return ErrInvalidTarget
+	}
+
+	var notify func(reflect.Value)
+	if len(onChange) > 0 {
+		notify = func(v reflect.Value) {
+			for _, fn := range onChange {
+				fn(v.Interface())
+			}
+		}
+	}
+	return s.bind(section, rv.Type().Elem(), func(v reflect.Value) {
+		rv.Elem().Set(v.Elem())
+	}, nil, notify)
+}
+
+// Value 热更新的配置值，Load 返回当前值，并发安全
+type Value[T any] struct {
+	p atomic.Pointer[T]
+}
+
+// Load 返回当前配置；返回值与其他读取方共享，只读
+func (v *Value[T]) Load() *T {
+	return v.p.Load()
+}
+
+// BindValue 将 section 解码为 T 并返回 Value，热更新时原子替换为新值并调用 onChange；
+// 解码与校验规则同 Bind。s 为 nil 时使用全局配置
+func BindValue[T any](s *Store, section string, onChange ...func(v *T)) (*Value[T], error) {
+	if s == nil {
+		if s = Global(); s == nil {
+			return nil, ErrNoGlobal
+		}
+	}
+
+	val := &Value[T]{}
+	store := func(v reflect.Value) {
+		val.p.Store(v.Interface().(*T))
+	}
+	var notify func(reflect.Value)
+	if len(onChange) > 0 {
+		notify = func(v reflect.Value) {
+			for _, fn := range onChange {
+				fn(v.Interface().(*T))
+			}
+		}
+	}
+	if err := s.bind(section, reflect.TypeFor[T](), store, store, notify); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// bind 解码 section 并交给 init，随后登记热更新
+func (s *Store) bind(section string, t reflect.Type, init, store, notify func(reflect.Value)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	raw, err := sectionJSON(s.data, section)
+	if err != nil {
+		return err
+	}
+	v, err := decode(raw, t)
+	if err != nil {
+		return fmt.Errorf("config: bind %q: %w", section, err)
+	}
+	init(v)
+
+	s.bindings = append(s.bindings, &binding{section: section, typ: t, raw: raw, store: store, notify: notify})
+	return nil
+}
+
+// Reload 重新读取配置文件，内容有变化的绑定会被更新并触发回调；
+// 解码或校验失败的绑定保留旧值，错误合并后返回。
+func (s *Store) Reload() error {
+	data, modTime, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.data = data
+	s.modTime = modTime
+
+	var errs []error
+	var callbacks []func()
+	for _, b := range s.bindings {
+		raw, err := sectionJSON(data, b.section)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if string(raw) == string(b.raw) {
+			continue
+		}
+
+		v, err := decode(raw, b.typ)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("config: rebind %q: %w", b.section, err))
+			continue
+		}
+		b.raw = raw
+		if b.store != nil {
+			b.store(v)
+		}
+		if notify := b.notify; notify != nil {
+			callbacks = append(callbacks, func() { notify(v) })
+		}
+	}
+	s.mu.Unlock()
+
+	for _, fn := range callbacks {
+		fn()
+	}
+	return errors.Join(errs...)
+}
+
+// Watch 按 interval 轮询文件修改时间，变化时调用 Reload，直到 ctx 结束；
+// onError 接收重新加载时的错误，可为 nil。
+func (s *Store) Watch(ctx context.Context, interval time.Duration, onError func(error)) {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				info, err := os.Stat(s.path)
+				if err != nil {
+					if onError != nil {
+						onError(fmt.Errorf("config: %w", err))
+					}
+					continue
+				}
+
+				s.mu.Lock()
+				changed := !info.ModTime().Equal(s.modTime)
+				s.mu.Unlock()
+				if !changed {
+					continue
+				}
+
+				if err := s.Reload(); err != nil && onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+}
+
+// sectionJSON 取出 section 并编码为 JSON，不存在时返回 nil
+func sectionJSON(data map[string]any, section string) ([]byte, error) {
+	var v any = data
+	if section != "" {
+		for _, part := range strings.Split(section, ".") {
+			m, ok := v.(map[string]any)
+			if !ok {
+				return nil, nil
+			}
+			if v, ok = m[part]; !ok {
+				return nil, nil
+			}
+		}
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("config: encode %q: %w", section, err)
+	}
+	return raw, nil
+}
+
+// decode 解码到 t 类型的新值，并应用默认值与校验，返回指向新值的指针
+func decode(raw []byte, t reflect.Type) (reflect.Value, error) {
+	ptr := reflect.New(t)
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, ptr.Interface()); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+	if d, ok := ptr.Interface().(Defaulter); ok {
+		d.SetDefaults()
+	}
+	if v, ok := ptr.Interface().(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+	return ptr, nil
+}
+
+var (
+	globalMu sync.RWMutex
+	global   *Store
+)
+
+// Init 加载配置文件作为全局配置
+func Init(path string, opts ...Option) error {
+	s, err := Load(path, opts...)
+	if err != nil {
+		return err
+	}
+
+	globalMu.Lock()
+	global = s
+	globalMu.Unlock()
+	return nil
+}
+
+// Global 返回全局配置
+func Global() *Store {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+	return global
+}
+
+// Bind 使用全局配置绑定 section
+func Bind(section string, out any, onChange ...func(v any)) error {
+	s := Global()
+	if s == nil {
+		return ErrNoGlobal
+	}
+	return s.Bind(section, out, onChange...)
+}
+
+// Reload 重新加载全局配置
+func Reload() error {
+	s := Global()
+	if s == nil {
+		return ErrNoGlobal
+	}
+	return s.Reload()
+}
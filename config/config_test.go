@@ -0,0 +1,133 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jiajia556/tool-box/log"
+)
+
+func TestStore_BindAndReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.toml")
+	write := func(content string) {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+	write(`
+[cache]
+adapter = "redis"
+default_ttl = "10m"
+
+[log]
+level = "debug"
+`)
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	changed := 0
+	cacheVal, err := BindValue(s, "cache", func(*CacheConfig) { changed++ })
+	if err != nil {
+		t.Fatalf("Bind cache: %v", err)
+	}
+	cacheConf := cacheVal.Load()
+	if cacheConf.Redis.Addr != "localhost:6379" || cacheConf.DefaultTTL.Std() != 10*time.Minute {
+		t.Fatalf("unexpected cache config: %+v", cacheConf)
+	}
+
+	var logConf LogConfig
+	if err := s.Bind("log", &logConf); err != nil {
+		t.Fatalf("Bind log: %v", err)
+	}
+	if lc := logConf.LogConfig(); lc.Level != log.LevelDebug || lc.Output != "file" || !lc.Caller {
+		t.Fatalf("unexpected log config: %+v", lc)
+	}
+
+	// 校验失败时保留旧值
+	write(`
+[cache]
+adapter = "unknown"
+`)
+	if err := s.Reload(); err == nil {
+		t.Fatalf("expected validation error on reload")
+	}
+	if cacheVal.Load().Adapter != "redis" || changed != 0 {
+		t.Fatalf("expected old value to be kept, got %+v changed=%d", cacheVal.Load(), changed)
+	}
+
+	write(`
+[cache]
+adapter = "memory"
+`)
+	if err := s.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if cacheVal.Load().Adapter != "memory" || changed != 1 {
+		t.Fatalf("expected rebind, got %+v changed=%d", cacheVal.Load(), changed)
+	}
+	// 已取出的值不会被热更新修改
+	if cacheConf.Adapter != "redis" {
+		t.Fatalf("previously loaded value was modified: %+v", cacheConf)
+	}
+}
+
+func TestStore_Secrets(t *testing.T) {
+	requests := 0
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/v1/secret/data/app/redis" || r.Header.Get("X-Vault-Token") != "root" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"data":{"data":{"password":"pass-%d"}}}`, requests)
+	}))
+	defer vault.Close()
+
+	t.Setenv("APP_REDIS_ADDR", "10.0.0.1:6379")
+	path := filepath.Join(t.TempDir(), "app.json")
+	content := `{"cache":{"adapter":"redis","redis":{"addr":"${APP_REDIS_ADDR}","username":"${APP_REDIS_USER:-default}","password":"vault:secret/app/redis#password"}}}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	s, err := Load(path, WithSecretResolver("vault", NewVaultResolver(VaultConfig{Addr: vault.URL, Token: "root"})))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	var conf CacheConfig
+	var latest *CacheConfig
+	changed := 0
+	if err := s.Bind("cache", &conf, func(v any) { latest = v.(*CacheConfig); changed++ }); err != nil {
+		t.Fatalf("Bind: %v", err)
+	}
+	if conf.Redis.Addr != "10.0.0.1:6379" || conf.Redis.Username != "default" || conf.Redis.Password != "pass-1" {
+		t.Fatalf("unexpected resolved config: %+v", conf.Redis)
+	}
+
+	// Reload 重新解析，密钥轮换后触发回调
+	if err := s.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if changed != 1 || latest.Redis.Password != "pass-2" {
+		t.Fatalf("expected rotated secret in callback, got changed=%d", changed)
+	}
+	if conf.Redis.Password != "pass-1" {
+		t.Fatalf("out should not be modified after Bind, got %q", conf.Redis.Password)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"cache":{"redis":{"addr":"${APP_MISSING}"}}}`), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err := s.Reload(); !errors.Is(err, ErrSecretNotFound) {
+		t.Fatalf("expected ErrSecretNotFound, got %v", err)
+	}
+}
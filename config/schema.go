@@ -0,0 +1,275 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jiajia556/tool-box/cache"
+	cachefile "github.com/jiajia556/tool-box/cache/file"
+	cacheredis "github.com/jiajia556/tool-box/cache/redis"
+	"github.com/jiajia556/tool-box/locker"
+	lockerredis "github.com/jiajia556/tool-box/locker/redis"
+	"github.com/jiajia556/tool-box/log"
+)
+
+// RedisConfig Redis 连接配置
+type RedisConfig struct {
+	Addr     string   `json:"addr"`
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	DB       int      `json:"db"`
+	Timeout  Duration `json:"timeout"`
+
+	// Redis Cluster 种子节点，非空时忽略 addr 与 db；目前仅 cache 支持
+	Addrs []string `json:"addrs"`
+
+	// 哨兵模式的主节点名称与哨兵地址，均非空时忽略 addr；目前仅 cache 支持
+	MasterName       string   `json:"master_name"`
+	SentinelAddrs    []string `json:"sentinel_addrs"`
+	SentinelPassword string   `json:"sentinel_password"`
+}
+
+// CacheConfig cache 包配置，例如：
+//
+//	[cache]
+//	adapter = "redis"
+//	default_ttl = "10m"
+//	prefix = "app"
+//	redis = { addr = "127.0.0.1:6379" }
+type CacheConfig struct {
+	Adapter    string      `json:"adapter"`
+	DefaultTTL Duration    `json:"default_ttl"`
+	Prefix     string      `json:"prefix"`
+	Redis      RedisConfig `json:"redis"`
+	Dir        string      `json:"dir"`
+}
+
+func (c *CacheConfig) SetDefaults() {
+	if c.Adapter == "" {
+		c.Adapter = cache.AdapterMemory
+	}
+	if c.Adapter == cache.AdapterRedis && c.Redis.Addr == "" && len(c.Redis.Addrs) == 0 && len(c.Redis.SentinelAddrs) == 0 {
+		c.Redis.Addr = "localhost:6379"
+	}
+	if c.Adapter == cache.AdapterFile && c.Dir == "" {
+		c.Dir = "./cache"
+	}
+}
+
+func (c *CacheConfig) Validate() error {
+	switch c.Adapter {
+	case cache.AdapterMemory, cache.AdapterRedis, cache.AdapterFile:
+	default:
+		return fmt.Errorf("config: cache: unknown adapter %q", c.Adapter)
+	}
+	if c.DefaultTTL < 0 {
+		return fmt.Errorf("config: cache: default_ttl must not be negative")
+	}
+	return nil
+}
+
+// AdapterConfig 返回传给 cache.Init 的适配器配置
+func (c CacheConfig) AdapterConfig() any {
+	switch c.Adapter {
+	case cache.AdapterRedis:
+		return cacheredis.Options{
+			Addr:             c.Redis.Addr,
+			Addrs:            c.Redis.Addrs,
+			MasterName:       c.Redis.MasterName,
+			SentinelAddrs:    c.Redis.SentinelAddrs,
+			SentinelPassword: c.Redis.SentinelPassword,
+			Username:         c.Redis.Username,
+			Password:         c.Redis.Password,
+			DB:               c.Redis.DB,
+			DefaultTTL:       c.DefaultTTL.Std(),
+			Prefix:           c.Prefix,
+		}
+	case cache.AdapterFile:
+		return cachefile.Options{Dir: c.Dir}
+	}
+	return nil
+}
+
+// Init 按配置初始化全局缓存
+func (c CacheConfig) Init() error {
+	return cache.Init(c.Adapter, c.AdapterConfig())
+}
+
+// LockerConfig locker 包配置
+type LockerConfig struct {
+	Adapter      string      `json:"adapter"`
+	Redis        RedisConfig `json:"redis"`
+	TTL          Duration    `json:"ttl"`
+	Timeout      Duration    `json:"timeout"`
+	PollInterval Duration    `json:"poll_interval"`
+	HoldWarning  Duration    `json:"hold_warning"`
+	SlowAcquire  Duration    `json:"slow_acquire"`
+}
+
+func (c *LockerConfig) SetDefaults() {
+	def := locker.DefaultConfig()
+	if c.Adapter == "" {
+		c.Adapter = locker.AdapterMemory
+	}
+	if c.Adapter == locker.AdapterRedis && c.Redis.Addr == "" {
+		c.Redis.Addr = "localhost:6379"
+	}
+	if c.TTL == 0 {
+		c.TTL = Duration(def.TTL)
+	}
+	if c.Timeout == 0 {
+		c.Timeout = Duration(def.Timeout)
+	}
+	if c.PollInterval == 0 {
+		c.PollInterval = Duration(def.PollInterval)
+	}
+}
+
+func (c *LockerConfig) Validate() error {
+	switch c.Adapter {
+	case locker.AdapterMemory, locker.AdapterRedis:
+	default:
+		return fmt.Errorf("config: locker: unknown adapter %q", c.Adapter)
+	}
+	if c.TTL <= 0 || c.Timeout <= 0 || c.PollInterval <= 0 {
+		return fmt.Errorf("config: locker: ttl, timeout and poll_interval must be positive")
+	}
+	return nil
+}
+
+// AdapterConfig 返回传给 locker.Init 的适配器配置
+func (c LockerConfig) AdapterConfig() any {
+	if c.Adapter == locker.AdapterRedis {
+		return lockerredis.Options{
+			Addr:     c.Redis.Addr,
+			Username: c.Redis.Username,
+			Password: c.Redis.Password,
+			DB:       c.Redis.DB,
+			Timeout:  c.Redis.Timeout.Std(),
+		}
+	}
+	return nil
+}
+
+// Options 返回创建锁时使用的选项
+func (c LockerConfig) Options() []locker.Option {
+	return []locker.Option{
+		locker.WithTTL(c.TTL.Std()),
+		locker.WithTimeout(c.Timeout.Std()),
+		locker.WithPollInterval(c.PollInterval.Std()),
+		locker.WithHoldWarning(c.HoldWarning.Std()),
+		locker.WithSlowAcquire(c.SlowAcquire.Std()),
+	}
+}
+
+// Init 按配置初始化全局锁管理器
+func (c LockerConfig) Init() error {
+	return locker.Init(c.Adapter, c.AdapterConfig())
+}
+
+// LogConfig log 包配置，级别使用字符串（debug / info / warn / error / fatal / panic）
+type LogConfig struct {
+	Level      string `json:"level"`
+	Output     string `json:"output"`
+	Encoder    string `json:"encoder"`
+	Schema     string `json:"schema"`
+	Identifier string `json:"identifier"`
+	Caller     *bool  `json:"caller"`
+	TimeFormat string `json:"time_format"`
+	File       struct {
+		Dir       string `json:"dir"`
+		MaxSize   int    `json:"max_size"`
+		MaxAge    int    `json:"max_age"`
+		MaxBackup int    `json:"max_backup"`
+		Compress  bool   `json:"compress"`
+	} `json:"file"`
+}
+
+func (c *LogConfig) SetDefaults() {
+	def := log.DefaultConfig()
+	if c.Level == "" {
+		c.Level = strings.ToLower(def.Level.String())
+	}
+	if c.Output == "" {
+		c.Output = def.Output
+	}
+	if c.Encoder == "" {
+		c.Encoder = def.Encoder
+	}
+	if c.Caller == nil {
+		c.Caller = &def.Caller
+	}
+	if c.TimeFormat == "" {
+		c.TimeFormat = def.TimeFormat
+	}
+	if c.File.Dir == "" {
+		c.File.Dir = def.File.Dir
+	}
+	if c.File.MaxSize == 0 {
+		c.File.MaxSize = def.File.MaxSize
+	}
+	if c.File.MaxAge == 0 {
+		c.File.MaxAge = def.File.MaxAge
+	}
+	if c.File.MaxBackup == 0 {
+		c.File.MaxBackup = def.File.MaxBackup
+	}
+}
+
+func (c *LogConfig) Validate() error {
+	if _, ok := parseLevel(c.Level); !ok {
+		return fmt.Errorf("config: log: unknown level %q", c.Level)
+	}
+	switch c.Output {
+	case "stdout", "stderr", "file", "combined", "journald", "eventlog":
+	default:
+		return fmt.Errorf("config: log: unknown output %q", c.Output)
+	}
+	switch c.Encoder {
+	case "text", "json", "pretty":
+	default:
+		return fmt.Errorf("config: log: unknown encoder %q", c.Encoder)
+	}
+	switch c.Schema {
+	case "", "ecs", "otel":
+	default:
+		return fmt.Errorf("config: log: unknown schema %q", c.Schema)
+	}
+	return nil
+}
+
+// LogConfig 转换为 log.Config
+func (c LogConfig) LogConfig() log.Config {
+	config := log.DefaultConfig()
+	config.Level, _ = parseLevel(c.Level)
+	config.Output = c.Output
+	config.Encoder = c.Encoder
+	config.Schema = c.Schema
+	config.Identifier = c.Identifier
+	if c.Caller != nil {
+		config.Caller = *c.Caller
+	}
+	config.TimeFormat = c.TimeFormat
+	config.File = log.FileConfig{
+		Dir:       c.File.Dir,
+		MaxSize:   c.File.MaxSize,
+		MaxAge:    c.File.MaxAge,
+		MaxBackup: c.File.MaxBackup,
+		Compress:  c.File.Compress,
+	}
+	return config
+}
+
+// Init 按配置初始化全局日志记录器（需导入 log/std 等适配器）
+func (c LogConfig) Init(adapter ...string) error {
+	return log.Init(c.LogConfig(), adapter...)
+}
+
+func parseLevel(s string) (log.Level, bool) {
+	for l := log.LevelDebug; l <= log.LevelPanic; l++ {
+		if strings.EqualFold(l.String(), s) {
+			return l, true
+		}
+	}
+	return log.LevelInfo, false
+}
@@ -0,0 +1,208 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var ErrSecretNotFound = errors.New("config: secret not found")
+
+// SecretResolver 解析 "scheme:ref" 形式的密钥引用，ref 不含 scheme 前缀
+type SecretResolver func(ctx context.Context, ref string) (string, error)
+
+// Option 选项函数
+type Option func(*Store)
+
+// WithSecretResolver 注册密钥解析器，值为 "scheme:ref" 的字符串会被替换为解析结果；
+// 内置 "vault"（使用 VAULT_ADDR / VAULT_TOKEN 环境变量），可用同名 scheme 覆盖
+func WithSecretResolver(scheme string, r SecretResolver) Option {
+	return func(s *Store) {
+		s.resolvers[scheme] = r
+	}
+}
+
+// envRe 匹配 ${VAR} 与 ${VAR:-default}
+var envRe = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// secrets 一次加载内的密钥解析，同一引用只解析一次；每次 Reload 使用新的实例以获取轮换后的值
+type secrets struct {
+	ctx       context.Context
+	resolvers map[string]SecretResolver
+	cache     map[string]string
+}
+
+// resolve 递归替换配置中的 ${ENV} 与 "scheme:ref" 引用，path 为当前值的位置，用于错误信息
+func (sc *secrets) resolve(v any, path string) (any, error) {
+	switch x := v.(type) {
+	case map[string]any:
+		for k, item := range x {
+			r, err := sc.resolve(item, joinPath(path, k))
+			if err != nil {
+				return nil, err
+			}
+			x[k] = r
+		}
+	case []any:
+		for i, item := range x {
+			r, err := sc.resolve(item, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			x[i] = r
+		}
+	case []map[string]any: // TOML 的表数组
+		for i, item := range x {
+			if _, err := sc.resolve(item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return nil, err
+			}
+		}
+	case string:
+		r, err := sc.resolveString(x)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return r, nil
+	}
+	return v, nil
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func (sc *secrets) resolveString(s string) (string, error) {
+	if scheme, ref, ok := strings.Cut(s, ":"); ok {
+		if r, ok := sc.resolvers[scheme]; ok {
+			if v, ok := sc.cache[s]; ok {
+				return v, nil
+			}
+			v, err := r(sc.ctx, ref)
+			if err != nil {
+				return "", err
+			}
+			sc.cache[s] = v
+			return v, nil
+		}
+	}
+
+	var err error
+	out := envRe.ReplaceAllStringFunc(s, func(m string) string {
+		sub := envRe.FindStringSubmatch(m)
+		if v, ok := os.LookupEnv(sub[1]); ok {
+			return v
+		}
+		if strings.Contains(m, ":-") {
+			return sub[2]
+		}
+		if err == nil {
+			err = fmt.Errorf("%w: env %s is not set", ErrSecretNotFound, sub[1])
+		}
+		return m
+	})
+	return out, err
+}
+
+// VaultConfig Vault KV 引擎配置
+type VaultConfig struct {
+	Addr      string        // 如 https://vault.example.com:8200
+	Token     string        // X-Vault-Token
+	KVVersion int           // KV 引擎版本，默认 2
+	Timeout   time.Duration // 请求超时，默认 5s
+}
+
+// NewVaultResolver 创建 Vault 密钥解析器，引用格式为 "mount/path#key"，如 vault:secret/myapp/db#password；
+// KV v2 会自动在 mount 后插入 "data/"。
+func NewVaultResolver(config VaultConfig) SecretResolver {
+	if config.KVVersion == 0 {
+		config.KVVersion = 2
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 5 * time.Second
+	}
+	client := &http.Client{Timeout: config.Timeout}
+
+	return func(ctx context.Context, ref string) (string, error) {
+		path, key, ok := strings.Cut(ref, "#")
+		if !ok || path == "" || key == "" {
+			return "", fmt.Errorf("config: invalid vault reference %q, expect \"mount/path#key\"", ref)
+		}
+		if config.Addr == "" {
+			return "", fmt.Errorf("config: vault address is not configured for %q", path)
+		}
+
+		data, err := readVault(ctx, client, config, path)
+		if err != nil {
+			return "", err
+		}
+		v, ok := data[key]
+		if !ok {
+			return "", fmt.Errorf("%w: vault %s#%s", ErrSecretNotFound, path, key)
+		}
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+		return fmt.Sprint(v), nil
+	}
+}
+
+// envVaultResolver 默认的 vault 解析器，在解析时读取 VAULT_ADDR / VAULT_TOKEN
+func envVaultResolver(ctx context.Context, ref string) (string, error) {
+	return NewVaultResolver(VaultConfig{
+		Addr:  os.Getenv("VAULT_ADDR"),
+		Token: os.Getenv("VAULT_TOKEN"),
+	})(ctx, ref)
+}
+
+func readVault(ctx context.Context, client *http.Client, config VaultConfig, path string) (map[string]any, error) {
+	path = strings.Trim(path, "/")
+	if config.KVVersion == 2 {
+		mount, rest, _ := strings.Cut(path, "/")
+		path = mount + "/data/" + rest
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(config.Addr, "/")+"/v1/"+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("config: vault: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", config.Token)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("config: vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("config: vault: %w", err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%w: vault %s", ErrSecretNotFound, path)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("config: vault %s: status %d", path, resp.StatusCode)
+	}
+
+	var out struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("config: vault %s: %w", path, err)
+	}
+	if config.KVVersion == 2 {
+		data, _ := out.Data["data"].(map[string]any)
+		return data, nil
+	}
+	return out.Data, nil
+}
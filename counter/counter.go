@@ -0,0 +1,217 @@
+package counter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNoGlobal      = errors.New("counter: global instance is nil")
+	ErrInvalidConfig = errors.New("counter: invalid config")
+	ErrNotFound      = errors.New("counter: member not found")
+	ErrInvalidWindow = errors.New("counter: window must be a whole number of seconds and at least 1s")
+)
+
+// Window 计数窗口
+type Window time.Duration
+
+const (
+	Minute = Window(time.Minute)
+	Hour   = Window(time.Hour)
+	Day    = Window(24 * time.Hour)
+)
+
+// Validate 检查窗口是否为不小于 1 秒的整秒数，存储 key 按秒划分窗口
+func (w Window) Validate() error {
+	d := time.Duration(w)
+	if d < time.Second || d%time.Second != 0 {
+		return fmt.Errorf("%w: %v", ErrInvalidWindow, d)
+	}
+	return nil
+}
+
+// BucketStart 返回时间 t 所在窗口的起始时间（UTC 对齐）
+func (w Window) BucketStart(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Duration(w))
+}
+
+// BucketKey 返回 key 在时间 t 所在窗口的存储 key，例如 "pv:60:28512345"；w 须通过 Validate
+func (w Window) BucketKey(key string, t time.Time) string {
+	d := time.Duration(w)
+	return key + ":" + strconv.FormatInt(int64(d/time.Second), 10) + ":" + strconv.FormatInt(t.Unix()/int64(d/time.Second), 10)
+}
+
+// Entry 排行榜条目，Rank 从 1 开始
+type Entry struct {
+	Member string  `json:"member"`
+	Score  float64 `json:"score"`
+	Rank   int64   `json:"rank"`
+}
+
+// Counter 按时间窗口计数，过期窗口自动清理
+type Counter interface {
+	// 当前窗口计数增加 delta，返回增加后的值
+	Incr(ctx context.Context, key string, window Window, delta int64) (int64, error)
+
+	// 当前窗口的计数
+	Get(ctx context.Context, key string, window Window) (int64, error)
+
+	// 最近 n 个窗口（含当前窗口）的计数之和
+	Sum(ctx context.Context, key string, window Window, n int) (int64, error)
+}
+
+// Leaderboard 排行榜（按分数从高到低排名，分数相同按成员名逆序，与 Redis ZREVRANGE 一致）
+type Leaderboard interface {
+	// 成员分数增加 delta，返回新分数
+	IncrScore(ctx context.Context, board, member string, delta float64) (float64, error)
+
+	// 设置成员分数
+	SetScore(ctx context.Context, board, member string, score float64) error
+
+	// 成员分数；成员不存在返回 ErrNotFound
+	Score(ctx context.Context, board, member string) (float64, error)
+
+	// 成员排名；成员不存在返回 ErrNotFound
+	Rank(ctx context.Context, board, member string) (Entry, error)
+
+	// 前 n 名
+	Top(ctx context.Context, board string, n int) ([]Entry, error)
+
+	// 成员前后各 n 名（含成员本身）
+	Around(ctx context.Context, board, member string, n int) ([]Entry, error)
+
+	// 移除成员
+	Remove(ctx context.Context, board string, members ...string) error
+
+	// 删除排行榜
+	Clear(ctx context.Context, board string) error
+}
+
+// Store 计数与排行榜存储
+type Store interface {
+	Counter
+	Leaderboard
+
+	// 关闭（释放资源）
+	Close() error
+}
+
+// Instance 适配器工厂函数
+type Instance func(config any) (Store, error)
+
+var (
+	adaptersMu sync.RWMutex
+	adapters   = make(map[string]Instance)
+)
+
+const (
+	AdapterMemory = "memory"
+	AdapterRedis  = "redis"
+)
+
+var (
+	globalMu sync.RWMutex
+	global   Store
+)
+
+// Register 注册适配器
+func Register(name string, adapter Instance) {
+	adaptersMu.Lock()
+	defer adaptersMu.Unlock()
+
+	if adapter == nil {
+		panic("counter: Register adapter is nil")
+	}
+	if _, ok := adapters[name]; ok {
+		panic("counter: Register called twice for adapter " + name)
+	}
+	adapters[name] = adapter
+}
+
+// New 按适配器名称创建存储
+func New(adapterName string, config any) (Store, error) {
+	adaptersMu.RLock()
+	instanceFunc, ok := adapters[adapterName]
+	adaptersMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("counter: unknown adapter name %q (forgot to import?)", adapterName)
+	}
+	return instanceFunc(config)
+}
+
+// Init 初始化全局存储
+// 参数 config 为适配器配置：
+// - "memory": 接受 memory.Options 结构体或 nil
+// - "redis": 接受 redis.Options 结构体
+func Init(adapterName string, config any) error {
+	s, err := New(adapterName, config)
+	if err != nil {
+		return err
+	}
+
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	global = s
+	return nil
+}
+
+// Global 返回全局存储
+func Global() Store {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+	return global
+}
+
+// Incr 使用全局存储计数
+func Incr(ctx context.Context, key string, window Window, delta int64) (int64, error) {
+	s := Global()
+	if s == nil {
+		return 0, ErrNoGlobal
+	}
+	return s.Incr(ctx, key, window, delta)
+}
+
+// Sum 使用全局存储求最近 n 个窗口之和
+func Sum(ctx context.Context, key string, window Window, n int) (int64, error) {
+	s := Global()
+	if s == nil {
+		return 0, ErrNoGlobal
+	}
+	return s.Sum(ctx, key, window, n)
+}
+
+// IncrScore 使用全局存储累加排行榜分数
+func IncrScore(ctx context.Context, board, member string, delta float64) (float64, error) {
+	s := Global()
+	if s == nil {
+		return 0, ErrNoGlobal
+	}
+	return s.IncrScore(ctx, board, member, delta)
+}
+
+// Top 使用全局存储查询前 n 名
+func Top(ctx context.Context, board string, n int) ([]Entry, error) {
+	s := Global()
+	if s == nil {
+		return nil, ErrNoGlobal
+	}
+	return s.Top(ctx, board, n)
+}
+
+// Close 关闭全局存储
+func Close() error {
+	globalMu.Lock()
+	s := global
+	global = nil
+	globalMu.Unlock()
+
+	if s == nil {
+		return nil
+	}
+	return s.Close()
+}
@@ -0,0 +1,255 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jiajia556/tool-box/counter"
+	"github.com/jiajia556/tool-box/utils"
+)
+
+// Options 内存存储配置
+type Options struct {
+	// 时间源，nil 表示使用系统时间；测试中可传入 utils.FakeClock 控制窗口切换
+	Clock utils.Clock `json:"-"`
+
+	// 每个 key 保留的窗口数，默认 60
+	Retention int `json:"retention"`
+
+	// 过期窗口清理间隔，默认 1 分钟
+	CleanupInterval time.Duration `json:"cleanup_interval"`
+}
+
+type bucket struct {
+	value    int64
+	expireAt time.Time
+}
+
+// MemoryStore 单进程内存实现，排行榜查询时排序，适合测试与小规模数据
+type MemoryStore struct {
+	opts  Options
+	clock utils.Clock
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	boards  map[string]map[string]float64
+
+	stop chan struct{}
+	once sync.Once
+}
+
+// NewMemoryStore 创建内存存储
+func NewMemoryStore(config any) (counter.Store, error) {
+	var opts Options
+	if config != nil {
+		o, ok := config.(Options)
+		if !ok {
+			return nil, fmt.Errorf("%w: expect memory.Options", counter.ErrInvalidConfig)
+		}
+		opts = o
+	}
+	if opts.Retention <= 0 {
+		opts.Retention = 60
+	}
+	if opts.CleanupInterval <= 0 {
+		opts.CleanupInterval = time.Minute
+	}
+
+	s := &MemoryStore{
+		opts:    opts,
+		clock:   utils.ClockOrReal(opts.Clock),
+		buckets: make(map[string]*bucket),
+		boards:  make(map[string]map[string]float64),
+		stop:    make(chan struct{}),
+	}
+	go s.cleanup()
+	return s, nil
+}
+
+func (s *MemoryStore) cleanup() {
+	ticker := s.clock.NewTicker(s.opts.CleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case now := <-ticker.C():
+			s.mu.Lock()
+			for k, b := range s.buckets {
+				if now.After(b.expireAt) {
+					delete(s.buckets, k)
+				}
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+func (s *MemoryStore) Incr(ctx context.Context, key string, window counter.Window, delta int64) (int64, error) {
+	if err := window.Validate(); err != nil {
+		return 0, err
+	}
+	now := s.clock.Now()
+	k := window.BucketKey(key, now)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[k]
+	if !ok || now.After(b.expireAt) {
+		b = &bucket{expireAt: window.BucketStart(now).Add(time.Duration(window) * time.Duration(s.opts.Retention))}
+		s.buckets[k] = b
+	}
+	b.value += delta
+	return b.value, nil
+}
+
+func (s *MemoryStore) Get(ctx context.Context, key string, window counter.Window) (int64, error) {
+	return s.Sum(ctx, key, window, 1)
+}
+
+func (s *MemoryStore) Sum(ctx context.Context, key string, window counter.Window, n int) (int64, error) {
+	if err := window.Validate(); err != nil {
+		return 0, err
+	}
+	now := s.clock.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var total int64
+	for i := 0; i < n; i++ {
+		t := now.Add(-time.Duration(window) * time.Duration(i))
+		if b, ok := s.buckets[window.BucketKey(key, t)]; ok && !now.After(b.expireAt) {
+			total += b.value
+		}
+	}
+	return total, nil
+}
+
+func (s *MemoryStore) IncrScore(ctx context.Context, board, member string, delta float64) (float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m := s.board(board)
+	m[member] += delta
+	return m[member], nil
+}
+
+func (s *MemoryStore) SetScore(ctx context.Context, board, member string, score float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.board(board)[member] = score
+	return nil
+}
+
+func (s *MemoryStore) Score(ctx context.Context, board, member string) (float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	score, ok := s.boards[board][member]
+	if !ok {
+		return 0, counter.ErrNotFound
+	}
+	return score, nil
+}
+
+func (s *MemoryStore) Rank(ctx context.Context, board, member string) (counter.Entry, error) {
+	entries := s.sorted(board)
+	for _, e := range entries {
+		if e.Member == member {
+			return e, nil
+		}
+	}
+	return counter.Entry{}, counter.ErrNotFound
+}
+
+func (s *MemoryStore) Top(ctx context.Context, board string, n int) ([]counter.Entry, error) {
+	entries := s.sorted(board)
+	if n < len(entries) {
+		entries = entries[:n]
+	}
+	return entries, nil
+}
+
+func (s *MemoryStore) Around(ctx context.Context, board, member string, n int) ([]counter.Entry, error) {
+	entries := s.sorted(board)
+	for i, e := range entries {
+		if e.Member != member {
+			continue
+		}
+		start, end := i-n, i+n+1
+		if start < 0 {
+			start = 0
+		}
+		if end > len(entries) {
+			end = len(entries)
+		}
+		return entries[start:end], nil
+	}
+	return nil, counter.ErrNotFound
+}
+
+func (s *MemoryStore) Remove(ctx context.Context, board string, members ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, m := range members {
+		delete(s.boards[board], m)
+	}
+	return nil
+}
+
+func (s *MemoryStore) Clear(ctx context.Context, board string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.boards, board)
+	return nil
+}
+
+func (s *MemoryStore) board(name string) map[string]float64 {
+	m, ok := s.boards[name]
+	if !ok {
+		m = make(map[string]float64)
+		s.boards[name] = m
+	}
+	return m
+}
+
+// sorted 返回按排名排序的全部条目
+func (s *MemoryStore) sorted(board string) []counter.Entry {
+	s.mu.Lock()
+	entries := make([]counter.Entry, 0, len(s.boards[board]))
+	for member, score := range s.boards[board] {
+		entries = append(entries, counter.Entry{Member: member, Score: score})
+	}
+	s.mu.Unlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Score != entries[j].Score {
+			return entries[i].Score > entries[j].Score
+		}
+		return entries[i].Member > entries[j].Member
+	})
+	for i := range entries {
+		entries[i].Rank = int64(i + 1)
+	}
+	return entries
+}
+
+func (s *MemoryStore) Close() error {
+	s.once.Do(func() {
+		close(s.stop)
+	})
+	return nil
+}
+
+func init() {
+	counter.Register(counter.AdapterMemory, NewMemoryStore)
+}
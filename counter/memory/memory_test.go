@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jiajia556/tool-box/counter"
+	"github.com/jiajia556/tool-box/utils"
+)
+
+func newTestStore(t *testing.T, clock utils.Clock) counter.Store {
+	s, err := NewMemoryStore(Options{Clock: clock, Retention: 3})
+	if err != nil {
+		t.Fatalf("NewMemoryStore: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func TestMemoryStore_Incr(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t, utils.NewFakeClock(time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC)))
+
+	for i, want := range []int64{1, 3, 6} {
+		n, err := s.Incr(ctx, "pv", counter.Minute, int64(i+1))
+		if err != nil || n != want {
+			t.Fatalf("Incr = %d, %v; want %d", n, err, want)
+		}
+	}
+	if n, err := s.Get(ctx, "pv", counter.Minute); err != nil || n != 6 {
+		t.Fatalf("Get = %d, %v; want 6", n, err)
+	}
+	if n, _ := s.Get(ctx, "pv", counter.Hour); n != 0 {
+		t.Fatalf("windows should be counted separately, got %d", n)
+	}
+}
+
+func TestMemoryStore_WindowRollover(t *testing.T) {
+	ctx := context.Background()
+	clock := utils.NewFakeClock(time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC))
+	s := newTestStore(t, clock)
+
+	_, _ = s.Incr(ctx, "pv", counter.Minute, 5)
+	clock.Advance(time.Minute)
+	_, _ = s.Incr(ctx, "pv", counter.Minute, 2)
+
+	if n, _ := s.Get(ctx, "pv", counter.Minute); n != 2 {
+		t.Fatalf("current window = %d, want 2", n)
+	}
+	if n, _ := s.Sum(ctx, "pv", counter.Minute, 2); n != 7 {
+		t.Fatalf("Sum of 2 windows = %d, want 7", n)
+	}
+
+	// 超过 Retention 个窗口后旧窗口过期
+	clock.Advance(4 * time.Minute)
+	if n, _ := s.Sum(ctx, "pv", counter.Minute, 10); n != 0 {
+		t.Fatalf("expired windows still counted: %d", n)
+	}
+}
+
+func TestMemoryStore_InvalidWindow(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t, nil)
+
+	for _, w := range []counter.Window{0, counter.Window(500 * time.Millisecond), counter.Window(1500 * time.Millisecond)} {
+		if _, err := s.Incr(ctx, "pv", w, 1); !errors.Is(err, counter.ErrInvalidWindow) {
+			t.Fatalf("Incr(%v): expected ErrInvalidWindow, got %v", time.Duration(w), err)
+		}
+		if _, err := s.Sum(ctx, "pv", w, 2); !errors.Is(err, counter.ErrInvalidWindow) {
+			t.Fatalf("Sum(%v): expected ErrInvalidWindow, got %v", time.Duration(w), err)
+		}
+	}
+}
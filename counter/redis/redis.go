@@ -0,0 +1,229 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/jiajia556/tool-box/counter"
+)
+
+// Options Redis 存储配置
+type Options struct {
+	Addr     string `json:"addr"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	DB       int    `json:"db"`
+
+	// key 前缀
+	Prefix string `json:"prefix"`
+
+	// 每个 key 保留的窗口数，默认 60
+	Retention int `json:"retention"`
+
+	// 连接超时
+	Timeout time.Duration `json:"timeout"`
+}
+
+// RedisStore 基于 Redis 的实现：窗口计数使用 INCRBY + EXPIREAT，排行榜使用有序集合
+type RedisStore struct {
+	client *redis.Client
+	opts   Options
+}
+
+// NewRedisStore 创建 Redis 存储
+func NewRedisStore(config any) (counter.Store, error) {
+	opts, ok := config.(Options)
+	if !ok {
+		return nil, fmt.Errorf("%w: expect redis.Options", counter.ErrInvalidConfig)
+	}
+	if opts.Addr == "" {
+		opts.Addr = "localhost:6379"
+	}
+	if opts.Retention <= 0 {
+		opts.Retention = 60
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:         opts.Addr,
+		Username:     opts.Username,
+		Password:     opts.Password,
+		DB:           opts.DB,
+		DialTimeout:  opts.Timeout,
+		ReadTimeout:  opts.Timeout,
+		WriteTimeout: opts.Timeout,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("counter: failed to connect to redis: %w", err)
+	}
+
+	return &RedisStore{client: client, opts: opts}, nil
+}
+
+// NewWithClient 使用已有客户端创建 Redis 存储
+func NewWithClient(client *redis.Client, opts Options) *RedisStore {
+	if opts.Retention <= 0 {
+		opts.Retention = 60
+	}
+	return &RedisStore{client: client, opts: opts}
+}
+
+func (r *RedisStore) key(k string) string {
+	if r.opts.Prefix == "" {
+		return k
+	}
+	return r.opts.Prefix + ":" + k
+}
+
+func (r *RedisStore) Incr(ctx context.Context, key string, window counter.Window, delta int64) (int64, error) {
+	if err := window.Validate(); err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	k := r.key(window.BucketKey(key, now))
+	expireAt := window.BucketStart(now).Add(time.Duration(window) * time.Duration(r.opts.Retention))
+
+	pipe := r.client.TxPipeline()
+	incr := pipe.IncrBy(ctx, k, delta)
+	pipe.ExpireAt(ctx, k, expireAt)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+func (r *RedisStore) Get(ctx context.Context, key string, window counter.Window) (int64, error) {
+	return r.Sum(ctx, key, window, 1)
+}
+
+func (r *RedisStore) Sum(ctx context.Context, key string, window counter.Window, n int) (int64, error) {
+	if err := window.Validate(); err != nil {
+		return 0, err
+	}
+	if n <= 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+	keys := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		keys = append(keys, r.key(window.BucketKey(key, now.Add(-time.Duration(window)*time.Duration(i)))))
+	}
+
+	vals, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, v := range vals {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		if c, err := strconv.ParseInt(s, 10, 64); err == nil {
+			total += c
+		}
+	}
+	return total, nil
+}
+
+func (r *RedisStore) IncrScore(ctx context.Context, board, member string, delta float64) (float64, error) {
+	return r.client.ZIncrBy(ctx, r.key(board), delta, member).Result()
+}
+
+func (r *RedisStore) SetScore(ctx context.Context, board, member string, score float64) error {
+	return r.client.ZAdd(ctx, r.key(board), redis.Z{Score: score, Member: member}).Err()
+}
+
+func (r *RedisStore) Score(ctx context.Context, board, member string) (float64, error) {
+	score, err := r.client.ZScore(ctx, r.key(board), member).Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, counter.ErrNotFound
+	}
+	return score, err
+}
+
+func (r *RedisStore) Rank(ctx context.Context, board, member string) (counter.Entry, error) {
+	pipe := r.client.Pipeline()
+	rank := pipe.ZRevRank(ctx, r.key(board), member)
+	score := pipe.ZScore(ctx, r.key(board), member)
+	if _, err := pipe.Exec(ctx); err != nil {
+		if errors.Is(err, redis.Nil) {
+			return counter.Entry{}, counter.ErrNotFound
+		}
+		return counter.Entry{}, err
+	}
+	return counter.Entry{Member: member, Score: score.Val(), Rank: rank.Val() + 1}, nil
+}
+
+func (r *RedisStore) Top(ctx context.Context, board string, n int) ([]counter.Entry, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	return r.revRange(ctx, board, 0, int64(n-1))
+}
+
+func (r *RedisStore) Around(ctx context.Context, board, member string, n int) ([]counter.Entry, error) {
+	rank, err := r.client.ZRevRank(ctx, r.key(board), member).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, counter.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	start := rank - int64(n)
+	if start < 0 {
+		start = 0
+	}
+	return r.revRange(ctx, board, start, rank+int64(n))
+}
+
+func (r *RedisStore) revRange(ctx context.Context, board string, start, stop int64) ([]counter.Entry, error) {
+	zs, err := r.client.ZRevRangeWithScores(ctx, r.key(board), start, stop).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]counter.Entry, 0, len(zs))
+	for i, z := range zs {
+		member, _ := z.Member.(string)
+		out = append(out, counter.Entry{Member: member, Score: z.Score, Rank: start + int64(i) + 1})
+	}
+	return out, nil
+}
+
+func (r *RedisStore) Remove(ctx context.Context, board string, members ...string) error {
+	if len(members) == 0 {
+		return nil
+	}
+	args := make([]any, len(members))
+	for i, m := range members {
+		args[i] = m
+	}
+	return r.client.ZRem(ctx, r.key(board), args...).Err()
+}
+
+func (r *RedisStore) Clear(ctx context.Context, board string) error {
+	return r.client.Del(ctx, r.key(board)).Err()
+}
+
+func (r *RedisStore) Close() error {
+	return r.client.Close()
+}
+
+func init() {
+	counter.Register(counter.AdapterRedis, NewRedisStore)
+}
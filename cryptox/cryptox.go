@@ -179,6 +179,62 @@ func DecryptStringWithPassword(password, b64 string, opts ...Option) (string, er
 	return string(pt), nil
 }
 
+// EncryptWithKey：使用原始密钥加密（不经过 KDF，适合字段级等高频加密；输出含 alg/nonce 信息）
+func EncryptWithKey(alg Alg, key, plaintext []byte) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, errors.New("key is empty")
+	}
+	aead, nonceLen, err := newAEAD(alg, key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, nonceLen)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	// 数据格式：
+	// magic(4) + ver(1) + alg(1) + nonceLen(1) + nonce + ciphertext
+	out := make([]byte, 0, 4+1+1+1+len(nonce)+len(plaintext)+aead.Overhead())
+	out = append(out, 'C', 'X', 'K', 'Y') // CryptoX KeY
+	out = append(out, 1)                  // version
+	out = append(out, byte(alg))
+	out = append(out, byte(len(nonce)))
+	out = append(out, nonce...)
+	return aead.Seal(out, nonce, plaintext, nil), nil
+}
+
+// DecryptWithKey：解密 EncryptWithKey 的输出（自动从数据里读出 alg/nonce）
+func DecryptWithKey(key, data []byte) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, errors.New("key is empty")
+	}
+	if len(data) < 4+1+1+1 {
+		return nil, errors.New("ciphertext too short")
+	}
+	if data[0] != 'C' || data[1] != 'X' || data[2] != 'K' || data[3] != 'Y' {
+		return nil, errors.New("invalid magic header")
+	}
+	if ver := data[4]; ver != 1 {
+		return nil, fmt.Errorf("unsupported version: %d", ver)
+	}
+
+	alg := Alg(data[5])
+	nonceLen := int(data[6])
+	headerLen := 4 + 1 + 1 + 1
+	if nonceLen <= 0 || len(data) < headerLen+nonceLen {
+		return nil, errors.New("invalid nonce length")
+	}
+
+	aead, _, err := newAEAD(alg, key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := data[headerLen : headerLen+nonceLen]
+	return aead.Open(nil, nonce, data[headerLen+nonceLen:], nil)
+}
+
 func newAEAD(alg Alg, key []byte) (cipher.AEAD, int, error) {
 	switch alg {
 	case AESGCM:
@@ -0,0 +1,206 @@
+package dedupe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNoGlobal      = errors.New("dedupe: global instance is nil")
+	ErrInvalidConfig = errors.New("dedupe: invalid config")
+)
+
+// Filter 布隆过滤器：MightContain 返回 false 时一定不存在，返回 true 时可能存在（误判率约为 FPR）
+type Filter interface {
+	// 添加元素
+	Add(ctx context.Context, items ...string) error
+
+	// 判断元素是否可能存在
+	MightContain(ctx context.Context, item string) (bool, error)
+
+	// 元素不存在时添加并返回 true，已存在（或误判）时返回 false；原子操作，可用于消息去重
+	AddIfAbsent(ctx context.Context, item string) (bool, error)
+
+	// 清空
+	Reset(ctx context.Context) error
+
+	// 关闭（释放资源）
+	Close() error
+}
+
+// Config 过滤器参数
+type Config struct {
+	// 预期元素数量
+	Capacity uint64 `json:"capacity"`
+
+	// 期望误判率，如 0.01
+	FPR float64 `json:"fpr"`
+
+	// 轮换周期，<=0 表示不轮换。
+	// 轮换时保留上一代过滤器，元素在 1~2 个周期内可查到，之后自然淘汰，避免过滤器被写满。
+	RotateInterval time.Duration `json:"rotate_interval"`
+}
+
+// Normalize 填充默认值：容量 100 万，误判率 1%
+func (c *Config) Normalize() error {
+	if c.Capacity == 0 {
+		c.Capacity = 1_000_000
+	}
+	if c.FPR <= 0 {
+		c.FPR = 0.01
+	}
+	if c.FPR >= 1 {
+		return fmt.Errorf("%w: fpr must be in (0, 1)", ErrInvalidConfig)
+	}
+	return nil
+}
+
+// Params 根据容量与误判率计算位数组大小 m 与哈希函数个数 k
+func Params(capacity uint64, fpr float64) (m uint64, k uint) {
+	n := float64(capacity)
+	m = uint64(math.Ceil(-n * math.Log(fpr) / (math.Ln2 * math.Ln2)))
+	k = uint(math.Round(float64(m) / n * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return m, k
+}
+
+// Locations 计算元素在位数组中的 k 个位置（双重哈希）
+func Locations(item string, m uint64, k uint) []uint64 {
+	h := fnv.New128a()
+	h.Write([]byte(item))
+	sum := h.Sum(nil)
+
+	var h1, h2 uint64
+	for i := 0; i < 8; i++ {
+		h1 = h1<<8 | uint64(sum[i])
+		h2 = h2<<8 | uint64(sum[8+i])
+	}
+
+	out := make([]uint64, k)
+	for i := uint(0); i < k; i++ {
+		out[i] = (h1 + uint64(i)*h2) % m
+	}
+	return out
+}
+
+// Generation 返回时间 t 所处的轮换代数；不轮换时恒为 0
+func (c Config) Generation(t time.Time) int64 {
+	if c.RotateInterval <= 0 {
+		return 0
+	}
+	return t.UnixNano() / int64(c.RotateInterval)
+}
+
+// Instance 适配器工厂函数
+type Instance func(config any) (Filter, error)
+
+var (
+	adaptersMu sync.RWMutex
+	adapters   = make(map[string]Instance)
+)
+
+const (
+	AdapterMemory = "memory"
+	AdapterRedis  = "redis"
+)
+
+var (
+	globalMu sync.RWMutex
+	global   Filter
+)
+
+// Register 注册适配器
+func Register(name string, adapter Instance) {
+	adaptersMu.Lock()
+	defer adaptersMu.Unlock()
+
+	if adapter == nil {
+		panic("dedupe: Register adapter is nil")
+	}
+	if _, ok := adapters[name]; ok {
+		panic("dedupe: Register called twice for adapter " + name)
+	}
+	adapters[name] = adapter
+}
+
+// New 按适配器名称创建过滤器
+func New(adapterName string, config any) (Filter, error) {
+	adaptersMu.RLock()
+	instanceFunc, ok := adapters[adapterName]
+	adaptersMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("dedupe: unknown adapter name %q (forgot to import?)", adapterName)
+	}
+	return instanceFunc(config)
+}
+
+// Init 初始化全局过滤器
+// 参数 config 为适配器配置：
+// - "memory": 接受 dedupe.Config 结构体
+// - "redis": 接受 redis.Options 结构体
+func Init(adapterName string, config any) error {
+	f, err := New(adapterName, config)
+	if err != nil {
+		return err
+	}
+
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	global = f
+	return nil
+}
+
+// Global 返回全局过滤器
+func Global() Filter {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+	return global
+}
+
+// Add 使用全局过滤器添加元素
+func Add(ctx context.Context, items ...string) error {
+	f := Global()
+	if f == nil {
+		return ErrNoGlobal
+	}
+	return f.Add(ctx, items...)
+}
+
+// MightContain 使用全局过滤器判断元素是否可能存在
+func MightContain(ctx context.Context, item string) (bool, error) {
+	f := Global()
+	if f == nil {
+		return false, ErrNoGlobal
+	}
+	return f.MightContain(ctx, item)
+}
+
+// AddIfAbsent 使用全局过滤器去重
+func AddIfAbsent(ctx context.Context, item string) (bool, error) {
+	f := Global()
+	if f == nil {
+		return false, ErrNoGlobal
+	}
+	return f.AddIfAbsent(ctx, item)
+}
+
+// Close 关闭全局过滤器
+func Close() error {
+	globalMu.Lock()
+	f := global
+	global = nil
+	globalMu.Unlock()
+
+	if f == nil {
+		return nil
+	}
+	return f.Close()
+}
@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jiajia556/tool-box/dedupe"
+)
+
+type bitset []uint64
+
+func newBitset(m uint64) bitset {
+	return make(bitset, (m+63)/64)
+}
+
+func (b bitset) set(i uint64) bool {
+	old := b[i/64]&(1<<(i%64)) != 0
+	b[i/64] |= 1 << (i % 64)
+	return old
+}
+
+func (b bitset) test(i uint64) bool {
+	return b[i/64]&(1<<(i%64)) != 0
+}
+
+// MemoryFilter 进程内布隆过滤器
+type MemoryFilter struct {
+	config dedupe.Config
+	m      uint64
+	k      uint
+
+	mu       sync.Mutex
+	gen      int64
+	current  bitset
+	previous bitset
+}
+
+// NewMemoryFilter 创建内存布隆过滤器
+func NewMemoryFilter(config any) (dedupe.Filter, error) {
+	var c dedupe.Config
+	if config != nil {
+		var ok bool
+		if c, ok = config.(dedupe.Config); !ok {
+			return nil, fmt.Errorf("%w: expect dedupe.Config", dedupe.ErrInvalidConfig)
+		}
+	}
+	if err := c.Normalize(); err != nil {
+		return nil, err
+	}
+
+	m, k := dedupe.Params(c.Capacity, c.FPR)
+	return &MemoryFilter{
+		config:  c,
+		m:       m,
+		k:       k,
+		gen:     c.Generation(time.Now()),
+		current: newBitset(m),
+	}, nil
+}
+
+// rotate 按代数切换过滤器，调用方需持有锁
+func (f *MemoryFilter) rotate() {
+	gen := f.config.Generation(time.Now())
+	switch {
+	case gen == f.gen:
+		return
+	case gen == f.gen+1:
+		f.previous = f.current
+	default:
+		f.previous = nil
+	}
+	f.current = newBitset(f.m)
+	f.gen = gen
+}
+
+func (f *MemoryFilter) Add(ctx context.Context, items ...string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.rotate()
+	for _, item := range items {
+		for _, loc := range dedupe.Locations(item, f.m, f.k) {
+			f.current.set(loc)
+		}
+	}
+	return nil
+}
+
+func (f *MemoryFilter) MightContain(ctx context.Context, item string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.rotate()
+	locs := dedupe.Locations(item, f.m, f.k)
+	return contains(f.current, locs) || contains(f.previous, locs), nil
+}
+
+func (f *MemoryFilter) AddIfAbsent(ctx context.Context, item string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.rotate()
+	locs := dedupe.Locations(item, f.m, f.k)
+	if contains(f.previous, locs) {
+		return false, nil
+	}
+
+	added := false
+	for _, loc := range locs {
+		if !f.current.set(loc) {
+			added = true
+		}
+	}
+	return added, nil
+}
+
+func (f *MemoryFilter) Reset(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.current = newBitset(f.m)
+	f.previous = nil
+	return nil
+}
+
+func (f *MemoryFilter) Close() error {
+	return nil
+}
+
+func contains(b bitset, locs []uint64) bool {
+	if b == nil {
+		return false
+	}
+	for _, loc := range locs {
+		if !b.test(loc) {
+			return false
+		}
+	}
+	return true
+}
+
+func init() {
+	dedupe.Register(dedupe.AdapterMemory, NewMemoryFilter)
+}
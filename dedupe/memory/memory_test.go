@@ -0,0 +1,39 @@
+package memory
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/jiajia556/tool-box/dedupe"
+)
+
+func TestMemoryFilter(t *testing.T) {
+	ctx := context.Background()
+	f, err := NewMemoryFilter(dedupe.Config{Capacity: 10000, FPR: 0.01})
+	if err != nil {
+		t.Fatalf("NewMemoryFilter: %v", err)
+	}
+
+	for i := 0; i < 10000; i++ {
+		added, _ := f.AddIfAbsent(ctx, "msg-"+strconv.Itoa(i))
+		if !added && i < 100 {
+			t.Fatalf("expected msg-%d to be new", i)
+		}
+	}
+	for i := 0; i < 10000; i++ {
+		if ok, _ := f.MightContain(ctx, "msg-"+strconv.Itoa(i)); !ok {
+			t.Fatalf("false negative for msg-%d", i)
+		}
+	}
+
+	fp := 0
+	for i := 0; i < 10000; i++ {
+		if ok, _ := f.MightContain(ctx, "other-"+strconv.Itoa(i)); ok {
+			fp++
+		}
+	}
+	if fp > 300 {
+		t.Fatalf("false positive rate too high: %d/10000", fp)
+	}
+}
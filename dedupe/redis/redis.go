@@ -0,0 +1,217 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/jiajia556/tool-box/dedupe"
+)
+
+// Options Redis 布隆过滤器配置
+type Options struct {
+	Addr     string `json:"addr"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	DB       int    `json:"db"`
+
+	// 过滤器 key，轮换时实际 key 为 "<Key>:<代数>"
+	Key string `json:"key"`
+
+	// 连接超时
+	Timeout time.Duration `json:"timeout"`
+
+	dedupe.Config
+}
+
+// Redis 位图单个 key 最多 2^32 位
+const maxBits = 1 << 32
+
+var (
+	addScript = redis.NewScript(`
+		for i = 2, #ARGV do
+			redis.call("SETBIT", KEYS[1], ARGV[i], 1)
+		end
+		if tonumber(ARGV[1]) > 0 then
+			redis.call("PEXPIRE", KEYS[1], ARGV[1])
+		end
+		return 1
+	`)
+
+	containsScript = redis.NewScript(`
+		for k = 1, #KEYS do
+			local hit = 1
+			for i = 1, #ARGV do
+				if redis.call("GETBIT", KEYS[k], ARGV[i]) == 0 then
+					hit = 0
+					break
+				end
+			end
+			if hit == 1 then
+				return 1
+			end
+		end
+		return 0
+	`)
+
+	addIfAbsentScript = redis.NewScript(`
+		if KEYS[2] then
+			local hit = 1
+			for i = 2, #ARGV do
+				if redis.call("GETBIT", KEYS[2], ARGV[i]) == 0 then
+					hit = 0
+					break
+				end
+			end
+			if hit == 1 then
+				return 0
+			end
+		end
+		local added = 0
+		for i = 2, #ARGV do
+			if redis.call("SETBIT", KEYS[1], ARGV[i], 1) == 0 then
+				added = 1
+			end
+		end
+		if tonumber(ARGV[1]) > 0 then
+			redis.call("PEXPIRE", KEYS[1], ARGV[1])
+		end
+		return added
+	`)
+)
+
+// RedisFilter 基于 Redis 位图的布隆过滤器，多实例共享
+type RedisFilter struct {
+	client *redis.Client
+	opts   Options
+	m      uint64
+	k      uint
+}
+
+// NewRedisFilter 创建 Redis 布隆过滤器
+func NewRedisFilter(config any) (dedupe.Filter, error) {
+	opts, ok := config.(Options)
+	if !ok {
+		return nil, fmt.Errorf("%w: expect redis.Options", dedupe.ErrInvalidConfig)
+	}
+	if opts.Addr == "" {
+		opts.Addr = "localhost:6379"
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:         opts.Addr,
+		Username:     opts.Username,
+		Password:     opts.Password,
+		DB:           opts.DB,
+		DialTimeout:  opts.Timeout,
+		ReadTimeout:  opts.Timeout,
+		WriteTimeout: opts.Timeout,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("dedupe: failed to connect to redis: %w", err)
+	}
+
+	f, err := NewWithClient(client, opts)
+	if err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// NewWithClient 使用已有客户端创建过滤器
+func NewWithClient(client *redis.Client, opts Options) (*RedisFilter, error) {
+	if opts.Key == "" {
+		opts.Key = "dedupe"
+	}
+	if err := opts.Config.Normalize(); err != nil {
+		return nil, err
+	}
+
+	m, k := dedupe.Params(opts.Capacity, opts.FPR)
+	if m > maxBits {
+		return nil, fmt.Errorf("%w: capacity too large for a single redis bitmap", dedupe.ErrInvalidConfig)
+	}
+	return &RedisFilter{client: client, opts: opts, m: m, k: k}, nil
+}
+
+// keys 返回当前代与上一代的 key；不轮换时只有一个 key
+func (f *RedisFilter) keys() []string {
+	if f.opts.RotateInterval <= 0 {
+		return []string{f.opts.Key}
+	}
+	gen := f.opts.Generation(time.Now())
+	return []string{
+		f.opts.Key + ":" + strconv.FormatInt(gen, 10),
+		f.opts.Key + ":" + strconv.FormatInt(gen-1, 10),
+	}
+}
+
+// ttl 每一代保留两个周期
+func (f *RedisFilter) ttl() int64 {
+	if f.opts.RotateInterval <= 0 {
+		return 0
+	}
+	return (2 * f.opts.RotateInterval).Milliseconds()
+}
+
+func (f *RedisFilter) offsets(item string) []any {
+	locs := dedupe.Locations(item, f.m, f.k)
+	out := make([]any, len(locs))
+	for i, l := range locs {
+		out[i] = l
+	}
+	return out
+}
+
+func (f *RedisFilter) Add(ctx context.Context, items ...string) error {
+	key := f.keys()[0]
+	ttl := f.ttl()
+
+	pipe := f.client.Pipeline()
+	for _, item := range items {
+		args := append([]any{ttl}, f.offsets(item)...)
+		addScript.Eval(ctx, pipe, []string{key}, args...)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (f *RedisFilter) MightContain(ctx context.Context, item string) (bool, error) {
+	n, err := containsScript.Run(ctx, f.client, f.keys(), f.offsets(item)...).Int()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (f *RedisFilter) AddIfAbsent(ctx context.Context, item string) (bool, error) {
+	args := append([]any{f.ttl()}, f.offsets(item)...)
+	n, err := addIfAbsentScript.Run(ctx, f.client, f.keys(), args...).Int()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (f *RedisFilter) Reset(ctx context.Context) error {
+	return f.client.Del(ctx, f.keys()...).Err()
+}
+
+func (f *RedisFilter) Close() error {
+	return f.client.Close()
+}
+
+func init() {
+	dedupe.Register(dedupe.AdapterRedis, NewRedisFilter)
+}
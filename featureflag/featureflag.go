@@ -0,0 +1,236 @@
+package featureflag
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNoGlobal = errors.New("featureflag: global instance is nil")
+)
+
+// Config 客户端配置
+type Config struct {
+	// 定时刷新间隔，<=0 表示不自动刷新
+	RefreshInterval time.Duration
+
+	// 刷新失败回调（刷新失败时继续使用上一次的开关数据）
+	OnError func(err error)
+}
+
+// Option 选项函数
+type Option func(*Config)
+
+// WithRefreshInterval 设置刷新间隔
+func WithRefreshInterval(d time.Duration) Option {
+	return func(c *Config) {
+		c.RefreshInterval = d
+	}
+}
+
+// WithOnError 设置刷新失败回调
+func WithOnError(fn func(err error)) Option {
+	return func(c *Config) {
+		c.OnError = fn
+	}
+}
+
+// DefaultConfig 默认配置
+func DefaultConfig() Config {
+	return Config{
+		RefreshInterval: 30 * time.Second,
+	}
+}
+
+// Client 开关客户端：从 Source 加载开关后在本地求值，并定时刷新
+type Client struct {
+	source Source
+	config Config
+
+	mu        sync.RWMutex
+	flags     map[string]*Flag
+	overrides map[string]bool
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+	once sync.Once
+}
+
+// New 创建客户端并完成首次加载
+func New(ctx context.Context, source Source, opts ...Option) (*Client, error) {
+	config := DefaultConfig()
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	c := &Client{
+		source:    source,
+		config:    config,
+		flags:     map[string]*Flag{},
+		overrides: map[string]bool{},
+		stop:      make(chan struct{}),
+	}
+	if err := c.Refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	if config.RefreshInterval > 0 {
+		c.wg.Add(1)
+		go c.loop()
+	}
+	return c, nil
+}
+
+// Refresh 立即从数据源重新加载
+func (c *Client) Refresh(ctx context.Context) error {
+	flags, err := c.source.Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.flags = flags
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *Client) loop() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.config.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			if err := c.Refresh(context.Background()); err != nil && c.config.OnError != nil {
+				c.config.OnError(err)
+			}
+		}
+	}
+}
+
+// IsEnabled 对开关求值；开关不存在时返回 false
+func (c *Client) IsEnabled(key string, ec EvalContext) bool {
+	c.mu.RLock()
+	v, overridden := c.overrides[key]
+	f := c.flags[key]
+	c.mu.RUnlock()
+
+	if overridden {
+		return v
+	}
+	return f.Evaluate(ec)
+}
+
+// Flag 返回开关定义
+func (c *Client) Flag(key string) (*Flag, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	f, ok := c.flags[key]
+	return f, ok
+}
+
+// Override 强制指定开关结果，优先于数据源，主要用于测试
+func (c *Client) Override(key string, enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.overrides[key] = enabled
+}
+
+// ClearOverride 取消单个开关的强制结果
+func (c *Client) ClearOverride(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.overrides, key)
+}
+
+// ResetOverrides 取消全部强制结果
+func (c *Client) ResetOverrides() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.overrides = map[string]bool{}
+}
+
+// Close 停止定时刷新
+func (c *Client) Close() error {
+	c.once.Do(func() {
+		close(c.stop)
+	})
+	c.wg.Wait()
+	return nil
+}
+
+var (
+	globalMu sync.RWMutex
+	global   *Client
+)
+
+// Init 初始化全局客户端
+func Init(ctx context.Context, source Source, opts ...Option) error {
+	c, err := New(ctx, source, opts...)
+	if err != nil {
+		return err
+	}
+
+	globalMu.Lock()
+	old := global
+	global = c
+	globalMu.Unlock()
+
+	if old != nil {
+		_ = old.Close()
+	}
+	return nil
+}
+
+// SetGlobal 设置全局客户端
+func SetGlobal(c *Client) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	global = c
+}
+
+// Global 返回全局客户端
+func Global() *Client {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+	return global
+}
+
+// IsEnabled 使用全局客户端求值；未初始化时返回 false
+func IsEnabled(key string, ec EvalContext) bool {
+	c := Global()
+	if c == nil {
+		return false
+	}
+	return c.IsEnabled(key, ec)
+}
+
+// Override 对全局客户端设置强制结果
+func Override(key string, enabled bool) error {
+	c := Global()
+	if c == nil {
+		return ErrNoGlobal
+	}
+	c.Override(key, enabled)
+	return nil
+}
+
+// Close 关闭全局客户端
+func Close() error {
+	globalMu.Lock()
+	c := global
+	global = nil
+	globalMu.Unlock()
+
+	if c == nil {
+		return nil
+	}
+	return c.Close()
+}
@@ -0,0 +1,85 @@
+package featureflag
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/jiajia556/tool-box/cache/memory"
+)
+
+func TestFlag_Evaluate(t *testing.T) {
+	half := 50.0
+	f := &Flag{
+		Key:        "new-checkout",
+		Enabled:    true,
+		Percentage: &half,
+		Rules:      []Rule{{Attribute: "country", Operator: OpIn, Values: []string{"CN", "SG"}}},
+	}
+
+	if f.Evaluate(EvalContext{Key: "u1", Attributes: map[string]string{"country": "US"}}) {
+		t.Fatalf("expected rule mismatch to disable flag")
+	}
+
+	on := 0
+	for i := 0; i < 1000; i++ {
+		ec := EvalContext{Key: "user-" + strconv.Itoa(i), Attributes: map[string]string{"country": "CN"}}
+		if f.Evaluate(ec) {
+			on++
+		}
+	}
+	if on < 400 || on > 600 {
+		t.Fatalf("expected roughly 50%% rollout, got %d/1000", on)
+	}
+}
+
+func TestClient_FileSourceAndOverride(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.toml")
+	content := `
+[[flags]]
+key = "beta"
+enabled = true
+
+[[flags.rules]]
+attribute = "plan"
+operator = "eq"
+values = ["pro"]
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := New(context.Background(), NewFileSource(path), WithRefreshInterval(0))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer c.Close()
+
+	pro := EvalContext{Attributes: map[string]string{"plan": "pro"}}
+	if !c.IsEnabled("beta", pro) {
+		t.Fatalf("expected beta enabled for pro plan")
+	}
+	c.Override("beta", false)
+	if c.IsEnabled("beta", pro) {
+		t.Fatalf("expected override to win")
+	}
+	c.ResetOverrides()
+	if !c.IsEnabled("beta", pro) || c.IsEnabled("missing", pro) {
+		t.Fatalf("unexpected result after reset")
+	}
+}
+
+func TestCacheSource(t *testing.T) {
+	src := NewCacheSource(memory.NewMemoryCache(), "")
+	src.Save([]*Flag{{Key: "a", Enabled: true}})
+
+	flags, err := src.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !flags["a"].Evaluate(EvalContext{}) {
+		t.Fatalf("expected flag a enabled")
+	}
+}
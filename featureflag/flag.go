@@ -0,0 +1,147 @@
+package featureflag
+
+import (
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// 规则运算符
+const (
+	OpEq       = "eq"
+	OpNeq      = "neq"
+	OpIn       = "in"
+	OpNotIn    = "not_in"
+	OpPrefix   = "prefix"
+	OpSuffix   = "suffix"
+	OpContains = "contains"
+	OpGt       = "gt"
+	OpGte      = "gte"
+	OpLt       = "lt"
+	OpLte      = "lte"
+)
+
+// Rule 属性规则，例如 {"attribute": "country", "operator": "in", "values": ["CN", "SG"]}
+type Rule struct {
+	Attribute string   `json:"attribute" toml:"attribute"`
+	Operator  string   `json:"operator" toml:"operator"`
+	Values    []string `json:"values" toml:"values"`
+}
+
+// Flag 开关定义
+//
+// 求值顺序：
+//  1. Enabled 为 false 时直接关闭
+//  2. 配置了 Rules 时，所有规则都满足才继续（AND 语义）
+//  3. 配置了 Percentage 时，按 EvalContext.Key 哈希分桶，落入比例内才开启
+type Flag struct {
+	Key string `json:"key" toml:"key"`
+
+	// 总开关
+	Enabled bool `json:"enabled" toml:"enabled"`
+
+	// 灰度比例 0~100，nil 表示不按比例（全部开启）
+	Percentage *float64 `json:"percentage,omitempty" toml:"percentage,omitempty"`
+
+	// 属性规则
+	Rules []Rule `json:"rules,omitempty" toml:"rules,omitempty"`
+
+	Description string `json:"description,omitempty" toml:"description,omitempty"`
+}
+
+// EvalContext 求值上下文
+type EvalContext struct {
+	// 分桶标识，一般为用户 ID
+	Key string
+
+	// 用于规则匹配的属性
+	Attributes map[string]string
+}
+
+// Evaluate 对上下文求值
+func (f *Flag) Evaluate(ec EvalContext) bool {
+	if f == nil || !f.Enabled {
+		return false
+	}
+	for _, r := range f.Rules {
+		if !r.Match(ec.Attributes) {
+			return false
+		}
+	}
+	if f.Percentage != nil {
+		return bucket(f.Key, ec.Key) < *f.Percentage*100
+	}
+	return true
+}
+
+// Match 判断属性是否满足规则；属性不存在时视为不满足
+func (r Rule) Match(attrs map[string]string) bool {
+	v, ok := attrs[r.Attribute]
+	if !ok {
+		return false
+	}
+
+	switch r.Operator {
+	case OpEq:
+		return len(r.Values) > 0 && v == r.Values[0]
+	case OpNeq:
+		return len(r.Values) > 0 && v != r.Values[0]
+	case OpIn:
+		return contains(r.Values, v)
+	case OpNotIn:
+		return !contains(r.Values, v)
+	case OpPrefix:
+		return anyOf(r.Values, func(s string) bool { return strings.HasPrefix(v, s) })
+	case OpSuffix:
+		return anyOf(r.Values, func(s string) bool { return strings.HasSuffix(v, s) })
+	case OpContains:
+		return anyOf(r.Values, func(s string) bool { return strings.Contains(v, s) })
+	case OpGt, OpGte, OpLt, OpLte:
+		if len(r.Values) == 0 {
+			return false
+		}
+		a, err1 := strconv.ParseFloat(v, 64)
+		b, err2 := strconv.ParseFloat(r.Values[0], 64)
+		if err1 != nil || err2 != nil {
+			return false
+		}
+		switch r.Operator {
+		case OpGt:
+			return a > b
+		case OpGte:
+			return a >= b
+		case OpLt:
+			return a < b
+		default:
+			return a <= b
+		}
+	}
+	return false
+}
+
+// bucket 将 flag+key 稳定映射到 [0, 10000)
+func bucket(flagKey, key string) float64 {
+	h := fnv.New32a()
+	h.Write([]byte(flagKey))
+	h.Write([]byte{':'})
+	h.Write([]byte(key))
+	return float64(h.Sum32() % 10000)
+}
+
+func contains(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+func anyOf(list []string, fn func(string) bool) bool {
+	for _, s := range list {
+		if fn(s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package featureflag
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/jiajia556/tool-box/cache"
+)
+
+// Source 开关数据源
+type Source interface {
+	// 加载全部开关，key 为开关名
+	Load(ctx context.Context) (map[string]*Flag, error)
+}
+
+// SourceFunc 函数形式的数据源
+type SourceFunc func(ctx context.Context) (map[string]*Flag, error)
+
+func (f SourceFunc) Load(ctx context.Context) (map[string]*Flag, error) {
+	return f(ctx)
+}
+
+// document 配置文件结构：{"flags": [{...}, {...}]}
+type document struct {
+	Flags []*Flag `json:"flags" toml:"flags"`
+}
+
+// FileSource 从 JSON / TOML 配置文件加载开关（按扩展名识别格式）
+type FileSource struct {
+	Path string
+}
+
+// NewFileSource 创建文件数据源
+func NewFileSource(path string) *FileSource {
+	return &FileSource{Path: path}
+}
+
+func (s *FileSource) Load(ctx context.Context) (map[string]*Flag, error) {
+	b, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil, fmt.Errorf("featureflag: read %s: %w", s.Path, err)
+	}
+
+	var doc document
+	switch strings.ToLower(filepath.Ext(s.Path)) {
+	case ".toml":
+		err = toml.Unmarshal(b, &doc)
+	default:
+		err = json.Unmarshal(b, &doc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("featureflag: parse %s: %w", s.Path, err)
+	}
+	return index(doc.Flags), nil
+}
+
+// CacheSource 从缓存中加载开关，所有开关以 []*Flag 保存在同一个 key 下。
+// 配合 redis 适配器即可在多个实例间共享开关配置。
+type CacheSource struct {
+	Store cache.Cache
+	Key   string
+}
+
+// NewCacheSource 创建缓存数据源，key 为空时使用 "featureflag:flags"
+func NewCacheSource(store cache.Cache, key string) *CacheSource {
+	if key == "" {
+		key = "featureflag:flags"
+	}
+	return &CacheSource{Store: store, Key: key}
+}
+
+func (s *CacheSource) Load(ctx context.Context) (map[string]*Flag, error) {
+	v, err := s.Store.Get(s.Key)
+	if errors.Is(err, cache.ErrNotFound) {
+		return map[string]*Flag{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("featureflag: load from cache: %w", err)
+	}
+
+	// 缓存适配器以 JSON 保存数据，取出后为通用结构，这里重新解码
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var flags []*Flag
+	if err := json.Unmarshal(b, &flags); err != nil {
+		return nil, fmt.Errorf("featureflag: decode flags: %w", err)
+	}
+	return index(flags), nil
+}
+
+// Save 将开关写入缓存（永不过期），供管理端发布配置
+func (s *CacheSource) Save(flags []*Flag) {
+	s.Store.Set(s.Key, flags, 0)
+}
+
+func index(flags []*Flag) map[string]*Flag {
+	out := make(map[string]*Flag, len(flags))
+	for _, f := range flags {
+		if f != nil && f.Key != "" {
+			out[f.Key] = f
+		}
+	}
+	return out
+}
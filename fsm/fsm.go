@@ -0,0 +1,246 @@
+package fsm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/jiajia556/tool-box/locker"
+)
+
+var (
+	ErrInvalidTransition = errors.New("fsm: invalid transition")
+	ErrGuardRejected     = errors.New("fsm: transition rejected by guard")
+)
+
+// Event 一次状态流转的上下文，传给守卫与钩子
+type Event[S, E comparable] struct {
+	// 业务对象标识（如订单号），用于加锁与持久化
+	Key string
+
+	From  S
+	To    S
+	Event E
+
+	// 调用方附带的数据
+	Payload any
+}
+
+// Guard 守卫：返回错误时拒绝流转
+type Guard[S, E comparable] func(ctx context.Context, e *Event[S, E]) error
+
+// Hook 钩子：离开 / 进入状态及流转完成时调用，返回错误时中止流转
+type Hook[S, E comparable] func(ctx context.Context, e *Event[S, E]) error
+
+// Config 状态机配置
+type Config[S, E comparable] struct {
+	// 加载当前状态；设置后 Fire 在加锁后以加载结果为准，忽略调用方传入的 current
+	Load func(ctx context.Context, key string) (S, error)
+
+	// 持久化新状态；在离开钩子之后、进入钩子之前调用，失败则中止流转
+	Save func(ctx context.Context, e *Event[S, E]) error
+
+	// 分布式锁管理器；设置后同一 Key 的流转串行执行
+	Locker locker.Manager
+
+	// 锁 key 前缀
+	LockPrefix string
+
+	// 加锁选项
+	LockOptions []locker.Option
+}
+
+// Option 选项函数
+type Option[S, E comparable] func(*Config[S, E])
+
+// WithLoad 设置状态加载回调
+func WithLoad[S, E comparable](fn func(ctx context.Context, key string) (S, error)) Option[S, E] {
+	return func(c *Config[S, E]) {
+		c.Load = fn
+	}
+}
+
+// WithSave 设置状态持久化回调
+func WithSave[S, E comparable](fn func(ctx context.Context, e *Event[S, E]) error) Option[S, E] {
+	return func(c *Config[S, E]) {
+		c.Save = fn
+	}
+}
+
+// WithLocker 启用分布式锁
+func WithLocker[S, E comparable](m locker.Manager, opts ...locker.Option) Option[S, E] {
+	return func(c *Config[S, E]) {
+		c.Locker = m
+		c.LockOptions = opts
+	}
+}
+
+// WithLockPrefix 设置锁 key 前缀
+func WithLockPrefix[S, E comparable](prefix string) Option[S, E] {
+	return func(c *Config[S, E]) {
+		c.LockPrefix = prefix
+	}
+}
+
+// DefaultConfig 默认配置
+func DefaultConfig[S, E comparable]() Config[S, E] {
+	return Config[S, E]{
+		LockPrefix: "fsm:",
+	}
+}
+
+type transition[S, E comparable] struct {
+	to     S
+	guards []Guard[S, E]
+}
+
+// Machine 有限状态机定义。定义完成后可被多个 goroutine 并发使用。
+type Machine[S, E comparable] struct {
+	config Config[S, E]
+
+	mu          sync.RWMutex
+	transitions map[S]map[E]*transition[S, E]
+	onEnter     map[S][]Hook[S, E]
+	onExit      map[S][]Hook[S, E]
+	after       []Hook[S, E]
+}
+
+// New 创建状态机
+func New[S, E comparable](opts ...Option[S, E]) *Machine[S, E] {
+	config := DefaultConfig[S, E]()
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return &Machine[S, E]{
+		config:      config,
+		transitions: make(map[S]map[E]*transition[S, E]),
+		onEnter:     make(map[S][]Hook[S, E]),
+		onExit:      make(map[S][]Hook[S, E]),
+	}
+}
+
+// Transition 定义流转：在 from 中任一状态下触发 event 时进入 to
+func (m *Machine[S, E]) Transition(event E, from []S, to S, guards ...Guard[S, E]) *Machine[S, E] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, s := range from {
+		events, ok := m.transitions[s]
+		if !ok {
+			events = make(map[E]*transition[S, E])
+			m.transitions[s] = events
+		}
+		events[event] = &transition[S, E]{to: to, guards: guards}
+	}
+	return m
+}
+
+// OnEnter 注册进入状态钩子
+func (m *Machine[S, E]) OnEnter(state S, hooks ...Hook[S, E]) *Machine[S, E] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onEnter[state] = append(m.onEnter[state], hooks...)
+	return m
+}
+
+// OnExit 注册离开状态钩子
+func (m *Machine[S, E]) OnExit(state S, hooks ...Hook[S, E]) *Machine[S, E] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onExit[state] = append(m.onExit[state], hooks...)
+	return m
+}
+
+// AfterTransition 注册流转完成钩子（所有流转都会调用）
+func (m *Machine[S, E]) AfterTransition(hooks ...Hook[S, E]) *Machine[S, E] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.after = append(m.after, hooks...)
+	return m
+}
+
+// Can 判断在 state 下能否触发 event（不执行守卫）
+func (m *Machine[S, E]) Can(state S, event E) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.transitions[state][event]
+	return ok
+}
+
+// Events 返回在 state 下可触发的事件
+func (m *Machine[S, E]) Events(state S) []E {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	out := make([]E, 0, len(m.transitions[state]))
+	for e := range m.transitions[state] {
+		out = append(out, e)
+	}
+	return out
+}
+
+// Fire 触发事件并返回新状态。
+// 执行顺序：加锁 -> 加载状态 -> 守卫 -> 离开钩子 -> 持久化 -> 进入钩子 -> 完成钩子。
+// 任一步骤出错都会中止并返回原状态；持久化之后的钩子出错时状态已变更，返回新状态与错误。
+func (m *Machine[S, E]) Fire(ctx context.Context, key string, current S, event E, payload any) (S, error) {
+	if m.config.Locker != nil {
+		lock := m.config.Locker.New(m.config.LockPrefix+key, m.config.LockOptions...)
+		if err := lock.Lock(ctx); err != nil {
+			lock.Close()
+			return current, fmt.Errorf("fsm: lock %q: %w", key, err)
+		}
+		defer lock.Close()
+	}
+
+	if m.config.Load != nil {
+		s, err := m.config.Load(ctx, key)
+		if err != nil {
+			return current, fmt.Errorf("fsm: load %q: %w", key, err)
+		}
+		current = s
+	}
+
+	m.mu.RLock()
+	t, ok := m.transitions[current][event]
+	exit := m.onExit[current]
+	var enter []Hook[S, E]
+	if ok {
+		enter = m.onEnter[t.to]
+	}
+	after := m.after
+	m.mu.RUnlock()
+
+	if !ok {
+		return current, fmt.Errorf("%w: event %v from state %v", ErrInvalidTransition, event, current)
+	}
+
+	e := &Event[S, E]{Key: key, From: current, To: t.to, Event: event, Payload: payload}
+
+	for _, g := range t.guards {
+		if err := g(ctx, e); err != nil {
+			return current, fmt.Errorf("%w: %w", ErrGuardRejected, err)
+		}
+	}
+	for _, h := range exit {
+		if err := h(ctx, e); err != nil {
+			return current, err
+		}
+	}
+	if m.config.Save != nil {
+		if err := m.config.Save(ctx, e); err != nil {
+			return current, fmt.Errorf("fsm: save %q: %w", key, err)
+		}
+	}
+	for _, h := range enter {
+		if err := h(ctx, e); err != nil {
+			return e.To, err
+		}
+	}
+	for _, h := range after {
+		if err := h(ctx, e); err != nil {
+			return e.To, err
+		}
+	}
+	return e.To, nil
+}
@@ -0,0 +1,62 @@
+package fsm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jiajia556/tool-box/locker/memory"
+)
+
+type orderState string
+type orderEvent string
+
+func TestMachine_Fire(t *testing.T) {
+	mgr, _ := memory.NewMemoryManager(nil)
+	saved := map[string]orderState{"o1": "created"}
+
+	var trail []string
+	m := New(
+		WithLocker[orderState, orderEvent](mgr),
+		WithLoad[orderState, orderEvent](func(ctx context.Context, key string) (orderState, error) {
+			return saved[key], nil
+		}),
+		WithSave(func(ctx context.Context, e *Event[orderState, orderEvent]) error {
+			saved[e.Key] = e.To
+			return nil
+		}),
+	)
+	m.Transition("pay", []orderState{"created"}, "paid", func(ctx context.Context, e *Event[orderState, orderEvent]) error {
+		if e.Payload != 100 {
+			return errors.New("amount mismatch")
+		}
+		return nil
+	})
+	m.Transition("cancel", []orderState{"created", "paid"}, "cancelled")
+	m.OnExit("created", func(ctx context.Context, e *Event[orderState, orderEvent]) error {
+		trail = append(trail, "exit:"+string(e.From))
+		return nil
+	})
+	m.OnEnter("paid", func(ctx context.Context, e *Event[orderState, orderEvent]) error {
+		trail = append(trail, "enter:"+string(e.To))
+		return nil
+	})
+
+	ctx := context.Background()
+	if _, err := m.Fire(ctx, "o1", "", "pay", 1); !errors.Is(err, ErrGuardRejected) {
+		t.Fatalf("expected guard rejection, got %v", err)
+	}
+	s, err := m.Fire(ctx, "o1", "", "pay", 100)
+	if err != nil || s != "paid" || saved["o1"] != "paid" {
+		t.Fatalf("unexpected result %v, %v, saved %v", s, err, saved["o1"])
+	}
+	if len(trail) != 2 || trail[0] != "exit:created" || trail[1] != "enter:paid" {
+		t.Fatalf("unexpected hook order %v", trail)
+	}
+	if _, err := m.Fire(ctx, "o1", "", "pay", 100); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("expected ErrInvalidTransition, got %v", err)
+	}
+	if !m.Can("paid", "cancel") || m.Can("cancelled", "cancel") {
+		t.Fatalf("unexpected Can result")
+	}
+}
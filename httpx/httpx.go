@@ -9,12 +9,16 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/jiajia556/tool-box/retry"
 )
 
 type Client struct {
-	hc *http.Client
+	hc    *http.Client
+	retry *retry.Policy
 }
 
 type Option func(*requestOptions)
@@ -58,6 +62,15 @@ func WithTimeout(d time.Duration) ClientOption {
 	}
 }
 
+// WithRetry 为请求启用重试策略。
+// 网络错误与 408/425/429/5xx 响应会重试（429/503 优先遵循 Retry-After），其余 4xx 立即返回。
+// 策略对所有方法生效，非幂等请求（如 POST）是否重试需由调用方权衡。
+func WithRetry(p *retry.Policy) ClientOption {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
 // ---------------- Options ----------------
 
 func Header(k, v string) Option {
@@ -133,6 +146,28 @@ func DeleteJSON[T any](ctx context.Context, rawURL string, opts ...Option) (T, e
 // ---------------- Core ----------------
 
 func (c *Client) Do(ctx context.Context, method, rawURL string, opts ...Option) ([]byte, error) {
+	if c.retry == nil {
+		return c.do(ctx, method, rawURL, opts...)
+	}
+
+	// 原始 body 只能读取一次，重试前先缓存
+	o := applyOptions(opts)
+	if o.rawBody != nil {
+		b, err := io.ReadAll(o.rawBody)
+		if err != nil {
+			return nil, err
+		}
+		// 截断容量后追加，避免写入调用方切片的剩余容量
+		opts = append(opts[:len(opts):len(opts)], func(o *requestOptions) { o.rawBody = bytes.NewReader(b) })
+	}
+
+	return retry.Do(ctx, c.retry, func(ctx context.Context) ([]byte, error) {
+		b, err := c.do(ctx, method, rawURL, opts...)
+		return b, classify(err)
+	})
+}
+
+func (c *Client) do(ctx context.Context, method, rawURL string, opts ...Option) ([]byte, error) {
 	req, err := buildRequest(ctx, method, rawURL, opts...)
 	if err != nil {
 		return nil, err
@@ -158,6 +193,7 @@ func (c *Client) Do(ctx context.Context, method, rawURL string, opts ...Option)
 			StatusCode: resp.StatusCode,
 			Status:     resp.Status,
 			Body:       msg,
+			Header:     resp.Header,
 		}
 	}
 	return b, nil
@@ -201,12 +237,39 @@ type HTTPError struct {
 	StatusCode int
 	Status     string
 	Body       string
+	Header     http.Header
 }
 
 func (e *HTTPError) Error() string {
 	return fmt.Sprintf("http status %d (%s): %s", e.StatusCode, e.Status, e.Body)
 }
 
+// classify 将错误转换为重试策略可识别的形式
+func classify(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var he *HTTPError
+	if !errors.As(err, &he) {
+		if errors.Is(err, context.Canceled) {
+			return retry.Permanent(err)
+		}
+		return err
+	}
+
+	switch {
+	case he.StatusCode == http.StatusTooManyRequests, he.StatusCode == http.StatusServiceUnavailable:
+		if secs, convErr := strconv.Atoi(he.Header.Get("Retry-After")); convErr == nil && secs >= 0 {
+			return retry.After(err, time.Duration(secs)*time.Second)
+		}
+		return err
+	case he.StatusCode == http.StatusRequestTimeout, he.StatusCode == http.StatusTooEarly, he.StatusCode >= 500:
+		return err
+	}
+	return retry.Permanent(err)
+}
+
 // ---------------- Helpers ----------------
 
 func buildRequest(ctx context.Context, method, rawURL string, opts ...Option) (*http.Request, error) {
@@ -220,15 +283,7 @@ func buildRequest(ctx context.Context, method, rawURL string, opts ...Option) (*
 		return nil, errors.New("url is empty")
 	}
 
-	o := &requestOptions{
-		header: make(http.Header),
-		query:  make(url.Values),
-	}
-	for _, opt := range opts {
-		if opt != nil {
-			opt(o)
-		}
-	}
+	o := applyOptions(opts)
 
 	u, err := url.Parse(rawURL)
 	if err != nil {
@@ -281,6 +336,19 @@ func buildRequest(ctx context.Context, method, rawURL string, opts ...Option) (*
 	return req, nil
 }
 
+func applyOptions(opts []Option) *requestOptions {
+	o := &requestOptions{
+		header: make(http.Header),
+		query:  make(url.Values),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	return o
+}
+
 func safeSnippet(b []byte, n int) string {
 	s := string(b)
 	if len(s) <= n {
@@ -0,0 +1,238 @@
+package httpx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/jiajia556/tool-box/utils"
+)
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	optionsType = reflect.TypeOf([]Option(nil))
+	valuesType  = reflect.TypeOf(url.Values(nil))
+
+	pathParamRe = regexp.MustCompile(`\{[^{}]+\}`)
+)
+
+// Implement 根据结构体中带 `http:"METHOD /path/{param}"` 标签的函数字段生成 REST API 客户端，
+// 请求经由 c 发送（重试等行为由 c 的配置决定，c 为 nil 时使用 Default），opts 会附加到每个请求上。
+//
+// 函数字段的签名约定：
+//   - 第一个参数必须是 context.Context；可选的最后一个变参 ...httpx.Option 作为单次请求的额外选项
+//   - 基础类型参数按顺序填充路径中的 {param}
+//   - 结构体（或其指针）、map 参数：GET / DELETE / HEAD 按 `form` 标签编码为查询参数（见 utils.ToQuery），其余方法作为 JSON body
+//   - url.Values 参数：GET / DELETE / HEAD 作为查询参数，其余方法作为表单 body
+//   - 返回值为 error 或 (T, error)，T 按 JSON 解码（[]byte / string 直接返回原始内容）
+//
+// 示例：
+//
+//	type UserAPI struct {
+//		Get    func(ctx context.Context, id int64) (*User, error)          `http:"GET /users/{id}"`
+//		Search func(ctx context.Context, q SearchQuery) ([]User, error)    `http:"GET /users"`
+//		Create func(ctx context.Context, in CreateUser) (*User, error)     `http:"POST /users"`
+//		Delete func(ctx context.Context, id int64, opts ...httpx.Option) error `http:"DELETE /users/{id}"`
+//	}
+//
+//	api, err := httpx.Implement[UserAPI]("https://api.example.com", client, httpx.Header("Authorization", token))
+func Implement[T any](baseURL string, c *Client, opts ...Option) (T, error) {
+	var api T
+	rv := reflect.ValueOf(&api).Elem()
+	if rv.Kind() != reflect.Struct {
+		return api, fmt.Errorf("httpx: Implement: %T is not a struct", api)
+	}
+	if c == nil {
+		c = Default
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		tag, ok := sf.Tag.Lookup("http")
+		if !ok {
+			continue
+		}
+		if !sf.IsExported() || sf.Type.Kind() != reflect.Func {
+			return api, fmt.Errorf("httpx: Implement: field %s must be an exported func", sf.Name)
+		}
+
+		ep, err := parseEndpoint(tag, sf.Type)
+		if err != nil {
+			return api, fmt.Errorf("httpx: Implement: field %s: %w", sf.Name, err)
+		}
+		rv.Field(i).Set(reflect.MakeFunc(sf.Type, ep.handler(c, baseURL, opts)))
+	}
+	return api, nil
+}
+
+// endpoint 一个函数字段对应的请求定义
+type endpoint struct {
+	method   string
+	path     string
+	fn       reflect.Type
+	hasQuery bool // 结构体参数是否编码为查询参数
+}
+
+func parseEndpoint(tag string, ft reflect.Type) (*endpoint, error) {
+	method, path, ok := strings.Cut(strings.TrimSpace(tag), " ")
+	if !ok || method == "" {
+		return nil, fmt.Errorf("invalid http tag %q, expect \"METHOD /path\"", tag)
+	}
+	ep := &endpoint{method: strings.ToUpper(method), path: strings.TrimSpace(path), fn: ft}
+	switch ep.method {
+	case http.MethodGet, http.MethodDelete, http.MethodHead:
+		ep.hasQuery = true
+	}
+
+	if ft.NumIn() == 0 || ft.In(0) != contextType {
+		return nil, errors.New("first parameter must be context.Context")
+	}
+
+	scalars := 0
+	for i := 1; i < ft.NumIn(); i++ {
+		in := ft.In(i)
+		if ft.IsVariadic() && i == ft.NumIn()-1 {
+			if in != optionsType {
+				return nil, errors.New("variadic parameter must be ...httpx.Option")
+			}
+			continue
+		}
+		if isScalarKind(in.Kind()) {
+			scalars++
+		}
+	}
+	if params := len(pathParamRe.FindAllString(ep.path, -1)); params != scalars {
+		return nil, fmt.Errorf("path has %d params but func has %d scalar arguments", params, scalars)
+	}
+
+	switch ft.NumOut() {
+	case 1:
+	case 2:
+		if ft.Out(0) == errorType {
+			return nil, errors.New("first result must not be error")
+		}
+	default:
+		return nil, errors.New("results must be error or (T, error)")
+	}
+	if ft.Out(ft.NumOut()-1) != errorType {
+		return nil, errors.New("last result must be error")
+	}
+	return ep, nil
+}
+
+func (ep *endpoint) handler(c *Client, baseURL string, defaults []Option) func([]reflect.Value) []reflect.Value {
+	return func(args []reflect.Value) []reflect.Value {
+		ctx, _ := args[0].Interface().(context.Context)
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		opts := append([]Option(nil), defaults...)
+		var scalars []string
+		for i := 1; i < len(args); i++ {
+			arg := args[i]
+			if ep.fn.IsVariadic() && i == len(args)-1 {
+				opts = append(opts, arg.Interface().([]Option)...)
+				continue
+			}
+			switch {
+			case isScalarKind(arg.Kind()):
+				scalars = append(scalars, url.PathEscape(fmt.Sprint(arg.Interface())))
+			case arg.Type() == valuesType:
+				values := arg.Interface().(url.Values)
+				if ep.hasQuery {
+					opts = append(opts, queryValues(values))
+				} else {
+					opts = append(opts, Form(values))
+				}
+			case ep.hasQuery:
+				if arg.Kind() == reflect.Map {
+					opts = append(opts, queryValues(mapValues(arg)))
+				} else {
+					opts = append(opts, queryValues(utils.ToQuery(arg.Interface())))
+				}
+			default:
+				opts = append(opts, JSONBody(arg.Interface()))
+			}
+		}
+
+		n := 0
+		path := pathParamRe.ReplaceAllStringFunc(ep.path, func(string) string {
+			s := scalars[n]
+			n++
+			return s
+		})
+
+		b, err := c.Do(ctx, ep.method, baseURL+path, append(opts, ExpectJSON())...)
+		return ep.results(b, err)
+	}
+}
+
+func (ep *endpoint) results(b []byte, err error) []reflect.Value {
+	errValue := reflect.Zero(errorType)
+	if err != nil {
+		errValue = reflect.ValueOf(&err).Elem()
+	}
+	if ep.fn.NumOut() == 1 {
+		return []reflect.Value{errValue}
+	}
+
+	outType := ep.fn.Out(0)
+	out := reflect.New(outType).Elem()
+	if err != nil {
+		return []reflect.Value{out, errValue}
+	}
+
+	switch {
+	case outType.Kind() == reflect.String:
+		out.SetString(string(b))
+	case outType.Kind() == reflect.Slice && outType.Elem().Kind() == reflect.Uint8:
+		out.SetBytes(b)
+	case len(bytes.TrimSpace(b)) > 0:
+		if err := json.Unmarshal(b, out.Addr().Interface()); err != nil {
+			err = fmt.Errorf("json unmarshal failed: %w; body=%s", err, safeSnippet(b, 2048))
+			return []reflect.Value{reflect.New(outType).Elem(), reflect.ValueOf(&err).Elem()}
+		}
+	}
+	return []reflect.Value{out, errValue}
+}
+
+func queryValues(values url.Values) Option {
+	return func(o *requestOptions) {
+		for k, vs := range values {
+			for _, v := range vs {
+				o.query.Add(k, v)
+			}
+		}
+	}
+}
+
+func mapValues(m reflect.Value) url.Values {
+	values := url.Values{}
+	iter := m.MapRange()
+	for iter.Next() {
+		values.Set(fmt.Sprint(iter.Key().Interface()), fmt.Sprint(iter.Value().Interface()))
+	}
+	return values
+}
+
+func isScalarKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package httpx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type testUser struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type testSearch struct {
+	Name string `form:"name"`
+	Page int    `form:"page,omitempty"`
+}
+
+type testAPI struct {
+	Get    func(ctx context.Context, id int64) (*testUser, error)      `http:"GET /users/{id}"`
+	Search func(ctx context.Context, q testSearch) ([]testUser, error) `http:"GET /users"`
+	Create func(ctx context.Context, in testUser) (testUser, error)    `http:"POST /users"`
+	Delete func(ctx context.Context, id int64, opts ...Option) error   `http:"DELETE /users/{id}"`
+	Ignore func()
+}
+
+func TestImplement(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Token") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/users/7":
+			_ = json.NewEncoder(w).Encode(testUser{ID: 7, Name: "seven"})
+		case r.Method == http.MethodGet && r.URL.Path == "/users":
+			_ = json.NewEncoder(w).Encode([]testUser{{ID: 1, Name: r.URL.Query().Get("name")}})
+		case r.Method == http.MethodPost && r.URL.Path == "/users":
+			var in testUser
+			_ = json.NewDecoder(r.Body).Decode(&in)
+			in.ID = 42
+			_ = json.NewEncoder(w).Encode(in)
+		case r.Method == http.MethodDelete && r.URL.Path == "/users/7" && r.Header.Get("X-Reason") == "test":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	api, err := Implement[testAPI](srv.URL+"/", New(), Header("X-Token", "secret"))
+	if err != nil {
+		t.Fatalf("Implement: %v", err)
+	}
+	ctx := context.Background()
+
+	u, err := api.Get(ctx, 7)
+	if err != nil || u.Name != "seven" {
+		t.Fatalf("Get: %+v %v", u, err)
+	}
+
+	users, err := api.Search(ctx, testSearch{Name: "bob"})
+	if err != nil || len(users) != 1 || users[0].Name != "bob" {
+		t.Fatalf("Search: %+v %v", users, err)
+	}
+
+	created, err := api.Create(ctx, testUser{Name: "new"})
+	if err != nil || created.ID != 42 || created.Name != "new" {
+		t.Fatalf("Create: %+v %v", created, err)
+	}
+
+	if err := api.Delete(ctx, 7, Header("X-Reason", "test")); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	var httpErr *HTTPError
+	if err := api.Delete(ctx, 8); err == nil || !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 HTTPError, got %v", err)
+	}
+}
+
+func TestImplement_InvalidSignature(t *testing.T) {
+	type badAPI struct {
+		Get func(id int64) error `http:"GET /users/{id}"`
+	}
+	if _, err := Implement[badAPI]("http://example.com", nil); err == nil {
+		t.Fatalf("expected error for missing context parameter")
+	}
+}
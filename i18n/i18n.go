@@ -0,0 +1,338 @@
+package i18n
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Args 模板参数；其中 "Count" 同时用于选择复数形式
+type Args map[string]any
+
+// message 单条翻译：普通文本或按复数类别区分的多个文本
+type message struct {
+	text   string
+	plural map[string]string
+}
+
+// Bundle 翻译集合
+type Bundle struct {
+	defaultLang string
+
+	mu       sync.RWMutex
+	catalogs map[string]map[string]*message
+
+	tplCache sync.Map // string -> *template.Template
+}
+
+// NewBundle 创建翻译集合，defaultLang 为找不到翻译时的回退语言
+func NewBundle(defaultLang string) *Bundle {
+	return &Bundle{
+		defaultLang: defaultLang,
+		catalogs:    make(map[string]map[string]*message),
+	}
+}
+
+// DefaultLanguage 返回默认语言
+func (b *Bundle) DefaultLanguage() string {
+	return b.defaultLang
+}
+
+// Languages 返回已加载的语言
+func (b *Bundle) Languages() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	out := make([]string, 0, len(b.catalogs))
+	for lang := range b.catalogs {
+		out = append(out, lang)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// AddMessages 添加翻译。
+// 值为字符串时是普通文本；值为对象且 key 都是复数类别（one/other 等）时是复数文本；
+// 其他对象视为命名空间，key 以 "." 连接，例如 {"user": {"name": "..."}} 对应 "user.name"。
+func (b *Bundle) AddMessages(lang string, msgs map[string]any) error {
+	flat := make(map[string]*message)
+	if err := flatten("", msgs, flat); err != nil {
+		return fmt.Errorf("i18n: %s: %w", lang, err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	catalog, ok := b.catalogs[lang]
+	if !ok {
+		catalog = make(map[string]*message, len(flat))
+		b.catalogs[lang] = catalog
+	}
+	for k, m := range flat {
+		catalog[k] = m
+	}
+	return nil
+}
+
+// LoadFile 加载 JSON / TOML 翻译文件。
+// 语言取自文件名，例如 "zh-CN.json"、"messages.en.toml"。
+func (b *Bundle) LoadFile(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("i18n: read %s: %w", file, err)
+	}
+	return b.load(filepath.Base(file), data)
+}
+
+// LoadFS 从 fs.FS（通常为 embed.FS）中按 patterns 加载翻译文件
+func (b *Bundle) LoadFS(fsys fs.FS, patterns ...string) error {
+	for _, pattern := range patterns {
+		files, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return fmt.Errorf("i18n: %w", err)
+		}
+		for _, file := range files {
+			data, err := fs.ReadFile(fsys, file)
+			if err != nil {
+				return fmt.Errorf("i18n: read %s: %w", file, err)
+			}
+			if err := b.load(path.Base(file), data); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (b *Bundle) load(name string, data []byte) error {
+	ext := strings.ToLower(path.Ext(name))
+	base := strings.TrimSuffix(name, path.Ext(name))
+	lang := base[strings.LastIndex(base, ".")+1:]
+
+	msgs := make(map[string]any)
+	var err error
+	switch ext {
+	case ".json":
+		err = json.Unmarshal(data, &msgs)
+	case ".toml":
+		err = toml.Unmarshal(data, &msgs)
+	default:
+		return fmt.Errorf("i18n: unsupported file format %q", name)
+	}
+	if err != nil {
+		return fmt.Errorf("i18n: parse %s: %w", name, err)
+	}
+	return b.AddMessages(lang, msgs)
+}
+
+func flatten(prefix string, in map[string]any, out map[string]*message) error {
+	for k, v := range in {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+
+		switch val := v.(type) {
+		case string:
+			out[key] = &message{text: val}
+		case map[string]any:
+			if isPlural(val) {
+				m := &message{plural: make(map[string]string, len(val))}
+				for cat, s := range val {
+					m.plural[cat] = s.(string)
+				}
+				out[key] = m
+				continue
+			}
+			if err := flatten(key, val, out); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("invalid value for key %q", key)
+		}
+	}
+	return nil
+}
+
+func isPlural(m map[string]any) bool {
+	if len(m) == 0 {
+		return false
+	}
+	for k, v := range m {
+		switch k {
+		case PluralZero, PluralOne, PluralTwo, PluralFew, PluralMany, PluralOther:
+		default:
+			return false
+		}
+		if _, ok := v.(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Translate 翻译 key。
+// args 可选，第一个参数作为模板数据（如 Args{"Name": "Tom", "Count": 3}），
+// 文本中使用 {{.Name}} 引用参数。
+// 查找顺序：lang -> 基础语言 -> 默认语言，均找不到时返回 key 本身。
+func (b *Bundle) Translate(lang, key string, args ...any) string {
+	msg, lang := b.lookup(lang, key)
+	if msg == nil {
+		return key
+	}
+
+	var data any
+	if len(args) > 0 {
+		data = args[0]
+	}
+
+	text := msg.text
+	if msg.plural != nil {
+		n, _ := countOf(data)
+		var ok bool
+		if text, ok = msg.plural[PluralCategory(lang, n)]; !ok {
+			text = msg.plural[PluralOther]
+		}
+	}
+
+	if data == nil || !strings.Contains(text, "{{") {
+		return text
+	}
+	return b.execute(text, data)
+}
+
+// T 按 context 中的语言翻译（见 WithLocale / Middleware），未设置时使用默认语言
+func (b *Bundle) T(ctx context.Context, key string, args ...any) string {
+	lang, ok := LocaleFromContext(ctx)
+	if !ok {
+		lang = b.defaultLang
+	}
+	return b.Translate(lang, key, args...)
+}
+
+func (b *Bundle) lookup(lang, key string) (*message, string) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, l := range []string{lang, baseLang(lang), b.defaultLang} {
+		if catalog, ok := b.catalogs[l]; ok {
+			if m, ok := catalog[key]; ok {
+				return m, l
+			}
+		}
+	}
+	return nil, lang
+}
+
+func (b *Bundle) execute(text string, data any) string {
+	var tpl *template.Template
+	if v, ok := b.tplCache.Load(text); ok {
+		tpl = v.(*template.Template)
+	} else {
+		t, err := template.New("").Option("missingkey=zero").Parse(text)
+		if err != nil {
+			return text
+		}
+		b.tplCache.Store(text, t)
+		tpl = t
+	}
+
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return text
+	}
+	return buf.String()
+}
+
+// countOf 从模板数据中取出 Count（支持 map 与结构体字段）
+func countOf(data any) (int, bool) {
+	var v any
+	switch d := data.(type) {
+	case nil:
+		return 0, false
+	case Args:
+		v = d["Count"]
+	case map[string]any:
+		v = d["Count"]
+	default:
+		rv := reflect.Indirect(reflect.ValueOf(data))
+		if rv.Kind() != reflect.Struct {
+			return 0, false
+		}
+		f := rv.FieldByName("Count")
+		if !f.IsValid() || !f.CanInterface() {
+			return 0, false
+		}
+		v = f.Interface()
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return int(rv.Float()), true
+	}
+	return 0, false
+}
+
+var (
+	globalMu sync.RWMutex
+	global   *Bundle
+)
+
+// Init 创建全局翻译集合并加载文件
+func Init(defaultLang string, files ...string) error {
+	b := NewBundle(defaultLang)
+	for _, f := range files {
+		if err := b.LoadFile(f); err != nil {
+			return err
+		}
+	}
+	SetGlobal(b)
+	return nil
+}
+
+// SetGlobal 设置全局翻译集合
+func SetGlobal(b *Bundle) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	global = b
+}
+
+// Global 返回全局翻译集合
+func Global() *Bundle {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+	return global
+}
+
+// T 使用全局翻译集合翻译；未初始化时返回 key 本身
+func T(ctx context.Context, key string, args ...any) string {
+	b := Global()
+	if b == nil {
+		return key
+	}
+	return b.T(ctx, key, args...)
+}
+
+// FuncMap 返回模板函数 {{t .Lang "key" .Args}}，可用于 html/template 与 text/template
+func (b *Bundle) FuncMap() map[string]any {
+	return map[string]any{
+		"t": b.Translate,
+	}
+}
@@ -0,0 +1,62 @@
+package i18n
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestBundle(t *testing.T) {
+	fsys := fstest.MapFS{
+		"locales/en.json": {Data: []byte(`{
+			"hello": "Hello, {{.Name}}",
+			"cart": {"items": {"one": "{{.Count}} item", "other": "{{.Count}} items"}}
+		}`)},
+		"locales/zh-CN.toml": {Data: []byte(`
+hello = "你好，{{.Name}}"
+[cart.items]
+other = "{{.Count}} 件商品"
+`)},
+	}
+
+	b := NewBundle("en")
+	if err := b.LoadFS(fsys, "locales/*"); err != nil {
+		t.Fatalf("LoadFS: %v", err)
+	}
+
+	cases := []struct {
+		lang, key string
+		args      Args
+		want      string
+	}{
+		{"en", "hello", Args{"Name": "Tom"}, "Hello, Tom"},
+		{"zh-CN", "hello", Args{"Name": "Tom"}, "你好，Tom"},
+		{"en", "cart.items", Args{"Count": 1}, "1 item"},
+		{"en", "cart.items", Args{"Count": 2}, "2 items"},
+		{"zh-CN", "cart.items", Args{"Count": 1}, "1 件商品"},
+		{"fr", "hello", Args{"Name": "Tom"}, "Hello, Tom"},
+		{"en", "missing", nil, "missing"},
+	}
+	for _, c := range cases {
+		if got := b.Translate(c.lang, c.key, c.args); got != c.want {
+			t.Errorf("Translate(%s, %s) = %q, want %q", c.lang, c.key, got, c.want)
+		}
+	}
+
+	var got string
+	h := b.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = b.T(r.Context(), "hello", Args{"Name": "Tom"})
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "fr;q=0.9, zh;q=0.8, en;q=0.1")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if got != "你好，Tom" {
+		t.Fatalf("unexpected negotiated translation %q", got)
+	}
+
+	if v := b.T(context.Background(), "hello", Args{"Name": "Tom"}); v != "Hello, Tom" {
+		t.Fatalf("expected default language, got %q", v)
+	}
+}
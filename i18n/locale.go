@@ -0,0 +1,125 @@
+package i18n
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type ctxKey struct{}
+
+// WithLocale 将语言写入 context
+func WithLocale(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, lang)
+}
+
+// LocaleFromContext 从 context 读取语言
+func LocaleFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	lang, ok := ctx.Value(ctxKey{}).(string)
+	return lang, ok && lang != ""
+}
+
+// ParseAcceptLanguage 解析 Accept-Language，按权重从高到低返回语言列表
+func ParseAcceptLanguage(header string) []string {
+	type entry struct {
+		lang string
+		q    float64
+	}
+
+	var entries []entry
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		lang, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		lang = strings.TrimSpace(lang)
+		if lang == "" || lang == "*" || q <= 0 {
+			continue
+		}
+		entries = append(entries, entry{lang: lang, q: q})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].q > entries[j].q
+	})
+
+	out := make([]string, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, e.lang)
+	}
+	return out
+}
+
+// Match 从 supported 中选出与 preferred 最匹配的语言。
+// 依次尝试：完全匹配 -> 基础语言匹配（zh-TW 匹配 zh）-> 同基础语言的其他地区（zh 匹配 zh-CN）。
+func Match(preferred, supported []string) (string, bool) {
+	for _, p := range preferred {
+		for _, s := range supported {
+			if strings.EqualFold(p, s) {
+				return s, true
+			}
+		}
+		base := baseLang(p)
+		for _, s := range supported {
+			if strings.EqualFold(base, s) {
+				return s, true
+			}
+		}
+		for _, s := range supported {
+			if baseLang(s) == base {
+				return s, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Negotiate 根据 Accept-Language 选择 Bundle 支持的语言，无法匹配时返回默认语言
+func (b *Bundle) Negotiate(acceptLanguage string) string {
+	if lang, ok := Match(ParseAcceptLanguage(acceptLanguage), b.Languages()); ok {
+		return lang
+	}
+	return b.defaultLang
+}
+
+// Middleware 协商请求语言并写入 context。
+// 优先级：查询参数 lang > Cookie lang > Accept-Language。
+func (b *Bundle) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var preferred []string
+		if v := r.URL.Query().Get("lang"); v != "" {
+			preferred = append(preferred, v)
+		}
+		if c, err := r.Cookie("lang"); err == nil && c.Value != "" {
+			preferred = append(preferred, c.Value)
+		}
+		preferred = append(preferred, ParseAcceptLanguage(r.Header.Get("Accept-Language"))...)
+
+		lang, ok := Match(preferred, b.Languages())
+		if !ok {
+			lang = b.defaultLang
+		}
+		w.Header().Set("Content-Language", lang)
+		next.ServeHTTP(w, r.WithContext(WithLocale(r.Context(), lang)))
+	})
+}
+
+func baseLang(lang string) string {
+	lang = strings.ToLower(lang)
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		return lang[:i]
+	}
+	return lang
+}
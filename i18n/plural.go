@@ -0,0 +1,109 @@
+package i18n
+
+import (
+	"strings"
+	"sync"
+)
+
+// 复数类别（CLDR）
+const (
+	PluralZero  = "zero"
+	PluralOne   = "one"
+	PluralTwo   = "two"
+	PluralFew   = "few"
+	PluralMany  = "many"
+	PluralOther = "other"
+)
+
+// PluralRule 根据数量返回复数类别
+type PluralRule func(n int) string
+
+var (
+	pluralMu    sync.RWMutex
+	pluralRules = map[string]PluralRule{}
+)
+
+func init() {
+	for _, lang := range []string{"zh", "ja", "ko", "vi", "th", "id", "ms"} {
+		pluralRules[lang] = pluralOther
+	}
+	for _, lang := range []string{"en", "de", "nl", "sv", "da", "no", "nb", "fi", "it", "es", "pt", "el", "hu", "tr", "bg"} {
+		pluralRules[lang] = pluralOneOther
+	}
+	pluralRules["fr"] = func(n int) string {
+		if n == 0 || n == 1 {
+			return PluralOne
+		}
+		return PluralOther
+	}
+	for _, lang := range []string{"ru", "uk", "be"} {
+		pluralRules[lang] = pluralSlavic
+	}
+	pluralRules["pl"] = func(n int) string {
+		switch {
+		case n == 1:
+			return PluralOne
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return PluralFew
+		}
+		return PluralMany
+	}
+	pluralRules["ar"] = func(n int) string {
+		switch {
+		case n == 0:
+			return PluralZero
+		case n == 1:
+			return PluralOne
+		case n == 2:
+			return PluralTwo
+		case n%100 >= 3 && n%100 <= 10:
+			return PluralFew
+		case n%100 >= 11:
+			return PluralMany
+		}
+		return PluralOther
+	}
+}
+
+// RegisterPluralRule 注册（或覆盖）语言的复数规则，lang 使用基础语言代码，如 "en"
+func RegisterPluralRule(lang string, rule PluralRule) {
+	pluralMu.Lock()
+	defer pluralMu.Unlock()
+	pluralRules[strings.ToLower(lang)] = rule
+}
+
+// PluralCategory 返回语言 lang 下数量 n 的复数类别；未知语言按 one/other 处理
+func PluralCategory(lang string, n int) string {
+	pluralMu.RLock()
+	rule, ok := pluralRules[strings.ToLower(lang)]
+	if !ok {
+		rule, ok = pluralRules[baseLang(lang)]
+	}
+	pluralMu.RUnlock()
+
+	if !ok {
+		rule = pluralOneOther
+	}
+	return rule(n)
+}
+
+func pluralOther(int) string {
+	return PluralOther
+}
+
+func pluralOneOther(n int) string {
+	if n == 1 {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+func pluralSlavic(n int) string {
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return PluralOne
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return PluralFew
+	}
+	return PluralMany
+}
@@ -0,0 +1,302 @@
+package idgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jiajia556/tool-box/locker"
+)
+
+var (
+	ErrNoGlobal      = errors.New("idgen: global instance is nil")
+	ErrNoWorkerID    = errors.New("idgen: no free worker id")
+	ErrLeaseLost     = errors.New("idgen: worker id lease lost")
+	ErrNoLocker      = errors.New("idgen: locker manager is nil")
+	ErrGeneratorOff  = errors.New("idgen: generator closed")
+	ErrInvalidConfig = errors.New("idgen: invalid config")
+)
+
+// Config 生成器配置
+type Config struct {
+	// 起始时间
+	Epoch time.Time
+
+	// 租约 key 前缀，实际 key 为 "<前缀><workerID>"
+	KeyPrefix string
+
+	// 租约有效期
+	LeaseTTL time.Duration
+
+	// 续约间隔，默认 LeaseTTL/3，须小于 LeaseTTL；续约出错时按此间隔重试，直到租约到期
+	HeartbeatInterval time.Duration
+
+	// 可分配的 worker ID 数量（0 ~ MaxWorkers-1）
+	MaxWorkers int
+
+	// 租约丢失（锁被他人持有，或续约持续失败直到租约到期）回调；此后 NextID 返回 ErrLeaseLost
+	OnLeaseLost func(workerID int64, err error)
+}
+
+// Option 选项函数
+type Option func(*Config)
+
+// WithEpoch 设置起始时间
+func WithEpoch(t time.Time) Option {
+	return func(c *Config) {
+		c.Epoch = t
+	}
+}
+
+// WithKeyPrefix 设置租约 key 前缀
+func WithKeyPrefix(prefix string) Option {
+	return func(c *Config) {
+		c.KeyPrefix = prefix
+	}
+}
+
+// WithLease 设置租约有效期与续约间隔
+func WithLease(ttl, heartbeat time.Duration) Option {
+	return func(c *Config) {
+		c.LeaseTTL = ttl
+		c.HeartbeatInterval = heartbeat
+	}
+}
+
+// WithMaxWorkers 设置可分配的 worker ID 数量
+func WithMaxWorkers(n int) Option {
+	return func(c *Config) {
+		c.MaxWorkers = n
+	}
+}
+
+// WithOnLeaseLost 设置续约失败回调
+func WithOnLeaseLost(fn func(workerID int64, err error)) Option {
+	return func(c *Config) {
+		c.OnLeaseLost = fn
+	}
+}
+
+// DefaultConfig 默认配置
+func DefaultConfig() Config {
+	return Config{
+		Epoch:      DefaultEpoch,
+		KeyPrefix:  "idgen:worker:",
+		LeaseTTL:   30 * time.Second,
+		MaxWorkers: MaxWorkerID + 1,
+	}
+}
+
+// Generator 通过分布式锁租用 worker ID 的雪花 ID 生成器。
+// 启动时抢占一个空闲 worker ID 并定期续约，Close 时释放，避免扩缩容时多个实例使用相同 worker ID。
+type Generator struct {
+	sf     *Snowflake
+	lock   locker.Locker
+	config Config
+
+	mu         sync.RWMutex
+	err        error
+	leaseUntil time.Time // 最近一次成功续约后租约的到期时间
+	stop       chan struct{}
+	wg         sync.WaitGroup
+	closed     sync.Once
+}
+
+// New 租用 worker ID 并创建生成器
+func New(ctx context.Context, m locker.Manager, opts ...Option) (*Generator, error) {
+	if m == nil {
+		return nil, ErrNoLocker
+	}
+
+	config := DefaultConfig()
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.MaxWorkers <= 0 || config.MaxWorkers > MaxWorkerID+1 {
+		config.MaxWorkers = MaxWorkerID + 1
+	}
+	if config.LeaseTTL <= 0 {
+		return nil, fmt.Errorf("%w: LeaseTTL must be positive", ErrInvalidConfig)
+	}
+	if config.HeartbeatInterval <= 0 {
+		config.HeartbeatInterval = config.LeaseTTL / 3
+	}
+	if config.HeartbeatInterval >= config.LeaseTTL {
+		return nil, fmt.Errorf("%w: HeartbeatInterval must be less than LeaseTTL", ErrInvalidConfig)
+	}
+
+	leased := time.Now()
+	workerID, lock, err := claim(ctx, m, config)
+	if err != nil {
+		return nil, err
+	}
+
+	sf, err := NewSnowflake(workerID, config.Epoch)
+	if err != nil {
+		lock.Close()
+		return nil, err
+	}
+
+	g := &Generator{
+		sf:         sf,
+		lock:       lock,
+		config:     config,
+		leaseUntil: leased.Add(config.LeaseTTL),
+		stop:       make(chan struct{}),
+	}
+	g.wg.Add(1)
+	go g.heartbeat()
+	return g, nil
+}
+
+// claim 从随机位置开始依次尝试抢占 worker ID，减少实例同时启动时的冲突
+func claim(ctx context.Context, m locker.Manager, config Config) (int64, locker.Locker, error) {
+	start := rand.IntN(config.MaxWorkers)
+	for i := 0; i < config.MaxWorkers; i++ {
+		id := int64((start + i) % config.MaxWorkers)
+		lock := m.New(config.KeyPrefix+strconv.FormatInt(id, 10),
+			locker.WithTTL(config.LeaseTTL),
+			locker.WithAutoClose(false),
+		)
+
+		ok, err := lock.TryLock(ctx)
+		if err != nil {
+			lock.Close()
+			return 0, nil, fmt.Errorf("idgen: claim worker id: %w", err)
+		}
+		if ok {
+			return id, lock, nil
+		}
+		lock.Close()
+	}
+	return 0, nil, ErrNoWorkerID
+}
+
+func (g *Generator) heartbeat() {
+	defer g.wg.Done()
+
+	ticker := time.NewTicker(g.config.HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-g.stop:
+			return
+		case <-ticker.C:
+			start := time.Now()
+			ctx, cancel := context.WithTimeout(context.Background(), g.config.HeartbeatInterval)
+			err := g.lock.Refresh(ctx, g.config.LeaseTTL)
+			cancel()
+
+			g.mu.Lock()
+			if err == nil {
+				g.leaseUntil = start.Add(g.config.LeaseTTL)
+				g.mu.Unlock()
+				continue
+			}
+			// 锁已不属于自己时立即判定丢失；其他错误（如网络抖动）在租约到期前继续重试
+			if !errors.Is(err, locker.ErrLockNotHeld) && time.Now().Before(g.leaseUntil) {
+				g.mu.Unlock()
+				continue
+			}
+			g.err = fmt.Errorf("%w: %w", ErrLeaseLost, err)
+			g.mu.Unlock()
+			if g.config.OnLeaseLost != nil {
+				g.config.OnLeaseLost(g.sf.WorkerID(), err)
+			}
+			return
+		}
+	}
+}
+
+// WorkerID 返回租用的 worker ID
+func (g *Generator) WorkerID() int64 {
+	return g.sf.WorkerID()
+}
+
+// NextID 生成下一个 ID；租约丢失、续约失败至租约到期或已关闭时返回错误
+func (g *Generator) NextID() (int64, error) {
+	g.mu.RLock()
+	err, until := g.err, g.leaseUntil
+	g.mu.RUnlock()
+
+	if err != nil {
+		return 0, err
+	}
+	if !time.Now().Before(until) {
+		return 0, ErrLeaseLost
+	}
+	return g.sf.NextID()
+}
+
+// Close 停止续约并释放 worker ID
+func (g *Generator) Close() error {
+	g.closed.Do(func() {
+		close(g.stop)
+		g.wg.Wait()
+
+		g.mu.Lock()
+		if g.err == nil {
+			g.err = ErrGeneratorOff
+		}
+		g.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = g.lock.Unlock(ctx)
+		_ = g.lock.Close()
+	})
+	return nil
+}
+
+var (
+	globalMu sync.RWMutex
+	global   *Generator
+)
+
+// Init 初始化全局生成器
+func Init(ctx context.Context, m locker.Manager, opts ...Option) error {
+	g, err := New(ctx, m, opts...)
+	if err != nil {
+		return err
+	}
+
+	globalMu.Lock()
+	old := global
+	global = g
+	globalMu.Unlock()
+
+	if old != nil {
+		_ = old.Close()
+	}
+	return nil
+}
+
+// NextID 使用全局生成器生成 ID
+func NextID() (int64, error) {
+	globalMu.RLock()
+	g := global
+	globalMu.RUnlock()
+
+	if g == nil {
+		return 0, ErrNoGlobal
+	}
+	return g.NextID()
+}
+
+// Close 关闭全局生成器
+func Close() error {
+	globalMu.Lock()
+	g := global
+	global = nil
+	globalMu.Unlock()
+
+	if g == nil {
+		return nil
+	}
+	return g.Close()
+}
@@ -0,0 +1,121 @@
+package idgen
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jiajia556/tool-box/locker"
+	"github.com/jiajia556/tool-box/locker/memory"
+)
+
+func TestGenerator_LeasesDistinctWorkers(t *testing.T) {
+	m, _ := memory.NewMemoryManager(nil)
+	ctx := context.Background()
+
+	g1, err := New(ctx, m, WithMaxWorkers(2), WithLease(time.Minute, 0))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	g2, err := New(ctx, m, WithMaxWorkers(2), WithLease(time.Minute, 0))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if g1.WorkerID() == g2.WorkerID() {
+		t.Fatalf("expected distinct worker ids, both got %d", g1.WorkerID())
+	}
+	if _, err := New(ctx, m, WithMaxWorkers(2)); !errors.Is(err, ErrNoWorkerID) {
+		t.Fatalf("expected ErrNoWorkerID, got %v", err)
+	}
+
+	seen := make(map[int64]bool)
+	for i := 0; i < 10000; i++ {
+		id, err := g1.NextID()
+		if err != nil {
+			t.Fatalf("NextID: %v", err)
+		}
+		if seen[id] {
+			t.Fatalf("duplicate id %d", id)
+		}
+		seen[id] = true
+	}
+	id, _ := g1.NextID()
+	if _, worker, _ := Parse(id, time.Time{}); worker != g1.WorkerID() {
+		t.Fatalf("unexpected worker id %d in %d", worker, id)
+	}
+
+	g1.Close()
+	if _, err := g1.NextID(); !errors.Is(err, ErrGeneratorOff) {
+		t.Fatalf("expected ErrGeneratorOff, got %v", err)
+	}
+	g3, err := New(ctx, m, WithMaxWorkers(2))
+	if err != nil {
+		t.Fatalf("expected released worker id to be reusable: %v", err)
+	}
+	g2.Close()
+	g3.Close()
+}
+
+// flakyManager 创建的锁在 fails 次续约内返回临时错误
+type flakyManager struct {
+	locker.Manager
+	fails *atomic.Int32
+}
+
+func (m flakyManager) New(key string, opts ...locker.Option) locker.Locker {
+	return flakyLocker{m.Manager.New(key, opts...), m.fails}
+}
+
+type flakyLocker struct {
+	locker.Locker
+	fails *atomic.Int32
+}
+
+func (l flakyLocker) Refresh(ctx context.Context, ttl time.Duration) error {
+	if l.fails.Add(-1) >= 0 {
+		return errors.New("connection reset")
+	}
+	return l.Locker.Refresh(ctx, ttl)
+}
+
+func TestGenerator_HeartbeatRetriesTransientErrors(t *testing.T) {
+	mm, _ := memory.NewMemoryManager(nil)
+	fails := new(atomic.Int32)
+	fails.Store(2)
+
+	lost := make(chan struct{}, 1)
+	g, err := New(context.Background(), flakyManager{mm, fails}, WithLease(300*time.Millisecond, 50*time.Millisecond),
+		WithOnLeaseLost(func(int64, error) { lost <- struct{}{} }))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer g.Close()
+
+	// 两次续约失败后恢复，租约未到期，生成器继续可用
+	time.Sleep(250 * time.Millisecond)
+	if _, err := g.NextID(); err != nil {
+		t.Fatalf("NextID after transient refresh errors: %v", err)
+	}
+
+	// 续约一直失败直到租约到期后判定丢失
+	fails.Store(1 << 30)
+	select {
+	case <-lost:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("lease not reported lost after expiry")
+	}
+	if _, err := g.NextID(); !errors.Is(err, ErrLeaseLost) {
+		t.Fatalf("expected ErrLeaseLost, got %v", err)
+	}
+}
+
+func TestNew_InvalidLease(t *testing.T) {
+	m, _ := memory.NewMemoryManager(nil)
+	for _, opt := range []Option{WithLease(0, 0), WithLease(-time.Second, 0), WithLease(time.Second, time.Second)} {
+		if _, err := New(context.Background(), m, opt); !errors.Is(err, ErrInvalidConfig) {
+			t.Fatalf("expected ErrInvalidConfig, got %v", err)
+		}
+	}
+}
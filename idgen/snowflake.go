@@ -0,0 +1,96 @@
+package idgen
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrClockBackwards = errors.New("idgen: clock moved backwards")
+	ErrInvalidWorker  = errors.New("idgen: worker id out of range")
+)
+
+const (
+	workerBits   = 10
+	sequenceBits = 12
+
+	// MaxWorkerID 最大 worker ID
+	MaxWorkerID = 1<<workerBits - 1
+	maxSequence = 1<<sequenceBits - 1
+
+	// 时钟回拨容忍范围，范围内等待追上，超过返回错误
+	maxBackwards = 5 * time.Millisecond
+)
+
+// DefaultEpoch 默认起始时间 2024-01-01 00:00:00 UTC
+var DefaultEpoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Snowflake 雪花 ID 生成器：41 位毫秒时间戳 | 10 位 worker ID | 12 位序列号
+type Snowflake struct {
+	mu       sync.Mutex
+	epoch    int64
+	workerID int64
+	lastMs   int64
+	sequence int64
+}
+
+// NewSnowflake 创建生成器，epoch 为零值时使用 DefaultEpoch
+func NewSnowflake(workerID int64, epoch time.Time) (*Snowflake, error) {
+	if workerID < 0 || workerID > MaxWorkerID {
+		return nil, ErrInvalidWorker
+	}
+	if epoch.IsZero() {
+		epoch = DefaultEpoch
+	}
+	return &Snowflake{epoch: epoch.UnixMilli(), workerID: workerID}, nil
+}
+
+// WorkerID 返回 worker ID
+func (s *Snowflake) WorkerID() int64 {
+	return s.workerID
+}
+
+// NextID 生成下一个 ID
+func (s *Snowflake) NextID() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UnixMilli()
+	if now < s.lastMs {
+		back := time.Duration(s.lastMs-now) * time.Millisecond
+		if back > maxBackwards {
+			return 0, ErrClockBackwards
+		}
+		time.Sleep(back)
+		now = time.Now().UnixMilli()
+		if now < s.lastMs {
+			return 0, ErrClockBackwards
+		}
+	}
+
+	if now == s.lastMs {
+		s.sequence = (s.sequence + 1) & maxSequence
+		if s.sequence == 0 {
+			// 当前毫秒序列号用尽，等待下一毫秒
+			for now <= s.lastMs {
+				time.Sleep(100 * time.Microsecond)
+				now = time.Now().UnixMilli()
+			}
+		}
+	} else {
+		s.sequence = 0
+	}
+	s.lastMs = now
+
+	return (now-s.epoch)<<(workerBits+sequenceBits) | s.workerID<<sequenceBits | s.sequence, nil
+}
+
+// Parse 解析 ID 的生成时间、worker ID 与序列号
+func Parse(id int64, epoch time.Time) (t time.Time, workerID, sequence int64) {
+	if epoch.IsZero() {
+		epoch = DefaultEpoch
+	}
+	ms := id>>(workerBits+sequenceBits) + epoch.UnixMilli()
+	return time.UnixMilli(ms), id >> sequenceBits & MaxWorkerID, id & maxSequence
+}
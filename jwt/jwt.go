@@ -0,0 +1,291 @@
+package jwt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jiajia556/tool-box/cache"
+)
+
+var (
+	ErrTokenMalformed   = errors.New("jwt: token malformed")
+	ErrTokenExpired     = errors.New("jwt: token expired")
+	ErrTokenNotValidYet = errors.New("jwt: token not valid yet")
+	ErrTokenRevoked     = errors.New("jwt: token revoked")
+	ErrSignatureInvalid = errors.New("jwt: signature invalid")
+	ErrUnknownKey       = errors.New("jwt: unknown key id")
+	ErrInvalidKey       = errors.New("jwt: invalid key")
+	ErrUnsupportedAlg   = errors.New("jwt: unsupported algorithm")
+	ErrInvalidIssuer    = errors.New("jwt: invalid issuer")
+	ErrInvalidAudience  = errors.New("jwt: invalid audience")
+)
+
+const revokePrefix = "jwt:revoked:"
+
+// RegisteredClaims 标准声明（RFC 7519）
+type RegisteredClaims struct {
+	Issuer    string   `json:"iss,omitempty"`
+	Subject   string   `json:"sub,omitempty"`
+	Audience  []string `json:"aud,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	ID        string   `json:"jti,omitempty"`
+}
+
+// Claims 带自定义数据的声明，自定义数据序列化在 data 字段中
+type Claims[T any] struct {
+	RegisteredClaims
+	Data T `json:"data"`
+}
+
+type header struct {
+	Alg Algorithm `json:"alg"`
+	Typ string    `json:"typ"`
+	Kid string    `json:"kid,omitempty"`
+}
+
+// Config JWT 配置
+type Config struct {
+	// 签发者，非空时签发写入 iss，校验时要求一致
+	Issuer string
+
+	// 受众，非空时签发写入 aud，校验时要求 token 至少包含其中之一
+	Audience []string
+
+	// token 有效期
+	TTL time.Duration
+
+	// 时钟偏差容忍度
+	Leeway time.Duration
+
+	// 吊销列表存储（为 nil 时不支持吊销）
+	RevocationStore cache.Cache
+}
+
+// Option 选项函数
+type Option func(*Config)
+
+// WithIssuer 设置签发者
+func WithIssuer(issuer string) Option {
+	return func(c *Config) {
+		c.Issuer = issuer
+	}
+}
+
+// WithAudience 设置受众
+func WithAudience(aud ...string) Option {
+	return func(c *Config) {
+		c.Audience = aud
+	}
+}
+
+// WithTTL 设置 token 有效期
+func WithTTL(ttl time.Duration) Option {
+	return func(c *Config) {
+		c.TTL = ttl
+	}
+}
+
+// WithLeeway 设置时钟偏差容忍度
+func WithLeeway(leeway time.Duration) Option {
+	return func(c *Config) {
+		c.Leeway = leeway
+	}
+}
+
+// WithRevocationStore 设置吊销列表存储
+func WithRevocationStore(store cache.Cache) Option {
+	return func(c *Config) {
+		c.RevocationStore = store
+	}
+}
+
+// DefaultConfig 默认配置
+func DefaultConfig() Config {
+	return Config{
+		TTL:    2 * time.Hour,
+		Leeway: 30 * time.Second,
+	}
+}
+
+// JWT token 签发与校验器
+type JWT struct {
+	keys   *KeySet
+	config Config
+}
+
+// New 创建 JWT 签发与校验器
+func New(keys *KeySet, opts ...Option) *JWT {
+	config := DefaultConfig()
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return &JWT{keys: keys, config: config}
+}
+
+// Keys 返回密钥集合
+func (j *JWT) Keys() *KeySet {
+	return j.keys
+}
+
+// Issue 使用当前密钥签发 token，subject 可为空
+func Issue[T any](j *JWT, subject string, data T) (string, error) {
+	now := time.Now()
+	claims := Claims[T]{
+		RegisteredClaims: RegisteredClaims{
+			Issuer:   j.config.Issuer,
+			Subject:  subject,
+			Audience: j.config.Audience,
+			IssuedAt: now.Unix(),
+			ID:       uuid.New().String(),
+		},
+		Data: data,
+	}
+	if j.config.TTL > 0 {
+		claims.ExpiresAt = now.Add(j.config.TTL).Unix()
+	}
+	return Sign(j, claims)
+}
+
+// Sign 使用当前密钥对自定义声明签名，不做任何字段填充
+func Sign[T any](j *JWT, claims Claims[T]) (string, error) {
+	key, ok := j.keys.Current()
+	if !ok {
+		return "", ErrUnknownKey
+	}
+
+	hb, err := json.Marshal(header{Alg: key.Alg, Typ: "JWT", Kid: key.ID})
+	if err != nil {
+		return "", err
+	}
+	cb, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	input := encodeSegment(hb) + "." + encodeSegment(cb)
+	sig, err := sign(key, []byte(input))
+	if err != nil {
+		return "", err
+	}
+	return input + "." + encodeSegment(sig), nil
+}
+
+// Parse 校验 token 并解析声明
+func Parse[T any](j *JWT, token string) (*Claims[T], error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrTokenMalformed
+	}
+
+	hb, err := decodeSegment(parts[0])
+	if err != nil {
+		return nil, ErrTokenMalformed
+	}
+	var h header
+	if err := json.Unmarshal(hb, &h); err != nil {
+		return nil, ErrTokenMalformed
+	}
+
+	key, ok := j.keys.Get(h.Kid)
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	// 防止算法混淆攻击：必须与密钥声明的算法一致
+	if h.Alg != key.Alg {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlg, h.Alg)
+	}
+
+	sig, err := decodeSegment(parts[2])
+	if err != nil {
+		return nil, ErrTokenMalformed
+	}
+	if err := verify(key, []byte(parts[0]+"."+parts[1]), sig); err != nil {
+		return nil, err
+	}
+
+	cb, err := decodeSegment(parts[1])
+	if err != nil {
+		return nil, ErrTokenMalformed
+	}
+	claims := new(Claims[T])
+	if err := json.Unmarshal(cb, claims); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTokenMalformed, err)
+	}
+
+	if err := j.validate(&claims.RegisteredClaims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (j *JWT) validate(c *RegisteredClaims) error {
+	now := time.Now()
+	leeway := j.config.Leeway
+
+	if c.ExpiresAt != 0 && now.After(time.Unix(c.ExpiresAt, 0).Add(leeway)) {
+		return ErrTokenExpired
+	}
+	if c.NotBefore != 0 && now.Add(leeway).Before(time.Unix(c.NotBefore, 0)) {
+		return ErrTokenNotValidYet
+	}
+	if j.config.Issuer != "" && c.Issuer != j.config.Issuer {
+		return ErrInvalidIssuer
+	}
+	if len(j.config.Audience) > 0 {
+		matched := false
+		for _, aud := range c.Audience {
+			if slices.Contains(j.config.Audience, aud) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return ErrInvalidAudience
+		}
+	}
+	if c.ID != "" && j.IsRevoked(c.ID) {
+		return ErrTokenRevoked
+	}
+	return nil
+}
+
+// Revoke 吊销 token（按 jti），记录保留到 token 过期为止；expiresAt 为 0 时使用配置的 TTL
+func (j *JWT) Revoke(jti string, expiresAt int64) {
+	if j.config.RevocationStore == nil || jti == "" {
+		return
+	}
+
+	ttl := j.config.TTL
+	if expiresAt != 0 {
+		ttl = time.Until(time.Unix(expiresAt, 0)) + j.config.Leeway
+	}
+	if ttl <= 0 {
+		return
+	}
+	j.config.RevocationStore.Set(revokePrefix+jti, true, ttl)
+}
+
+// IsRevoked 判断 token 是否已被吊销
+func (j *JWT) IsRevoked(jti string) bool {
+	if j.config.RevocationStore == nil {
+		return false
+	}
+	return j.config.RevocationStore.Exists(revokePrefix + jti)
+}
+
+func encodeSegment(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeSegment(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
@@ -0,0 +1,106 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jiajia556/tool-box/cache/memory"
+)
+
+type user struct {
+	UID  int64  `json:"uid"`
+	Role string `json:"role"`
+}
+
+func TestIssueParse_Algorithms(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey: %v", err)
+	}
+
+	keys := []Key{
+		{ID: "hs", Alg: HS256, Secret: []byte("secret")},
+		{ID: "rs", Alg: RS256, PrivateKey: rsaKey},
+		{ID: "es", Alg: ES256, PrivateKey: ecKey},
+	}
+	for _, k := range keys {
+		t.Run(string(k.Alg), func(t *testing.T) {
+			j := New(NewKeySet(k), WithIssuer("tool-box"))
+			token, err := Issue(j, "42", user{UID: 42, Role: "admin"})
+			if err != nil {
+				t.Fatalf("Issue: %v", err)
+			}
+			claims, err := Parse[user](j, token)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if claims.Subject != "42" || claims.Data.UID != 42 || claims.Data.Role != "admin" {
+				t.Fatalf("unexpected claims %+v", claims)
+			}
+		})
+	}
+}
+
+func TestParse_RotationAndRevocation(t *testing.T) {
+	ks := NewKeySet(Key{ID: "k1", Alg: HS256, Secret: []byte("one")})
+	j := New(ks, WithRevocationStore(memory.NewMemoryCache()))
+
+	old, err := Issue(j, "", user{UID: 1})
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	ks.Add(Key{ID: "k2", Alg: HS256, Secret: []byte("two")})
+	if err := ks.SetCurrent("k2"); err != nil {
+		t.Fatalf("SetCurrent: %v", err)
+	}
+	if _, err := Parse[user](j, old); err != nil {
+		t.Fatalf("token signed by rotated key should still verify: %v", err)
+	}
+
+	claims, _ := Parse[user](j, old)
+	j.Revoke(claims.ID, claims.ExpiresAt)
+	if _, err := Parse[user](j, old); !errors.Is(err, ErrTokenRevoked) {
+		t.Fatalf("expected ErrTokenRevoked, got %v", err)
+	}
+
+	ks.Remove("k1")
+	if _, err := Parse[user](j, old); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("expected ErrUnknownKey, got %v", err)
+	}
+}
+
+func TestParse_ExpiredWithLeeway(t *testing.T) {
+	j := New(NewKeySet(Key{ID: "k", Alg: HS256, Secret: []byte("s")}), WithLeeway(time.Minute))
+
+	claims := Claims[user]{RegisteredClaims: RegisteredClaims{ExpiresAt: time.Now().Add(-30 * time.Second).Unix()}}
+	token, err := Sign(j, claims)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if _, err := Parse[user](j, token); err != nil {
+		t.Fatalf("expected token within leeway to pass, got %v", err)
+	}
+
+	claims.ExpiresAt = time.Now().Add(-2 * time.Minute).Unix()
+	token, _ = Sign(j, claims)
+	if _, err := Parse[user](j, token); !errors.Is(err, ErrTokenExpired) {
+		t.Fatalf("expected ErrTokenExpired, got %v", err)
+	}
+}
+
+func TestVerify_EmptySecret(t *testing.T) {
+	key := Key{ID: "k", Alg: HS256}
+	if err := verify(key, []byte("input"), nil); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected ErrInvalidKey, got %v", err)
+	}
+}
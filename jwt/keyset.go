@@ -0,0 +1,96 @@
+package jwt
+
+import (
+	"crypto"
+	"sync"
+)
+
+// Key 签名密钥
+// - HS*: 使用 Secret
+// - RS*/ES*: 签发需要 PrivateKey；仅校验时可只提供 PublicKey
+type Key struct {
+	ID         string
+	Alg        Algorithm
+	Secret     []byte
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+}
+
+func (k Key) publicKey() crypto.PublicKey {
+	if k.PublicKey != nil {
+		return k.PublicKey
+	}
+	if k.PrivateKey != nil {
+		return k.PrivateKey.Public()
+	}
+	return nil
+}
+
+// KeySet 按 kid 管理的密钥集合，用于密钥轮换：
+// 新 token 使用 current 签发，旧 token 仍可按 kid 找到对应密钥校验。
+type KeySet struct {
+	mu      sync.RWMutex
+	keys    map[string]Key
+	current string
+}
+
+// NewKeySet 创建密钥集合，第一个密钥作为当前签发密钥
+func NewKeySet(keys ...Key) *KeySet {
+	ks := &KeySet{keys: make(map[string]Key)}
+	for _, k := range keys {
+		ks.Add(k)
+	}
+	return ks
+}
+
+// Add 添加密钥；集合为空时同时设为当前签发密钥
+func (ks *KeySet) Add(key Key) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	ks.keys[key.ID] = key
+	if ks.current == "" {
+		ks.current = key.ID
+	}
+}
+
+// Remove 移除密钥；移除后使用该 kid 签发的 token 将无法通过校验
+func (ks *KeySet) Remove(kid string) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	delete(ks.keys, kid)
+	if ks.current == kid {
+		ks.current = ""
+	}
+}
+
+// SetCurrent 设置当前签发密钥
+func (ks *KeySet) SetCurrent(kid string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if _, ok := ks.keys[kid]; !ok {
+		return ErrUnknownKey
+	}
+	ks.current = kid
+	return nil
+}
+
+// Current 获取当前签发密钥
+func (ks *KeySet) Current() (Key, bool) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	k, ok := ks.keys[ks.current]
+	return k, ok
+}
+
+// Get 按 kid 获取密钥
+func (ks *KeySet) Get(kid string) (Key, bool) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	k, ok := ks.keys[kid]
+	return k, ok
+}
@@ -0,0 +1,56 @@
+package jwt
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+type claimsKey struct{}
+
+// NewContext 将声明写入 context
+func NewContext[T any](ctx context.Context, claims *Claims[T]) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// FromContext 获取 context 中的声明
+func FromContext[T any](ctx context.Context) (*Claims[T], bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	claims, ok := ctx.Value(claimsKey{}).(*Claims[T])
+	return claims, ok
+}
+
+// TokenFromRequest 从 Authorization: Bearer <token> 请求头中提取 token
+func TokenFromRequest(r *http.Request) string {
+	auth := r.Header.Get("Authorization")
+	if len(auth) > 7 && strings.EqualFold(auth[:7], "Bearer ") {
+		return strings.TrimSpace(auth[7:])
+	}
+	return ""
+}
+
+// Middleware 返回 net/http 中间件：校验 Bearer token 并将声明写入 context，失败时返回 401。
+func Middleware[T any](j *JWT) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := TokenFromRequest(r)
+			if token == "" {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			claims, err := Parse[T](j, token)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), claims)))
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package locker
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jiajia556/tool-box/cache"
+)
+
+// AuditAction 审计记录的操作类型
+type AuditAction string
+
+const (
+	AuditAcquire     AuditAction = "acquire"
+	AuditRelease     AuditAction = "release"
+	AuditRefresh     AuditAction = "refresh"
+	AuditForceUnlock AuditAction = "force_unlock"
+)
+
+// AuditEvent 一次锁操作的审计记录
+type AuditEvent struct {
+	Time     time.Time   `json:"time"`
+	Action   AuditAction `json:"action"`
+	Key      string      `json:"key"`
+	Token    string      `json:"token,omitempty"`
+	Owner    string      `json:"owner,omitempty"` // 通过 WithOwner 设置的持有者标识
+	Hostname string      `json:"hostname"`
+	PID      int         `json:"pid"`
+	OK       bool        `json:"ok"`
+	Error    string      `json:"error,omitempty"`
+}
+
+// AuditSink 审计记录的输出，Record 在锁操作的调用方 goroutine 中同步执行
+type AuditSink interface {
+	Record(event AuditEvent)
+}
+
+var (
+	auditMu   sync.RWMutex
+	auditSink AuditSink
+)
+
+// SetAuditSink 设置审计输出，nil 表示关闭审计
+func SetAuditSink(sink AuditSink) {
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	auditSink = sink
+}
+
+var identity = sync.OnceValues(func() (string, int) {
+	hostname, _ := os.Hostname()
+	return hostname, os.Getpid()
+})
+
+// Audit 记录一次锁操作，供适配器调用；err 为 nil 表示成功。未设置审计输出时不做任何事
+func Audit(config Config, action AuditAction, key, token string, err error) {
+	auditMu.RLock()
+	sink := auditSink
+	auditMu.RUnlock()
+	if sink == nil {
+		return
+	}
+
+	hostname, pid := identity()
+	event := AuditEvent{
+		Time:     time.Now(),
+		Action:   action,
+		Key:      key,
+		Token:    token,
+		Owner:    config.Owner,
+		Hostname: hostname,
+		PID:      pid,
+		OK:       err == nil,
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	sink.Record(event)
+}
+
+// CacheAudit 将审计记录按锁 key 保存到缓存中，每个 key 保留最近 MaxEvents 条，
+// 使用共享的 Redis 缓存时可在事故后汇总各实例的锁操作
+type CacheAudit struct {
+	c         cache.Cache
+	prefix    string
+	maxEvents int
+	retention time.Duration
+}
+
+// CacheAuditOption 选项函数
+type CacheAuditOption func(*CacheAudit)
+
+// WithAuditPrefix 设置缓存 key 前缀，默认 "locker:audit:"
+func WithAuditPrefix(prefix string) CacheAuditOption {
+	return func(a *CacheAudit) {
+		a.prefix = prefix
+	}
+}
+
+// WithAuditMaxEvents 设置每个锁 key 保留的记录数，默认 100
+func WithAuditMaxEvents(n int) CacheAuditOption {
+	return func(a *CacheAudit) {
+		a.maxEvents = n
+	}
+}
+
+// WithAuditRetention 设置记录的保留时间，默认 7 天；每次写入后重新计时
+func WithAuditRetention(d time.Duration) CacheAuditOption {
+	return func(a *CacheAudit) {
+		a.retention = d
+	}
+}
+
+// NewCacheAudit 创建保存到 c 的审计输出
+func NewCacheAudit(c cache.Cache, opts ...CacheAuditOption) *CacheAudit {
+	a := &CacheAudit{
+		c:         c,
+		prefix:    "locker:audit:",
+		maxEvents: 100,
+		retention: 7 * 24 * time.Hour,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Record 追加一条记录，并发写入同一 key 时通过 CompareAndSwap 重试
+func (a *CacheAudit) Record(event AuditEvent) {
+	k := a.prefix + event.Key
+	for i := 0; i < 5; i++ {
+		old, events := a.load(k)
+		events = append(events, event)
+		if len(events) > a.maxEvents {
+			events = events[len(events)-a.maxEvents:]
+		}
+		b, err := json.Marshal(events)
+		if err != nil {
+			return
+		}
+		if ok, err := a.c.CompareAndSwap(k, old, string(b), a.retention); ok || err != nil {
+			return
+		}
+	}
+}
+
+// Events 返回锁 key 的审计记录，按时间先后排列
+func (a *CacheAudit) Events(key string) []AuditEvent {
+	_, events := a.load(a.prefix + key)
+	return events
+}
+
+// load 返回当前保存的原始值（用于 CompareAndSwap，不存在时为 nil）与解析后的记录
+func (a *CacheAudit) load(k string) (any, []AuditEvent) {
+	v, err := a.c.Get(k)
+	if err != nil {
+		return nil, nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return v, nil
+	}
+	var events []AuditEvent
+	if err := json.Unmarshal([]byte(s), &events); err != nil {
+		return v, nil
+	}
+	return v, events
+}
@@ -0,0 +1,132 @@
+package locker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jiajia556/tool-box/utils"
+)
+
+// ContentionWindow 统计获取失败率的时间窗口
+const ContentionWindow = time.Minute
+
+// contentionMaxKeys 最多跟踪的 key 数量，超出时淘汰最久未访问的 key
+const contentionMaxKeys = 10000
+
+// Contention 单个锁 key 的竞争情况
+type Contention struct {
+	// 当前阻塞等待该锁的数量
+	Waiters int
+
+	// 最近一个统计窗口内的尝试次数与未抢到锁的次数
+	Attempts uint64
+	Failures uint64
+
+	// 最近一个统计窗口内的获取失败率（Failures / Attempts），没有尝试时为 0
+	FailureRate float64
+}
+
+// ContentionTracker 按 key 统计等待者数量与最近的获取失败率，供适配器实现 Manager.Contention。
+// 失败率按前后两个窗口滑动估算；最多跟踪 10000 个 key，超出时淘汰最久未访问的 key。
+type ContentionTracker struct {
+	mu   sync.Mutex
+	keys *utils.LRUMap[string, *contentionStat]
+}
+
+type contentionStat struct {
+	waiters int
+
+	start    time.Time // 当前窗口的开始时间
+	attempts [2]uint64 // [0] 当前窗口，[1] 上一个窗口
+	failures [2]uint64
+}
+
+// NewContentionTracker 创建竞争统计
+func NewContentionTracker() *ContentionTracker {
+	return &ContentionTracker{keys: utils.NewLRUMap[string, *contentionStat](contentionMaxKeys, nil)}
+}
+
+// Wait 标记开始阻塞等待 key，返回的 done 在等待结束（获取成功或放弃）时调用
+func (t *ContentionTracker) Wait(key string) (done func()) {
+	t.mu.Lock()
+	t.stat(key, time.Now()).waiters++
+	t.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			t.mu.Lock()
+			if s, ok := t.keys.Peek(key); ok && s.waiters > 0 {
+				s.waiters--
+			}
+			t.mu.Unlock()
+		})
+	}
+}
+
+// Record 记录一次获取尝试，acquired 为 false 表示锁被占用
+func (t *ContentionTracker) Record(key string, acquired bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := t.stat(key, time.Now())
+	s.attempts[0]++
+	if !acquired {
+		s.failures[0]++
+	}
+}
+
+// Get 返回 key 当前的竞争情况
+func (t *ContentionTracker) Get(key string) Contention {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, ok := t.keys.Peek(key)
+	if !ok {
+		return Contention{}
+	}
+	now := time.Now()
+	s.rotate(now)
+
+	// 上一个窗口按尚未被当前窗口覆盖的比例计入
+	weight := 1 - float64(now.Sub(s.start))/float64(ContentionWindow)
+	attempts := float64(s.attempts[0]) + float64(s.attempts[1])*weight
+	failures := float64(s.failures[0]) + float64(s.failures[1])*weight
+
+	c := Contention{
+		Waiters:  s.waiters,
+		Attempts: uint64(attempts + 0.5),
+		Failures: uint64(failures + 0.5),
+	}
+	if attempts > 0 {
+		c.FailureRate = failures / attempts
+	}
+	return c
+}
+
+// stat 返回 key 的统计项，不存在时创建
+func (t *ContentionTracker) stat(key string, now time.Time) *contentionStat {
+	if s, ok := t.keys.Get(key); ok {
+		s.rotate(now)
+		return s
+	}
+
+	s := &contentionStat{start: now}
+	t.keys.Set(key, s)
+	return s
+}
+
+func (s *contentionStat) rotate(now time.Time) {
+	elapsed := now.Sub(s.start)
+	switch {
+	case elapsed < ContentionWindow:
+		return
+	case elapsed < 2*ContentionWindow:
+		s.attempts[1], s.failures[1] = s.attempts[0], s.failures[0]
+		s.start = s.start.Add(ContentionWindow)
+	default:
+		s.attempts[1], s.failures[1] = 0, 0
+		s.start = now
+	}
+	s.attempts[0], s.failures[0] = 0, 0
+}
@@ -0,0 +1,51 @@
+package locker
+
+import (
+	"sync"
+	"time"
+)
+
+// Grace 管理 UnlockAfter 的延迟释放，供适配器嵌入到锁实现中。
+// 宽限期内重新获取锁时调用 Cancel 取消释放，避免热点 key 在释放瞬间被大量等待者争抢。
+type Grace struct {
+	mu    sync.Mutex
+	timer *time.Timer
+	gen   uint64
+}
+
+// Schedule d 后调用 unlock，替换尚未触发的上一次调度
+func (g *Grace) Schedule(d time.Duration, unlock func()) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.timer != nil {
+		g.timer.Stop()
+	}
+	g.gen++
+	gen := g.gen
+	g.timer = time.AfterFunc(d, func() {
+		g.mu.Lock()
+		if g.gen != gen {
+			// 已被 Cancel 或重新调度
+			g.mu.Unlock()
+			return
+		}
+		g.timer = nil
+		g.mu.Unlock()
+		unlock()
+	})
+}
+
+// Cancel 取消尚未执行的释放，返回是否存在待释放的调度
+func (g *Grace) Cancel() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.timer == nil {
+		return false
+	}
+	g.gen++
+	g.timer.Stop()
+	g.timer = nil
+	return true
+}
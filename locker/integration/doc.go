@@ -0,0 +1,16 @@
+// Package integration 通过 dockertest 启动真实的后端服务（目前为 Redis），
+// 并对相应的锁适配器运行 lockertest 一致性测试，覆盖 Lua 脚本与 TTL 等只有真实服务才能验证的路径。
+//
+// 包内代码带有 integration 构建标签，普通的 go test ./... 不会编译与运行，需要本地可用的 Docker：
+//
+//	go test -tags integration ./locker/integration/...
+//
+// 第三方适配器也可以复用 StartRedis 启动测试用的 Redis：
+//
+//	func TestMyAdapter(t *testing.T) {
+//		addr := integration.StartRedis(t)
+//		m, _ := myadapter.NewManager(myadapter.Options{Addr: addr})
+//		defer m.Close()
+//		lockertest.Run(t, m, lockertest.WithTTL(time.Second))
+//	}
+package integration
@@ -0,0 +1,124 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// Config 容器配置
+type Config struct {
+	// Redis 镜像与标签，默认 redis:7-alpine
+	Repository string
+	Tag        string
+
+	// 等待服务可用的最长时间，默认 1 分钟
+	MaxWait time.Duration
+
+	// 容器的最长存活时间，测试进程异常退出、未执行清理时由 Docker 到期回收，默认 10 分钟
+	Expire time.Duration
+}
+
+// Option 选项函数
+type Option func(*Config)
+
+// WithImage 设置镜像与标签
+func WithImage(repository, tag string) Option {
+	return func(c *Config) {
+		c.Repository = repository
+		c.Tag = tag
+	}
+}
+
+// WithMaxWait 设置等待服务可用的最长时间
+func WithMaxWait(d time.Duration) Option {
+	return func(c *Config) {
+		c.MaxWait = d
+	}
+}
+
+// WithExpire 设置容器的最长存活时间
+func WithExpire(d time.Duration) Option {
+	return func(c *Config) {
+		c.Expire = d
+	}
+}
+
+// DefaultConfig 默认配置
+func DefaultConfig() Config {
+	return Config{
+		Repository: "redis",
+		Tag:        "7-alpine",
+		MaxWait:    time.Minute,
+		Expire:     10 * time.Minute,
+	}
+}
+
+// StartRedis 启动一个 Redis 容器并返回可连接的地址，测试结束时删除容器。
+// 设置了环境变量 REDIS_ADDR 时直接返回该地址，不启动容器；Docker 不可用时跳过测试
+func StartRedis(t testing.TB, opts ...Option) string {
+	t.Helper()
+	if addr := os.Getenv("REDIS_ADDR"); addr != "" {
+		return addr
+	}
+
+	config := DefaultConfig()
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		t.Skipf("docker not available: %v", err)
+	}
+	if err := pool.Client.Ping(); err != nil {
+		t.Skipf("docker not available: %v", err)
+	}
+	pool.MaxWait = config.MaxWait
+
+	resource, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: config.Repository,
+		Tag:        config.Tag,
+	}, func(hc *docker.HostConfig) {
+		hc.AutoRemove = true
+		hc.RestartPolicy = docker.RestartPolicy{Name: "no"}
+	})
+	if err != nil {
+		t.Fatalf("integration: start redis: %v", err)
+	}
+	t.Cleanup(func() { _ = pool.Purge(resource) })
+	if config.Expire > 0 {
+		_ = resource.Expire(uint(config.Expire / time.Second))
+	}
+
+	addr := hostPort(resource.GetHostPort("6379/tcp"))
+	err = pool.Retry(func() error {
+		client := goredis.NewClient(&goredis.Options{Addr: addr})
+		defer client.Close()
+		return client.Ping(context.Background()).Err()
+	})
+	if err != nil {
+		t.Fatalf("integration: redis at %s not ready: %v", addr, err)
+	}
+	return addr
+}
+
+// hostPort 将 Docker 返回的 0.0.0.0 / :: 绑定地址改为本机回环地址
+func hostPort(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	if ip := net.ParseIP(host); ip == nil || ip.IsUnspecified() {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, port)
+}
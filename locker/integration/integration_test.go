@@ -0,0 +1,20 @@
+//go:build integration
+
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jiajia556/tool-box/locker/lockertest"
+	"github.com/jiajia556/tool-box/locker/redis"
+)
+
+func TestRedis(t *testing.T) {
+	m, err := redis.NewRedisManager(redis.Options{Addr: StartRedis(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	lockertest.Run(t, m, lockertest.WithTTL(time.Second))
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/jiajia556/tool-box/utils"
 )
 
 var (
@@ -26,6 +28,10 @@ type Locker interface {
 	// 释放锁
 	Unlock(ctx context.Context) error
 
+	// 宽限期 d 后释放锁（d<=0 时立即释放），期间重新 TryLock/Lock 会取消释放并继续持有；
+	// 宽限期内锁的 TTL 缩短为 d，持有者退出后也会按时释放
+	UnlockAfter(d time.Duration) error
+
 	// 获取锁的剩余 TTL
 	TTL(ctx context.Context) (time.Duration, error)
 
@@ -47,6 +53,12 @@ type Manager interface {
 	// 创建新的锁
 	New(key string, opts ...Option) Locker
 
+	// 创建分段锁，将任意 key 映射到 stripes 把底层锁上
+	Striped(name string, stripes int, opts ...Option) *Striped
+
+	// 获取 key 当前的等待者数量与最近的获取失败率
+	Contention(ctx context.Context, key string) (Contention, error)
+
 	// 关闭锁管理器
 	Close() error
 }
@@ -67,6 +79,18 @@ type Config struct {
 
 	// 是否在释放时自动关闭
 	AutoClose bool
+
+	// 持锁超过该时长时输出告警（小于等于0表示不检查）
+	HoldWarning time.Duration
+
+	// 阻塞获取锁等待超过该时长时输出慢获取日志（小于等于0表示不记录）
+	SlowAcquire time.Duration
+
+	// 时间源（nil 表示使用系统时间），目前由内存锁用于 TTL 与等待超时
+	Clock utils.Clock
+
+	// 持有者标识，写入审计记录，便于事后定位是哪个任务持有了锁
+	Owner string
 }
 
 // Option 选项函数
@@ -107,6 +131,34 @@ func WithAutoClose(autoClose bool) Option {
 	}
 }
 
+// WithHoldWarning 设置持锁告警时长
+func WithHoldWarning(d time.Duration) Option {
+	return func(c *Config) {
+		c.HoldWarning = d
+	}
+}
+
+// WithSlowAcquire 设置慢获取日志阈值
+func WithSlowAcquire(d time.Duration) Option {
+	return func(c *Config) {
+		c.SlowAcquire = d
+	}
+}
+
+// WithClock 设置时间源
+func WithClock(clock utils.Clock) Option {
+	return func(c *Config) {
+		c.Clock = clock
+	}
+}
+
+// WithOwner 设置持有者标识
+func WithOwner(owner string) Option {
+	return func(c *Config) {
+		c.Owner = owner
+	}
+}
+
 // DefaultConfig 默认配置
 func DefaultConfig() Config {
 	return Config{
@@ -215,6 +267,14 @@ func Lock(ctx context.Context, key string, opts ...Option) (Locker, error) {
 	return lock, nil
 }
 
+// GetContention 获取 key 的竞争情况（使用全局锁管理器）
+func GetContention(ctx context.Context, key string) (Contention, error) {
+	if globalManager == nil {
+		return Contention{}, fmt.Errorf("global manager not initialized")
+	}
+	return globalManager.Contention(ctx, key)
+}
+
 // Close 关闭全局锁管理器
 func Close() error {
 	if globalManager == nil {
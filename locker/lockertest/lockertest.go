@@ -0,0 +1,299 @@
+package lockertest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jiajia556/tool-box/locker"
+)
+
+// Config 一致性测试配置
+type Config struct {
+	// 测试使用的锁 TTL，过期相关的用例按此等待，默认 300ms；
+	// 远程存储的网络延迟较大时应适当调大
+	TTL time.Duration
+
+	// 互斥用例的并发数，默认 8
+	Concurrency int
+
+	// 互斥用例中每个 goroutine 获取锁的次数，默认 20
+	Iterations int
+
+	// key 前缀，默认 "lockertest"；每个用例在此基础上使用随机后缀，互不干扰
+	Prefix string
+}
+
+// Option 选项函数
+type Option func(*Config)
+
+// WithTTL 设置锁 TTL
+func WithTTL(ttl time.Duration) Option {
+	return func(c *Config) {
+		c.TTL = ttl
+	}
+}
+
+// WithConcurrency 设置互斥用例的并发数与每个 goroutine 的获取次数
+func WithConcurrency(goroutines, iterations int) Option {
+	return func(c *Config) {
+		c.Concurrency = goroutines
+		c.Iterations = iterations
+	}
+}
+
+// WithPrefix 设置 key 前缀
+func WithPrefix(prefix string) Option {
+	return func(c *Config) {
+		c.Prefix = prefix
+	}
+}
+
+// DefaultConfig 默认配置
+func DefaultConfig() Config {
+	return Config{
+		TTL:         300 * time.Millisecond,
+		Concurrency: 8,
+		Iterations:  20,
+		Prefix:      "lockertest",
+	}
+}
+
+// Run 对 m 运行锁适配器的一致性测试，第三方适配器可在自己的测试中调用以验证实现：
+//
+//	func TestConformance(t *testing.T) {
+//		m, err := mylocker.NewManager(cfg)
+//		if err != nil {
+//			t.Fatal(err)
+//		}
+//		defer m.Close()
+//		lockertest.Run(t, m)
+//	}
+//
+// 覆盖竞争下的互斥、TTL 过期、续期、持有者崩溃后的恢复与非持有者释放等语义
+func Run(t *testing.T, m locker.Manager, opts ...Option) {
+	config := DefaultConfig()
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	s := &suite{m: m, config: config}
+	t.Run("MutualExclusion", s.mutualExclusion)
+	t.Run("TryLockContended", s.tryLockContended)
+	t.Run("TTLExpiry", s.ttlExpiry)
+	t.Run("Refresh", s.refresh)
+	t.Run("CrashRecovery", s.crashRecovery)
+	t.Run("UnlockNotHeld", s.unlockNotHeld)
+}
+
+// RedisAddr 返回环境变量 REDIS_ADDR 指定的 Redis 地址，未设置时跳过测试；
+// 本地可通过 docker run -p 6379:6379 redis 启动后设置 REDIS_ADDR=localhost:6379，
+// 或使用 locker/integration 包由 dockertest 自动启动容器
+func RedisAddr(t testing.TB) string {
+	t.Helper()
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_ADDR not set, skipping integration test")
+	}
+	return addr
+}
+
+type suite struct {
+	m      locker.Manager
+	config Config
+}
+
+func (s *suite) key() string {
+	return fmt.Sprintf("%s:%s", s.config.Prefix, uuid.NewString())
+}
+
+func (s *suite) newLocker(key string, opts ...locker.Option) locker.Locker {
+	base := []locker.Option{
+		locker.WithTTL(s.config.TTL),
+		locker.WithTimeout(10 * s.config.TTL),
+		locker.WithPollInterval(s.config.TTL / 30),
+	}
+	return s.m.New(key, append(base, opts...)...)
+}
+
+// mutualExclusion 多个 goroutine 竞争同一把锁，任何时刻最多一个持有者
+func (s *suite) mutualExclusion(t *testing.T) {
+	key := s.key()
+	// 临界区很短，TTL 放大以免慢机器上锁在持有期间过期
+	ttl := locker.WithTTL(10 * s.config.TTL)
+	timeout := locker.WithTimeout(time.Duration(s.config.Concurrency*s.config.Iterations) * s.config.TTL)
+
+	var (
+		holders atomic.Int32
+		total   atomic.Int32
+		wg      sync.WaitGroup
+		errs    = make(chan error, s.config.Concurrency)
+	)
+	for i := 0; i < s.config.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l := s.newLocker(key, ttl, timeout)
+			defer l.Close()
+
+			for j := 0; j < s.config.Iterations; j++ {
+				if err := l.Lock(context.Background()); err != nil {
+					errs <- fmt.Errorf("Lock: %w", err)
+					return
+				}
+				if n := holders.Add(1); n != 1 {
+					errs <- fmt.Errorf("%d concurrent holders", n)
+				}
+				total.Add(1)
+				time.Sleep(time.Millisecond)
+				holders.Add(-1)
+				if err := l.Unlock(context.Background()); err != nil {
+					errs <- fmt.Errorf("Unlock: %w", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+	if want := int32(s.config.Concurrency * s.config.Iterations); total.Load() != want {
+		t.Errorf("critical section entered %d times, want %d", total.Load(), want)
+	}
+}
+
+// tryLockContended 锁被持有时 TryLock 立即返回 false，释放后可以获取
+func (s *suite) tryLockContended(t *testing.T) {
+	ctx := context.Background()
+	key := s.key()
+	a, b := s.newLocker(key), s.newLocker(key)
+	defer a.Close()
+	defer b.Close()
+
+	if ok, err := a.TryLock(ctx); err != nil || !ok {
+		t.Fatalf("first TryLock = %v, %v; want true", ok, err)
+	}
+	if a.Token() == b.Token() {
+		t.Fatalf("lockers share token %q", a.Token())
+	}
+	if ok, err := b.TryLock(ctx); err != nil || ok {
+		t.Fatalf("contended TryLock = %v, %v; want false", ok, err)
+	}
+	if err := a.Unlock(ctx); err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	if ok, err := b.TryLock(ctx); err != nil || !ok {
+		t.Fatalf("TryLock after release = %v, %v; want true", ok, err)
+	}
+}
+
+// ttlExpiry 持有者不释放时锁在 TTL 后过期，TTL 返回的剩余时间不超过设置值
+func (s *suite) ttlExpiry(t *testing.T) {
+	ctx := context.Background()
+	key := s.key()
+	a, b := s.newLocker(key), s.newLocker(key)
+	defer a.Close()
+	defer b.Close()
+
+	if ok, err := a.TryLock(ctx); err != nil || !ok {
+		t.Fatalf("TryLock = %v, %v; want true", ok, err)
+	}
+	ttl, err := a.TTL(ctx)
+	if err != nil || ttl <= 0 || ttl > s.config.TTL {
+		t.Fatalf("TTL = %v, %v; want (0, %v]", ttl, err, s.config.TTL)
+	}
+
+	time.Sleep(s.config.TTL + s.config.TTL/2)
+	if ok, err := b.TryLock(ctx); err != nil || !ok {
+		t.Fatalf("TryLock after expiry = %v, %v; want true", ok, err)
+	}
+	// 过期后被他人获取，原持有者不能再释放或续期
+	if err := a.Unlock(ctx); !errors.Is(err, locker.ErrLockNotHeld) {
+		t.Fatalf("Unlock by expired holder = %v; want ErrLockNotHeld", err)
+	}
+	if err := a.Refresh(ctx, s.config.TTL); !errors.Is(err, locker.ErrLockNotHeld) {
+		t.Fatalf("Refresh by expired holder = %v; want ErrLockNotHeld", err)
+	}
+}
+
+// refresh 续期后锁在原 TTL 之后仍被持有
+func (s *suite) refresh(t *testing.T) {
+	ctx := context.Background()
+	key := s.key()
+	a, b := s.newLocker(key), s.newLocker(key)
+	defer a.Close()
+	defer b.Close()
+
+	if ok, err := a.TryLock(ctx); err != nil || !ok {
+		t.Fatalf("TryLock = %v, %v; want true", ok, err)
+	}
+	time.Sleep(s.config.TTL / 2)
+	if err := a.Refresh(ctx, 3*s.config.TTL); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	time.Sleep(s.config.TTL)
+	if ok, err := b.TryLock(ctx); err != nil || ok {
+		t.Fatalf("TryLock after refresh = %v, %v; want false", ok, err)
+	}
+	if err := a.Unlock(ctx); err != nil {
+		t.Fatalf("Unlock after refresh: %v", err)
+	}
+}
+
+// crashRecovery 持有者崩溃（不释放也不关闭）后，阻塞等待的 Lock 在 TTL 过期后获得锁
+func (s *suite) crashRecovery(t *testing.T) {
+	ctx := context.Background()
+	key := s.key()
+	crashed := s.newLocker(key)
+	if ok, err := crashed.TryLock(ctx); err != nil || !ok {
+		t.Fatalf("TryLock = %v, %v; want true", ok, err)
+	}
+
+	waiter := s.newLocker(key)
+	defer waiter.Close()
+	start := time.Now()
+	if err := waiter.Lock(ctx); err != nil {
+		t.Fatalf("Lock after crash: %v", err)
+	}
+	if waited := time.Since(start); waited > 3*s.config.TTL {
+		t.Fatalf("waited %v for crashed holder, TTL is %v", waited, s.config.TTL)
+	}
+	if err := waiter.Unlock(ctx); err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+}
+
+// unlockNotHeld 未持有锁时释放、查询 TTL 返回 ErrLockNotHeld，且不影响当前持有者
+func (s *suite) unlockNotHeld(t *testing.T) {
+	ctx := context.Background()
+	key := s.key()
+	a, b := s.newLocker(key), s.newLocker(key)
+	defer a.Close()
+	defer b.Close()
+
+	if err := b.Unlock(ctx); !errors.Is(err, locker.ErrLockNotHeld) {
+		t.Fatalf("Unlock without lock = %v; want ErrLockNotHeld", err)
+	}
+	if ok, err := a.TryLock(ctx); err != nil || !ok {
+		t.Fatalf("TryLock = %v, %v; want true", ok, err)
+	}
+	if err := b.Unlock(ctx); !errors.Is(err, locker.ErrLockNotHeld) {
+		t.Fatalf("Unlock by non-holder = %v; want ErrLockNotHeld", err)
+	}
+	if _, err := b.TTL(ctx); !errors.Is(err, locker.ErrLockNotHeld) {
+		t.Fatalf("TTL by non-holder = %v; want ErrLockNotHeld", err)
+	}
+	if ok, err := b.TryLock(ctx); err != nil || ok {
+		t.Fatalf("holder lost the lock after non-holder Unlock: TryLock = %v, %v", ok, err)
+	}
+}
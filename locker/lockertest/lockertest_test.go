@@ -0,0 +1,27 @@
+package lockertest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jiajia556/tool-box/locker/memory"
+	"github.com/jiajia556/tool-box/locker/redis"
+)
+
+func TestMemory(t *testing.T) {
+	m, err := memory.NewMemoryManager(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	Run(t, m)
+}
+
+func TestRedis(t *testing.T) {
+	m, err := redis.NewRedisManager(redis.Options{Addr: RedisAddr(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	Run(t, m, WithTTL(time.Second))
+}
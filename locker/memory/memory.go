@@ -2,18 +2,22 @@ package memory
 
 import (
 	"context"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/jiajia556/tool-box/locker"
+	"github.com/jiajia556/tool-box/utils"
 )
 
 // MemoryManager 内存锁管理器（单机用）
 type MemoryManager struct {
-	mu    sync.RWMutex
-	locks map[string]*memoryLocker
+	mu         sync.RWMutex
+	locks      map[string]*memoryLocker
+	contention *locker.ContentionTracker
 }
 
 // memoryLocker 内存锁实现
@@ -25,12 +29,15 @@ type memoryLocker struct {
 	expireTime time.Time
 	mu         sync.Mutex
 	locked     bool
+	stopWatch  func()
+	grace      locker.Grace
 }
 
 // NewMemoryManager 创建内存锁管理器
 func NewMemoryManager(config any) (locker.Manager, error) {
 	return &MemoryManager{
-		locks: make(map[string]*memoryLocker),
+		locks:      make(map[string]*memoryLocker),
+		contention: locker.NewContentionTracker(),
 	}, nil
 }
 
@@ -40,6 +47,7 @@ func (mm *MemoryManager) New(key string, opts ...locker.Option) locker.Locker {
 	for _, opt := range opts {
 		opt(&config)
 	}
+	config.Clock = utils.ClockOrReal(config.Clock)
 
 	token := uuid.New().String()
 
@@ -54,12 +62,33 @@ func (mm *MemoryManager) New(key string, opts ...locker.Option) locker.Locker {
 
 // TryLock 尝试获取锁
 func (ml *memoryLocker) TryLock(ctx context.Context) (bool, error) {
+	acquired, err := ml.tryLock(ctx)
+	if err == nil && !acquired {
+		locker.Audit(ml.config, locker.AuditAcquire, ml.key, ml.token, locker.ErrLockFailed)
+	} else {
+		locker.Audit(ml.config, locker.AuditAcquire, ml.key, ml.token, err)
+	}
+	return acquired, err
+}
+
+func (ml *memoryLocker) tryLock(ctx context.Context) (bool, error) {
 	ml.manager.mu.Lock()
 	defer ml.manager.mu.Unlock()
 
+	// 宽限期内重新获取：取消延迟释放并恢复 TTL
+	if ml.grace.Cancel() {
+		if existingLock, ok := ml.manager.locks[ml.key]; ok && existingLock.token == ml.token &&
+			ml.config.Clock.Now().Before(ml.expireTime) {
+			ml.expireTime = ml.config.Clock.Now().Add(ml.config.TTL)
+			ml.manager.contention.Record(ml.key, true)
+			return true, nil
+		}
+	}
+
 	// 检查锁是否存在且未过期
 	if existingLock, ok := ml.manager.locks[ml.key]; ok {
-		if time.Now().Before(existingLock.expireTime) {
+		if ml.config.Clock.Now().Before(existingLock.expireTime) {
+			ml.manager.contention.Record(ml.key, false)
 			return false, nil
 		}
 		// 锁已过期，删除它
@@ -67,16 +96,29 @@ func (ml *memoryLocker) TryLock(ctx context.Context) (bool, error) {
 	}
 
 	// 获取锁
-	ml.expireTime = time.Now().Add(ml.config.TTL)
+	ml.expireTime = ml.config.Clock.Now().Add(ml.config.TTL)
 	ml.manager.locks[ml.key] = ml
 	ml.locked = true
+	ml.stopWatch = locker.WatchHold(ml.config, ml.key)
+	ml.manager.contention.Record(ml.key, true)
 
 	return true, nil
 }
 
 // Lock 获取锁（阻塞）
-func (ml *memoryLocker) Lock(ctx context.Context) error {
-	deadline := time.Now().Add(ml.config.Timeout)
+func (ml *memoryLocker) Lock(ctx context.Context) (err error) {
+	start := ml.config.Clock.Now()
+	deadline := start.Add(ml.config.Timeout)
+	attempts := 0
+	// 首次尝试失败后才计为等待者
+	var done func()
+	defer func() {
+		if done != nil {
+			done()
+		}
+		locker.ReportAcquire(ml.config, ml.key, start, attempts, err)
+		locker.Audit(ml.config, locker.AuditAcquire, ml.key, ml.token, err)
+	}()
 
 	for {
 		select {
@@ -86,12 +128,13 @@ func (ml *memoryLocker) Lock(ctx context.Context) error {
 		}
 
 		// 检查是否超时
-		if time.Now().After(deadline) {
+		if ml.config.Clock.Now().After(deadline) {
 			return locker.ErrWaitTimeout
 		}
 
 		// 尝试获取锁
-		acquired, err := ml.TryLock(ctx)
+		attempts++
+		acquired, err := ml.tryLock(ctx)
 		if err != nil {
 			return err
 		}
@@ -99,18 +142,23 @@ func (ml *memoryLocker) Lock(ctx context.Context) error {
 		if acquired {
 			return nil
 		}
+		if done == nil {
+			done = ml.manager.contention.Wait(ml.key)
+		}
 
 		// 等待后重试
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		case <-time.After(ml.config.PollInterval):
+		case <-ml.config.Clock.After(ml.config.PollInterval):
 		}
 	}
 }
 
 // Unlock 释放锁
-func (ml *memoryLocker) Unlock(ctx context.Context) error {
+func (ml *memoryLocker) Unlock(ctx context.Context) (err error) {
+	defer func() { locker.Audit(ml.config, locker.AuditRelease, ml.key, ml.token, err) }()
+	ml.grace.Cancel()
 	ml.manager.mu.Lock()
 	defer ml.manager.mu.Unlock()
 
@@ -125,7 +173,29 @@ func (ml *memoryLocker) Unlock(ctx context.Context) error {
 
 	delete(ml.manager.locks, ml.key)
 	ml.locked = false
+	ml.stopWatching()
+
+	return nil
+}
+
+// UnlockAfter 宽限期 d 后释放锁
+func (ml *memoryLocker) UnlockAfter(d time.Duration) error {
+	if d <= 0 {
+		return ml.Unlock(context.Background())
+	}
+
+	ml.manager.mu.Lock()
+	existingLock, ok := ml.manager.locks[ml.key]
+	if !ml.locked || !ok || existingLock.token != ml.token {
+		ml.manager.mu.Unlock()
+		return locker.ErrLockNotHeld
+	}
+	ml.expireTime = ml.config.Clock.Now().Add(d)
+	ml.manager.mu.Unlock()
 
+	ml.grace.Schedule(d, func() {
+		_ = ml.Unlock(context.Background())
+	})
 	return nil
 }
 
@@ -143,7 +213,7 @@ func (ml *memoryLocker) TTL(ctx context.Context) (time.Duration, error) {
 		return 0, locker.ErrLockNotHeld
 	}
 
-	ttl := time.Until(ml.expireTime)
+	ttl := ml.expireTime.Sub(ml.config.Clock.Now())
 	if ttl < 0 {
 		return 0, locker.ErrLockNotHeld
 	}
@@ -152,7 +222,8 @@ func (ml *memoryLocker) TTL(ctx context.Context) (time.Duration, error) {
 }
 
 // Refresh 刷新锁的过期时间
-func (ml *memoryLocker) Refresh(ctx context.Context, ttl time.Duration) error {
+func (ml *memoryLocker) Refresh(ctx context.Context, ttl time.Duration) (err error) {
+	defer func() { locker.Audit(ml.config, locker.AuditRefresh, ml.key, ml.token, err) }()
 	ml.manager.mu.Lock()
 	defer ml.manager.mu.Unlock()
 
@@ -161,7 +232,7 @@ func (ml *memoryLocker) Refresh(ctx context.Context, ttl time.Duration) error {
 		return locker.ErrLockNotHeld
 	}
 
-	ml.expireTime = time.Now().Add(ttl)
+	ml.expireTime = ml.config.Clock.Now().Add(ttl)
 	return nil
 }
 
@@ -177,17 +248,72 @@ func (ml *memoryLocker) Key() string {
 
 // Close 关闭锁
 func (ml *memoryLocker) Close() error {
+	ml.grace.Cancel()
 	ml.manager.mu.Lock()
-	defer ml.manager.mu.Unlock()
-
+	released := false
 	if ml.locked {
 		existingLock, ok := ml.manager.locks[ml.key]
 		if ok && existingLock.token == ml.token {
 			delete(ml.manager.locks, ml.key)
+			released = true
 		}
 		ml.locked = false
+		ml.stopWatching()
+	}
+	ml.manager.mu.Unlock()
+
+	// 审计输出可能较慢（如写缓存），在释放 mm.mu 后进行
+	if released {
+		locker.Audit(ml.config, locker.AuditRelease, ml.key, ml.token, nil)
+	}
+	return nil
+}
+
+func (ml *memoryLocker) stopWatching() {
+	if ml.stopWatch != nil {
+		ml.stopWatch()
+		ml.stopWatch = nil
+	}
+}
+
+// Striped 创建分段锁
+func (mm *MemoryManager) Striped(name string, stripes int, opts ...locker.Option) *locker.Striped {
+	return locker.NewStriped(mm, name, stripes, opts...)
+}
+
+// Contention 获取 key 的竞争情况
+func (mm *MemoryManager) Contention(ctx context.Context, key string) (locker.Contention, error) {
+	return mm.contention.Get(key), nil
+}
+
+// HeldKeys 列出以 prefix 开头、当前被持有的锁 key
+func (mm *MemoryManager) HeldKeys(ctx context.Context, prefix string) ([]string, error) {
+	mm.mu.RLock()
+	defer mm.mu.RUnlock()
+
+	keys := make([]string, 0)
+	for key, l := range mm.locks {
+		if strings.HasPrefix(key, prefix) && l.config.Clock.Now().Before(l.expireTime) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// ForceUnlock 强制释放 key，不校验持有者
+func (mm *MemoryManager) ForceUnlock(ctx context.Context, key string) error {
+	mm.mu.Lock()
+	token := ""
+	if l, ok := mm.locks[key]; ok {
+		delete(mm.locks, key)
+		l.locked = false
+		l.stopWatching()
+		token = l.token
 	}
+	mm.mu.Unlock()
 
+	locker.Audit(locker.Config{}, locker.AuditForceUnlock, key, token, nil)
 	return nil
 }
 
@@ -0,0 +1,99 @@
+package locker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jiajia556/tool-box/cache"
+	"github.com/jiajia556/tool-box/retry"
+)
+
+var ErrConflict = errors.New("optimistic update conflict")
+
+// Optimistic 基于缓存 CAS 的乐观锁：读取当前值、计算新值、CAS 写回，冲突时按重试策略重新执行。
+// 不持有阻塞锁，适合低冲突的计数器、配置更新等短临界区；fn 可能被执行多次，不应有副作用。
+type Optimistic struct {
+	cache  cache.Cache
+	policy *retry.Policy
+}
+
+// NewOptimistic 创建乐观锁，policy 为 nil 时最多尝试 10 次，1ms 起指数退避，上限 50ms
+func NewOptimistic(c cache.Cache, policy *retry.Policy) *Optimistic {
+	if policy == nil {
+		policy = retry.New(
+			retry.WithMaxAttempts(10),
+			retry.WithBackoff(retry.Exponential(time.Millisecond, 50*time.Millisecond, 0.5)),
+		)
+	}
+
+	// 只重试 CAS 冲突，fn 返回的错误直接返回
+	p := *policy
+	retryable := p.Retryable
+	p.Retryable = func(err error) bool {
+		if !errors.Is(err, ErrConflict) {
+			return false
+		}
+		return retryable == nil || retryable(err)
+	}
+	return &Optimistic{cache: c, policy: &p}
+}
+
+// Update 读取 key 的当前值交给 fn 计算新值并写回，返回写入的值；重试用尽时返回 ErrConflict。
+// current 为缓存读回的值（经 JSON 往返，数字为 float64），exists 表示 key 是否存在。
+func (o *Optimistic) Update(ctx context.Context, key string, ttl time.Duration, fn func(current any, exists bool) (any, error)) (any, error) {
+	return retry.Do(ctx, o.policy, func(ctx context.Context) (any, error) {
+		current, err := o.cache.Get(key)
+		exists := err == nil
+		if errors.Is(err, cache.ErrDecode) {
+			return nil, err
+		}
+		if !exists {
+			current = nil
+		}
+
+		next, err := fn(current, exists)
+		if err != nil {
+			return nil, err
+		}
+
+		ok, err := o.cache.CompareAndSwap(key, current, next, ttl)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, ErrConflict
+		}
+		return next, nil
+	})
+}
+
+// Update 泛型版本，current 解码为 T
+func Update[T any](ctx context.Context, o *Optimistic, key string, ttl time.Duration, fn func(current T, exists bool) (T, error)) (T, error) {
+	var out T
+	_, err := o.Update(ctx, key, ttl, func(current any, exists bool) (any, error) {
+		var cur T
+		if exists {
+			b, err := json.Marshal(current)
+			if err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(b, &cur); err != nil {
+				return nil, errors.Join(cache.ErrDecode, err)
+			}
+		}
+
+		next, err := fn(cur, exists)
+		if err != nil {
+			return nil, err
+		}
+		out = next
+		return next, nil
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return out, nil
+}
@@ -2,7 +2,10 @@ package redis
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -10,6 +13,8 @@ import (
 	"github.com/redis/go-redis/v9"
 
 	"github.com/jiajia556/tool-box/locker"
+	"github.com/jiajia556/tool-box/retry"
+	"github.com/jiajia556/tool-box/utils"
 )
 
 var (
@@ -28,8 +33,9 @@ type Options struct {
 
 // RedisManager Redis 分布式锁管理器
 type RedisManager struct {
-	mu    sync.RWMutex
-	locks map[string]*redisLocker
+	mu         sync.RWMutex
+	locks      map[string]*redisLocker
+	contention *locker.ContentionTracker
 }
 
 // redisLocker Redis 锁实现
@@ -44,6 +50,8 @@ type redisLocker struct {
 	refreshStopChan chan struct{}
 	mu              sync.Mutex
 	locked          bool
+	stopWatch       func()
+	grace           locker.Grace
 }
 
 // NewRedisManager 创建 Redis 锁管理器，同时初始化全局 Redis 客户端
@@ -69,7 +77,8 @@ func NewRedisManager(config any) (locker.Manager, error) {
 	}
 
 	return &RedisManager{
-		locks: make(map[string]*redisLocker),
+		locks:      make(map[string]*redisLocker),
+		contention: locker.NewContentionTracker(),
 	}, nil
 }
 
@@ -140,6 +149,16 @@ func (rm *RedisManager) New(key string, opts ...locker.Option) locker.Locker {
 
 // TryLock 尝试获取锁
 func (rl *redisLocker) TryLock(ctx context.Context) (bool, error) {
+	acquired, err := rl.tryLock(ctx)
+	if err == nil && !acquired {
+		locker.Audit(rl.config, locker.AuditAcquire, rl.key, rl.token, locker.ErrLockFailed)
+	} else {
+		locker.Audit(rl.config, locker.AuditAcquire, rl.key, rl.token, err)
+	}
+	return acquired, err
+}
+
+func (rl *redisLocker) tryLock(ctx context.Context) (bool, error) {
 	clientMu.RLock()
 	client := globalClient
 	clientMu.RUnlock()
@@ -148,6 +167,28 @@ func (rl *redisLocker) TryLock(ctx context.Context) (bool, error) {
 		return false, fmt.Errorf("redis client not initialized")
 	}
 
+	// 宽限期内重新获取：取消延迟释放并恢复 TTL；锁已过期时按未持有重新获取
+	if rl.grace.Cancel() {
+		err := rl.Refresh(ctx, rl.config.TTL)
+		if err == nil {
+			rl.manager.contention.Record(rl.key, true)
+			if rl.config.RefreshInterval > 0 {
+				rl.startRefresh()
+			}
+			return true, nil
+		}
+		if !errors.Is(err, locker.ErrLockNotHeld) {
+			return false, err
+		}
+		rl.mu.Lock()
+		rl.locked = false
+		if rl.stopWatch != nil {
+			rl.stopWatch()
+			rl.stopWatch = nil
+		}
+		rl.mu.Unlock()
+	}
+
 	rl.mu.Lock()
 	if rl.locked {
 		rl.mu.Unlock()
@@ -161,9 +202,11 @@ func (rl *redisLocker) TryLock(ctx context.Context) (bool, error) {
 		return false, err
 	}
 
+	rl.manager.contention.Record(rl.key, ok)
 	if ok {
 		rl.mu.Lock()
 		rl.locked = true
+		rl.stopWatch = locker.WatchHold(rl.config, rl.key)
 		rl.mu.Unlock()
 
 		// 启动自动续期
@@ -177,43 +220,101 @@ func (rl *redisLocker) TryLock(ctx context.Context) (bool, error) {
 	return false, nil
 }
 
+// errLockBusy 锁被占用，按轮询间隔重试
+var errLockBusy = errors.New("redis: lock is held by another holder")
+
 // Lock 获取锁（阻塞）
 func (rl *redisLocker) Lock(ctx context.Context) error {
-	deadline := time.Now().Add(rl.config.Timeout)
-
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-
-		// 检查是否超时
-		if time.Now().After(deadline) {
-			return locker.ErrWaitTimeout
+	// Timeout<=0 时只尝试一次；否则轮询到截止时间，并在截止时间再尝试一次
+	maxAttempts := 0
+	if rl.config.Timeout <= 0 {
+		maxAttempts = 1
+	}
+	policy := retry.New(
+		retry.WithMaxAttempts(maxAttempts),
+		retry.WithMaxElapsed(rl.config.Timeout),
+		retry.WithBackoff(retry.Constant(rl.config.PollInterval)),
+		retry.WithRetryable(func(err error) bool {
+			return errors.Is(err, errLockBusy)
+		}),
+	)
+
+	start := utils.ClockOrReal(rl.config.Clock).Now()
+	attempts := 0
+	// 首次尝试失败、确实需要等待时才登记为等待者，无竞争时不产生额外的往返
+	var done func()
+	defer func() {
+		if done != nil {
+			done()
+			rl.removeWaiter()
 		}
+	}()
 
-		// 尝试获取锁
-		acquired, err := rl.TryLock(ctx)
+	err := policy.Do(ctx, func(ctx context.Context) error {
+		attempts++
+		acquired, err := rl.tryLock(ctx)
 		if err != nil {
-			return err
+			return retry.Permanent(err)
 		}
-
-		if acquired {
-			return nil
+		if !acquired {
+			if done == nil && maxAttempts != 1 {
+				done = rl.manager.contention.Wait(rl.key)
+				rl.addWaiter(ctx)
+			}
+			return errLockBusy
 		}
+		return nil
+	})
+	if errors.Is(err, errLockBusy) {
+		err = locker.ErrWaitTimeout
+	}
+	locker.ReportAcquire(rl.config, rl.key, start, attempts, err)
+	locker.Audit(rl.config, locker.AuditAcquire, rl.key, rl.token, err)
+	return err
+}
 
-		// 等待后重试
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-time.After(rl.config.PollInterval):
-		}
+// waitersKey 记录阻塞等待者的有序集合，member 为 token，score 为放弃等待的时间（毫秒）
+func waitersKey(key string) string {
+	return key + ":waiters"
+}
+
+// addWaiter 登记为等待者，登记失败只影响 Contention 统计
+func (rl *redisLocker) addWaiter(ctx context.Context) {
+	clientMu.RLock()
+	client := globalClient
+	clientMu.RUnlock()
+	if client == nil {
+		return
+	}
+
+	giveUp := time.Now().Add(rl.config.Timeout)
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(giveUp) {
+		giveUp = deadline
+	}
+	wk := waitersKey(rl.key)
+	_, _ = client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, wk, redis.Z{Score: float64(giveUp.UnixMilli()), Member: rl.token})
+		pipe.PExpireAt(ctx, wk, giveUp.Add(time.Minute))
+		return nil
+	})
+}
+
+func (rl *redisLocker) removeWaiter() {
+	clientMu.RLock()
+	client := globalClient
+	clientMu.RUnlock()
+	if client == nil {
+		return
 	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = client.ZRem(ctx, waitersKey(rl.key), rl.token).Err()
 }
 
 // Unlock 释放锁
-func (rl *redisLocker) Unlock(ctx context.Context) error {
+func (rl *redisLocker) Unlock(ctx context.Context) (err error) {
+	defer func() { locker.Audit(rl.config, locker.AuditRelease, rl.key, rl.token, err) }()
 	clientMu.RLock()
 	client := globalClient
 	clientMu.RUnlock()
@@ -222,6 +323,7 @@ func (rl *redisLocker) Unlock(ctx context.Context) error {
 		return fmt.Errorf("redis client not initialized")
 	}
 
+	rl.grace.Cancel()
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
@@ -229,8 +331,12 @@ func (rl *redisLocker) Unlock(ctx context.Context) error {
 		return locker.ErrLockNotHeld
 	}
 
-	// 停止自动续期
+	// 停止自动续期与持锁看门狗
 	rl.stopRefresh()
+	if rl.stopWatch != nil {
+		rl.stopWatch()
+		rl.stopWatch = nil
+	}
 
 	// 检查锁是否仍然被当前 holder 持有
 	val, err := client.Get(ctx, rl.key).Result()
@@ -276,6 +382,34 @@ func (rl *redisLocker) Unlock(ctx context.Context) error {
 	return locker.ErrLockNotHeld
 }
 
+// UnlockAfter 宽限期 d 后释放锁，宽限期内停止自动续期
+func (rl *redisLocker) UnlockAfter(d time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if d <= 0 {
+		return rl.Unlock(ctx)
+	}
+
+	rl.mu.Lock()
+	if !rl.locked {
+		rl.mu.Unlock()
+		return locker.ErrLockNotHeld
+	}
+	rl.stopRefresh()
+	rl.mu.Unlock()
+
+	if err := rl.Refresh(ctx, d); err != nil {
+		return err
+	}
+	rl.grace.Schedule(d, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = rl.Unlock(ctx)
+	})
+	return nil
+}
+
 // TTL 获取锁的剩余时间
 func (rl *redisLocker) TTL(ctx context.Context) (time.Duration, error) {
 	clientMu.RLock()
@@ -306,7 +440,8 @@ func (rl *redisLocker) TTL(ctx context.Context) (time.Duration, error) {
 }
 
 // Refresh 刷新锁的过期时间
-func (rl *redisLocker) Refresh(ctx context.Context, ttl time.Duration) error {
+func (rl *redisLocker) Refresh(ctx context.Context, ttl time.Duration) (err error) {
+	defer func() { locker.Audit(rl.config, locker.AuditRefresh, rl.key, rl.token, err) }()
 	clientMu.RLock()
 	client := globalClient
 	clientMu.RUnlock()
@@ -411,6 +546,79 @@ func (rl *redisLocker) stopRefresh() {
 	}
 }
 
+// Striped 创建分段锁
+func (rm *RedisManager) Striped(name string, stripes int, opts ...locker.Option) *locker.Striped {
+	return locker.NewStriped(rm, name, stripes, opts...)
+}
+
+// Contention 获取 key 的竞争情况：
+// 等待者数量来自 Redis 中所有实例登记的等待者（已超过等待期限的会被清理），失败率仅统计当前实例的尝试
+func (rm *RedisManager) Contention(ctx context.Context, key string) (locker.Contention, error) {
+	clientMu.RLock()
+	client := globalClient
+	clientMu.RUnlock()
+
+	if client == nil {
+		return locker.Contention{}, fmt.Errorf("redis client not initialized")
+	}
+
+	wk := waitersKey(key)
+	var card *redis.IntCmd
+	_, err := client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRemRangeByScore(ctx, wk, "-inf", fmt.Sprintf("(%d", time.Now().UnixMilli()))
+		card = pipe.ZCard(ctx, wk)
+		return nil
+	})
+	if err != nil {
+		return locker.Contention{}, err
+	}
+
+	c := rm.contention.Get(key)
+	c.Waiters = int(card.Val())
+	return c, nil
+}
+
+// globEscaper 转义 SCAN MATCH 中的通配符
+var globEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
+// HeldKeys 列出以 prefix 开头、当前被持有的锁 key（通过 SCAN 遍历，跳过等待者集合）
+func (rm *RedisManager) HeldKeys(ctx context.Context, prefix string) ([]string, error) {
+	clientMu.RLock()
+	client := globalClient
+	clientMu.RUnlock()
+
+	if client == nil {
+		return nil, fmt.Errorf("redis client not initialized")
+	}
+
+	keys := make([]string, 0)
+	iter := client.Scan(ctx, 0, globEscaper.Replace(prefix)+"*", 0).Iterator()
+	for iter.Next(ctx) {
+		if key := iter.Val(); !strings.HasSuffix(key, ":waiters") {
+			keys = append(keys, key)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// ForceUnlock 强制释放 key，不校验持有者
+func (rm *RedisManager) ForceUnlock(ctx context.Context, key string) error {
+	clientMu.RLock()
+	client := globalClient
+	clientMu.RUnlock()
+
+	if client == nil {
+		return fmt.Errorf("redis client not initialized")
+	}
+	err := client.Del(ctx, key).Err()
+	locker.Audit(locker.Config{}, locker.AuditForceUnlock, key, "", err)
+	return err
+}
+
 // Close 关闭锁管理器
 func (rm *RedisManager) Close() error {
 	rm.mu.Lock()
@@ -0,0 +1,52 @@
+package locker
+
+import (
+	"hash/fnv"
+	"strconv"
+)
+
+// Striped 分段锁：将任意 key 通过一致性哈希映射到固定数量的底层锁上，
+// 在限制锁 key 数量（如 Redis key）的同时分散竞争。映射到同一段的不同 key 会互斥。
+type Striped struct {
+	manager Manager
+	name    string
+	stripes int
+	opts    []Option
+}
+
+// NewStriped 创建分段锁，stripes 小于 1 时按 1 处理；opts 作为每把锁的默认选项
+func NewStriped(m Manager, name string, stripes int, opts ...Option) *Striped {
+	if stripes < 1 {
+		stripes = 1
+	}
+	return &Striped{manager: m, name: name, stripes: stripes, opts: opts}
+}
+
+// Stripe 返回 key 对应的段号
+func (s *Striped) Stripe(key string) int {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return int(jumpHash(h.Sum64(), s.stripes))
+}
+
+// ForKey 返回 key 所在段的锁，opts 追加在默认选项之后
+func (s *Striped) ForKey(key string, opts ...Option) Locker {
+	all := append(append([]Option(nil), s.opts...), opts...)
+	return s.manager.New(s.name+":stripe:"+strconv.Itoa(s.Stripe(key)), all...)
+}
+
+// Stripes 返回段数
+func (s *Striped) Stripes() int {
+	return s.stripes
+}
+
+// jumpHash Jump Consistent Hash：段数变化时只有约 1/n 的 key 需要迁移
+func jumpHash(key uint64, buckets int) int32 {
+	var b, j int64 = -1, 0
+	for j < int64(buckets) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int32(b)
+}
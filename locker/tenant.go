@@ -0,0 +1,114 @@
+package locker
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ErrAdminUnsupported 管理器不支持列出或强制释放锁
+var ErrAdminUnsupported = errors.New("locker: manager does not support listing or force unlock")
+
+// Admin 支持列出与强制释放锁的管理器，由适配器实现
+type Admin interface {
+	// 列出以 prefix 开头、当前被持有的锁 key
+	HeldKeys(ctx context.Context, prefix string) ([]string, error)
+
+	// 强制释放 key，不校验持有者；原持有者随后 Unlock 会返回 ErrLockNotHeld 或直接成功
+	ForceUnlock(ctx context.Context, key string) error
+}
+
+// Scoped 自动为锁 key 加前缀的管理器，用于多租户服务隔离不同租户的锁，避免 key 冲突。
+// 创建的锁的 Key() 返回加前缀后的完整 key。
+type Scoped struct {
+	manager Manager
+	prefix  string
+}
+
+// WithPrefix 创建为所有 key 加上 prefix 的管理器
+func WithPrefix(m Manager, prefix string) *Scoped {
+	return &Scoped{manager: m, prefix: prefix}
+}
+
+// ForTenant 创建租户隔离的管理器，key 形如 "tenant:123:order:456"
+func ForTenant(m Manager, tenant string) *Scoped {
+	return WithPrefix(m, "tenant:"+tenant+":")
+}
+
+// Tenant 基于全局锁管理器创建租户隔离的管理器，全局管理器未初始化时返回 nil
+func Tenant(tenant string) *Scoped {
+	if globalManager == nil {
+		return nil
+	}
+	return ForTenant(globalManager, tenant)
+}
+
+// Prefix 返回 key 前缀
+func (s *Scoped) Prefix() string {
+	return s.prefix
+}
+
+// New 创建新的锁
+func (s *Scoped) New(key string, opts ...Option) Locker {
+	return s.manager.New(s.prefix+key, opts...)
+}
+
+// Striped 创建分段锁，各段的 key 同样加上前缀
+func (s *Scoped) Striped(name string, stripes int, opts ...Option) *Striped {
+	return NewStriped(s, name, stripes, opts...)
+}
+
+// Contention 获取 key 的竞争情况
+func (s *Scoped) Contention(ctx context.Context, key string) (Contention, error) {
+	return s.manager.Contention(ctx, s.prefix+key)
+}
+
+// Keys 列出本前缀下当前被持有的锁 key（不含前缀）
+func (s *Scoped) Keys(ctx context.Context) ([]string, error) {
+	admin, ok := s.manager.(Admin)
+	if !ok {
+		return nil, ErrAdminUnsupported
+	}
+	keys, err := admin.HeldKeys(ctx, s.prefix)
+	if err != nil {
+		return nil, err
+	}
+	for i, key := range keys {
+		keys[i] = strings.TrimPrefix(key, s.prefix)
+	}
+	return keys, nil
+}
+
+// ForceUnlock 强制释放本前缀下的 key
+func (s *Scoped) ForceUnlock(ctx context.Context, key string) error {
+	admin, ok := s.manager.(Admin)
+	if !ok {
+		return ErrAdminUnsupported
+	}
+	return admin.ForceUnlock(ctx, s.prefix+key)
+}
+
+// ForceUnlockAll 强制释放本前缀下的所有锁，返回释放的数量
+func (s *Scoped) ForceUnlockAll(ctx context.Context) (int, error) {
+	admin, ok := s.manager.(Admin)
+	if !ok {
+		return 0, ErrAdminUnsupported
+	}
+	keys, err := admin.HeldKeys(ctx, s.prefix)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, key := range keys {
+		if err := admin.ForceUnlock(ctx, key); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// Close 不关闭底层管理器，底层管理器通常被多个租户共享
+func (s *Scoped) Close() error {
+	return nil
+}
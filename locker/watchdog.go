@@ -0,0 +1,78 @@
+package locker
+
+import (
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jiajia556/tool-box/log"
+	"github.com/jiajia556/tool-box/utils"
+)
+
+var holdWarnings atomic.Uint64
+
+// HoldWarnings 返回持锁超时告警的累计次数
+func HoldWarnings() uint64 {
+	return holdWarnings.Load()
+}
+
+// WatchHold 在获取锁后启动持锁时长看门狗，供适配器调用。
+// 持锁超过 config.HoldWarning 时输出一条包含获取锁调用栈的 WARN 日志并累加 HoldWarnings，
+// 用于发现遗漏的 Unlock 与失控的临界区。返回的 stop 在释放锁时调用；未开启时返回空函数。
+func WatchHold(config Config, key string) (stop func()) {
+	if config.HoldWarning <= 0 {
+		return func() {}
+	}
+
+	clock := utils.ClockOrReal(config.Clock)
+	start := clock.Now()
+	stack := string(debug.Stack())
+	done := make(chan struct{})
+	// 使用锁的时间源计时，与锁自身的过期判断一致
+	go func() {
+		select {
+		case <-clock.After(config.HoldWarning):
+		case <-done:
+			return
+		}
+		holdWarnings.Add(1)
+		log.Warn("locker: lock held longer than expected",
+			"key", key,
+			"held", clock.Now().Sub(start).String(),
+			"threshold", config.HoldWarning.String(),
+			"stack", stack,
+		)
+	}()
+	return sync.OnceFunc(func() { close(done) })
+}
+
+// ReportAcquire 在阻塞获取锁结束后调用，供适配器使用。
+// 等待超过 config.SlowAcquire 时输出包含等待时长与竞争次数（未抢到锁的尝试次数）的结构化日志：
+// 成功获取为 WARN，失败为 ERROR。
+func ReportAcquire(config Config, key string, start time.Time, attempts int, err error) {
+	if config.SlowAcquire <= 0 {
+		return
+	}
+	wait := utils.ClockOrReal(config.Clock).Now().Sub(start)
+	if wait < config.SlowAcquire {
+		return
+	}
+
+	contention := attempts - 1
+	if err != nil {
+		contention = attempts
+		log.Error("locker: lock acquisition failed after waiting",
+			"key", key,
+			"wait", wait.String(),
+			"contention", contention,
+			"error", err,
+		)
+		return
+	}
+	log.Warn("locker: slow lock acquisition",
+		"key", key,
+		"wait", wait.String(),
+		"contention", contention,
+	)
+}
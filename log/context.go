@@ -0,0 +1,44 @@
+package log
+
+import "context"
+
+type contextFieldsKey struct{}
+
+// PushFields 将键值对附加到上下文（MDC），之后使用该上下文的 XxxContext 调用都会自动带上这些字段。
+// 通常在中间件中设置 user_id、request_id 等请求级字段，无需在调用链中层层传递 logger。
+// 同名字段以最后一次设置为准；奇数个参数时最后一个值以 "extra" 为 key。
+func PushFields(ctx context.Context, kv ...interface{}) context.Context {
+	if len(kv) == 0 {
+		return ctx
+	}
+
+	parent := ContextFields(ctx)
+	fields := pairFields(kv...)
+
+	// 先放入父级中未被覆盖的字段，保持原有顺序
+	merged := make([]Field, 0, len(parent)+len(fields))
+	for _, p := range parent {
+		overridden := false
+		for _, f := range fields {
+			if f.Key == p.Key {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			merged = append(merged, p)
+		}
+	}
+	merged = append(merged, fields...)
+
+	return context.WithValue(ctx, contextFieldsKey{}, merged)
+}
+
+// ContextFields 返回上下文中的 MDC 字段，按设置顺序排列；返回值不可修改
+func ContextFields(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(contextFieldsKey{}).([]Field)
+	return fields
+}
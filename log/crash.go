@@ -0,0 +1,49 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"time"
+)
+
+// CaptureStderrPanics 将未恢复的 panic 与 runtime 致命错误（如 concurrent map writes）的完整输出
+// 在写 stderr 的同时追加写入崩溃日志文件，避免进程退出后只留在丢失的控制台上。
+// path 为空时使用默认日志记录器的 File.Dir 下的 crash.log（未初始化时为 ./logs/crash.log）。
+// 每次调用会覆盖之前的设置；进程存活期间文件保持打开。
+func CaptureStderrPanics(path ...string) error {
+	file := ""
+	if len(path) > 0 {
+		file = path[0]
+	}
+	if file == "" {
+		dir := "./logs"
+		if logger := Get(); logger != nil {
+			if d := logger.GetConfig().File.Dir; d != "" {
+				dir = d
+			}
+		}
+		file = filepath.Join(dir, "crash.log")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return fmt.Errorf("logger: create crash log dir: %w", err)
+	}
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("logger: open crash log: %w", err)
+	}
+
+	// 记录进程启动信息，便于将崩溃输出与具体进程对应
+	_, _ = fmt.Fprintf(f, "%s process started pid=%d args=%q\n",
+		time.Now().Format("2006-01-02 15:04:05"), os.Getpid(), os.Args)
+
+	if err := debug.SetCrashOutput(f, debug.CrashOptions{}); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("logger: set crash output: %w", err)
+	}
+	// SetCrashOutput 会复制文件描述符，这里可以关闭原文件
+	_ = f.Close()
+	return nil
+}
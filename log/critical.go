@@ -0,0 +1,102 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/jiajia556/tool-box/retry"
+)
+
+// AlertHook 关键事件的告警回调（如发送 IM / 短信），返回错误时会重试
+type AlertHook func(ctx context.Context, entry *Entry) error
+
+// CriticalWriter 支持可靠写入关键事件的 Logger：同步写入所有输出并落盘，返回写入错误
+type CriticalWriter interface {
+	WriteCritical(entry *Entry) error
+}
+
+var (
+	alertMu     sync.RWMutex
+	alertHooks  []AlertHook
+	alertPolicy = retry.New(
+		retry.WithMaxAttempts(5),
+		retry.WithBackoff(retry.Exponential(100*time.Millisecond, 2*time.Second, 0.2)),
+	)
+)
+
+// RegisterAlertHook 注册关键事件告警回调
+func RegisterAlertHook(hook AlertHook) {
+	if hook == nil {
+		panic("logger: RegisterAlertHook hook is nil")
+	}
+	alertMu.Lock()
+	defer alertMu.Unlock()
+	alertHooks = append(alertHooks, hook)
+}
+
+// Critical 记录必须送达的关键事件（如检测到数据损坏、支付金额不一致）：
+// 以 ERROR 级别（附带 critical=true 与调用栈）同步写入默认日志记录器的所有输出并 fsync 文件，
+// 随后依次调用告警回调，失败时按退避重试。返回写入与告警中的全部错误，调用方可据此进一步兜底。
+func Critical(msg string, fields ...interface{}) error {
+	return CriticalContext(context.Background(), msg, fields...)
+}
+
+// CriticalContext 同 Critical，ctx 用于控制告警回调的重试并提供上下文字段
+func CriticalContext(ctx context.Context, msg string, fields ...interface{}) error {
+	ordered := append(append([]Field(nil), ContextFields(ctx)...), pairFields(fields...)...)
+	ordered = append(ordered, Field{Key: "critical", Value: true})
+	fieldMap := make(map[string]interface{}, len(ordered))
+	for _, f := range ordered {
+		fieldMap[f.Key] = f.Value
+	}
+	entry := &Entry{
+		Time:          time.Now(),
+		Level:         LevelError,
+		Message:       msg,
+		Fields:        fieldMap,
+		OrderedFields: ordered,
+		Stack:         string(debug.Stack()),
+		Ctx:           ctx,
+	}
+
+	var errs []error
+	switch logger := Get().(type) {
+	case nil:
+		errs = append(errs, errors.New("logger: no default logger for critical event"))
+	case CriticalWriter:
+		if err := logger.WriteCritical(entry); err != nil {
+			errs = append(errs, err)
+		}
+	default:
+		logger.ErrorContext(ctx, msg, append(fields, "critical", true)...)
+	}
+
+	alertMu.RLock()
+	hooks := append([]AlertHook(nil), alertHooks...)
+	alertMu.RUnlock()
+	for _, hook := range hooks {
+		if err := alertPolicy.Do(ctx, func(ctx context.Context) error {
+			return hook(ctx, entry)
+		}); err != nil {
+			errs = append(errs, fmt.Errorf("logger: critical alert hook: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// pairFields 将键值对转换为字段；奇数个参数时最后一个值以 "extra" 为 key
+func pairFields(kv ...interface{}) []Field {
+	fields := make([]Field, 0, len(kv)/2+1)
+	for i := 0; i < len(kv); i += 2 {
+		if i+1 < len(kv) {
+			fields = append(fields, Field{Key: fmt.Sprint(kv[i]), Value: kv[i+1]})
+		} else {
+			fields = append(fields, Field{Key: "extra", Value: kv[i]})
+		}
+	}
+	return fields
+}
@@ -0,0 +1,144 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Enricher 在每条日志写出前为其补充字段，用于主机名、版本号、部署区域等进程级的固定信息，
+// 在 Config.Enrichers 中按名称启用一次即可，无需在每个调用点 WithFields
+type Enricher interface {
+	Enrich(entry *Entry)
+}
+
+// EnricherFunc 函数形式的 Enricher
+type EnricherFunc func(entry *Entry)
+
+func (f EnricherFunc) Enrich(entry *Entry) { f(entry) }
+
+var (
+	enrichersMu sync.RWMutex
+	enrichers   = make(map[string]Enricher)
+)
+
+// 内置的 Enricher
+const (
+	EnricherHostname = "hostname" // host: 主机名
+	EnricherVersion  = "version"  // version: 环境变量 APP_VERSION，未设置时取构建信息中的模块版本
+	EnricherK8s      = "k8s"      // k8s.pod / k8s.namespace / k8s.node: 通过 Downward API 注入的 POD_NAME、POD_NAMESPACE、NODE_NAME
+	EnricherRegion   = "region"   // region: 环境变量 REGION，未设置时取 DEPLOY_REGION
+)
+
+func init() {
+	host, _ := os.Hostname()
+	RegisterEnricher(EnricherHostname, StaticFields("host", host))
+	RegisterEnricher(EnricherVersion, StaticFields("version", appVersion()))
+	RegisterEnricher(EnricherK8s, EnvFields(map[string]string{
+		"k8s.pod":       "POD_NAME",
+		"k8s.namespace": "POD_NAMESPACE",
+		"k8s.node":      "NODE_NAME",
+	}))
+	region := os.Getenv("REGION")
+	if region == "" {
+		region = os.Getenv("DEPLOY_REGION")
+	}
+	RegisterEnricher(EnricherRegion, StaticFields("region", region))
+}
+
+// RegisterEnricher 注册 Enricher，之后可在 Config.Enrichers 中按 name 启用
+func RegisterEnricher(name string, e Enricher) {
+	enrichersMu.Lock()
+	defer enrichersMu.Unlock()
+	if e == nil {
+		panic("logger: RegisterEnricher enricher is nil")
+	}
+	if _, ok := enrichers[name]; ok {
+		panic("logger: RegisterEnricher called twice for " + name)
+	}
+	enrichers[name] = e
+}
+
+// LookupEnrichers 按逗号分隔的名称依次查找已注册的 Enricher，供适配器在 SetConfig 时解析 Config.Enrichers；
+// 名称两侧的空白与空名称被忽略
+func LookupEnrichers(names string) ([]Enricher, error) {
+	enrichersMu.RLock()
+	defer enrichersMu.RUnlock()
+
+	var list []Enricher
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		e, ok := enrichers[name]
+		if !ok {
+			return nil, fmt.Errorf("logger: unknown enricher %q", name)
+		}
+		list = append(list, e)
+	}
+	return list, nil
+}
+
+// Enrich 依次执行 list 中的 Enricher
+func Enrich(entry *Entry, list []Enricher) {
+	for _, e := range list {
+		e.Enrich(entry)
+	}
+}
+
+// SetDefaultField 为条目添加字段，已有同名字段（调用点或 WithFields 传入）时不覆盖
+func (e *Entry) SetDefaultField(key string, value interface{}) {
+	if _, ok := e.Fields[key]; ok {
+		return
+	}
+	if e.Fields == nil {
+		e.Fields = make(map[string]interface{})
+	}
+	e.Fields[key] = value
+	e.OrderedFields = append(e.OrderedFields, Field{Key: key, Value: value})
+}
+
+// StaticFields 返回添加固定字段的 Enricher，值为空字符串的字段被忽略
+func StaticFields(kv ...interface{}) Enricher {
+	fields := pairFields(kv...)
+	return EnricherFunc(func(entry *Entry) {
+		for _, f := range fields {
+			if s, ok := f.Value.(string); ok && s == "" {
+				continue
+			}
+			entry.SetDefaultField(f.Key, f.Value)
+		}
+	})
+}
+
+// EnvFields 返回从环境变量添加字段的 Enricher，fields 为字段名到环境变量名的映射；
+// 环境变量在创建时读取一次，未设置的跳过
+func EnvFields(fields map[string]string) Enricher {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	kv := make([]interface{}, 0, len(fields)*2)
+	for _, key := range keys {
+		if v := os.Getenv(fields[key]); v != "" {
+			kv = append(kv, key, v)
+		}
+	}
+	return StaticFields(kv...)
+}
+
+func appVersion() string {
+	if v := os.Getenv("APP_VERSION"); v != "" {
+		return v
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return ""
+}
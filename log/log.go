@@ -3,8 +3,11 @@ package log
 import (
 	"context"
 	"fmt"
+	"io"
 	"sync"
 	"time"
+
+	"github.com/jiajia556/tool-box/utils"
 )
 
 // Level 日志级别
@@ -123,14 +126,28 @@ type Logger interface {
 // Config 日志配置
 type Config struct {
 	Level       Level
-	Format      string // "text" 或 "json"
-	Output      string // "stdout", "stderr", "file", "combined"
+	Format      string    // "text" 或 "json"
+	Output      string    // "stdout", "stderr", "file", "combined", "journald", "eventlog", "writer"
+	Writer      io.Writer // Output 为 "writer" 时的输出目标，由调用方负责关闭
 	File        FileConfig
+	Identifier  string // journald 的 SYSLOG_IDENTIFIER / Windows 事件日志来源，默认取程序名
 	Caller      bool
 	CallDepth   int
 	TimeFormat  string
 	Encoder     string // "text", "json", "pretty"
+	Schema      string // json 编码的字段规范："" 为默认字段名，"ecs" 为 Elastic Common Schema，"otel" 为 OpenTelemetry 日志数据模型
 	Development bool
+	Clock       utils.Clock // 日志时间戳的时间源，nil 表示使用系统时间
+	Failover    FailoverConfig
+	Enrichers   string // 启用的 Enricher 名称，逗号分隔（如 "hostname,version"），按顺序对每条日志执行，见 RegisterEnricher
+}
+
+// FailoverConfig 输出写入失败时的处理：磁盘写满、网络中断等导致写入失败时，
+// 向 Fallback 输出一条内部错误日志并转写原日志，避免日志被静默丢弃
+type FailoverConfig struct {
+	Fallback  io.Writer     // 备用输出，nil 表示 stderr
+	Threshold int           // 同一输出连续失败达到该次数时暂停写入 Cooldown，期间日志直接写入 Fallback；<=0 表示不暂停
+	Cooldown  time.Duration // 暂停时长，默认 30s
 }
 
 // FileConfig 文件输出配置
@@ -382,6 +399,48 @@ func Panicln(args ...interface{}) {
 	}
 }
 
+// DebugContext 使用默认日志记录器记录 DEBUG 级别日志，并附带上下文中的字段
+func DebugContext(ctx context.Context, msg string, fields ...interface{}) {
+	if logger := Get(); logger != nil {
+		logger.DebugContext(ctx, msg, fields...)
+	}
+}
+
+// InfoContext 使用默认日志记录器记录 INFO 级别日志，并附带上下文中的字段
+func InfoContext(ctx context.Context, msg string, fields ...interface{}) {
+	if logger := Get(); logger != nil {
+		logger.InfoContext(ctx, msg, fields...)
+	}
+}
+
+// WarnContext 使用默认日志记录器记录 WARN 级别日志，并附带上下文中的字段
+func WarnContext(ctx context.Context, msg string, fields ...interface{}) {
+	if logger := Get(); logger != nil {
+		logger.WarnContext(ctx, msg, fields...)
+	}
+}
+
+// ErrorContext 使用默认日志记录器记录 ERROR 级别日志，并附带上下文中的字段
+func ErrorContext(ctx context.Context, msg string, fields ...interface{}) {
+	if logger := Get(); logger != nil {
+		logger.ErrorContext(ctx, msg, fields...)
+	}
+}
+
+// FatalContext 使用默认日志记录器记录 FATAL 级别日志，并附带上下文中的字段
+func FatalContext(ctx context.Context, msg string, fields ...interface{}) {
+	if logger := Get(); logger != nil {
+		logger.FatalContext(ctx, msg, fields...)
+	}
+}
+
+// PanicContext 使用默认日志记录器记录 PANIC 级别日志，并附带上下文中的字段
+func PanicContext(ctx context.Context, msg string, fields ...interface{}) {
+	if logger := Get(); logger != nil {
+		logger.PanicContext(ctx, msg, fields...)
+	}
+}
+
 // Close 关闭所有日志记录器
 func Close() error {
 	globalMu.Lock()
@@ -0,0 +1,288 @@
+package logquery
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jiajia556/tool-box/log"
+)
+
+var ErrInvalidMatcher = errors.New("logquery: invalid matcher")
+
+// 解析 timestamp 时依次尝试的格式，Query.TimeFormat 不为空时优先使用
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04:05.000",
+	"2006-01-02T15:04:05",
+}
+
+// Entry 一条日志。
+// logquery 读取 log 包以 json 编码输出的日志文件（每行一个 JSON 对象），按级别、时间范围、字段条件过滤，
+// 用于没有集中式日志的环境中按字段检索日志。
+type Entry struct {
+	Time    time.Time
+	Level   log.Level
+	Message string
+	Caller  string
+	Fields  map[string]any
+	// Raw 原始行内容
+	Raw string
+}
+
+// Matcher 字段条件
+type Matcher struct {
+	Key string
+	// 操作符：= 等于、!= 不等于、~ 包含、!~ 不包含、? 存在
+	Op    string
+	Value string
+}
+
+// ParseMatcher 解析 "key=value"、"key!=value"、"key~value"、"key!~value"、"key?" 形式的条件
+func ParseMatcher(s string) (Matcher, error) {
+	if key, ok := strings.CutSuffix(s, "?"); ok && key != "" {
+		return Matcher{Key: key, Op: "?"}, nil
+	}
+	for _, op := range []string{"!=", "!~", "=", "~"} {
+		if key, value, ok := strings.Cut(s, op); ok && key != "" {
+			return Matcher{Key: key, Op: op, Value: value}, nil
+		}
+	}
+	return Matcher{}, fmt.Errorf("%w: %q", ErrInvalidMatcher, s)
+}
+
+// Match 判断 entry 是否满足条件；key 为 level / message / caller 时匹配对应的内置字段
+func (m Matcher) Match(e *Entry) bool {
+	var actual string
+	var exists bool
+	switch m.Key {
+	case "level":
+		actual, exists = e.Level.String(), true
+	case "message", "msg":
+		actual, exists = e.Message, true
+	case "caller":
+		actual, exists = e.Caller, e.Caller != ""
+	default:
+		var v any
+		v, exists = e.Fields[m.Key]
+		if exists {
+			actual = fieldString(v)
+		}
+	}
+
+	switch m.Op {
+	case "?":
+		return exists
+	case "=":
+		return exists && actual == m.Value
+	case "!=":
+		return !exists || actual != m.Value
+	case "~":
+		return exists && strings.Contains(actual, m.Value)
+	case "!~":
+		return !exists || !strings.Contains(actual, m.Value)
+	}
+	return false
+}
+
+// Query 查询条件，零值字段表示不限制
+type Query struct {
+	// 最低级别
+	MinLevel log.Level
+	// 起止时间（含 Since，不含 Until）
+	Since time.Time
+	Until time.Time
+	// 所有条件都满足才匹配
+	Matchers []Matcher
+	// 最多返回条数
+	Limit int
+	// 日志的 TimeFormat，为空时自动识别常见格式
+	TimeFormat string
+}
+
+// Match 判断 entry 是否满足查询条件
+func (q *Query) Match(e *Entry) bool {
+	if e.Level < q.MinLevel {
+		return false
+	}
+	if !q.Since.IsZero() && e.Time.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !e.Time.Before(q.Until) {
+		return false
+	}
+	for _, m := range q.Matchers {
+		if !m.Match(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// Read 逐行读取 r，对满足条件的日志调用 fn，fn 返回 false 时停止；非 JSON 行会被跳过
+func Read(r io.Reader, q Query, fn func(e Entry) bool) error {
+	_, err := read(r, q, 0, fn)
+	return err
+}
+
+func read(r io.Reader, q Query, matched int, fn func(e Entry) bool) (int, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || line[0] != '{' {
+			continue
+		}
+		e, err := Parse(line, q.TimeFormat)
+		if err != nil || !q.Match(&e) {
+			continue
+		}
+
+		matched++
+		if !fn(e) || (q.Limit > 0 && matched >= q.Limit) {
+			return matched, errStop
+		}
+	}
+	return matched, sc.Err()
+}
+
+var errStop = errors.New("logquery: stop")
+
+// ReadFiles 按顺序读取多个文件
+func ReadFiles(paths []string, q Query, fn func(e Entry) bool) error {
+	matched := 0
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("logquery: %w", err)
+		}
+		matched, err = read(f, q, matched, fn)
+		_ = f.Close()
+		if err == errStop {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("logquery: read %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// ReadDir 读取日志目录下按日期命名的文件（YYYY-MM-DD.log），按日期顺序，
+// 并根据 Since / Until 跳过范围之外的日期
+func ReadDir(dir string, q Query, fn func(e Entry) bool) error {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.log"))
+	if err != nil {
+		return fmt.Errorf("logquery: %w", err)
+	}
+
+	var paths []string
+	for _, path := range matches {
+		day, err := time.ParseInLocation("2006-01-02", strings.TrimSuffix(filepath.Base(path), ".log"), time.Local)
+		if err != nil {
+			continue
+		}
+		if !q.Since.IsZero() && day.AddDate(0, 0, 1).Before(q.Since) {
+			continue
+		}
+		if !q.Until.IsZero() && !day.Before(q.Until) {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return ReadFiles(paths, q, fn)
+}
+
+// Search 读取日志目录，将满足条件的原始行写入 w
+func Search(dir string, q Query, w io.Writer) error {
+	var werr error
+	err := ReadDir(dir, q, func(e Entry) bool {
+		_, werr = io.WriteString(w, e.Raw+"\n")
+		return werr == nil
+	})
+	if err != nil {
+		return err
+	}
+	return werr
+}
+
+// Parse 解析一行 JSON 日志
+func Parse(line, timeFormat string) (Entry, error) {
+	var raw map[string]any
+	dec := json.NewDecoder(strings.NewReader(line))
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return Entry{}, err
+	}
+
+	e := Entry{Raw: line, Level: log.LevelInfo, Fields: make(map[string]any, len(raw))}
+	for k, v := range raw {
+		switch k {
+		case "timestamp":
+			s, _ := v.(string)
+			e.Time = parseTime(s, timeFormat)
+		case "level":
+			s, _ := v.(string)
+			e.Level = parseLevel(s)
+		case "message":
+			e.Message, _ = v.(string)
+		case "caller":
+			e.Caller, _ = v.(string)
+		default:
+			e.Fields[k] = v
+		}
+	}
+	return e, nil
+}
+
+func parseTime(s, layout string) time.Time {
+	if layout != "" {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t
+		}
+	}
+	for _, l := range timeLayouts {
+		if t, err := time.ParseInLocation(l, s, time.Local); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func parseLevel(s string) log.Level {
+	for l := log.LevelDebug; l <= log.LevelPanic; l++ {
+		if strings.EqualFold(l.String(), s) {
+			return l
+		}
+	}
+	return log.LevelInfo
+}
+
+func fieldString(v any) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case json.Number:
+		return x.String()
+	case nil:
+		return "null"
+	case bool:
+		if x {
+			return "true"
+		}
+		return "false"
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
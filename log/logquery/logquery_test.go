@@ -0,0 +1,52 @@
+package logquery
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jiajia556/tool-box/log"
+)
+
+func TestSearch(t *testing.T) {
+	dir := t.TempDir()
+	content := strings.Join([]string{
+		`{"timestamp":"2024-05-06 10:00:00","level":"INFO","message":"login","user_id":42}`,
+		`not a json line`,
+		`{"timestamp":"2024-05-06 10:05:00","level":"ERROR","message":"pay failed","user_id":42,"error":"timeout"}`,
+		`{"timestamp":"2024-05-06 11:00:00","level":"ERROR","message":"pay failed","user_id":7}`,
+	}, "\n")
+	if err := os.WriteFile(filepath.Join(dir, "2024-05-06.log"), []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	m, err := ParseMatcher("user_id=42")
+	if err != nil {
+		t.Fatalf("ParseMatcher: %v", err)
+	}
+	q := Query{
+		MinLevel: log.LevelWarn,
+		Since:    time.Date(2024, 5, 6, 0, 0, 0, 0, time.Local),
+		Matchers: []Matcher{m, {Key: "error", Op: "?"}},
+	}
+
+	var out strings.Builder
+	if err := Search(dir, q, &out); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 1 || !strings.Contains(lines[0], `"timeout"`) {
+		t.Fatalf("unexpected result %q", out.String())
+	}
+
+	q = Query{Until: time.Date(2024, 5, 6, 10, 30, 0, 0, time.Local)}
+	count := 0
+	if err := ReadDir(dir, q, func(e Entry) bool { count++; return true }); err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("expected 2 entries before 10:30, got %d", count)
+	}
+}
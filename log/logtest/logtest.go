@@ -0,0 +1,453 @@
+package logtest
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jiajia556/tool-box/log"
+)
+
+// Factory 按给定配置创建待测日志记录器。
+// 配置的 Output 为 "writer"、Encoder 为 "json"，适配器须将每条日志以一行 JSON 写入 config.Writer
+type Factory func(t *testing.T, config log.Config) log.Logger
+
+// Config 一致性测试配置
+type Config struct {
+	// JSON 输出中级别字段的 key，默认 "level"；级别值按 Level.String() 不区分大小写比较
+	LevelKey string
+
+	// JSON 输出中消息字段的 key，默认 "message"
+	MessageKey string
+
+	// 业务字段所在的 key，为空表示与级别、消息同级输出，默认为空
+	FieldsKey string
+
+	// 并发用例的 goroutine 数，默认 8
+	Concurrency int
+
+	// 并发用例中每个 goroutine 写入的条数，默认 100
+	Iterations int
+}
+
+// Option 选项函数
+type Option func(*Config)
+
+// WithKeys 设置级别与消息字段的 key
+func WithKeys(levelKey, messageKey string) Option {
+	return func(c *Config) {
+		c.LevelKey = levelKey
+		c.MessageKey = messageKey
+	}
+}
+
+// WithFieldsKey 设置业务字段所在的 key
+func WithFieldsKey(key string) Option {
+	return func(c *Config) {
+		c.FieldsKey = key
+	}
+}
+
+// WithConcurrency 设置并发用例的 goroutine 数与每个 goroutine 的写入条数
+func WithConcurrency(goroutines, iterations int) Option {
+	return func(c *Config) {
+		c.Concurrency = goroutines
+		c.Iterations = iterations
+	}
+}
+
+// DefaultConfig 默认配置，与 std 适配器的 JSON 输出一致
+func DefaultConfig() Config {
+	return Config{
+		LevelKey:    "level",
+		MessageKey:  "message",
+		Concurrency: 8,
+		Iterations:  100,
+	}
+}
+
+// RunConformance 对 factory 创建的日志记录器运行适配器一致性测试，第三方适配器可在自己的测试中调用：
+//
+//	func TestConformance(t *testing.T) {
+//		logtest.RunConformance(t, func(t *testing.T, config log.Config) log.Logger {
+//			l := myadapter.New()
+//			if err := l.SetConfig(config); err != nil {
+//				t.Fatal(err)
+//			}
+//			return l
+//		})
+//	}
+//
+// 覆盖级别过滤、字段绑定的隔离、上下文字段、并发写入以及 Close 语义；建议配合 -race 运行
+func RunConformance(t *testing.T, factory Factory, opts ...Option) {
+	config := DefaultConfig()
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	s := &suite{factory: factory, config: config}
+	t.Run("Levels", s.levels)
+	t.Run("SetLevel", s.setLevel)
+	t.Run("Fields", s.fields)
+	t.Run("Formatted", s.formatted)
+	t.Run("FieldIsolation", s.fieldIsolation)
+	t.Run("Context", s.context)
+	t.Run("Concurrent", s.concurrent)
+	t.Run("Panic", s.panic)
+	t.Run("Close", s.close)
+}
+
+// record 解析后的一条日志
+type record struct {
+	Level   string
+	Message string
+	Fields  map[string]any
+}
+
+type suite struct {
+	factory Factory
+	config  Config
+}
+
+// buffer 并发安全的输出缓冲，不假设适配器在写入时持有锁
+type buffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *buffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *buffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]byte(nil), b.buf.Bytes()...)
+}
+
+// newLogger 创建输出到内存缓冲的日志记录器，测试结束时关闭
+func (s *suite) newLogger(t *testing.T, level log.Level) (log.Logger, *buffer) {
+	t.Helper()
+	out := &buffer{}
+	config := log.DefaultConfig()
+	config.Level = level
+	config.Output = "writer"
+	config.Writer = out
+	config.Format = "json"
+	config.Encoder = "json"
+	config.Caller = false
+
+	l := s.factory(t, config)
+	if l == nil {
+		t.Fatal("factory returned nil logger")
+	}
+	t.Cleanup(func() { _ = l.Close() })
+	return l, out
+}
+
+// records 解析输出中的每一行 JSON，非法行直接判定失败
+func (s *suite) records(t *testing.T, out *buffer) []record {
+	t.Helper()
+	var records []record
+	sc := bufio.NewScanner(bytes.NewReader(out.Bytes()))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var m map[string]any
+		if err := json.Unmarshal(line, &m); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", line, err)
+		}
+		r := record{Fields: m}
+		r.Level, _ = m[s.config.LevelKey].(string)
+		r.Message, _ = m[s.config.MessageKey].(string)
+		if s.config.FieldsKey != "" {
+			r.Fields, _ = m[s.config.FieldsKey].(map[string]any)
+		}
+		records = append(records, r)
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	return records
+}
+
+// one 返回唯一的一条日志
+func (s *suite) one(t *testing.T, out *buffer) record {
+	t.Helper()
+	records := s.records(t, out)
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1: %q", len(records), out.Bytes())
+	}
+	return records[0]
+}
+
+func checkField(t *testing.T, r record, key string, want any) {
+	t.Helper()
+	got, ok := r.Fields[key]
+	if !ok {
+		t.Fatalf("record %q missing field %q: %v", r.Message, key, r.Fields)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("record %q field %q = %v, want %v", r.Message, key, got, want)
+	}
+}
+
+func checkNoField(t *testing.T, r record, key string) {
+	t.Helper()
+	if v, ok := r.Fields[key]; ok {
+		t.Fatalf("record %q has unexpected field %q = %v", r.Message, key, v)
+	}
+}
+
+func messages(records []record) []string {
+	msgs := make([]string, len(records))
+	for i, r := range records {
+		msgs[i] = r.Message
+	}
+	return msgs
+}
+
+// levels 低于配置级别的日志被过滤，各级别输出的级别字段正确
+func (s *suite) levels(t *testing.T) {
+	l, out := s.newLogger(t, log.LevelWarn)
+	ctx := context.Background()
+
+	l.Debug("debug")
+	l.Info("info")
+	l.Infof("%s", "infof")
+	l.Infoln("infoln")
+	l.InfoContext(ctx, "info-ctx")
+	l.Warn("warn")
+	l.Error("error")
+	l.ErrorContext(ctx, "error-ctx")
+
+	records := s.records(t, out)
+	want := []string{"warn", "error", "error-ctx"}
+	if got := messages(records); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("messages = %q, want %q", got, want)
+	}
+	for i, level := range []log.Level{log.LevelWarn, log.LevelError, log.LevelError} {
+		if !strings.EqualFold(records[i].Level, level.String()) {
+			t.Fatalf("record %q level = %q, want %s", records[i].Message, records[i].Level, level)
+		}
+	}
+}
+
+// setLevel 运行时调整级别立即生效
+func (s *suite) setLevel(t *testing.T) {
+	l, out := s.newLogger(t, log.LevelError)
+
+	l.Info("before")
+	l.SetLevel(log.LevelInfo)
+	l.Info("after")
+	l.SetLevel(log.LevelError)
+	l.Warn("raised")
+
+	if got := messages(s.records(t, out)); len(got) != 1 || got[0] != "after" {
+		t.Fatalf("messages = %q, want [after]", got)
+	}
+}
+
+// fields 键值对按字段输出
+func (s *suite) fields(t *testing.T) {
+	l, out := s.newLogger(t, log.LevelInfo)
+
+	l.Info("fields", "user", "alice", "count", 3, "ok", true)
+
+	r := s.one(t, out)
+	checkField(t, r, "user", "alice")
+	checkField(t, r, "count", 3)
+	checkField(t, r, "ok", true)
+}
+
+// formatted f / ln 风格的消息格式化
+func (s *suite) formatted(t *testing.T) {
+	l, out := s.newLogger(t, log.LevelInfo)
+
+	l.Infof("n=%d s=%s", 7, "x")
+	l.Warnln("a", "b", 1)
+
+	got := messages(s.records(t, out))
+	want := []string{"n=7 s=x", "a b 1"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("messages = %q, want %q", got, want)
+	}
+}
+
+// fieldIsolation With / WithFields 返回新的 logger，不影响父 logger 与兄弟 logger，
+// 也不受调用方之后修改传入 map 的影响
+func (s *suite) fieldIsolation(t *testing.T) {
+	l, out := s.newLogger(t, log.LevelInfo)
+
+	extra := map[string]interface{}{"b": 2}
+	parent := l.With("a", 1)
+	child := parent.WithFields(extra)
+	sibling := parent.With("c", 3)
+	extra["b"] = "mutated"
+	extra["d"] = 4
+
+	l.Info("root")
+	parent.Info("parent")
+	child.Info("child")
+	sibling.Info("sibling")
+
+	records := s.records(t, out)
+	if len(records) != 4 {
+		t.Fatalf("got %d records, want 4", len(records))
+	}
+	root, p, c, sib := records[0], records[1], records[2], records[3]
+
+	checkNoField(t, root, "a")
+	checkNoField(t, root, "b")
+
+	checkField(t, p, "a", 1)
+	checkNoField(t, p, "b")
+	checkNoField(t, p, "c")
+
+	checkField(t, c, "a", 1)
+	checkField(t, c, "b", 2)
+	checkNoField(t, c, "c")
+	checkNoField(t, c, "d")
+
+	checkField(t, sib, "a", 1)
+	checkField(t, sib, "c", 3)
+	checkNoField(t, sib, "b")
+
+	// 子 logger 调整级别不影响父 logger
+	child.SetLevel(log.LevelError)
+	parent.Info("parent-after")
+	if got := messages(s.records(t, out)); got[len(got)-1] != "parent-after" {
+		t.Fatalf("parent affected by child SetLevel: messages = %q", got)
+	}
+}
+
+// context XxxContext 附带上下文中的 MDC 字段，已取消的上下文仍然输出
+func (s *suite) context(t *testing.T) {
+	l, out := s.newLogger(t, log.LevelInfo)
+
+	ctx := log.PushFields(context.Background(), "request_id", "r-1")
+	l.With("a", 1).InfoContext(ctx, "with-ctx", "k", "v")
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	l.WarnContext(canceled, "canceled")
+
+	l.InfoContext(context.Background(), "plain")
+
+	records := s.records(t, out)
+	if got := messages(records); strings.Join(got, ",") != "with-ctx,canceled,plain" {
+		t.Fatalf("messages = %q, want [with-ctx canceled plain]", got)
+	}
+	checkField(t, records[0], "request_id", "r-1")
+	checkField(t, records[0], "a", 1)
+	checkField(t, records[0], "k", "v")
+	checkField(t, records[1], "request_id", "r-1")
+	checkNoField(t, records[2], "request_id")
+}
+
+// concurrent 多个 goroutine 同时写入、派生子 logger 并调整级别，每条日志完整输出且互不交错
+func (s *suite) concurrent(t *testing.T) {
+	l, out := s.newLogger(t, log.LevelInfo)
+	ctx := log.PushFields(context.Background(), "request_id", "r-1")
+
+	var wg sync.WaitGroup
+	for i := 0; i < s.config.Concurrency; i++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			child := l.With("g", g)
+			for j := 0; j < s.config.Iterations; j++ {
+				switch j % 3 {
+				case 0:
+					child.Info("concurrent", "j", j)
+				case 1:
+					child.InfoContext(ctx, "concurrent", "j", j)
+				default:
+					l.WithFields(map[string]interface{}{"g": g, "j": j}).Warn("concurrent")
+				}
+			}
+		}(i)
+	}
+	// 写入的同时调整根 logger 的级别，级别不低于 Info 时不影响上面的输出
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < s.config.Iterations; j++ {
+			l.SetLevel(log.LevelInfo)
+			_ = l.GetConfig()
+		}
+	}()
+	wg.Wait()
+
+	records := s.records(t, out)
+	if want := s.config.Concurrency * s.config.Iterations; len(records) != want {
+		t.Fatalf("got %d records, want %d", len(records), want)
+	}
+	seen := make(map[string]bool, len(records))
+	for _, r := range records {
+		if r.Message != "concurrent" {
+			t.Fatalf("unexpected message %q", r.Message)
+		}
+		id := fmt.Sprint(r.Fields["g"], "/", r.Fields["j"])
+		if seen[id] {
+			t.Fatalf("duplicate record %s", id)
+		}
+		seen[id] = true
+	}
+}
+
+// panic Panic 先输出日志再以消息 panic
+func (s *suite) panic(t *testing.T) {
+	l, out := s.newLogger(t, log.LevelInfo)
+
+	func() {
+		defer func() {
+			r := recover()
+			if r == nil {
+				t.Fatal("Panic did not panic")
+			}
+			if !strings.Contains(fmt.Sprint(r), "boom") {
+				t.Fatalf("panic value = %v, want containing %q", r, "boom")
+			}
+		}()
+		l.Panic("boom", "k", "v")
+	}()
+
+	r := s.one(t, out)
+	if r.Message != "boom" || !strings.EqualFold(r.Level, log.LevelPanic.String()) {
+		t.Fatalf("record = %q %q, want PANIC boom", r.Level, r.Message)
+	}
+	checkField(t, r, "k", "v")
+}
+
+// close Close 可重复调用，关闭后写日志不 panic
+func (s *suite) close(t *testing.T) {
+	l, out := s.newLogger(t, log.LevelInfo)
+
+	l.Info("before")
+	child := l.With("a", 1)
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+
+	l.Info("after")
+	child.Info("child-after")
+	l.InfoContext(context.Background(), "after-ctx")
+
+	if got := messages(s.records(t, out)); len(got) == 0 || got[0] != "before" {
+		t.Fatalf("messages = %q, want first message %q", got, "before")
+	}
+}
@@ -0,0 +1,18 @@
+package logtest
+
+import (
+	"testing"
+
+	"github.com/jiajia556/tool-box/log"
+	"github.com/jiajia556/tool-box/log/std"
+)
+
+func TestStd(t *testing.T) {
+	RunConformance(t, func(t *testing.T, config log.Config) log.Logger {
+		l := std.NewStdLogger()
+		if err := l.SetConfig(config); err != nil {
+			t.Fatalf("SetConfig: %v", err)
+		}
+		return l
+	})
+}
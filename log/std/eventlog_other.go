@@ -0,0 +1,13 @@
+//go:build !windows
+
+package std
+
+import (
+	"errors"
+
+	"github.com/jiajia556/tool-box/log"
+)
+
+func newEventlogSink(identifier string) (log.WriterAdapter, error) {
+	return nil, errors.New("log: eventlog output is only supported on windows")
+}
@@ -0,0 +1,64 @@
+//go:build windows
+
+package std
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/sys/windows/svc/eventlog"
+
+	"github.com/jiajia556/tool-box/log"
+)
+
+// 事件 ID，统一使用 1
+const eventID = 1
+
+// eventlogSink 写入 Windows 事件日志，结构化字段以 key=value 形式追加到消息后
+type eventlogSink struct {
+	log *eventlog.Log
+}
+
+func newEventlogSink(identifier string) (log.WriterAdapter, error) {
+	// 来源未注册时尝试注册（需要管理员权限），已存在或无权限时忽略
+	_ = eventlog.InstallAsEventCreate(identifier, eventlog.Error|eventlog.Warning|eventlog.Info)
+
+	l, err := eventlog.Open(identifier)
+	if err != nil {
+		return nil, fmt.Errorf("log: open eventlog source %q: %w", identifier, err)
+	}
+	return &eventlogSink{log: l}, nil
+}
+
+func (s *eventlogSink) Write(entry *log.Entry) error {
+	var b strings.Builder
+	b.WriteString(entry.Message)
+	for _, f := range sinkFields(entry) {
+		fmt.Fprintf(&b, "\r\n%s=%v", f.Key, f.Value)
+	}
+	if entry.Caller != nil {
+		fmt.Fprintf(&b, "\r\ncaller=%s:%d", entry.Caller.File, entry.Caller.Line)
+	}
+	if entry.Stack != "" {
+		b.WriteString("\r\n")
+		b.WriteString(entry.Stack)
+	}
+
+	msg := b.String()
+	switch entry.Level {
+	case log.LevelDebug, log.LevelInfo:
+		return s.log.Info(eventID, msg)
+	case log.LevelWarn:
+		return s.log.Warning(eventID, msg)
+	default:
+		return s.log.Error(eventID, msg)
+	}
+}
+
+func (s *eventlogSink) Sync() error {
+	return nil
+}
+
+func (s *eventlogSink) Close() error {
+	return s.log.Close()
+}
@@ -0,0 +1,129 @@
+package std
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jiajia556/tool-box/log"
+)
+
+// outputState 单个输出的连续失败次数与暂停截止时间
+type outputState struct {
+	failures      int
+	disabledUntil time.Time
+}
+
+// failover 输出失败的自我监控，WithFields 派生的 logger 共享同一份状态与输出；
+// 各 logger 的 mu 互不相同，写入输出与访问状态需持有 failover.mu
+type failover struct {
+	mu      sync.Mutex
+	writers map[int]*outputState
+	sinks   map[int]*outputState
+	failed  atomic.Uint64
+}
+
+func newFailover() *failover {
+	return &failover{
+		writers: make(map[int]*outputState),
+		sinks:   make(map[int]*outputState),
+	}
+}
+
+func (fo *failover) state(states map[int]*outputState, i int) *outputState {
+	st, ok := states[i]
+	if !ok {
+		st = &outputState{}
+		states[i] = st
+	}
+	return st
+}
+
+// FailedWrites 返回输出写入失败的累计次数
+func (sl *StdLogger) FailedWrites() uint64 {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	return sl.failoverState().failed.Load()
+}
+
+func (sl *StdLogger) failoverState() *failover {
+	if sl.failover == nil {
+		sl.failover = newFailover()
+	}
+	return sl.failover
+}
+
+func (sl *StdLogger) fallbackWriter() io.Writer {
+	if sl.config.Failover.Fallback != nil {
+		return sl.config.Failover.Fallback
+	}
+	return os.Stderr
+}
+
+// paused 输出是否处于暂停期，暂停期内将日志直接转写到备用输出
+func (sl *StdLogger) paused(st *outputState, target any, output string) bool {
+	if st.disabledUntil.IsZero() || !sl.now().Before(st.disabledUntil) {
+		return false
+	}
+	sl.writeFallback(target, output)
+	return true
+}
+
+// succeeded 写入成功，重置连续失败次数
+func (sl *StdLogger) succeeded(st *outputState) {
+	st.failures = 0
+	st.disabledUntil = time.Time{}
+}
+
+// failed 记录一次写入失败：向备用输出写入内部错误日志与原日志，连续失败达到阈值时暂停该输出
+func (sl *StdLogger) failed(st *outputState, target any, output string, err error) {
+	sl.failoverState().failed.Add(1)
+	st.failures++
+
+	cfg := sl.config.Failover
+	msg := fmt.Sprintf("%s [ERROR] log: output write failed output=%s consecutive=%d error=%q",
+		sl.now().Format(time.RFC3339), outputName(target), st.failures, err.Error())
+	if cfg.Threshold > 0 && st.failures >= cfg.Threshold {
+		cooldown := cfg.Cooldown
+		if cooldown <= 0 {
+			cooldown = 30 * time.Second
+		}
+		st.disabledUntil = sl.now().Add(cooldown)
+		msg += fmt.Sprintf(" disabled_for=%s", cooldown)
+	}
+
+	fallback := sl.fallbackWriter()
+	if sameOutput(fallback, target) {
+		// 备用输出本身写入失败，无处可写
+		return
+	}
+	_, _ = fmt.Fprintln(fallback, msg)
+	_, _ = fmt.Fprint(fallback, output)
+}
+
+func (sl *StdLogger) writeFallback(target any, output string) {
+	if fallback := sl.fallbackWriter(); !sameOutput(fallback, target) {
+		_, _ = fmt.Fprint(fallback, output)
+	}
+}
+
+func sameOutput(a, b any) bool {
+	ta := reflect.TypeOf(a)
+	return ta == reflect.TypeOf(b) && ta.Comparable() && a == b
+}
+
+func outputName(target any) string {
+	switch t := target.(type) {
+	case *dailyFileWriter:
+		return "file:" + t.dir
+	case *os.File:
+		return t.Name()
+	case log.WriterAdapter:
+		return fmt.Sprintf("sink:%T", t)
+	}
+	return fmt.Sprintf("%T", target)
+}
@@ -0,0 +1,179 @@
+//go:build linux
+
+package std
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/jiajia556/tool-box/log"
+)
+
+const journalSocket = "/run/systemd/journal/socket"
+
+// journaldSink 通过 native 协议写入 systemd-journald，结构化字段作为 journal 变量保存
+type journaldSink struct {
+	conn       *net.UnixConn
+	addr       *net.UnixAddr
+	identifier string
+}
+
+func newJournaldSink(identifier string) (log.WriterAdapter, error) {
+	addr := &net.UnixAddr{Name: journalSocket, Net: "unixgram"}
+	if _, err := os.Stat(journalSocket); err != nil {
+		return nil, fmt.Errorf("log: journald not available: %w", err)
+	}
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+	if err != nil {
+		return nil, fmt.Errorf("log: open journald socket: %w", err)
+	}
+	return &journaldSink{conn: conn, addr: addr, identifier: identifier}, nil
+}
+
+// journalPriority 日志级别映射为 syslog 优先级
+func journalPriority(level log.Level) int {
+	switch level {
+	case log.LevelDebug:
+		return 7
+	case log.LevelInfo:
+		return 6
+	case log.LevelWarn:
+		return 4
+	case log.LevelError:
+		return 3
+	case log.LevelFatal:
+		return 2
+	case log.LevelPanic:
+		return 0
+	default:
+		return 6
+	}
+}
+
+func (s *journaldSink) Write(entry *log.Entry) error {
+	var buf bytes.Buffer
+	writeJournalVar(&buf, "MESSAGE", entry.Message)
+	writeJournalVar(&buf, "PRIORITY", strconv.Itoa(journalPriority(entry.Level)))
+	writeJournalVar(&buf, "SYSLOG_IDENTIFIER", s.identifier)
+	if entry.Caller != nil {
+		writeJournalVar(&buf, "CODE_FILE", entry.Caller.File)
+		writeJournalVar(&buf, "CODE_LINE", strconv.Itoa(entry.Caller.Line))
+		writeJournalVar(&buf, "CODE_FUNC", entry.Caller.Function)
+	}
+	if entry.Stack != "" {
+		writeJournalVar(&buf, "STACK", entry.Stack)
+	}
+	for _, f := range sinkFields(entry) {
+		name := journalVarName(f.Key)
+		if name == "" {
+			continue
+		}
+		writeJournalVar(&buf, name, journalValue(f.Value))
+	}
+
+	_, _, err := s.conn.WriteMsgUnix(buf.Bytes(), nil, s.addr)
+	if err == nil {
+		return nil
+	}
+	if !isMsgTooLarge(err) {
+		return err
+	}
+	return s.writeLarge(buf.Bytes())
+}
+
+// writeLarge 数据报过大时写入已删除的临时文件，通过 SCM_RIGHTS 传递文件描述符
+func (s *journaldSink) writeLarge(data []byte) error {
+	f, err := os.CreateTemp("/dev/shm", "journal-")
+	if err != nil {
+		f, err = os.CreateTemp("", "journal-")
+		if err != nil {
+			return err
+		}
+	}
+	defer f.Close()
+	_ = os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+	rights := syscall.UnixRights(int(f.Fd()))
+	_, _, err = s.conn.WriteMsgUnix(nil, rights, s.addr)
+	return err
+}
+
+func (s *journaldSink) Sync() error {
+	return nil
+}
+
+func (s *journaldSink) Close() error {
+	return s.conn.Close()
+}
+
+// writeJournalVar 写入一个变量；值包含换行时使用带长度前缀的二进制格式
+func writeJournalVar(buf *bytes.Buffer, name, value string) {
+	buf.WriteString(name)
+	if !strings.Contains(value, "\n") {
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+		return
+	}
+	buf.WriteByte('\n')
+	_ = binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+// journalVarName 转换为合法的 journal 变量名：大写字母、数字与下划线，不能以下划线或数字开头
+func journalVarName(key string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(key) {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	name := strings.TrimLeft(b.String(), "_0123456789")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+func journalValue(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case error:
+		return val.Error()
+	case fmt.Stringer:
+		return val.String()
+	case []byte:
+		return string(val)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, bool:
+		return fmt.Sprint(val)
+	}
+	if data, err := json.Marshal(v); err == nil {
+		return string(data)
+	}
+	return fmt.Sprint(v)
+}
+
+func isMsgTooLarge(err error) bool {
+	var errno syscall.Errno
+	if opErr, ok := err.(*net.OpError); ok {
+		if sysErr, ok := opErr.Err.(*os.SyscallError); ok {
+			errno, _ = sysErr.Err.(syscall.Errno)
+		}
+	}
+	return errno == syscall.EMSGSIZE || errno == syscall.ENOBUFS
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package std
+
+import (
+	"errors"
+
+	"github.com/jiajia556/tool-box/log"
+)
+
+func newJournaldSink(identifier string) (log.WriterAdapter, error) {
+	return nil, errors.New("log: journald output is only supported on linux")
+}
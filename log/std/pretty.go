@@ -0,0 +1,168 @@
+package std
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jiajia556/tool-box/log"
+)
+
+const (
+	// 字段数不超过该值且都较短时在消息后单行输出，否则按对齐的键值块输出
+	prettyInlineFields = 4
+	// 单行输出时单个字段值的最大长度
+	prettyInlineValue = 40
+	// 多行内容的缩进
+	prettyIndent = "    "
+)
+
+// prettyHighlight 需要高亮的字段及颜色
+var prettyHighlight = map[string]string{
+	"error":      colorRed + colorBold,
+	"err":        colorRed + colorBold,
+	"trace_id":   colorPurple,
+	"span_id":    colorPurple,
+	"request_id": colorPurple,
+}
+
+// prettyField 待输出的字段，extra 字段 key 为空
+type prettyField struct {
+	key   string
+	value string
+}
+
+// formatPretty 本地开发用的彩色输出：
+// 多行消息与堆栈缩进显示，字段较多或较长时输出为对齐的键值块，error / trace_id 等字段高亮。
+func (sl *StdLogger) formatPretty(entry *log.Entry) string {
+	timeStr := entry.Time.Format(sl.config.TimeFormat)
+	if timeStr == "" {
+		timeStr = entry.Time.Format("2006-01-02 15:04:05")
+	}
+
+	var b strings.Builder
+	if entry.Caller != nil {
+		// 将调用位置放到最前边
+		fmt.Fprintf(&b, "%s(%s:%d)%s ", colorGray, entry.Caller.File, entry.Caller.Line, colorReset)
+	}
+	levelColor := sl.getLevelColor(entry.Level)
+	fmt.Fprintf(&b, "%s [%s%s%s] ", timeStr, levelColor, entry.Level.String(), colorReset)
+
+	message, rest, multiline := strings.Cut(strings.TrimRight(entry.Message, "\n"), "\n")
+	b.WriteString(message)
+
+	fields := prettyFields(entry)
+	inline := !multiline && len(fields) <= prettyInlineFields
+	for _, f := range fields {
+		if len(f.value) > prettyInlineValue || strings.Contains(f.value, "\n") {
+			inline = false
+			break
+		}
+	}
+
+	if inline {
+		for _, f := range fields {
+			b.WriteByte(' ')
+			writePrettyField(&b, f, 0)
+		}
+	}
+	b.WriteByte('\n')
+
+	if multiline {
+		writeIndented(&b, rest, prettyIndent, "")
+	}
+
+	if !inline {
+		width := 0
+		for _, f := range fields {
+			if len(f.key) > width {
+				width = len(f.key)
+			}
+		}
+		for _, f := range fields {
+			b.WriteString(prettyIndent)
+			writePrettyField(&b, f, width)
+			b.WriteByte('\n')
+		}
+	}
+
+	if entry.Stack != "" {
+		writeIndented(&b, strings.TrimRight(entry.Stack, "\n"), prettyIndent, colorGray)
+	}
+
+	return b.String()
+}
+
+// writePrettyField 输出 key=value；width > 0 时按块格式对齐，值中的换行缩进到值所在列
+func writePrettyField(b *strings.Builder, f prettyField, width int) {
+	color, highlight := prettyHighlight[f.key]
+	if !highlight {
+		color = ""
+	}
+
+	if width == 0 {
+		if f.key != "" {
+			fmt.Fprintf(b, "%s%s=%s", colorCyan, f.key, colorReset)
+		}
+		b.WriteString(colorize(f.value, color))
+		return
+	}
+
+	fmt.Fprintf(b, "%s%-*s%s │ ", colorCyan, width, f.key, colorReset)
+	pad := strings.Repeat(" ", width+3)
+	lines := strings.Split(f.value, "\n")
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteByte('\n')
+			b.WriteString(prettyIndent + pad)
+		}
+		b.WriteString(colorize(line, color))
+	}
+}
+
+func writeIndented(b *strings.Builder, text, indent, color string) {
+	for _, line := range strings.Split(text, "\n") {
+		b.WriteString(indent)
+		b.WriteString(colorize(line, color))
+		b.WriteByte('\n')
+	}
+}
+
+func colorize(s, color string) string {
+	if color == "" || s == "" {
+		return s
+	}
+	return color + s + colorReset
+}
+
+func prettyFields(entry *log.Entry) []prettyField {
+	var fields []prettyField
+	if len(entry.OrderedFields) > 0 {
+		for _, f := range entry.OrderedFields {
+			if f.IsExtra {
+				fields = append(fields, prettyField{value: fmt.Sprintf("%v", f.Value)})
+				continue
+			}
+			if f.Key == "" {
+				continue
+			}
+			fields = append(fields, prettyField{key: f.Key, value: fmt.Sprintf("%v", f.Value)})
+		}
+		return fields
+	}
+
+	// 兼容：没有 OrderedFields 时回退 map，按 key 排序保证输出稳定
+	keys := make([]string, 0, len(entry.Fields))
+	for k := range entry.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if k == unpairedFieldKey {
+			fields = append(fields, prettyField{value: fmt.Sprintf("%v", entry.Fields[k])})
+			continue
+		}
+		fields = append(fields, prettyField{key: k, value: fmt.Sprintf("%v", entry.Fields[k])})
+	}
+	return fields
+}
@@ -0,0 +1,189 @@
+package std
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/jiajia556/tool-box/log"
+)
+
+// ecsVersion 输出的 ECS 版本
+const ecsVersion = "8.11.0"
+
+// ecsFieldNames 常用字段到 ECS 字段名的映射，未列出的字段原样输出
+var ecsFieldNames = map[string]string{
+	"trace_id":   "trace.id",
+	"span_id":    "span.id",
+	"error":      "error.message",
+	"err":        "error.message",
+	"request_id": "http.request.id",
+	"user_id":    "user.id",
+}
+
+// otelSeverity OTel 日志数据模型的 SeverityNumber
+var otelSeverity = map[log.Level]int{
+	log.LevelDebug: 5,
+	log.LevelInfo:  9,
+	log.LevelWarn:  13,
+	log.LevelError: 17,
+	log.LevelFatal: 21,
+	log.LevelPanic: 21,
+}
+
+// jsonObject 按写入顺序输出的 JSON 对象
+type jsonObject struct {
+	buf bytes.Buffer
+}
+
+func (o *jsonObject) add(k string, v interface{}) bool {
+	vb, err := json.Marshal(v)
+	if err != nil {
+		return false
+	}
+	o.addRaw(k, vb)
+	return true
+}
+
+func (o *jsonObject) addRaw(k string, raw []byte) {
+	kb, _ := json.Marshal(k)
+	if o.buf.Len() > 0 {
+		o.buf.WriteByte(',')
+	}
+	o.buf.Write(kb)
+	o.buf.WriteByte(':')
+	o.buf.Write(raw)
+}
+
+func (o *jsonObject) bytes() []byte {
+	return append(append([]byte{'{'}, o.buf.Bytes()...), '}')
+}
+
+// formatSchemaJSON 按 Config.Schema 指定的字段规范输出 JSON：
+//   - "ecs": Elastic Common Schema，@timestamp / log.level / message / trace.id 等
+//   - "otel": OpenTelemetry 日志数据模型，timestamp / severity_text / body，自定义字段放在 attributes 中
+//
+// 时间统一使用 UTC 的 RFC3339Nano，忽略 TimeFormat。
+func (sl *StdLogger) formatSchemaJSON(entry *log.Entry) string {
+	var out jsonObject
+	var ok bool
+	switch sl.config.Schema {
+	case "otel":
+		ok = writeOTel(&out, entry)
+	default:
+		ok = writeECS(&out, entry)
+	}
+	if !ok {
+		return sl.formatText(entry)
+	}
+	return string(out.bytes()) + "\n"
+}
+
+func writeECS(out *jsonObject, entry *log.Entry) bool {
+	out.add("@timestamp", entry.Time.UTC().Format(time.RFC3339Nano))
+	out.add("log.level", strings.ToLower(entry.Level.String()))
+	out.add("message", entry.Message)
+	out.add("ecs.version", ecsVersion)
+	if entry.LoggerKey != "" {
+		out.add("log.logger", entry.LoggerKey)
+	}
+	if entry.Caller != nil {
+		out.add("log.origin.file.name", entry.Caller.File)
+		out.add("log.origin.file.line", entry.Caller.Line)
+		out.add("log.origin.function", entry.Caller.Function)
+	}
+	if entry.Stack != "" {
+		out.add("error.stack_trace", entry.Stack)
+	}
+
+	var extras []interface{}
+	ok := true
+	eachField(entry, func(k string, v interface{}) {
+		if name, mapped := ecsFieldNames[k]; mapped {
+			k = name
+		}
+		if k == "error.message" {
+			if err, isErr := v.(error); isErr {
+				v = err.Error()
+			}
+		}
+		ok = ok && out.add(k, v)
+	}, func(v interface{}) {
+		extras = append(extras, v)
+	})
+	if len(extras) > 0 {
+		ok = ok && out.add("labels.extras", extras)
+	}
+	return ok
+}
+
+func writeOTel(out *jsonObject, entry *log.Entry) bool {
+	out.add("timestamp", entry.Time.UTC().Format(time.RFC3339Nano))
+	out.add("severity_text", entry.Level.String())
+	out.add("severity_number", otelSeverity[entry.Level])
+	out.add("body", entry.Message)
+
+	var attrs jsonObject
+	if entry.Caller != nil {
+		attrs.add("code.filepath", entry.Caller.File)
+		attrs.add("code.lineno", entry.Caller.Line)
+		attrs.add("code.function", entry.Caller.Function)
+	}
+	if entry.Stack != "" {
+		attrs.add("exception.stacktrace", entry.Stack)
+	}
+
+	var extras []interface{}
+	ok := true
+	eachField(entry, func(k string, v interface{}) {
+		switch k {
+		case "trace_id", "span_id":
+			ok = ok && out.add(k, v)
+		default:
+			if err, isErr := v.(error); isErr {
+				v = err.Error()
+			}
+			ok = ok && attrs.add(k, v)
+		}
+	}, func(v interface{}) {
+		extras = append(extras, v)
+	})
+	if len(extras) > 0 {
+		ok = ok && attrs.add("extras", extras)
+	}
+	if attrs.buf.Len() > 0 {
+		out.addRaw("attributes", attrs.bytes())
+	}
+	if entry.LoggerKey != "" {
+		out.addRaw("scope", mustJSON(map[string]string{"name": entry.LoggerKey}))
+	}
+	return ok
+}
+
+// eachField 按顺序遍历字段，extra 单独回调
+func eachField(entry *log.Entry, kv func(k string, v interface{}), extra func(v interface{})) {
+	if len(entry.OrderedFields) > 0 {
+		for _, f := range entry.OrderedFields {
+			switch {
+			case f.IsExtra:
+				extra(f.Value)
+			case f.Key != "":
+				kv(f.Key, f.Value)
+			}
+		}
+		return
+	}
+	for k, v := range entry.Fields {
+		if k == unpairedFieldKey {
+			extra(v)
+			continue
+		}
+		kv(k, v)
+	}
+}
+
+func mustJSON(v interface{}) []byte {
+	b, _ := json.Marshal(v)
+	return b
+}
@@ -0,0 +1,57 @@
+package std
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/jiajia556/tool-box/log"
+)
+
+// newSink 创建 journald / Windows 事件日志等结构化输出目标
+func newSink(output, identifier string) (log.WriterAdapter, error) {
+	if identifier == "" {
+		identifier = filepath.Base(os.Args[0])
+	}
+
+	switch output {
+	case "journald":
+		return newJournaldSink(identifier)
+	case "eventlog":
+		return newEventlogSink(identifier)
+	}
+	return nil, fmt.Errorf("log: unsupported output %q", output)
+}
+
+// sinkFields 按传入顺序返回 entry 的键值字段，未配对的单个 field 以 "extra<N>" 命名
+func sinkFields(entry *log.Entry) []log.Field {
+	if len(entry.OrderedFields) > 0 {
+		fields := make([]log.Field, 0, len(entry.OrderedFields))
+		extra := 0
+		for _, f := range entry.OrderedFields {
+			if f.IsExtra || f.Key == unpairedFieldKey {
+				extra++
+				f.Key = fmt.Sprintf("extra%d", extra)
+			}
+			fields = append(fields, f)
+		}
+		return fields
+	}
+
+	keys := make([]string, 0, len(entry.Fields))
+	for k := range entry.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fields := make([]log.Field, 0, len(keys))
+	for _, k := range keys {
+		name := k
+		if k == unpairedFieldKey {
+			name = "extra"
+		}
+		fields = append(fields, log.Field{Key: name, Value: entry.Fields[k]})
+	}
+	return fields
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -12,9 +13,11 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jiajia556/tool-box/log"
+	"github.com/jiajia556/tool-box/utils"
 )
 
 const (
@@ -24,6 +27,9 @@ const (
 	colorYellow = "\033[33m"
 	colorBlue   = "\033[34m"
 	colorGray   = "\033[37m"
+	colorPurple = "\033[35m"
+	colorCyan   = "\033[36m"
+	colorBold   = "\033[1m"
 )
 
 // 内部保留 key：用于保存“未配对的单个 field”。
@@ -33,27 +39,37 @@ const unpairedFieldKey = "__unpaired_field"
 // StdLogger 标准日志记录器实现
 type StdLogger struct {
 	mu        sync.Mutex
-	level     log.Level
+	level     atomic.Int32 // log.Level，未加锁的级别判断与 SetLevel 并发时需原子访问
 	config    log.Config
 	writers   []io.Writer
+	sinks     []log.WriterAdapter
 	fields    map[string]interface{}
 	callDepth int
+	failover  *failover
+	enrichers []log.Enricher
 }
 
 // NewStdLogger 创建标准日志记录器
 func NewStdLogger() log.Logger {
 	defaultConfig := log.DefaultConfig()
-	return &StdLogger{
-		level:     defaultConfig.Level,
+	sl := &StdLogger{
 		config:    defaultConfig,
 		writers:   []io.Writer{os.Stdout},
 		fields:    make(map[string]interface{}),
 		callDepth: defaultConfig.CallDepth,
+		failover:  newFailover(),
 	}
+	sl.level.Store(int32(defaultConfig.Level))
+	return sl
+}
+
+// enabled 判断 level 是否不低于当前日志级别
+func (sl *StdLogger) enabled(level log.Level) bool {
+	return int32(level) >= sl.level.Load()
 }
 
 func (sl *StdLogger) log(level log.Level, msg string, fields ...interface{}) {
-	if level < sl.level {
+	if !sl.enabled(level) {
 		return
 	}
 
@@ -87,15 +103,16 @@ func (sl *StdLogger) log(level log.Level, msg string, fields ...interface{}) {
 	}
 
 	entry := &log.Entry{
-		Time:          time.Now(),
+		Time:          sl.now(),
 		Level:         level,
 		Message:       msg,
 		Fields:        fieldMap,
 		OrderedFields: orderedFields,
 		Caller:        caller,
 	}
+	log.Enrich(entry, sl.enrichers)
 
-	sl.writeEntry(entry)
+	_ = sl.writeEntry(entry)
 
 	// FATAL 级别退出
 	if level == log.LevelFatal {
@@ -109,7 +126,10 @@ func (sl *StdLogger) log(level log.Level, msg string, fields ...interface{}) {
 }
 
 func (sl *StdLogger) logContext(ctx context.Context, level log.Level, msg string, fields ...interface{}) {
-	if level < sl.level {
+	// 尾部采样：请求内的低级别日志即使低于 logger 级别也先缓冲
+	tail := log.TailFromContext(ctx)
+	buffered := tail.Buffers(level)
+	if !sl.enabled(level) && !buffered {
 		return
 	}
 
@@ -137,6 +157,12 @@ func (sl *StdLogger) logContext(ctx context.Context, level log.Level, msg string
 		orderedFields = append(orderedFields, log.Field{Key: "trace_id", Value: traceID})
 	}
 
+	// 上下文中的 MDC 字段
+	for _, f := range log.ContextFields(ctx) {
+		fieldMap[f.Key] = f.Value
+		orderedFields = append(orderedFields, f)
+	}
+
 	orderedFields = append(orderedFields, mergeFields(fieldMap, fields...)...)
 
 	var caller *log.CallerInfo
@@ -145,7 +171,7 @@ func (sl *StdLogger) logContext(ctx context.Context, level log.Level, msg string
 	}
 
 	entry := &log.Entry{
-		Time:          time.Now(),
+		Time:          sl.now(),
 		Level:         level,
 		Message:       msg,
 		Fields:        fieldMap,
@@ -153,8 +179,15 @@ func (sl *StdLogger) logContext(ctx context.Context, level log.Level, msg string
 		Caller:        caller,
 		Ctx:           ctx,
 	}
+	log.Enrich(entry, sl.enrichers)
 
-	sl.writeEntry(entry)
+	if buffered && tail.Add(entry, sl.flushEntry) {
+		return
+	}
+	if !sl.enabled(level) {
+		return
+	}
+	_ = sl.writeEntry(entry)
 
 	if level == log.LevelFatal {
 		os.Exit(1)
@@ -197,7 +230,8 @@ func (sl *StdLogger) getCallerInfo(depth int) *log.CallerInfo {
 	}
 }
 
-func (sl *StdLogger) writeEntry(entry *log.Entry) {
+// writeEntry 输出日志，返回各输出的写入错误
+func (sl *StdLogger) writeEntry(entry *log.Entry) error {
 	var output string
 
 	switch sl.config.Encoder {
@@ -215,15 +249,102 @@ func (sl *StdLogger) writeEntry(entry *log.Entry) {
 		writers = append(append([]io.Writer(nil), writers...), os.Stdout)
 	}
 
+	fo := sl.failoverState()
+	fo.mu.Lock()
+	defer fo.mu.Unlock()
+
+	var errs []error
 	for _, w := range writers {
 		if dfw, ok := w.(*dailyFileWriter); ok {
-			_ = dfw.ensureForTime(entry.Time)
+			if err := dfw.ensureForTime(entry.Time); err != nil {
+				errs = append(errs, err)
+			}
 		}
 	}
 
-	for _, w := range writers {
-		_, _ = fmt.Fprint(w, output)
+	for i, w := range writers {
+		st := fo.state(fo.writers, i)
+		if sl.paused(st, w, output) {
+			continue
+		}
+		if _, err := fmt.Fprint(w, output); err != nil {
+			errs = append(errs, err)
+			sl.failed(st, w, output, err)
+			continue
+		}
+		sl.succeeded(st)
+	}
+
+	// journald / 事件日志等结构化输出直接接收 Entry
+	for i, s := range sl.sinks {
+		st := fo.state(fo.sinks, i)
+		if sl.paused(st, s, output) {
+			continue
+		}
+		if err := s.Write(entry); err != nil {
+			errs = append(errs, err)
+			sl.failed(st, s, output, err)
+			continue
+		}
+		sl.succeeded(st)
+	}
+	return errors.Join(errs...)
+}
+
+// WriteCritical 实现 log.CriticalWriter：忽略级别与尾部采样，附加 logger 字段后同步写入所有输出，
+// 并对文件输出 fsync、对 sink 调用 Sync，返回全部写入与落盘错误
+func (sl *StdLogger) WriteCritical(entry *log.Entry) error {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+
+	if entry.Fields == nil {
+		entry.Fields = make(map[string]interface{})
+	}
+	if len(sl.fields) > 0 {
+		keys := make([]string, 0, len(sl.fields))
+		for k := range sl.fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		ordered := make([]log.Field, 0, len(keys)+len(entry.OrderedFields))
+		for _, k := range keys {
+			if _, ok := entry.Fields[k]; ok {
+				continue
+			}
+			entry.Fields[k] = sl.fields[k]
+			ordered = append(ordered, log.Field{Key: k, Value: sl.fields[k]})
+		}
+		entry.OrderedFields = append(ordered, entry.OrderedFields...)
+	}
+	log.Enrich(entry, sl.enrichers)
+
+	errs := []error{sl.writeEntry(entry)}
+	for _, w := range sl.writers {
+		switch w := w.(type) {
+		case *dailyFileWriter:
+			errs = append(errs, w.Sync())
+		case *os.File:
+			// 终端与管道不支持 fsync
+			if w != os.Stdout && w != os.Stderr {
+				errs = append(errs, w.Sync())
+			}
+		}
 	}
+	for _, s := range sl.sinks {
+		errs = append(errs, s.Sync())
+	}
+	return errors.Join(errs...)
+}
+
+func (sl *StdLogger) now() time.Time {
+	return utils.ClockOrReal(sl.config.Clock).Now()
+}
+
+// flushEntry 输出尾部采样缓冲的日志
+func (sl *StdLogger) flushEntry(entry *log.Entry) {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	_ = sl.writeEntry(entry)
 }
 
 func hasWriter(writers []io.Writer, target io.Writer) bool {
@@ -255,29 +376,11 @@ func (sl *StdLogger) formatText(entry *log.Entry) string {
 	return msg + "\n"
 }
 
-func (sl *StdLogger) formatPretty(entry *log.Entry) string {
-	timeStr := entry.Time.Format(sl.config.TimeFormat)
-	if timeStr == "" {
-		timeStr = entry.Time.Format("2006-01-02 15:04:05")
-	}
-
-	levelStr := entry.Level.String()
-	levelColor := sl.getLevelColor(entry.Level)
-
-	msg := fmt.Sprintf("%s [%s%s%s] %s", timeStr, levelColor, levelStr, colorReset, entry.Message)
-	if entry.Caller != nil {
-		// 将调用位置放到最前边
-		msg = fmt.Sprintf("%s(%s:%d)%s %s", colorGray, entry.Caller.File, entry.Caller.Line, colorReset, msg)
-	}
-
-	if len(entry.Fields) > 0 {
-		msg += " " + sl.formatFields(entry)
+func (sl *StdLogger) formatJSON(entry *log.Entry) string {
+	if sl.config.Schema == "ecs" || sl.config.Schema == "otel" {
+		return sl.formatSchemaJSON(entry)
 	}
 
-	return msg + "\n"
-}
-
-func (sl *StdLogger) formatJSON(entry *log.Entry) string {
 	timeStr := entry.Time.Format(sl.config.TimeFormat)
 	if timeStr == "" {
 		timeStr = entry.Time.Format("2006-01-02T15:04:05Z07:00")
@@ -493,13 +596,17 @@ func (sl *StdLogger) WithFields(fields map[string]interface{}) log.Logger {
 
 	// 不要复制 mutex（复制后可能导致未定义行为），直接构造一个新的 logger。
 	newWriters := append([]io.Writer(nil), sl.writers...)
-	return &StdLogger{
-		level:     sl.level,
+	child := &StdLogger{
 		config:    sl.config,
 		writers:   newWriters,
+		sinks:     append([]log.WriterAdapter(nil), sl.sinks...),
 		fields:    newFields,
 		callDepth: sl.callDepth,
+		failover:  sl.failoverState(),
+		enrichers: sl.enrichers,
 	}
+	child.level.Store(sl.level.Load())
+	return child
 }
 
 func (sl *StdLogger) With(key string, value interface{}) log.Logger {
@@ -507,28 +614,49 @@ func (sl *StdLogger) With(key string, value interface{}) log.Logger {
 }
 
 func (sl *StdLogger) SetLevel(level log.Level) {
-	sl.mu.Lock()
-	defer sl.mu.Unlock()
-	sl.level = level
+	sl.level.Store(int32(level))
 }
 
 func (sl *StdLogger) SetConfig(config log.Config) error {
 	sl.mu.Lock()
 	defer sl.mu.Unlock()
 
+	enrichers, err := log.LookupEnrichers(config.Enrichers)
+	if err != nil {
+		return err
+	}
+
 	// 先关闭旧的文件 writer，避免配置切换时句柄泄漏。
 	sl.closeOwnedWritersLocked()
 
+	sl.enrichers = enrichers
+
 	sl.config = config
-	sl.level = config.Level
+	sl.level.Store(int32(config.Level))
 	if sl.config.File.Dir == "" {
 		sl.config.File.Dir = "./logs"
 	}
 
 	// 配置输出目标
+	sl.failover = newFailover()
+	sl.sinks = nil
 	switch config.Output {
+	case "journald", "eventlog":
+		sink, err := newSink(config.Output, config.Identifier)
+		if err != nil {
+			sl.writers = nil
+			return err
+		}
+		sl.writers = nil
+		sl.sinks = []log.WriterAdapter{sink}
 	case "stderr":
 		sl.writers = []io.Writer{os.Stderr}
+	case "writer":
+		if config.Writer == nil {
+			sl.writers = nil
+			return errors.New("log: output \"writer\" requires Config.Writer")
+		}
+		sl.writers = []io.Writer{config.Writer}
 	case "file":
 		sl.writers = []io.Writer{newDailyFileWriter(sl.config.File.Dir)}
 	case "combined":
@@ -555,6 +683,7 @@ func (sl *StdLogger) Close() error {
 	defer sl.mu.Unlock()
 	sl.closeOwnedWritersLocked()
 	sl.writers = nil
+	sl.sinks = nil
 	return nil
 }
 
@@ -608,13 +737,16 @@ func mergeFields(fieldMap map[string]interface{}, fields ...interface{}) []log.F
 }
 
 func (sl *StdLogger) closeOwnedWritersLocked() {
+	for _, s := range sl.sinks {
+		_ = s.Close()
+	}
 	for _, w := range sl.writers {
 		if dfw, ok := w.(*dailyFileWriter); ok {
 			_ = dfw.Close()
 			continue
 		}
 		f, ok := w.(*os.File)
-		if !ok {
+		if !ok || w == sl.config.Writer {
 			continue
 		}
 		if f == os.Stdout || f == os.Stderr {
@@ -673,6 +805,16 @@ func (w *dailyFileWriter) Write(p []byte) (int, error) {
 	return w.file.Write(p)
 }
 
+// Sync 将当前日志文件落盘
+func (w *dailyFileWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	return w.file.Sync()
+}
+
 func (w *dailyFileWriter) Close() error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
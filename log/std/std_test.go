@@ -1,12 +1,17 @@
 package std
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"io"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/jiajia556/tool-box/log"
 )
@@ -161,3 +166,254 @@ func TestStdLogger_DebugAlwaysAlsoToStdout(t *testing.T) {
 		}
 	})
 }
+
+func TestStdLogger_ContextFields(t *testing.T) {
+	l := NewStdLogger()
+
+	cfg := log.DefaultConfig()
+	cfg.Level = log.LevelDebug
+	cfg.Caller = false
+	cfg.Encoder = "text"
+	cfg.Output = "file"
+	cfg.File.Dir = filepath.Join(t.TempDir(), "logs")
+	if err := l.SetConfig(cfg); err != nil {
+		t.Fatalf("SetConfig: %v", err)
+	}
+	defer func() { _ = l.Close() }()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	oldStdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = oldStdout }()
+
+	ctx := log.PushFields(context.Background(), "user_id", 1, "tenant", "a")
+	ctx = log.PushFields(ctx, "user_id", 2)
+	l.DebugContext(ctx, "hello-ctx", "k", "v")
+
+	_ = w.Close()
+	b, _ := io.ReadAll(r)
+	_ = r.Close()
+
+	out := string(b)
+	for _, want := range []string{"tenant=a", "user_id=2", "k=v"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output; got %q", want, out)
+		}
+	}
+	if strings.Contains(out, "user_id=1") {
+		t.Fatalf("expected overridden field to be dropped; got %q", out)
+	}
+}
+
+func TestStdLogger_Tail(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	oldStdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = oldStdout }()
+
+	l := NewStdLogger()
+	cfg := log.DefaultConfig()
+	cfg.Level = log.LevelInfo
+	cfg.Caller = false
+	cfg.Output = "stdout"
+	if err := l.SetConfig(cfg); err != nil {
+		t.Fatalf("SetConfig: %v", err)
+	}
+	defer func() { _ = l.Close() }()
+
+	// 正常结束的请求丢弃 DEBUG
+	ctx, tail := log.StartTail(log.PushFields(context.Background(), "request_id", "ok-1"))
+	l.DebugContext(ctx, "debug-ok")
+	l.InfoContext(ctx, "info-ok")
+	tail.Finish(nil)
+
+	// 出错的请求输出缓冲的 DEBUG
+	ctx, tail = log.StartTail(log.PushFields(context.Background(), "request_id", "bad-1"))
+	l.DebugContext(ctx, "debug-bad")
+	tail.Finish(errors.New("boom"))
+
+	_ = w.Close()
+	b, _ := io.ReadAll(r)
+	_ = r.Close()
+
+	out := string(b)
+	if strings.Contains(out, "debug-ok") || !strings.Contains(out, "info-ok") {
+		t.Fatalf("expected only info for successful request; got %q", out)
+	}
+	if !strings.Contains(out, "debug-bad") || !strings.Contains(out, "request_id=bad-1") || !strings.Contains(out, "tail_elapsed=") {
+		t.Fatalf("expected buffered debug for failed request; got %q", out)
+	}
+}
+
+func TestStdLogger_JSONSchema(t *testing.T) {
+	l := NewStdLogger().(*StdLogger)
+	entry := &log.Entry{
+		Time:    time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC),
+		Level:   log.LevelWarn,
+		Message: "slow query",
+		OrderedFields: []log.Field{
+			{Key: "trace_id", Value: "abc"},
+			{Key: "error", Value: errors.New("timeout")},
+			{Key: "table", Value: "users"},
+		},
+		Caller: &log.CallerInfo{File: "db/query.go", Line: 42, Function: "db.Query"},
+	}
+
+	l.config.Encoder = "json"
+	l.config.Schema = "ecs"
+	var ecs map[string]interface{}
+	if err := json.Unmarshal([]byte(l.formatJSON(entry)), &ecs); err != nil {
+		t.Fatalf("ecs output is not JSON: %v", err)
+	}
+	for k, want := range map[string]interface{}{
+		"@timestamp": "2024-05-01T08:00:00Z", "log.level": "warn", "message": "slow query",
+		"trace.id": "abc", "error.message": "timeout", "table": "users", "log.origin.file.line": float64(42),
+	} {
+		if ecs[k] != want {
+			t.Fatalf("ecs[%q] = %v, want %v", k, ecs[k], want)
+		}
+	}
+
+	l.config.Schema = "otel"
+	var otel struct {
+		Timestamp      string                 `json:"timestamp"`
+		SeverityText   string                 `json:"severity_text"`
+		SeverityNumber int                    `json:"severity_number"`
+		Body           string                 `json:"body"`
+		TraceID        string                 `json:"trace_id"`
+		Attributes     map[string]interface{} `json:"attributes"`
+	}
+	if err := json.Unmarshal([]byte(l.formatJSON(entry)), &otel); err != nil {
+		t.Fatalf("otel output is not JSON: %v", err)
+	}
+	if otel.SeverityText != "WARN" || otel.SeverityNumber != 13 || otel.Body != "slow query" || otel.TraceID != "abc" ||
+		otel.Attributes["error"] != "timeout" || otel.Attributes["code.lineno"] != float64(42) {
+		t.Fatalf("unexpected otel output: %+v", otel)
+	}
+}
+
+func TestCritical(t *testing.T) {
+	cfg := log.DefaultConfig()
+	cfg.Level = log.LevelFatal
+	cfg.Caller = false
+	cfg.Output = "file"
+	cfg.File.Dir = filepath.Join(t.TempDir(), "logs")
+	if err := log.Init(cfg); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	defer func() { _ = log.Get().Close() }()
+
+	calls := 0
+	log.RegisterAlertHook(func(ctx context.Context, entry *log.Entry) error {
+		calls++
+		if calls == 1 {
+			return errors.New("alert unavailable")
+		}
+		if entry.Message != "ledger mismatch" {
+			t.Errorf("unexpected alert message %q", entry.Message)
+		}
+		return nil
+	})
+
+	// 级别高于 ERROR 时关键事件依然写入，告警失败后重试成功
+	if err := log.Critical("ledger mismatch", "order_id", 42); err != nil {
+		t.Fatalf("Critical: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected alert hook to be retried once, got %d calls", calls)
+	}
+
+	path := filepath.Join(cfg.File.Dir, time.Now().Format("2006-01-02")+".log")
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	out := string(b)
+	if !strings.Contains(out, "ledger mismatch") || !strings.Contains(out, "order_id=42") || !strings.Contains(out, "critical=true") {
+		t.Fatalf("expected critical entry in log file; got %q", out)
+	}
+}
+
+type failingWriter struct{ calls int }
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.calls++
+	return 0, errors.New("no space left on device")
+}
+
+func TestStdLogger_Failover(t *testing.T) {
+	l := NewStdLogger().(*StdLogger)
+	var fallback strings.Builder
+	cfg := log.DefaultConfig()
+	cfg.Caller = false
+	cfg.Output = "stdout"
+	cfg.Failover = log.FailoverConfig{Fallback: &fallback, Threshold: 2, Cooldown: time.Minute}
+	if err := l.SetConfig(cfg); err != nil {
+		t.Fatalf("SetConfig: %v", err)
+	}
+	w := &failingWriter{}
+	l.writers = []io.Writer{w}
+
+	l.Info("first")
+	l.Info("second")
+	l.Info("third")
+
+	// 连续失败 2 次后暂停，第三条日志不再尝试写入
+	if w.calls != 2 {
+		t.Fatalf("expected 2 write attempts, got %d", w.calls)
+	}
+	if l.FailedWrites() != 2 {
+		t.Fatalf("expected 2 failed writes, got %d", l.FailedWrites())
+	}
+	out := fallback.String()
+	for _, want := range []string{"output write failed", "consecutive=2", "disabled_for=1m0s", "first", "second", "third"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("fallback output missing %q: %q", want, out)
+		}
+	}
+}
+
+// 注册表是全局的，-count 多次运行时只注册一次
+var registerTestEnricher sync.Once
+
+func TestStdLogger_Enrichers(t *testing.T) {
+	registerTestEnricher.Do(func() {
+		log.RegisterEnricher("test_static", log.StaticFields("app", "billing", "region", "cn-east"))
+	})
+
+	l := NewStdLogger().(*StdLogger)
+	cfg := log.DefaultConfig()
+	cfg.Caller = false
+	cfg.Output = "stdout"
+	cfg.Format = "json"
+	cfg.Encoder = "json"
+	cfg.Enrichers = "test_static"
+	if err := l.SetConfig(cfg); err != nil {
+		t.Fatalf("SetConfig: %v", err)
+	}
+	var buf strings.Builder
+	l.writers = []io.Writer{&buf}
+
+	// 调用点传入的同名字段优先
+	l.With("order", 1).Info("paid", "region", "us-west")
+	out := buf.String()
+	for _, want := range []string{`"app":"billing"`, `"order":1`, `"region":"us-west"`} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("output missing %q: %q", want, out)
+		}
+	}
+	if strings.Contains(out, "cn-east") {
+		t.Fatalf("enricher should not override call-site field: %q", out)
+	}
+
+	cfg.Enrichers = "test_static, missing"
+	if err := l.SetConfig(cfg); err == nil {
+		t.Fatal("expected error for unknown enricher")
+	}
+}
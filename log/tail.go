@@ -0,0 +1,163 @@
+package log
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TailConfig 请求级尾部采样配置
+type TailConfig struct {
+	// 低于该级别的日志先缓冲，请求结束时再决定是否输出，默认 LevelInfo（即缓冲 DEBUG）
+	Level Level
+
+	// 请求耗时超过该值时输出缓冲的日志，<=0 表示只在出错时输出
+	Latency time.Duration
+
+	// 单个请求最多缓冲的条数，超出后丢弃最早的条目
+	MaxEntries int
+}
+
+// TailOption 选项函数
+type TailOption func(*TailConfig)
+
+// WithTailLevel 设置缓冲的级别上限（不含）
+func WithTailLevel(level Level) TailOption {
+	return func(c *TailConfig) {
+		c.Level = level
+	}
+}
+
+// WithTailLatency 设置触发输出的耗时阈值
+func WithTailLatency(d time.Duration) TailOption {
+	return func(c *TailConfig) {
+		c.Latency = d
+	}
+}
+
+// WithTailMaxEntries 设置单个请求最多缓冲的条数
+func WithTailMaxEntries(n int) TailOption {
+	return func(c *TailConfig) {
+		c.MaxEntries = n
+	}
+}
+
+// DefaultTailConfig 默认配置：缓冲 DEBUG，耗时超过 1s 或出错时输出，最多 1000 条
+func DefaultTailConfig() TailConfig {
+	return TailConfig{
+		Level:      LevelInfo,
+		Latency:    time.Second,
+		MaxEntries: 1000,
+	}
+}
+
+type tailEntry struct {
+	entry *Entry
+	write func(*Entry)
+}
+
+// Tail 单个请求的日志缓冲
+type Tail struct {
+	config TailConfig
+	start  time.Time
+
+	mu       sync.Mutex
+	entries  []tailEntry
+	dropped  int
+	finished bool
+}
+
+type tailKey struct{}
+
+// StartTail 为请求开启尾部采样：之后使用返回的上下文调用 XxxContext 时，
+// 低于 TailConfig.Level 的日志（即使低于 logger 的级别）会先缓冲在 Tail 中，
+// 请求结束时调用 Finish，仅在出错或超过耗时阈值时输出，正常请求的 DEBUG 日志直接丢弃。
+// 通常在中间件中与 PushFields(ctx, "request_id", id) 一起使用：
+//
+//	ctx, tail := log.StartTail(log.PushFields(ctx, "request_id", id))
+//	err := handle(ctx)
+//	tail.Finish(err)
+func StartTail(ctx context.Context, opts ...TailOption) (context.Context, *Tail) {
+	config := DefaultTailConfig()
+	for _, opt := range opts {
+		opt(&config)
+	}
+	t := &Tail{config: config, start: time.Now()}
+	return context.WithValue(ctx, tailKey{}, t), t
+}
+
+// TailFromContext 返回上下文中的 Tail，没有时返回 nil
+func TailFromContext(ctx context.Context) *Tail {
+	if ctx == nil {
+		return nil
+	}
+	t, _ := ctx.Value(tailKey{}).(*Tail)
+	return t
+}
+
+// Buffers 判断该级别的日志是否应交给 Tail 缓冲，供适配器使用
+func (t *Tail) Buffers(level Level) bool {
+	if t == nil || level >= t.config.Level {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return !t.finished
+}
+
+// Add 缓冲一条日志，write 在 Finish 决定输出时调用，供适配器使用。
+// 请求已结束时返回 false，由调用方按普通日志处理。
+func (t *Tail) Add(entry *Entry, write func(*Entry)) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.finished {
+		return false
+	}
+	if t.config.MaxEntries > 0 && len(t.entries) >= t.config.MaxEntries {
+		t.entries = t.entries[1:]
+		t.dropped++
+	}
+	t.entries = append(t.entries, tailEntry{entry: entry, write: write})
+	return true
+}
+
+// Finish 结束请求：err 不为 nil 或耗时超过阈值时按顺序输出缓冲的日志并返回 true，否则丢弃。
+// 重复调用无效果。
+func (t *Tail) Finish(err error) bool {
+	if t == nil {
+		return false
+	}
+
+	t.mu.Lock()
+	if t.finished {
+		t.mu.Unlock()
+		return false
+	}
+	t.finished = true
+	entries, dropped := t.entries, t.dropped
+	t.entries = nil
+	t.mu.Unlock()
+
+	elapsed := time.Since(t.start)
+	if err == nil && (t.config.Latency <= 0 || elapsed < t.config.Latency) {
+		return false
+	}
+
+	for i, e := range entries {
+		if i == 0 {
+			// 在第一条上标注触发原因，便于与正常日志区分
+			if e.entry.Fields == nil {
+				e.entry.Fields = make(map[string]interface{})
+			}
+			e.entry.OrderedFields = append(e.entry.OrderedFields, Field{Key: "tail_elapsed", Value: elapsed.String()})
+			e.entry.Fields["tail_elapsed"] = elapsed.String()
+			if dropped > 0 {
+				e.entry.OrderedFields = append(e.entry.OrderedFields, Field{Key: "tail_dropped", Value: dropped})
+				e.entry.Fields["tail_dropped"] = dropped
+			}
+		}
+		e.write(e.entry)
+	}
+	return true
+}
@@ -0,0 +1,394 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jiajia556/tool-box/retry"
+)
+
+var (
+	ErrNoGlobal      = errors.New("mailer: global instance is nil")
+	ErrInvalidConfig = errors.New("mailer: invalid config")
+	ErrNoRecipient   = errors.New("mailer: message has no recipient")
+	ErrClosed        = errors.New("mailer: mailer closed")
+	ErrQueueFull     = errors.New("mailer: async queue full")
+)
+
+// Attachment 邮件附件；Inline 为 true 时作为内嵌资源，可在 HTML 中用 cid:ContentID 引用
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+	Inline      bool
+	ContentID   string
+}
+
+// Message 邮件
+type Message struct {
+	From        string
+	To          []string
+	Cc          []string
+	Bcc         []string
+	ReplyTo     string
+	Subject     string
+	Text        string
+	HTML        string
+	Headers     map[string]string
+	Attachments []Attachment
+}
+
+// Recipients 返回所有收件人（To + Cc + Bcc）
+func (m *Message) Recipients() []string {
+	out := make([]string, 0, len(m.To)+len(m.Cc)+len(m.Bcc))
+	out = append(out, m.To...)
+	out = append(out, m.Cc...)
+	out = append(out, m.Bcc...)
+	return out
+}
+
+// Sender 邮件发送适配器接口
+type Sender interface {
+	// 发送邮件
+	Send(ctx context.Context, msg *Message) error
+
+	// 关闭（释放资源）
+	Close() error
+}
+
+// Instance 适配器工厂函数
+type Instance func(config any) (Sender, error)
+
+var (
+	adaptersMu sync.RWMutex
+	adapters   = make(map[string]Instance)
+)
+
+const (
+	AdapterSMTP     = "smtp"
+	AdapterSendGrid = "sendgrid"
+	AdapterSES      = "ses"
+)
+
+// Register 注册邮件发送适配器
+func Register(name string, adapter Instance) {
+	adaptersMu.Lock()
+	defer adaptersMu.Unlock()
+
+	if adapter == nil {
+		panic("mailer: Register adapter is nil")
+	}
+	if _, ok := adapters[name]; ok {
+		panic("mailer: Register called twice for adapter " + name)
+	}
+	adapters[name] = adapter
+}
+
+// NewSender 按适配器名称创建发送器
+func NewSender(adapterName string, config any) (Sender, error) {
+	adaptersMu.RLock()
+	instanceFunc, ok := adapters[adapterName]
+	adaptersMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("mailer: unknown adapter name %q (forgot to import?)", adapterName)
+	}
+	return instanceFunc(config)
+}
+
+// Config 发送策略配置
+type Config struct {
+	// 默认发件人（Message.From 为空时使用）
+	From string
+
+	// 失败重试次数（不含首次）
+	MaxRetries int
+
+	// 重试间隔（指数退避的初始值）
+	RetryBackoff time.Duration
+
+	// 重试策略，非 nil 时替代 MaxRetries 与 RetryBackoff，可与其他组件共享
+	Retry *retry.Policy
+
+	// 每秒最多发送封数，<=0 表示不限速
+	RateLimit float64
+
+	// 异步队列容量，<=0 表示不启用异步发送
+	QueueSize int
+
+	// 异步发送 worker 数
+	Workers int
+
+	// 异步发送失败回调
+	OnError func(msg *Message, err error)
+}
+
+// Option 选项函数
+type Option func(*Config)
+
+// WithFrom 设置默认发件人
+func WithFrom(from string) Option {
+	return func(c *Config) {
+		c.From = from
+	}
+}
+
+// WithRetry 设置失败重试
+func WithRetry(maxRetries int, backoff time.Duration) Option {
+	return func(c *Config) {
+		c.MaxRetries = maxRetries
+		c.RetryBackoff = backoff
+	}
+}
+
+// WithRetryPolicy 使用共享的重试策略，替代 WithRetry 的设置
+func WithRetryPolicy(p *retry.Policy) Option {
+	return func(c *Config) {
+		c.Retry = p
+	}
+}
+
+// WithRateLimit 设置每秒最多发送封数
+func WithRateLimit(perSecond float64) Option {
+	return func(c *Config) {
+		c.RateLimit = perSecond
+	}
+}
+
+// WithAsync 启用异步发送队列
+func WithAsync(queueSize, workers int, onError func(msg *Message, err error)) Option {
+	return func(c *Config) {
+		c.QueueSize = queueSize
+		c.Workers = workers
+		c.OnError = onError
+	}
+}
+
+// DefaultConfig 默认配置
+func DefaultConfig() Config {
+	return Config{
+		MaxRetries:   2,
+		RetryBackoff: time.Second,
+		Workers:      1,
+	}
+}
+
+// Mailer 在 Sender 之上提供重试、限速与异步队列
+type Mailer struct {
+	sender Sender
+	config Config
+
+	limitMu  sync.Mutex
+	nextSend time.Time
+
+	queue   chan *Message
+	wg      sync.WaitGroup
+	closeMu sync.RWMutex
+	closed  bool
+}
+
+// New 创建 Mailer
+func New(sender Sender, opts ...Option) *Mailer {
+	config := DefaultConfig()
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	if config.Workers <= 0 {
+		config.Workers = 1
+	}
+
+	if config.Retry == nil {
+		config.Retry = retry.New(
+			retry.WithMaxAttempts(config.MaxRetries+1),
+			retry.WithBackoff(retry.Exponential(config.RetryBackoff, 0, 0)),
+		)
+	}
+
+	m := &Mailer{sender: sender, config: config}
+	if config.QueueSize > 0 {
+		m.queue = make(chan *Message, config.QueueSize)
+		for i := 0; i < config.Workers; i++ {
+			m.wg.Add(1)
+			go m.worker()
+		}
+	}
+	return m
+}
+
+// Send 同步发送（带重试与限速）；msg 不会被修改，默认发件人填充在副本上
+func (m *Mailer) Send(ctx context.Context, msg *Message) error {
+	if msg.From == "" {
+		cp := *msg
+		cp.From = m.config.From
+		msg = &cp
+	}
+	if len(msg.Recipients()) == 0 {
+		return ErrNoRecipient
+	}
+
+	attempts := 0
+	err := m.config.Retry.Do(ctx, func(ctx context.Context) error {
+		if err := m.wait(ctx); err != nil {
+			return retry.Permanent(err)
+		}
+		attempts++
+		return m.sender.Send(ctx, msg)
+	})
+	if err != nil && attempts > 0 && !errors.Is(err, ctx.Err()) {
+		return fmt.Errorf("mailer: send failed after %d attempts: %w", attempts, err)
+	}
+	return err
+}
+
+// SendAsync 放入异步队列发送；未启用异步时退化为后台 goroutine 发送
+func (m *Mailer) SendAsync(msg *Message) error {
+	m.closeMu.RLock()
+	defer m.closeMu.RUnlock()
+
+	if m.closed {
+		return ErrClosed
+	}
+
+	if m.queue == nil {
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			m.deliver(msg)
+		}()
+		return nil
+	}
+
+	select {
+	case m.queue <- msg:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+func (m *Mailer) worker() {
+	defer m.wg.Done()
+	for msg := range m.queue {
+		m.deliver(msg)
+	}
+}
+
+func (m *Mailer) deliver(msg *Message) {
+	if err := m.Send(context.Background(), msg); err != nil && m.config.OnError != nil {
+		m.config.OnError(msg, err)
+	}
+}
+
+// wait 按 RateLimit 控制发送间隔
+func (m *Mailer) wait(ctx context.Context) error {
+	if m.config.RateLimit <= 0 {
+		return nil
+	}
+
+	interval := time.Duration(float64(time.Second) / m.config.RateLimit)
+
+	m.limitMu.Lock()
+	now := time.Now()
+	at := m.nextSend
+	if at.Before(now) {
+		at = now
+	}
+	m.nextSend = at.Add(interval)
+	m.limitMu.Unlock()
+
+	delay := time.Until(at)
+	if delay <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(delay):
+		return nil
+	}
+}
+
+// Close 停止接收异步任务，等待队列发送完毕后关闭 Sender
+func (m *Mailer) Close() error {
+	m.closeMu.Lock()
+	if m.closed {
+		m.closeMu.Unlock()
+		return nil
+	}
+	m.closed = true
+	if m.queue != nil {
+		close(m.queue)
+	}
+	m.closeMu.Unlock()
+
+	m.wg.Wait()
+	return m.sender.Close()
+}
+
+var (
+	globalMu sync.RWMutex
+	global   *Mailer
+)
+
+// Init 初始化全局 Mailer，已有的全局 Mailer 会被关闭
+// 参数 config 为适配器配置：
+// - "smtp": 接受 smtp.Options 结构体
+// - "sendgrid": 接受 sendgrid.Options 结构体
+// - "ses": 接受 ses.Options 结构体
+func Init(adapterName string, config any, opts ...Option) error {
+	sender, err := NewSender(adapterName, config)
+	if err != nil {
+		return err
+	}
+
+	globalMu.Lock()
+	old := global
+	global = New(sender, opts...)
+	globalMu.Unlock()
+
+	if old != nil {
+		return old.Close()
+	}
+	return nil
+}
+
+// Send 使用全局 Mailer 同步发送
+func Send(ctx context.Context, msg *Message) error {
+	globalMu.RLock()
+	m := global
+	globalMu.RUnlock()
+
+	if m == nil {
+		return ErrNoGlobal
+	}
+	return m.Send(ctx, msg)
+}
+
+// SendAsync 使用全局 Mailer 异步发送
+func SendAsync(msg *Message) error {
+	globalMu.RLock()
+	m := global
+	globalMu.RUnlock()
+
+	if m == nil {
+		return ErrNoGlobal
+	}
+	return m.SendAsync(msg)
+}
+
+// Close 关闭全局 Mailer
+func Close() error {
+	globalMu.Lock()
+	m := global
+	global = nil
+	globalMu.Unlock()
+
+	if m == nil {
+		return nil
+	}
+	return m.Close()
+}
@@ -0,0 +1,113 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakeSender struct {
+	fails int32
+	calls int32
+}
+
+func (f *fakeSender) Send(ctx context.Context, msg *Message) error {
+	n := atomic.AddInt32(&f.calls, 1)
+	if n <= atomic.LoadInt32(&f.fails) {
+		return errors.New("temporary failure")
+	}
+	return nil
+}
+
+func (f *fakeSender) Close() error { return nil }
+
+func TestMailer_Retry(t *testing.T) {
+	s := &fakeSender{fails: 2}
+	m := New(s, WithFrom("noreply@example.com"), WithRetry(2, time.Millisecond))
+
+	msg := &Message{To: []string{"a@example.com"}}
+	if err := m.Send(context.Background(), msg); err != nil {
+		t.Fatalf("expected send to succeed after retries, got %v", err)
+	}
+	if msg.From != "" {
+		t.Fatalf("Send should not modify the caller's message, From = %q", msg.From)
+	}
+	if s.calls != 3 {
+		t.Fatalf("expected 3 attempts, got %d", s.calls)
+	}
+
+	if err := m.Send(context.Background(), &Message{}); !errors.Is(err, ErrNoRecipient) {
+		t.Fatalf("expected ErrNoRecipient, got %v", err)
+	}
+}
+
+func TestMailer_AsyncDrainsOnClose(t *testing.T) {
+	s := &fakeSender{}
+	m := New(s, WithRetry(0, 0), WithAsync(10, 2, nil))
+
+	for i := 0; i < 5; i++ {
+		if err := m.SendAsync(&Message{From: "a@example.com", To: []string{"b@example.com"}}); err != nil {
+			t.Fatalf("SendAsync: %v", err)
+		}
+	}
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if s.calls != 5 {
+		t.Fatalf("expected 5 sends, got %d", s.calls)
+	}
+	if err := m.SendAsync(&Message{}); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+}
+
+func TestBuildMIME(t *testing.T) {
+	raw, err := BuildMIME(&Message{
+		From:    "Tool Box <noreply@example.com>",
+		To:      []string{"a@example.com"},
+		Subject: "你好",
+		Text:    "hello",
+		HTML:    `<img src="cid:logo">`,
+		Attachments: []Attachment{
+			{Filename: "logo.png", Data: []byte("png"), Inline: true, ContentID: "logo"},
+			{Filename: "report.csv", Data: []byte("a,b")},
+		},
+	})
+	if err != nil {
+		t.Fatalf("BuildMIME: %v", err)
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if subject != "你好" {
+		t.Fatalf("unexpected subject %q", subject)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		t.Fatalf("unexpected content type %q: %v", mediaType, err)
+	}
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	var types []string
+	for {
+		p, err := mr.NextPart()
+		if err != nil {
+			break
+		}
+		ct, _, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		types = append(types, ct)
+	}
+	if strings.Join(types, ",") != "multipart/related,text/csv" {
+		t.Fatalf("unexpected parts %v", types)
+	}
+}
@@ -0,0 +1,226 @@
+package mailer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BuildMIME 将邮件编码为 RFC 5322 / MIME 格式，供 SMTP 与原始邮件 API 使用。
+// 结构：mixed(related(alternative(text, html), 内嵌资源...), 附件...)，按需省略层级。
+func BuildMIME(msg *Message) ([]byte, error) {
+	var buf bytes.Buffer
+
+	writeHeader(&buf, "From", msg.From)
+	if len(msg.To) > 0 {
+		writeHeader(&buf, "To", strings.Join(msg.To, ", "))
+	}
+	if len(msg.Cc) > 0 {
+		writeHeader(&buf, "Cc", strings.Join(msg.Cc, ", "))
+	}
+	if msg.ReplyTo != "" {
+		writeHeader(&buf, "Reply-To", msg.ReplyTo)
+	}
+	writeHeader(&buf, "Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	writeHeader(&buf, "Date", time.Now().Format(time.RFC1123Z))
+	writeHeader(&buf, "Message-ID", fmt.Sprintf("<%s@%s>", uuid.New().String(), senderDomain(msg.From)))
+	writeHeader(&buf, "MIME-Version", "1.0")
+	for k, v := range msg.Headers {
+		writeHeader(&buf, k, v)
+	}
+
+	var inline, attached []Attachment
+	for _, a := range msg.Attachments {
+		if a.Inline {
+			inline = append(inline, a)
+		} else {
+			attached = append(attached, a)
+		}
+	}
+
+	if len(attached) == 0 {
+		if err := writeRelated(&buf, msg, inline); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	mw := multipart.NewWriter(&buf)
+	writeHeader(&buf, "Content-Type", "multipart/mixed; boundary="+mw.Boundary())
+	buf.WriteString("\r\n")
+
+	var body bytes.Buffer
+	if err := writeRelated(&body, msg, inline); err != nil {
+		return nil, err
+	}
+	if err := writeRawPart(mw, &body); err != nil {
+		return nil, err
+	}
+	for _, a := range attached {
+		if err := writeAttachment(mw, a); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeRelated 写入正文（含内嵌资源）
+func writeRelated(w *bytes.Buffer, msg *Message, inline []Attachment) error {
+	if len(inline) == 0 {
+		return writeAlternative(w, msg)
+	}
+
+	mw := multipart.NewWriter(w)
+	writeHeader(w, "Content-Type", "multipart/related; boundary="+mw.Boundary())
+	w.WriteString("\r\n")
+
+	var body bytes.Buffer
+	if err := writeAlternative(&body, msg); err != nil {
+		return err
+	}
+	if err := writeRawPart(mw, &body); err != nil {
+		return err
+	}
+	for _, a := range inline {
+		if err := writeAttachment(mw, a); err != nil {
+			return err
+		}
+	}
+	return mw.Close()
+}
+
+// writeAlternative 写入 text/html 正文；两者都有时使用 multipart/alternative
+func writeAlternative(w *bytes.Buffer, msg *Message) error {
+	switch {
+	case msg.HTML == "":
+		return writeText(w, "text/plain", msg.Text)
+	case msg.Text == "":
+		return writeText(w, "text/html", msg.HTML)
+	}
+
+	mw := multipart.NewWriter(w)
+	writeHeader(w, "Content-Type", "multipart/alternative; boundary="+mw.Boundary())
+	w.WriteString("\r\n")
+
+	for _, p := range []struct{ ct, body string }{{"text/plain", msg.Text}, {"text/html", msg.HTML}} {
+		var part bytes.Buffer
+		if err := writeText(&part, p.ct, p.body); err != nil {
+			return err
+		}
+		if err := writeRawPart(mw, &part); err != nil {
+			return err
+		}
+	}
+	return mw.Close()
+}
+
+func writeText(w *bytes.Buffer, contentType, body string) error {
+	writeHeader(w, "Content-Type", contentType+"; charset=utf-8")
+	writeHeader(w, "Content-Transfer-Encoding", "quoted-printable")
+	w.WriteString("\r\n")
+
+	qw := quotedprintable.NewWriter(w)
+	if _, err := qw.Write([]byte(body)); err != nil {
+		return err
+	}
+	return qw.Close()
+}
+
+// writeRawPart 将已包含头部的 part 内容写入 multipart
+func writeRawPart(mw *multipart.Writer, part *bytes.Buffer) error {
+	header, body, err := splitPart(part.Bytes())
+	if err != nil {
+		return err
+	}
+	pw, err := mw.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	_, err = pw.Write(body)
+	return err
+}
+
+func splitPart(b []byte) (textproto.MIMEHeader, []byte, error) {
+	idx := bytes.Index(b, []byte("\r\n\r\n"))
+	if idx < 0 {
+		return nil, nil, fmt.Errorf("mailer: malformed mime part")
+	}
+	header := make(textproto.MIMEHeader)
+	for _, line := range strings.Split(string(b[:idx]), "\r\n") {
+		k, v, ok := strings.Cut(line, ": ")
+		if ok {
+			header.Add(k, v)
+		}
+	}
+	return header, b[idx+4:], nil
+}
+
+func writeAttachment(mw *multipart.Writer, a Attachment) error {
+	ct := a.ContentType
+	if ct == "" {
+		ct = mime.TypeByExtension(filepath.Ext(a.Filename))
+	}
+	mediaType, params, err := mime.ParseMediaType(ct)
+	if err != nil {
+		mediaType, params = "application/octet-stream", map[string]string{}
+	}
+	params["name"] = a.Filename
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Type", mime.FormatMediaType(mediaType, params))
+	header.Set("Content-Transfer-Encoding", "base64")
+	if a.Inline {
+		header.Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": a.Filename}))
+		if a.ContentID != "" {
+			header.Set("Content-ID", "<"+a.ContentID+">")
+		}
+	} else {
+		header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename}))
+	}
+
+	pw, err := mw.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	return writeBase64Lines(pw, a.Data)
+}
+
+// writeBase64Lines 按 76 字符折行写入 base64
+func writeBase64Lines(w io.Writer, data []byte) error {
+	enc := base64.StdEncoding.EncodeToString(data)
+	for len(enc) > 76 {
+		if _, err := io.WriteString(w, enc[:76]+"\r\n"); err != nil {
+			return err
+		}
+		enc = enc[76:]
+	}
+	_, err := io.WriteString(w, enc+"\r\n")
+	return err
+}
+
+func writeHeader(w *bytes.Buffer, key, value string) {
+	w.WriteString(key)
+	w.WriteString(": ")
+	w.WriteString(value)
+	w.WriteString("\r\n")
+}
+
+func senderDomain(from string) string {
+	if i := strings.LastIndex(from, "@"); i >= 0 {
+		return strings.Trim(from[i+1:], "> ")
+	}
+	return "localhost"
+}
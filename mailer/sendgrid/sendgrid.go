@@ -0,0 +1,174 @@
+package sendgrid
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"time"
+
+	"github.com/jiajia556/tool-box/httpx"
+	"github.com/jiajia556/tool-box/mailer"
+)
+
+const defaultEndpoint = "https://api.sendgrid.com/v3/mail/send"
+
+// Options SendGrid 配置
+type Options struct {
+	APIKey string `json:"api_key"`
+
+	// API 地址，默认 https://api.sendgrid.com/v3/mail/send
+	Endpoint string `json:"endpoint"`
+
+	// 请求超时
+	Timeout time.Duration `json:"timeout"`
+}
+
+// SendGridSender SendGrid Web API 发送实现
+type SendGridSender struct {
+	opts   Options
+	client *httpx.Client
+}
+
+// NewSendGridSender 创建 SendGrid 发送器
+func NewSendGridSender(config any) (mailer.Sender, error) {
+	opts, ok := config.(Options)
+	if !ok {
+		return nil, fmt.Errorf("%w: expect sendgrid.Options", mailer.ErrInvalidConfig)
+	}
+	if opts.APIKey == "" {
+		return nil, fmt.Errorf("%w: sendgrid api key is empty", mailer.ErrInvalidConfig)
+	}
+	if opts.Endpoint == "" {
+		opts.Endpoint = defaultEndpoint
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	return &SendGridSender{
+		opts:   opts,
+		client: httpx.New(httpx.WithTimeout(opts.Timeout)),
+	}, nil
+}
+
+type address struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+type personalization struct {
+	To  []address `json:"to"`
+	Cc  []address `json:"cc,omitempty"`
+	Bcc []address `json:"bcc,omitempty"`
+}
+
+type content struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type attachment struct {
+	Content     string `json:"content"`
+	Filename    string `json:"filename"`
+	Type        string `json:"type,omitempty"`
+	Disposition string `json:"disposition,omitempty"`
+	ContentID   string `json:"content_id,omitempty"`
+}
+
+type payload struct {
+	Personalizations []personalization `json:"personalizations"`
+	From             address           `json:"from"`
+	ReplyTo          *address          `json:"reply_to,omitempty"`
+	Subject          string            `json:"subject"`
+	Content          []content         `json:"content"`
+	Attachments      []attachment      `json:"attachments,omitempty"`
+	Headers          map[string]string `json:"headers,omitempty"`
+}
+
+func (s *SendGridSender) Send(ctx context.Context, msg *mailer.Message) error {
+	p, err := buildPayload(msg)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, http.MethodPost, s.opts.Endpoint,
+		httpx.JSONBody(p),
+		httpx.Header("Authorization", "Bearer "+s.opts.APIKey),
+	)
+	if err != nil {
+		return fmt.Errorf("sendgrid: %w", err)
+	}
+	return nil
+}
+
+func buildPayload(msg *mailer.Message) (*payload, error) {
+	from, err := parseAddress(msg.From)
+	if err != nil {
+		return nil, err
+	}
+
+	per := personalization{}
+	for _, list := range []struct {
+		src []string
+		dst *[]address
+	}{{msg.To, &per.To}, {msg.Cc, &per.Cc}, {msg.Bcc, &per.Bcc}} {
+		for _, raw := range list.src {
+			a, err := parseAddress(raw)
+			if err != nil {
+				return nil, err
+			}
+			*list.dst = append(*list.dst, a)
+		}
+	}
+
+	p := &payload{
+		Personalizations: []personalization{per},
+		From:             from,
+		Subject:          msg.Subject,
+		Headers:          msg.Headers,
+	}
+	if msg.ReplyTo != "" {
+		a, err := parseAddress(msg.ReplyTo)
+		if err != nil {
+			return nil, err
+		}
+		p.ReplyTo = &a
+	}
+	if msg.Text != "" {
+		p.Content = append(p.Content, content{Type: "text/plain", Value: msg.Text})
+	}
+	if msg.HTML != "" {
+		p.Content = append(p.Content, content{Type: "text/html", Value: msg.HTML})
+	}
+	for _, a := range msg.Attachments {
+		att := attachment{
+			Content:     base64.StdEncoding.EncodeToString(a.Data),
+			Filename:    a.Filename,
+			Type:        a.ContentType,
+			Disposition: "attachment",
+		}
+		if a.Inline {
+			att.Disposition = "inline"
+			att.ContentID = a.ContentID
+		}
+		p.Attachments = append(p.Attachments, att)
+	}
+	return p, nil
+}
+
+func parseAddress(raw string) (address, error) {
+	a, err := mail.ParseAddress(raw)
+	if err != nil {
+		return address{}, fmt.Errorf("sendgrid: invalid address %q: %w", raw, err)
+	}
+	return address{Email: a.Address, Name: a.Name}, nil
+}
+
+func (s *SendGridSender) Close() error {
+	return nil
+}
+
+func init() {
+	mailer.Register("sendgrid", NewSendGridSender)
+}
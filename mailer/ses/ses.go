@@ -0,0 +1,131 @@
+package ses
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+
+	"github.com/jiajia556/tool-box/mailer"
+)
+
+// Options Amazon SES 配置
+type Options struct {
+	Region          string `json:"region"`
+	AccessKeyID     string `json:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key"`
+	SessionToken    string `json:"session_token"`
+
+	// API 地址，默认 https://email.<region>.amazonaws.com
+	Endpoint string `json:"endpoint"`
+
+	// 请求超时
+	Timeout time.Duration `json:"timeout"`
+}
+
+// SESSender Amazon SES v2 API 发送实现（以原始 MIME 邮件发送，支持附件与内嵌资源）
+type SESSender struct {
+	opts   Options
+	hc     *http.Client
+	signer *v4.Signer
+}
+
+// NewSESSender 创建 SES 发送器
+func NewSESSender(config any) (mailer.Sender, error) {
+	opts, ok := config.(Options)
+	if !ok {
+		return nil, fmt.Errorf("%w: expect ses.Options", mailer.ErrInvalidConfig)
+	}
+	if opts.Region == "" {
+		return nil, fmt.Errorf("%w: ses region is empty", mailer.ErrInvalidConfig)
+	}
+	if opts.Endpoint == "" {
+		opts.Endpoint = "https://email." + opts.Region + ".amazonaws.com"
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	return &SESSender{
+		opts:   opts,
+		hc:     &http.Client{Timeout: opts.Timeout},
+		signer: v4.NewSigner(),
+	}, nil
+}
+
+type destination struct {
+	ToAddresses  []string `json:"ToAddresses,omitempty"`
+	CcAddresses  []string `json:"CcAddresses,omitempty"`
+	BccAddresses []string `json:"BccAddresses,omitempty"`
+}
+
+type sendRequest struct {
+	FromEmailAddress string      `json:"FromEmailAddress"`
+	Destination      destination `json:"Destination"`
+	Content          struct {
+		Raw struct {
+			Data []byte `json:"Data"`
+		} `json:"Raw"`
+	} `json:"Content"`
+}
+
+func (s *SESSender) Send(ctx context.Context, msg *mailer.Message) error {
+	raw, err := mailer.BuildMIME(msg)
+	if err != nil {
+		return err
+	}
+
+	var reqBody sendRequest
+	reqBody.FromEmailAddress = msg.From
+	reqBody.Destination = destination{ToAddresses: msg.To, CcAddresses: msg.Cc, BccAddresses: msg.Bcc}
+	reqBody.Content.Raw.Data = raw
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.opts.Endpoint+"/v2/email/outbound-emails", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	sum := sha256.Sum256(body)
+	creds := aws.Credentials{
+		AccessKeyID:     s.opts.AccessKeyID,
+		SecretAccessKey: s.opts.SecretAccessKey,
+		SessionToken:    s.opts.SessionToken,
+	}
+	if err := s.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(sum[:]), "ses", s.opts.Region, time.Now()); err != nil {
+		return fmt.Errorf("ses: sign request: %w", err)
+	}
+
+	resp, err := s.hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("ses: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+		return fmt.Errorf("ses: status %d: %s", resp.StatusCode, string(b))
+	}
+	return nil
+}
+
+func (s *SESSender) Close() error {
+	s.hc.CloseIdleConnections()
+	return nil
+}
+
+func init() {
+	mailer.Register("ses", NewSESSender)
+}
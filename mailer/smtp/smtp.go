@@ -0,0 +1,159 @@
+package smtp
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/jiajia556/tool-box/mailer"
+)
+
+// Options SMTP 配置
+type Options struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+
+	// 使用隐式 TLS（通常为 465 端口）；为 false 时若服务端支持则自动 STARTTLS
+	TLS bool `json:"tls"`
+
+	// 跳过证书校验（仅用于测试环境）
+	InsecureSkipVerify bool `json:"insecure_skip_verify"`
+
+	// 连接超时
+	Timeout time.Duration `json:"timeout"`
+}
+
+// SMTPSender SMTP 发送实现，每封邮件使用独立连接
+type SMTPSender struct {
+	opts Options
+}
+
+// NewSMTPSender 创建 SMTP 发送器
+func NewSMTPSender(config any) (mailer.Sender, error) {
+	opts, ok := config.(Options)
+	if !ok {
+		return nil, fmt.Errorf("%w: expect smtp.Options", mailer.ErrInvalidConfig)
+	}
+	if opts.Host == "" {
+		return nil, fmt.Errorf("%w: smtp host is empty", mailer.ErrInvalidConfig)
+	}
+	if opts.Port == 0 {
+		opts.Port = 25
+		if opts.TLS {
+			opts.Port = 465
+		}
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	return &SMTPSender{opts: opts}, nil
+}
+
+func (s *SMTPSender) Send(ctx context.Context, msg *mailer.Message) error {
+	data, err := mailer.BuildMIME(msg)
+	if err != nil {
+		return err
+	}
+
+	from, err := mail.ParseAddress(msg.From)
+	if err != nil {
+		return fmt.Errorf("smtp: invalid from address: %w", err)
+	}
+
+	client, err := s.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if !s.opts.TLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(s.tlsConfig()); err != nil {
+				return fmt.Errorf("smtp: starttls: %w", err)
+			}
+		}
+	}
+
+	if s.opts.Username != "" {
+		auth := smtp.PlainAuth("", s.opts.Username, s.opts.Password, s.opts.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("smtp: auth: %w", err)
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("smtp: mail from: %w", err)
+	}
+	for _, rcpt := range msg.Recipients() {
+		addr, err := mail.ParseAddress(rcpt)
+		if err != nil {
+			return fmt.Errorf("smtp: invalid recipient %q: %w", rcpt, err)
+		}
+		if err := client.Rcpt(addr.Address); err != nil {
+			return fmt.Errorf("smtp: rcpt to %s: %w", addr.Address, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp: data: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		_ = w.Close()
+		return fmt.Errorf("smtp: write data: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp: close data: %w", err)
+	}
+	return client.Quit()
+}
+
+func (s *SMTPSender) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(s.opts.Host, strconv.Itoa(s.opts.Port))
+	dialer := &net.Dialer{Timeout: s.opts.Timeout}
+
+	var conn net.Conn
+	var err error
+	if s.opts.TLS {
+		td := &tls.Dialer{NetDialer: dialer, Config: s.tlsConfig()}
+		conn, err = td.DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("smtp: dial %s: %w", addr, err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.opts.Host)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("smtp: handshake: %w", err)
+	}
+	return client, nil
+}
+
+func (s *SMTPSender) tlsConfig() *tls.Config {
+	return &tls.Config{
+		ServerName:         s.opts.Host,
+		InsecureSkipVerify: s.opts.InsecureSkipVerify,
+	}
+}
+
+func (s *SMTPSender) Close() error {
+	return nil
+}
+
+func init() {
+	mailer.Register("smtp", NewSMTPSender)
+}
@@ -0,0 +1,72 @@
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// Templates HTML 邮件模板集合，模板与内嵌资源（图片等）来自同一个 fs.FS（通常为 embed.FS）
+type Templates struct {
+	fsys fs.FS
+	tpl  *template.Template
+}
+
+// ParseFS 从 fsys 中按 patterns 解析模板
+func ParseFS(fsys fs.FS, patterns ...string) (*Templates, error) {
+	return ParseFSFuncs(fsys, nil, patterns...)
+}
+
+// ParseFSFuncs 同 ParseFS，并注册模板函数，例如 i18n.Bundle.FuncMap() 提供的 {{t .Lang "key"}}
+func ParseFSFuncs(fsys fs.FS, funcs template.FuncMap, patterns ...string) (*Templates, error) {
+	tpl, err := template.New("").Funcs(funcs).ParseFS(fsys, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("mailer: parse templates: %w", err)
+	}
+	return &Templates{fsys: fsys, tpl: tpl}, nil
+}
+
+// Render 渲染模板为字符串
+func (t *Templates) Render(name string, data any) (string, error) {
+	var buf bytes.Buffer
+	if err := t.tpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", fmt.Errorf("mailer: render template %q: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// Fill 渲染模板到邮件正文。
+// 若存在名为 "<name>.subject" 的模板（例如 {{define "welcome.html.subject"}}），同时渲染为邮件主题。
+func (t *Templates) Fill(msg *Message, name string, data any) error {
+	html, err := t.Render(name, data)
+	if err != nil {
+		return err
+	}
+	msg.HTML = html
+
+	if t.tpl.Lookup(name+".subject") != nil {
+		subject, err := t.Render(name+".subject", data)
+		if err != nil {
+			return err
+		}
+		msg.Subject = strings.TrimSpace(subject)
+	}
+	return nil
+}
+
+// Inline 读取模板 FS 中的资源作为内嵌附件，HTML 中使用 cid:<contentID> 引用
+func (t *Templates) Inline(file, contentID string) (Attachment, error) {
+	data, err := fs.ReadFile(t.fsys, file)
+	if err != nil {
+		return Attachment{}, fmt.Errorf("mailer: read asset %q: %w", file, err)
+	}
+	return Attachment{
+		Filename:  path.Base(file),
+		Data:      data,
+		Inline:    true,
+		ContentID: contentID,
+	}, nil
+}
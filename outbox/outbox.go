@@ -0,0 +1,114 @@
+package outbox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jiajia556/tool-box/mysqlx"
+)
+
+var (
+	ErrNotInTransaction = errors.New("outbox: session is not in transaction")
+	ErrNoPublisher      = errors.New("outbox: publisher is nil")
+)
+
+// 消息状态
+const (
+	StatusPending int8 = 0
+	StatusSent    int8 = 1
+	StatusFailed  int8 = 2
+)
+
+// Message 发件箱消息，与业务数据在同一事务中写入
+type Message struct {
+	Id            uint64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Topic         string     `gorm:"column:topic" json:"topic"`
+	Key           string     `gorm:"column:msg_key" json:"key"`
+	Payload       []byte     `gorm:"column:payload" json:"payload"`
+	Headers       string     `gorm:"column:headers" json:"headers"`
+	Status        int8       `gorm:"column:status" json:"status"`
+	Attempts      int        `gorm:"column:attempts" json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"column:next_attempt_at" json:"next_attempt_at"`
+	LastError     string     `gorm:"column:last_error" json:"last_error"`
+	CreatedAt     time.Time  `gorm:"column:created_at" json:"created_at"`
+	SentAt        *time.Time `gorm:"column:sent_at" json:"sent_at"`
+}
+
+func (m *Message) ID() uint64 {
+	return m.Id
+}
+
+func (m *Message) TableName() string {
+	return "outbox_message"
+}
+
+func (m *Message) GetCreateDDL() string {
+	return "CREATE TABLE IF NOT EXISTS `outbox_message` (" +
+		"`id` bigint unsigned NOT NULL AUTO_INCREMENT," +
+		"`topic` varchar(255) NOT NULL," +
+		"`msg_key` varchar(255) NOT NULL DEFAULT ''," +
+		"`payload` mediumblob NOT NULL," +
+		"`headers` text," +
+		"`status` tinyint NOT NULL DEFAULT 0," +
+		"`attempts` int NOT NULL DEFAULT 0," +
+		"`next_attempt_at` datetime(3) NOT NULL," +
+		"`last_error` varchar(1024) NOT NULL DEFAULT ''," +
+		"`created_at` datetime(3) NOT NULL," +
+		"`sent_at` datetime(3) NULL," +
+		"PRIMARY KEY (`id`)," +
+		"KEY `idx_status_next` (`status`, `next_attempt_at`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4"
+}
+
+// HeaderMap 解析消息头
+func (m *Message) HeaderMap() map[string]string {
+	out := map[string]string{}
+	if m.Headers != "" {
+		_ = json.Unmarshal([]byte(m.Headers), &out)
+	}
+	return out
+}
+
+// Decode 将负载解码到 v
+func (m *Message) Decode(v any) error {
+	return json.Unmarshal(m.Payload, v)
+}
+
+// Write 在调用方事务中写入一条待发送消息，payload 以 JSON 保存。
+// 只有事务提交后消息才对 Relay 可见，保证业务数据与消息同时成功或同时失败。
+func Write(s mysqlx.Session, topic, key string, payload any, headers ...map[string]string) error {
+	if !s.IsInTransaction() {
+		return ErrNotInTransaction
+	}
+
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("outbox: encode payload: %w", err)
+	}
+
+	now := time.Now()
+	msg := &Message{
+		Topic:         topic,
+		Key:           key,
+		Payload:       b,
+		Status:        StatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+	if len(headers) > 0 && len(headers[0]) > 0 {
+		h, err := json.Marshal(headers[0])
+		if err != nil {
+			return fmt.Errorf("outbox: encode headers: %w", err)
+		}
+		msg.Headers = string(h)
+	}
+
+	if mysqlx.AutoCreateTable() {
+		if err := s.CreateTableIfNotExists(msg); err != nil {
+			return err
+		}
+	}
+	return s.DB().Create(msg).Error
+}
@@ -0,0 +1,271 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/jiajia556/tool-box/locker"
+)
+
+// errLockLost 转发锁续期失败
+var errLockLost = errors.New("outbox: relay lock lost")
+
+// Publisher 将消息投递到消息队列；返回错误时消息按退避策略重试
+type Publisher func(ctx context.Context, msg *Message) error
+
+// Config 转发配置
+type Config struct {
+	// 每批读取的消息数
+	BatchSize int
+
+	// 无消息时的轮询间隔
+	PollInterval time.Duration
+
+	// 最多尝试次数，超过后标记为失败
+	MaxAttempts int
+
+	// 重试退避初始值（指数增长，上限 MaxBackoff）
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+
+	// 分布式锁；设置后多个实例中只有持锁者转发，避免重复投递并保持写入顺序（重试中的消息除外）。
+	// 每条消息投递前续期，单条消息的投递耗时应小于 LockTTL
+	Locker  locker.Manager
+	LockKey string
+	LockTTL time.Duration
+
+	// 投递失败回调
+	OnError func(msg *Message, err error)
+}
+
+// Option 选项函数
+type Option func(*Config)
+
+// WithBatchSize 设置每批读取的消息数
+func WithBatchSize(n int) Option {
+	return func(c *Config) {
+		c.BatchSize = n
+	}
+}
+
+// WithPollInterval 设置轮询间隔
+func WithPollInterval(d time.Duration) Option {
+	return func(c *Config) {
+		c.PollInterval = d
+	}
+}
+
+// WithRetry 设置重试策略
+func WithRetry(maxAttempts int, backoff, maxBackoff time.Duration) Option {
+	return func(c *Config) {
+		c.MaxAttempts = maxAttempts
+		c.Backoff = backoff
+		c.MaxBackoff = maxBackoff
+	}
+}
+
+// WithLocker 使用分布式锁协调多个转发实例
+func WithLocker(m locker.Manager, key string, ttl time.Duration) Option {
+	return func(c *Config) {
+		c.Locker = m
+		c.LockKey = key
+		c.LockTTL = ttl
+	}
+}
+
+// WithOnError 设置投递失败回调
+func WithOnError(fn func(msg *Message, err error)) Option {
+	return func(c *Config) {
+		c.OnError = fn
+	}
+}
+
+// DefaultConfig 默认配置
+func DefaultConfig() Config {
+	return Config{
+		BatchSize:    100,
+		PollInterval: time.Second,
+		MaxAttempts:  10,
+		Backoff:      time.Second,
+		MaxBackoff:   5 * time.Minute,
+		LockKey:      "outbox:relay",
+		LockTTL:      30 * time.Second,
+	}
+}
+
+// Relay 读取待发送消息并投递到消息队列
+type Relay struct {
+	db      *gorm.DB
+	publish Publisher
+	config  Config
+}
+
+// NewRelay 创建转发器
+func NewRelay(db *gorm.DB, publish Publisher, opts ...Option) (*Relay, error) {
+	if publish == nil {
+		return nil, ErrNoPublisher
+	}
+
+	config := DefaultConfig()
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+	return &Relay{db: db, publish: publish, config: config}, nil
+}
+
+// Run 持续转发直到 ctx 取消
+func (r *Relay) Run(ctx context.Context) error {
+	var lock locker.Locker
+	defer func() {
+		if lock != nil {
+			lock.Close()
+		}
+	}()
+
+	for {
+		if r.config.Locker != nil {
+			var ok bool
+			lock, ok = r.hold(ctx, lock)
+			if !ok {
+				if err := sleep(ctx, r.config.PollInterval); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		n, err := r.relay(ctx, lock)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if errors.Is(err, errLockLost) {
+			// 无法确认仍持有锁，放弃本批剩余消息，下一轮重新抢锁
+			lock.Close()
+			lock = nil
+		}
+		if err != nil || n < r.config.BatchSize {
+			if err := sleep(ctx, r.config.PollInterval); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// hold 获取或续期转发锁
+func (r *Relay) hold(ctx context.Context, lock locker.Locker) (locker.Locker, bool) {
+	if lock != nil {
+		if err := lock.Refresh(ctx, r.config.LockTTL); err == nil {
+			return lock, true
+		}
+		lock.Close()
+	}
+
+	lock = r.config.Locker.New(r.config.LockKey, locker.WithTTL(r.config.LockTTL))
+	ok, err := lock.TryLock(ctx)
+	if err != nil || !ok {
+		lock.Close()
+		return nil, false
+	}
+	return lock, true
+}
+
+// RelayOnce 转发一批消息，返回读取到的消息数
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	return r.relay(ctx, nil)
+}
+
+// relay 转发一批消息；lock 不为 nil 时每条消息投递前续期，续期失败返回 errLockLost，
+// 避免批次耗时超过 LockTTL 后其他实例取得锁并重复投递
+func (r *Relay) relay(ctx context.Context, lock locker.Locker) (int, error) {
+	var msgs []*Message
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", StatusPending, time.Now()).
+		Order("id").
+		Limit(r.config.BatchSize).
+		Find(&msgs).Error
+	if err != nil {
+		return 0, err
+	}
+
+	for _, msg := range msgs {
+		if lock != nil {
+			if err := lock.Refresh(ctx, r.config.LockTTL); err != nil {
+				return len(msgs), fmt.Errorf("%w: %w", errLockLost, err)
+			}
+		}
+		if err := r.deliver(ctx, msg); err != nil {
+			return len(msgs), err
+		}
+	}
+	return len(msgs), nil
+}
+
+func (r *Relay) deliver(ctx context.Context, msg *Message) error {
+	pubErr := r.publish(ctx, msg)
+	if pubErr == nil {
+		now := time.Now()
+		return r.db.WithContext(ctx).Model(msg).Updates(map[string]any{
+			"status":   StatusSent,
+			"attempts": msg.Attempts + 1,
+			"sent_at":  now,
+		}).Error
+	}
+
+	if r.config.OnError != nil {
+		r.config.OnError(msg, pubErr)
+	}
+
+	attempts := msg.Attempts + 1
+	status := StatusPending
+	if r.config.MaxAttempts > 0 && attempts >= r.config.MaxAttempts {
+		status = StatusFailed
+	}
+	errMsg := pubErr.Error()
+	if len(errMsg) > 1024 {
+		errMsg = errMsg[:1024]
+	}
+	return errors.Join(pubErr, r.db.WithContext(ctx).Model(msg).Updates(map[string]any{
+		"status":          status,
+		"attempts":        attempts,
+		"next_attempt_at": time.Now().Add(r.backoff(attempts)),
+		"last_error":      errMsg,
+	}).Error)
+}
+
+func (r *Relay) backoff(attempts int) time.Duration {
+	d := r.config.Backoff
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if r.config.MaxBackoff > 0 && d >= r.config.MaxBackoff {
+			return r.config.MaxBackoff
+		}
+	}
+	return d
+}
+
+// Purge 删除 before 之前已发送的消息
+func (r *Relay) Purge(ctx context.Context, before time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).
+		Where("status = ? AND sent_at < ?", StatusSent, before).
+		Delete(&Message{})
+	return res.RowsAffected, res.Error
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
@@ -0,0 +1,199 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/jiajia556/tool-box/locker"
+	"github.com/jiajia556/tool-box/locker/memory"
+	"github.com/jiajia556/tool-box/utils"
+)
+
+// fakeDB 只实现 Relay 用到的 SELECT 与 UPDATE：SELECT 返回全部待发送消息，UPDATE 记录更新的消息 id
+type fakeDB struct {
+	mu      sync.Mutex
+	pending []uint64
+	updated []uint64
+}
+
+var fakeColumns = []string{"id", "topic", "msg_key", "payload", "status", "attempts", "next_attempt_at", "created_at"}
+
+func (f *fakeDB) Open(string) (driver.Conn, error)             { return fakeConn{f}, nil }
+func (f *fakeDB) Connect(context.Context) (driver.Conn, error) { return fakeConn{f}, nil }
+func (f *fakeDB) Driver() driver.Driver                        { return f }
+
+type fakeConn struct{ db *fakeDB }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{c.db, query}, nil }
+func (c fakeConn) Close() error                              { return nil }
+func (c fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("fake: transactions not supported")
+}
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if !strings.HasPrefix(s.query, "UPDATE") {
+		return nil, errors.New("fake: unexpected exec " + s.query)
+	}
+	id, _ := args[len(args)-1].(int64)
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	s.db.updated = append(s.db.updated, uint64(id))
+	for i, p := range s.db.pending {
+		if p == uint64(id) {
+			s.db.pending = append(s.db.pending[:i], s.db.pending[i+1:]...)
+			break
+		}
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if !strings.HasPrefix(s.query, "SELECT") {
+		return nil, errors.New("fake: unexpected query " + s.query)
+	}
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return &fakeRows{ids: append([]uint64(nil), s.db.pending...)}, nil
+}
+
+type fakeRows struct {
+	ids []uint64
+	i   int
+}
+
+func (r *fakeRows) Columns() []string { return fakeColumns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.i >= len(r.ids) {
+		return io.EOF
+	}
+	now := time.Now()
+	copy(dest, []driver.Value{int64(r.ids[r.i]), "topic", "", []byte("{}"), int64(StatusPending), int64(0), now, now})
+	r.i++
+	return nil
+}
+
+func newFakeDB(t *testing.T, ids ...uint64) (*fakeDB, *gorm.DB) {
+	f := &fakeDB{pending: ids}
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: sql.OpenDB(f), SkipInitializeWithVersion: true}), &gorm.Config{
+		SkipDefaultTransaction: true,
+		Logger:                 logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+	return f, db
+}
+
+// clockManager 为创建的锁统一设置时间源
+type clockManager struct {
+	locker.Manager
+	clock utils.Clock
+}
+
+func (m clockManager) New(key string, opts ...locker.Option) locker.Locker {
+	return m.Manager.New(key, append(opts, locker.WithClock(m.clock))...)
+}
+
+func TestRelay_RelayOnce(t *testing.T) {
+	f, db := newFakeDB(t, 1, 2, 3)
+
+	var published []uint64
+	r, err := NewRelay(db, func(ctx context.Context, msg *Message) error {
+		published = append(published, msg.Id)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("NewRelay: %v", err)
+	}
+
+	n, err := r.RelayOnce(context.Background())
+	if err != nil || n != 3 {
+		t.Fatalf("RelayOnce = %d, %v; want 3", n, err)
+	}
+	if len(published) != 3 || len(f.updated) != 3 || len(f.pending) != 0 {
+		t.Fatalf("published %v, updated %v, pending %v", published, f.updated, f.pending)
+	}
+}
+
+func TestRelay_RefreshesLockPerMessage(t *testing.T) {
+	const ttl = time.Second
+	_, db := newFakeDB(t, 1, 2, 3, 4)
+
+	clock := utils.NewFakeClock(time.Time{})
+	mm, _ := memory.NewMemoryManager(nil)
+	m := clockManager{mm, clock}
+
+	// 每条消息的投递耗时超过 LockTTL 的一半，整批耗时超过 LockTTL；期间其他实例不能取得锁
+	var stolen bool
+	r, _ := NewRelay(db, func(ctx context.Context, msg *Message) error {
+		clock.Advance(ttl * 6 / 10)
+		other := m.New("outbox:relay", locker.WithTTL(ttl))
+		if ok, _ := other.TryLock(ctx); ok {
+			stolen = true
+		}
+		other.Close()
+		return nil
+	}, WithLocker(m, "outbox:relay", ttl))
+
+	lock, ok := r.hold(context.Background(), nil)
+	if !ok {
+		t.Fatalf("hold: lock not acquired")
+	}
+	defer lock.Close()
+
+	if _, err := r.relay(context.Background(), lock); err != nil {
+		t.Fatalf("relay: %v", err)
+	}
+	if stolen {
+		t.Fatalf("relay lock was taken by another instance during the batch")
+	}
+}
+
+func TestRelay_StopsWhenLockLost(t *testing.T) {
+	f, db := newFakeDB(t, 1, 2, 3)
+
+	mm, _ := memory.NewMemoryManager(nil)
+	var published []uint64
+	r, _ := NewRelay(db, func(ctx context.Context, msg *Message) error {
+		published = append(published, msg.Id)
+		if msg.Id == 1 {
+			// 其他实例强制释放了锁
+			_ = mm.(*memory.MemoryManager).ForceUnlock(ctx, "outbox:relay")
+		}
+		return nil
+	}, WithLocker(mm, "outbox:relay", time.Second))
+
+	lock, ok := r.hold(context.Background(), nil)
+	if !ok {
+		t.Fatalf("hold: lock not acquired")
+	}
+	defer lock.Close()
+
+	if _, err := r.relay(context.Background(), lock); !errors.Is(err, errLockLost) {
+		t.Fatalf("expected errLockLost, got %v", err)
+	}
+	if len(published) != 1 || len(f.pending) != 2 {
+		t.Fatalf("expected delivery to stop after losing the lock, published %v pending %v", published, f.pending)
+	}
+}
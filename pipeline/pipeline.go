@@ -0,0 +1,290 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Pipeline 管理一组通过 channel 连接的处理阶段。
+// 任一阶段返回错误时取消共享的 context，其余阶段随之退出，Wait 返回第一个错误。
+type Pipeline struct {
+	g   *errgroup.Group
+	ctx context.Context
+}
+
+// New 创建 Pipeline
+func New(ctx context.Context) *Pipeline {
+	g, gctx := errgroup.WithContext(ctx)
+	return &Pipeline{g: g, ctx: gctx}
+}
+
+// Context 返回 Pipeline 的 context，出错或父 context 取消后被取消
+func (p *Pipeline) Context() context.Context {
+	return p.ctx
+}
+
+// Go 启动自定义阶段
+func (p *Pipeline) Go(fn func(ctx context.Context) error) {
+	p.g.Go(func() error {
+		return fn(p.ctx)
+	})
+}
+
+// Wait 等待所有阶段结束，返回第一个错误
+func (p *Pipeline) Wait() error {
+	return p.g.Wait()
+}
+
+// send 向 out 发送 v，context 取消时返回 false
+func send[T any](ctx context.Context, out chan<- T, v T) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case out <- v:
+		return true
+	}
+}
+
+// FromSlice 以切片作为数据源
+func FromSlice[T any](p *Pipeline, items []T) <-chan T {
+	out := make(chan T)
+	p.Go(func(ctx context.Context) error {
+		defer close(out)
+		for _, v := range items {
+			if !send(ctx, out, v) {
+				return nil
+			}
+		}
+		return nil
+	})
+	return out
+}
+
+// FromFunc 以生成函数作为数据源，emit 返回 false 表示下游已取消，生成函数应尽快返回
+func FromFunc[T any](p *Pipeline, gen func(ctx context.Context, emit func(T) bool) error) <-chan T {
+	out := make(chan T)
+	p.Go(func(ctx context.Context) error {
+		defer close(out)
+		return gen(ctx, func(v T) bool {
+			return send(ctx, out, v)
+		})
+	})
+	return out
+}
+
+// Map 对每个元素执行 fn。workers > 1 时并发执行，输出顺序不保证与输入一致。
+func Map[In, Out any](p *Pipeline, in <-chan In, workers int, fn func(ctx context.Context, v In) (Out, error)) <-chan Out {
+	if workers < 1 {
+		workers = 1
+	}
+
+	out := make(chan Out)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		p.Go(func(ctx context.Context) error {
+			defer wg.Done()
+			for v := range in {
+				r, err := fn(ctx, v)
+				if err != nil {
+					return err
+				}
+				if !send(ctx, out, r) {
+					return nil
+				}
+			}
+			return nil
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// Filter 仅保留 fn 返回 true 的元素
+func Filter[T any](p *Pipeline, in <-chan T, fn func(ctx context.Context, v T) (bool, error)) <-chan T {
+	out := make(chan T)
+	p.Go(func(ctx context.Context) error {
+		defer close(out)
+		for v := range in {
+			keep, err := fn(ctx, v)
+			if err != nil {
+				return err
+			}
+			if keep && !send(ctx, out, v) {
+				return nil
+			}
+		}
+		return nil
+	})
+	return out
+}
+
+// Batch 将元素按 size 分批；maxWait > 0 时，未满一批但等待超过 maxWait 也会输出
+func Batch[T any](p *Pipeline, in <-chan T, size int, maxWait time.Duration) <-chan []T {
+	if size < 1 {
+		size = 1
+	}
+
+	out := make(chan []T)
+	p.Go(func(ctx context.Context) error {
+		defer close(out)
+
+		var (
+			buf   = make([]T, 0, size)
+			timer *time.Timer
+			tick  <-chan time.Time
+		)
+		flush := func() bool {
+			if timer != nil {
+				timer.Stop()
+				timer, tick = nil, nil
+			}
+			if len(buf) == 0 {
+				return true
+			}
+			batch := buf
+			buf = make([]T, 0, size)
+			return send(ctx, out, batch)
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case v, ok := <-in:
+				if !ok {
+					flush()
+					return nil
+				}
+				buf = append(buf, v)
+				if len(buf) >= size {
+					if !flush() {
+						return nil
+					}
+				} else if maxWait > 0 && timer == nil {
+					timer = time.NewTimer(maxWait)
+					tick = timer.C
+				}
+			case <-tick:
+				timer, tick = nil, nil
+				if !flush() {
+					return nil
+				}
+			}
+		}
+	})
+	return out
+}
+
+// FanOut 将输入轮流分发到 n 个输出
+func FanOut[T any](p *Pipeline, in <-chan T, n int) []<-chan T {
+	if n < 1 {
+		n = 1
+	}
+
+	outs := make([]chan T, n)
+	ro := make([]<-chan T, n)
+	for i := range outs {
+		outs[i] = make(chan T)
+		ro[i] = outs[i]
+	}
+
+	p.Go(func(ctx context.Context) error {
+		defer func() {
+			for _, o := range outs {
+				close(o)
+			}
+		}()
+		i := 0
+		for v := range in {
+			if !send(ctx, outs[i], v) {
+				return nil
+			}
+			i = (i + 1) % n
+		}
+		return nil
+	})
+	return ro
+}
+
+// FanIn 合并多个输入
+func FanIn[T any](p *Pipeline, ins ...<-chan T) <-chan T {
+	out := make(chan T)
+	var wg sync.WaitGroup
+	wg.Add(len(ins))
+	for _, in := range ins {
+		p.Go(func(ctx context.Context) error {
+			defer wg.Done()
+			for v := range in {
+				if !send(ctx, out, v) {
+					return nil
+				}
+			}
+			return nil
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// Throttle 限制每秒通过的元素数
+func Throttle[T any](p *Pipeline, in <-chan T, perSecond float64) <-chan T {
+	out := make(chan T)
+	p.Go(func(ctx context.Context) error {
+		defer close(out)
+		if perSecond <= 0 {
+			for v := range in {
+				if !send(ctx, out, v) {
+					return nil
+				}
+			}
+			return nil
+		}
+
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / perSecond))
+		defer ticker.Stop()
+		for v := range in {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+			if !send(ctx, out, v) {
+				return nil
+			}
+		}
+		return nil
+	})
+	return out
+}
+
+// Sink 消费输出
+func Sink[T any](p *Pipeline, in <-chan T, fn func(ctx context.Context, v T) error) {
+	p.Go(func(ctx context.Context) error {
+		for v := range in {
+			if err := fn(ctx, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Collect 收集全部输出，需在 Wait 之后读取结果
+func Collect[T any](p *Pipeline, in <-chan T) *[]T {
+	out := new([]T)
+	Sink(p, in, func(ctx context.Context, v T) error {
+		*out = append(*out, v)
+		return nil
+	})
+	return out
+}
@@ -0,0 +1,57 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestPipeline(t *testing.T) {
+	p := New(context.Background())
+
+	src := FromSlice(p, []int{1, 2, 3, 4, 5, 6, 7})
+	even := Filter(p, src, func(ctx context.Context, v int) (bool, error) { return v%2 == 0, nil })
+	squared := Map(p, even, 2, func(ctx context.Context, v int) (int, error) { return v * v, nil })
+	batches := Batch(p, squared, 2, 10*time.Millisecond)
+	got := Collect(p, batches)
+
+	if err := p.Wait(); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+
+	var flat []int
+	for _, b := range *got {
+		flat = append(flat, b...)
+	}
+	sort.Ints(flat)
+	if len(flat) != 3 || flat[0] != 4 || flat[1] != 16 || flat[2] != 36 {
+		t.Fatalf("unexpected result %v", flat)
+	}
+}
+
+func TestPipeline_ErrorCancels(t *testing.T) {
+	p := New(context.Background())
+	boom := errors.New("boom")
+
+	src := FromFunc(p, func(ctx context.Context, emit func(int) bool) error {
+		for i := 0; ; i++ {
+			if !emit(i) {
+				return nil
+			}
+		}
+	})
+	outs := FanOut(p, src, 3)
+	merged := FanIn(p, outs...)
+	Sink(p, merged, func(ctx context.Context, v int) error {
+		if v == 100 {
+			return boom
+		}
+		return nil
+	})
+
+	if err := p.Wait(); !errors.Is(err, boom) {
+		t.Fatalf("expected boom, got %v", err)
+	}
+}
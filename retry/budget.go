@@ -0,0 +1,68 @@
+package retry
+
+import (
+	"sync"
+	"time"
+)
+
+// Budget 重试预算：限制重试请求占总请求的比例，避免下游故障时重试放大流量。
+// 每次调用存入 ratio 个令牌，每次重试取出 1 个；另外每秒保底允许 minPerSecond 次重试。
+type Budget struct {
+	ratio        float64
+	minPerSecond float64
+	max          float64
+
+	mu     sync.Mutex
+	tokens float64
+	floor  float64
+	last   time.Time
+}
+
+// NewBudget 创建重试预算，例如 NewBudget(0.1, 10) 表示重试不超过请求量的 10%，且每秒至少允许 10 次重试
+func NewBudget(ratio float64, minPerSecond int) *Budget {
+	// 积累的令牌上限，避免长时间健康后突发大量重试
+	max := float64(minPerSecond)
+	if max < 10 {
+		max = 10
+	}
+	return &Budget{
+		ratio:        ratio,
+		minPerSecond: float64(minPerSecond),
+		max:          max,
+		floor:        float64(minPerSecond),
+		last:         time.Now(),
+	}
+}
+
+func (b *Budget) deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens += b.ratio
+	if b.tokens > b.max {
+		b.tokens = b.max
+	}
+}
+
+func (b *Budget) withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// 保底额度按时间补充
+	now := time.Now()
+	b.floor += now.Sub(b.last).Seconds() * b.minPerSecond
+	if b.floor > b.minPerSecond {
+		b.floor = b.minPerSecond
+	}
+	b.last = now
+
+	switch {
+	case b.tokens >= 1:
+		b.tokens--
+		return true
+	case b.floor >= 1:
+		b.floor--
+		return true
+	}
+	return false
+}
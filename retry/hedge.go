@@ -0,0 +1,69 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// Hedge 对冲请求：先发起一次调用，每经过 delay 仍未返回则再并发发起一次，最多 max 个并发调用。
+// 返回最先成功的结果并取消其余调用；全部失败时返回最后一个错误。
+// fn 必须是幂等的。
+func Hedge[T any](ctx context.Context, delay time.Duration, max int, fn func(ctx context.Context) (T, error)) (T, error) {
+	if max < 1 {
+		max = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		v   T
+		err error
+	}
+	results := make(chan result, max)
+	launch := func() {
+		go func() {
+			v, err := fn(ctx)
+			results <- result{v: v, err: err}
+		}()
+	}
+
+	launched, done := 1, 0
+	launch()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	var zero T
+	var lastErr error
+	for {
+		select {
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		case <-timer.C:
+			if launched < max {
+				launched++
+				launch()
+				timer.Reset(delay)
+			}
+		case r := <-results:
+			done++
+			if r.err == nil {
+				return r.v, nil
+			}
+			lastErr = r.err
+			if IsPermanent(r.err) {
+				return zero, r.err
+			}
+			if done == launched {
+				if launched >= max {
+					return zero, lastErr
+				}
+				// 当前全部失败，立即发起下一次
+				launched++
+				launch()
+				timer.Reset(delay)
+			}
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+var (
+	ErrBudgetExhausted = errors.New("retry: retry budget exhausted")
+)
+
+// BackoffFunc 返回第 attempt 次重试前的等待时间（attempt 从 1 开始）
+type BackoffFunc func(attempt int) time.Duration
+
+// Constant 固定间隔
+func Constant(d time.Duration) BackoffFunc {
+	return func(int) time.Duration {
+		return d
+	}
+}
+
+// Exponential 指数退避：base * 2^(attempt-1)，不超过 max；jitter 为随机抖动比例（0~1）
+func Exponential(base, max time.Duration, jitter float64) BackoffFunc {
+	return func(attempt int) time.Duration {
+		d := base
+		for i := 1; i < attempt; i++ {
+			d *= 2
+			if max > 0 && d >= max {
+				d = max
+				break
+			}
+		}
+		if jitter > 0 {
+			delta := float64(d) * jitter
+			d = time.Duration(float64(d) - delta + rand.Float64()*2*delta)
+		}
+		if d < 0 {
+			d = 0
+		}
+		return d
+	}
+}
+
+// Classifier 判断错误是否可重试
+type Classifier func(err error) bool
+
+// permanentError 不可重试的错误
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent 标记错误不可重试，Do 会立即返回原始错误
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent 判断错误是否被标记为不可重试
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// afterError 指定下次重试等待时间的错误（例如 HTTP Retry-After）
+type afterError struct {
+	err   error
+	delay time.Duration
+}
+
+func (e *afterError) Error() string { return e.err.Error() }
+func (e *afterError) Unwrap() error { return e.err }
+
+// After 标记错误可重试，并指定下次重试前的等待时间（覆盖 Backoff）
+func After(err error, delay time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &afterError{err: err, delay: delay}
+}
+
+// Policy 重试策略，可在多个组件间共享（并发安全，Budget 为共享状态）
+type Policy struct {
+	// 最多尝试次数（含首次），<=0 表示不限制（受 MaxElapsed 与 ctx 约束）
+	MaxAttempts int
+
+	// 从首次尝试开始的最长耗时，<=0 表示不限制；退避会被截断到截止时间，并在截止时间做最后一次尝试
+	MaxElapsed time.Duration
+
+	// 退避策略
+	Backoff BackoffFunc
+
+	// 可重试判断，nil 表示除 Permanent 外的错误都可重试
+	Retryable Classifier
+
+	// 重试预算，nil 表示不限制
+	Budget *Budget
+
+	// 每次重试前回调
+	OnRetry func(attempt int, err error, delay time.Duration)
+}
+
+// Option 选项函数
+type Option func(*Policy)
+
+// WithMaxAttempts 设置最多尝试次数
+func WithMaxAttempts(n int) Option {
+	return func(p *Policy) {
+		p.MaxAttempts = n
+	}
+}
+
+// WithMaxElapsed 设置最长耗时
+func WithMaxElapsed(d time.Duration) Option {
+	return func(p *Policy) {
+		p.MaxElapsed = d
+	}
+}
+
+// WithBackoff 设置退避策略
+func WithBackoff(b BackoffFunc) Option {
+	return func(p *Policy) {
+		p.Backoff = b
+	}
+}
+
+// WithRetryable 设置可重试判断
+func WithRetryable(c Classifier) Option {
+	return func(p *Policy) {
+		p.Retryable = c
+	}
+}
+
+// WithBudget 设置重试预算
+func WithBudget(b *Budget) Option {
+	return func(p *Policy) {
+		p.Budget = b
+	}
+}
+
+// WithOnRetry 设置重试回调
+func WithOnRetry(fn func(attempt int, err error, delay time.Duration)) Option {
+	return func(p *Policy) {
+		p.OnRetry = fn
+	}
+}
+
+// DefaultPolicy 默认策略：最多 3 次，100ms 起指数退避，上限 2s，20% 抖动
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxAttempts: 3,
+		Backoff:     Exponential(100*time.Millisecond, 2*time.Second, 0.2),
+	}
+}
+
+// New 创建重试策略
+func New(opts ...Option) *Policy {
+	p := DefaultPolicy()
+	for _, opt := range opts {
+		opt(&p)
+	}
+	return &p
+}
+
+// Do 按策略执行 fn，直到成功、遇到不可重试错误或超出限制；返回最后一次的错误
+func (p *Policy) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	start := time.Now()
+
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if p.Budget != nil {
+			p.Budget.deposit()
+		}
+		if err == nil {
+			return nil
+		}
+
+		var perm *permanentError
+		if errors.As(err, &perm) {
+			return perm.err
+		}
+		if p.Retryable != nil && !p.Retryable(err) {
+			return err
+		}
+		if p.MaxAttempts > 0 && attempt >= p.MaxAttempts {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		var delay time.Duration
+		var after *afterError
+		if errors.As(err, &after) {
+			delay = after.delay
+		} else if p.Backoff != nil {
+			delay = p.Backoff(attempt)
+		}
+		if p.MaxElapsed > 0 {
+			remaining := p.MaxElapsed - time.Since(start)
+			if remaining <= 0 {
+				return err
+			}
+			if delay > remaining {
+				delay = remaining
+			}
+		}
+
+		if p.Budget != nil && !p.Budget.withdraw() {
+			return fmt.Errorf("%w: %w", ErrBudgetExhausted, err)
+		}
+		if p.OnRetry != nil {
+			p.OnRetry(attempt, err, delay)
+		}
+
+		if delay > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+	}
+}
+
+// Do 泛型版本，返回 fn 的结果
+func Do[T any](ctx context.Context, p *Policy, fn func(ctx context.Context) (T, error)) (T, error) {
+	var out T
+	err := p.Do(ctx, func(ctx context.Context) error {
+		v, err := fn(ctx)
+		if err == nil {
+			out = v
+		}
+		return err
+	})
+	return out, err
+}
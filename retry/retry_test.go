@@ -0,0 +1,95 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPolicy_Do(t *testing.T) {
+	p := New(WithMaxAttempts(4), WithBackoff(Constant(time.Millisecond)))
+
+	calls := 0
+	err := p.Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("temporary")
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf("expected success on 3rd attempt, got err=%v calls=%d", err, calls)
+	}
+
+	fatal := errors.New("fatal")
+	calls = 0
+	err = p.Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		return Permanent(fatal)
+	})
+	if err != fatal || calls != 1 {
+		t.Fatalf("expected permanent error without retry, got err=%v calls=%d", err, calls)
+	}
+
+	calls = 0
+	v, err := Do(context.Background(), p, func(ctx context.Context) (int, error) {
+		calls++
+		return 0, errors.New("always")
+	})
+	if err == nil || v != 0 || calls != 4 {
+		t.Fatalf("expected 4 attempts, got err=%v calls=%d", err, calls)
+	}
+}
+
+func TestPolicy_MaxElapsed(t *testing.T) {
+	p := New(WithMaxAttempts(0), WithMaxElapsed(25*time.Millisecond), WithBackoff(Constant(time.Hour)))
+
+	start := time.Now()
+	calls := 0
+	err := p.Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		if calls < 2 {
+			return errors.New("busy")
+		}
+		return nil
+	})
+	// 退避被截断到截止时间，并在截止时间做最后一次尝试
+	if err != nil || calls != 2 {
+		t.Fatalf("expected final attempt at deadline to succeed, got err=%v calls=%d", err, calls)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("backoff not capped by MaxElapsed, took %v", elapsed)
+	}
+}
+
+func TestPolicy_Budget(t *testing.T) {
+	p := New(WithMaxAttempts(10), WithBackoff(Constant(0)), WithBudget(NewBudget(0.1, 0)))
+
+	err := p.Do(context.Background(), func(ctx context.Context) error {
+		return errors.New("down")
+	})
+	if !errors.Is(err, ErrBudgetExhausted) {
+		t.Fatalf("expected ErrBudgetExhausted, got %v", err)
+	}
+}
+
+func TestHedge(t *testing.T) {
+	var calls int32
+	v, err := Hedge(context.Background(), 10*time.Millisecond, 3, func(ctx context.Context) (int32, error) {
+		n := atomic.AddInt32(&calls, 1)
+		if n == 1 {
+			// 第一次调用很慢，由对冲请求先返回
+			select {
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			case <-time.After(time.Second):
+			}
+		}
+		return n, nil
+	})
+	if err != nil || v != 2 {
+		t.Fatalf("expected hedged call to win, got v=%d err=%v", v, err)
+	}
+}
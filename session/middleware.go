@@ -0,0 +1,56 @@
+package session
+
+import (
+	"net/http"
+)
+
+const (
+	csrfHeader = "X-CSRF-Token"
+	csrfForm   = "csrf_token"
+)
+
+// Middleware 返回 net/http 中间件：请求开始时加载会话并写入 context，处理结束后保存会话。
+//
+// 注意：会话在 handler 返回后才写回缓存；如需在 handler 内部立即持久化，可手动调用 Save。
+func (m *Manager) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, err := m.Start(w, r)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), s)))
+
+		_ = s.Save()
+	})
+}
+
+// CSRFMiddleware 返回 CSRF 校验中间件，需放在 Middleware 之后。
+// 对非安全方法（POST/PUT/PATCH/DELETE 等）校验请求头 X-CSRF-Token 或表单字段 csrf_token。
+func CSRFMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		s, ok := FromContext(r.Context())
+		if !ok {
+			http.Error(w, ErrInvalidCSRF.Error(), http.StatusForbidden)
+			return
+		}
+
+		token := r.Header.Get(csrfHeader)
+		if token == "" {
+			token = r.PostFormValue(csrfForm)
+		}
+		if !s.VerifyCSRF(token) {
+			http.Error(w, ErrInvalidCSRF.Error(), http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,467 @@
+package session
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jiajia556/tool-box/cache"
+	"github.com/jiajia556/tool-box/utils"
+)
+
+var (
+	ErrNoStore      = errors.New("session: cache store is nil")
+	ErrNotFound     = errors.New("session: key not found")
+	ErrDestroyed    = errors.New("session: session destroyed")
+	ErrInvalidCSRF  = errors.New("session: invalid csrf token")
+	ErrTypeMismatch = errors.New("session: type mismatch")
+)
+
+const (
+	idLength      = 40
+	csrfKey       = "__csrf_token"
+	csrfLength    = 32
+	defaultName   = "session_id"
+	defaultAge    = 30 * time.Minute
+	defaultPrefix = "session:"
+)
+
+// Config 会话配置
+type Config struct {
+	// Cookie 名称
+	CookieName string
+
+	// 令牌请求头名称（非空时优先从请求头读取会话 ID，适用于无 Cookie 的 API 客户端）
+	HeaderName string
+
+	// 缓存 key 前缀
+	KeyPrefix string
+
+	// 会话有效期
+	MaxAge time.Duration
+
+	// 是否滚动过期（每次请求都刷新有效期）
+	Rolling bool
+
+	// Cookie 属性
+	Path     string
+	Domain   string
+	Secure   bool
+	HttpOnly bool
+	SameSite http.SameSite
+}
+
+// Option 选项函数
+type Option func(*Config)
+
+// WithCookieName 设置 Cookie 名称
+func WithCookieName(name string) Option {
+	return func(c *Config) {
+		c.CookieName = name
+	}
+}
+
+// WithHeaderName 设置令牌请求头名称
+func WithHeaderName(name string) Option {
+	return func(c *Config) {
+		c.HeaderName = name
+	}
+}
+
+// WithKeyPrefix 设置缓存 key 前缀
+func WithKeyPrefix(prefix string) Option {
+	return func(c *Config) {
+		c.KeyPrefix = prefix
+	}
+}
+
+// WithMaxAge 设置会话有效期
+func WithMaxAge(maxAge time.Duration) Option {
+	return func(c *Config) {
+		c.MaxAge = maxAge
+	}
+}
+
+// WithRolling 设置是否滚动过期
+func WithRolling(rolling bool) Option {
+	return func(c *Config) {
+		c.Rolling = rolling
+	}
+}
+
+// WithCookie 设置 Cookie 属性
+func WithCookie(path, domain string, secure, httpOnly bool, sameSite http.SameSite) Option {
+	return func(c *Config) {
+		c.Path = path
+		c.Domain = domain
+		c.Secure = secure
+		c.HttpOnly = httpOnly
+		c.SameSite = sameSite
+	}
+}
+
+// DefaultConfig 默认配置
+func DefaultConfig() Config {
+	return Config{
+		CookieName: defaultName,
+		KeyPrefix:  defaultPrefix,
+		MaxAge:     defaultAge,
+		Rolling:    true,
+		Path:       "/",
+		HttpOnly:   true,
+		SameSite:   http.SameSiteLaxMode,
+	}
+}
+
+// Manager 会话管理器，会话数据保存在任意 cache 适配器中。
+type Manager struct {
+	store  cache.Cache
+	config Config
+}
+
+// NewManager 创建会话管理器
+func NewManager(store cache.Cache, opts ...Option) *Manager {
+	config := DefaultConfig()
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.CookieName == "" {
+		config.CookieName = defaultName
+	}
+	if config.MaxAge <= 0 {
+		config.MaxAge = defaultAge
+	}
+	return &Manager{store: store, config: config}
+}
+
+// Config 返回管理器配置
+func (m *Manager) Config() Config {
+	return m.config
+}
+
+// Session 单个会话
+type Session struct {
+	mu        sync.RWMutex
+	manager   *Manager
+	id        string
+	values    map[string]any
+	isNew     bool
+	dirty     bool
+	destroyed bool
+}
+
+// Start 从请求中加载会话，不存在或已过期时创建新会话，并写回 Cookie。
+func (m *Manager) Start(w http.ResponseWriter, r *http.Request) (*Session, error) {
+	if m.store == nil {
+		return nil, ErrNoStore
+	}
+
+	if id := m.idFromRequest(r); id != "" {
+		if s, ok := m.load(id); ok {
+			if m.config.Rolling {
+				s.dirty = true
+				m.setCookie(w, s.id)
+			}
+			return s, nil
+		}
+	}
+
+	s, err := m.newSession()
+	if err != nil {
+		return nil, err
+	}
+	m.setCookie(w, s.id)
+	return s, nil
+}
+
+// Load 按会话 ID 加载会话（不写 Cookie），适用于 WebSocket 等场景。
+func (m *Manager) Load(id string) (*Session, bool) {
+	if m.store == nil || id == "" {
+		return nil, false
+	}
+	return m.load(id)
+}
+
+func (m *Manager) load(id string) (*Session, bool) {
+	v, err := m.store.Get(m.key(id))
+	if err != nil {
+		return nil, false
+	}
+
+	values, err := toValues(v)
+	if err != nil {
+		return nil, false
+	}
+
+	return &Session{
+		manager: m,
+		id:      id,
+		values:  values,
+	}, true
+}
+
+func (m *Manager) newSession() (*Session, error) {
+	id, err := utils.GetRandString(idLength)
+	if err != nil {
+		return nil, fmt.Errorf("session: generate id: %w", err)
+	}
+	return &Session{
+		manager: m,
+		id:      id,
+		values:  make(map[string]any),
+		isNew:   true,
+		dirty:   true,
+	}, nil
+}
+
+func (m *Manager) idFromRequest(r *http.Request) string {
+	if m.config.HeaderName != "" {
+		if id := r.Header.Get(m.config.HeaderName); id != "" {
+			return id
+		}
+	}
+	if c, err := r.Cookie(m.config.CookieName); err == nil {
+		return c.Value
+	}
+	return ""
+}
+
+func (m *Manager) key(id string) string {
+	return m.config.KeyPrefix + id
+}
+
+func (m *Manager) setCookie(w http.ResponseWriter, id string) {
+	if w == nil {
+		return
+	}
+	if m.config.HeaderName != "" {
+		w.Header().Set(m.config.HeaderName, id)
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     m.config.CookieName,
+		Value:    id,
+		Path:     m.config.Path,
+		Domain:   m.config.Domain,
+		MaxAge:   int(m.config.MaxAge / time.Second),
+		Expires:  time.Now().Add(m.config.MaxAge),
+		Secure:   m.config.Secure,
+		HttpOnly: m.config.HttpOnly,
+		SameSite: m.config.SameSite,
+	})
+}
+
+func (m *Manager) clearCookie(w http.ResponseWriter) {
+	if w == nil {
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     m.config.CookieName,
+		Value:    "",
+		Path:     m.config.Path,
+		Domain:   m.config.Domain,
+		MaxAge:   -1,
+		Expires:  time.Unix(0, 0),
+		Secure:   m.config.Secure,
+		HttpOnly: m.config.HttpOnly,
+		SameSite: m.config.SameSite,
+	})
+}
+
+// ID 返回会话 ID
+func (s *Session) ID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.id
+}
+
+// IsNew 是否为本次请求新建的会话
+func (s *Session) IsNew() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.isNew
+}
+
+// Get 获取会话值
+func (s *Session) Get(key string) (any, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.values[key]
+	return v, ok
+}
+
+// Set 设置会话值
+func (s *Session) Set(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+	s.dirty = true
+}
+
+// Delete 删除会话值
+func (s *Session) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+	s.dirty = true
+}
+
+// Clear 清空会话值（保留会话 ID）
+func (s *Session) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values = make(map[string]any)
+	s.dirty = true
+}
+
+// Save 将会话写回缓存；未修改且非滚动过期时不写入。
+func (s *Session) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.destroyed {
+		return ErrDestroyed
+	}
+	if !s.dirty {
+		return nil
+	}
+
+	s.manager.store.Set(s.manager.key(s.id), s.values, s.manager.config.MaxAge)
+	s.dirty = false
+	s.isNew = false
+	return nil
+}
+
+// Destroy 销毁会话并清除 Cookie
+func (s *Session) Destroy(w http.ResponseWriter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.manager.store.Delete(s.manager.key(s.id))
+	s.manager.clearCookie(w)
+	s.values = make(map[string]any)
+	s.destroyed = true
+	return nil
+}
+
+// Regenerate 更换会话 ID 并保留数据，登录等权限变化后应调用以防止会话固定攻击。
+func (s *Session) Regenerate(w http.ResponseWriter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.destroyed {
+		return ErrDestroyed
+	}
+
+	id, err := utils.GetRandString(idLength)
+	if err != nil {
+		return fmt.Errorf("session: generate id: %w", err)
+	}
+
+	s.manager.store.Delete(s.manager.key(s.id))
+	s.id = id
+	s.dirty = true
+	s.manager.setCookie(w, id)
+	return nil
+}
+
+// CSRFToken 返回会话绑定的 CSRF 令牌，不存在时生成。
+func (s *Session) CSRFToken() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if token, ok := s.values[csrfKey].(string); ok && token != "" {
+		return token, nil
+	}
+
+	token, err := utils.GetRandString(csrfLength)
+	if err != nil {
+		return "", fmt.Errorf("session: generate csrf token: %w", err)
+	}
+	s.values[csrfKey] = token
+	s.dirty = true
+	return token, nil
+}
+
+// VerifyCSRF 校验 CSRF 令牌（常量时间比较）
+func (s *Session) VerifyCSRF(token string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	expected, ok := s.values[csrfKey].(string)
+	if !ok || expected == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(token)) == 1
+}
+
+// Get 以类型 T 读取会话值。
+// 会话数据经过缓存序列化后结构体会变为 map，这里通过 JSON 重新解码到 T。
+func Get[T any](s *Session, key string) (T, error) {
+	var zero T
+	v, ok := s.Get(key)
+	if !ok {
+		return zero, ErrNotFound
+	}
+
+	if tv, ok := v.(T); ok {
+		return tv, nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return zero, fmt.Errorf("%w: %v", ErrTypeMismatch, err)
+	}
+	var out T
+	if err := json.Unmarshal(b, &out); err != nil {
+		return zero, fmt.Errorf("%w: %v", ErrTypeMismatch, err)
+	}
+	return out, nil
+}
+
+// Set 以类型 T 写入会话值
+func Set[T any](s *Session, key string, value T) {
+	s.Set(key, value)
+}
+
+type sessionKey struct{}
+
+// NewContext 将会话写入 context
+func NewContext(ctx context.Context, s *Session) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, sessionKey{}, s)
+}
+
+// FromContext 获取 context 中的会话
+func FromContext(ctx context.Context) (*Session, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	s, ok := ctx.Value(sessionKey{}).(*Session)
+	return s, ok
+}
+
+func toValues(v any) (map[string]any, error) {
+	switch m := v.(type) {
+	case map[string]any:
+		return m, nil
+	case nil:
+		return make(map[string]any), nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]any)
+	if err := json.Unmarshal(b, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
@@ -0,0 +1,87 @@
+package session
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jiajia556/tool-box/cache/memory"
+)
+
+type profile struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func TestManager_RoundTrip(t *testing.T) {
+	m := NewManager(memory.NewMemoryCache())
+
+	var sid string
+	h := m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, ok := FromContext(r.Context())
+		if !ok {
+			t.Fatalf("expected session in context")
+		}
+		if r.URL.Path == "/set" {
+			Set(s, "profile", profile{Name: "tom", Age: 18})
+			return
+		}
+		p, err := Get[profile](s, "profile")
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if p.Name != "tom" || p.Age != 18 {
+			t.Fatalf("unexpected profile %+v", p)
+		}
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/set", nil))
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == defaultName {
+			sid = c.Value
+		}
+	}
+	if sid == "" {
+		t.Fatalf("expected session cookie")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/get", nil)
+	req.AddCookie(&http.Cookie{Name: defaultName, Value: sid})
+	h.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestCSRFMiddleware(t *testing.T) {
+	m := NewManager(memory.NewMemoryCache())
+
+	var token string
+	h := m.Middleware(CSRFMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, _ := FromContext(r.Context())
+		token, _ = s.CSRFToken()
+	})))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	cookies := rec.Result().Cookies()
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 without token, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", nil)
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	req.Header.Set(csrfHeader, token)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 with token, got %d", rec.Code)
+	}
+}
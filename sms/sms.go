@@ -0,0 +1,174 @@
+package sms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	ErrNoGlobal      = errors.New("sms: global instance is nil")
+	ErrInvalidConfig = errors.New("sms: invalid config")
+	ErrNoPhone       = errors.New("sms: message has no phone number")
+	ErrTooFrequent   = errors.New("sms: send too frequently")
+	ErrDailyLimit    = errors.New("sms: daily limit exceeded")
+	ErrCodeInvalid   = errors.New("sms: verification code invalid")
+	ErrCodeExpired   = errors.New("sms: verification code expired")
+)
+
+// Param 模板参数。
+// 不同服务商对参数的要求不同：阿里云按名称填充（JSON 对象），腾讯云按顺序填充（数组），
+// 因此这里使用有序的键值对，两种方式都能表达。
+type Param struct {
+	Key   string
+	Value string
+}
+
+// Message 短信
+type Message struct {
+	// 手机号列表
+	Phones []string
+
+	// 模板 ID / 模板 Code
+	Template string
+
+	// 签名，为空时使用适配器配置中的默认签名
+	SignName string
+
+	// 模板参数（有序）
+	Params []Param
+}
+
+// ParamMap 返回参数的 map 形式
+func (m *Message) ParamMap() map[string]string {
+	out := make(map[string]string, len(m.Params))
+	for _, p := range m.Params {
+		out[p.Key] = p.Value
+	}
+	return out
+}
+
+// ParamValues 按顺序返回参数值
+func (m *Message) ParamValues() []string {
+	out := make([]string, 0, len(m.Params))
+	for _, p := range m.Params {
+		out = append(out, p.Value)
+	}
+	return out
+}
+
+// Sender 短信发送适配器接口
+type Sender interface {
+	// 发送短信
+	Send(ctx context.Context, msg *Message) error
+
+	// 关闭（释放资源）
+	Close() error
+}
+
+// ProviderError 服务商返回的业务错误
+type ProviderError struct {
+	Provider  string
+	Code      string
+	Message   string
+	RequestID string
+}
+
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("sms: %s error %s: %s (request_id=%s)", e.Provider, e.Code, e.Message, e.RequestID)
+}
+
+// Instance 适配器工厂函数
+type Instance func(config any) (Sender, error)
+
+var (
+	adaptersMu sync.RWMutex
+	adapters   = make(map[string]Instance)
+)
+
+const (
+	AdapterAliyun  = "aliyun"
+	AdapterTencent = "tencent"
+)
+
+var (
+	globalMu sync.RWMutex
+	global   Sender
+)
+
+// Register 注册短信适配器
+func Register(name string, adapter Instance) {
+	adaptersMu.Lock()
+	defer adaptersMu.Unlock()
+
+	if adapter == nil {
+		panic("sms: Register adapter is nil")
+	}
+	if _, ok := adapters[name]; ok {
+		panic("sms: Register called twice for adapter " + name)
+	}
+	adapters[name] = adapter
+}
+
+// New 按适配器名称创建发送器
+func New(adapterName string, config any) (Sender, error) {
+	adaptersMu.RLock()
+	instanceFunc, ok := adapters[adapterName]
+	adaptersMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("sms: unknown adapter name %q (forgot to import?)", adapterName)
+	}
+	return instanceFunc(config)
+}
+
+// Init 初始化全局发送器，已有全局发送器时替换后关闭旧的
+// 参数 config 为适配器配置：
+// - "aliyun": 接受 aliyun.Options 结构体
+// - "tencent": 接受 tencent.Options 结构体
+func Init(adapterName string, config any) error {
+	s, err := New(adapterName, config)
+	if err != nil {
+		return err
+	}
+
+	globalMu.Lock()
+	old := global
+	global = s
+	globalMu.Unlock()
+
+	if old != nil {
+		return old.Close()
+	}
+	return nil
+}
+
+// Global 返回全局发送器
+func Global() Sender {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+	return global
+}
+
+// Send 使用全局发送器发送短信
+func Send(ctx context.Context, msg *Message) error {
+	s := Global()
+	if s == nil {
+		return ErrNoGlobal
+	}
+	return s.Send(ctx, msg)
+}
+
+// Close 关闭全局发送器
+func Close() error {
+	globalMu.Lock()
+	s := global
+	global = nil
+	globalMu.Unlock()
+
+	if s == nil {
+		return nil
+	}
+	return s.Close()
+}
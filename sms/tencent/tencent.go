@@ -0,0 +1,220 @@
+package tencent
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/jiajia556/tool-box/sms"
+)
+
+const (
+	defaultEndpoint = "https://sms.tencentcloudapi.com"
+	service         = "sms"
+	apiVersion      = "2021-01-11"
+)
+
+// Options 腾讯云短信配置
+type Options struct {
+	SecretID  string `json:"secret_id"`
+	SecretKey string `json:"secret_key"`
+
+	// 短信应用 SdkAppId
+	AppID string `json:"app_id"`
+
+	// 默认签名
+	SignName string `json:"sign_name"`
+
+	// 地域，默认 ap-guangzhou
+	Region string `json:"region"`
+
+	// API 地址，默认 https://sms.tencentcloudapi.com
+	Endpoint string `json:"endpoint"`
+
+	// 请求超时
+	Timeout time.Duration `json:"timeout"`
+}
+
+// TencentSender 腾讯云短信（API 3.0，TC3-HMAC-SHA256 签名）发送实现
+type TencentSender struct {
+	opts Options
+	host string
+	hc   *http.Client
+}
+
+// NewTencentSender 创建腾讯云短信发送器
+func NewTencentSender(config any) (sms.Sender, error) {
+	opts, ok := config.(Options)
+	if !ok {
+		return nil, fmt.Errorf("%w: expect tencent.Options", sms.ErrInvalidConfig)
+	}
+	if opts.SecretID == "" || opts.SecretKey == "" {
+		return nil, fmt.Errorf("%w: tencent secret is empty", sms.ErrInvalidConfig)
+	}
+	if opts.AppID == "" {
+		return nil, fmt.Errorf("%w: tencent sms app id is empty", sms.ErrInvalidConfig)
+	}
+	if opts.Region == "" {
+		opts.Region = "ap-guangzhou"
+	}
+	if opts.Endpoint == "" {
+		opts.Endpoint = defaultEndpoint
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+
+	u, err := url.Parse(opts.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid endpoint: %v", sms.ErrInvalidConfig, err)
+	}
+	return &TencentSender{
+		opts: opts,
+		host: u.Host,
+		hc:   &http.Client{Timeout: opts.Timeout},
+	}, nil
+}
+
+type sendRequest struct {
+	PhoneNumberSet   []string `json:"PhoneNumberSet"`
+	SmsSdkAppId      string   `json:"SmsSdkAppId"`
+	SignName         string   `json:"SignName,omitempty"`
+	TemplateId       string   `json:"TemplateId"`
+	TemplateParamSet []string `json:"TemplateParamSet,omitempty"`
+}
+
+type sendResponse struct {
+	Response struct {
+		Error *struct {
+			Code    string `json:"Code"`
+			Message string `json:"Message"`
+		} `json:"Error"`
+		SendStatusSet []struct {
+			PhoneNumber string `json:"PhoneNumber"`
+			Code        string `json:"Code"`
+			Message     string `json:"Message"`
+		} `json:"SendStatusSet"`
+		RequestId string `json:"RequestId"`
+	} `json:"Response"`
+}
+
+func (s *TencentSender) Send(ctx context.Context, msg *sms.Message) error {
+	if len(msg.Phones) == 0 {
+		return sms.ErrNoPhone
+	}
+
+	signName := msg.SignName
+	if signName == "" {
+		signName = s.opts.SignName
+	}
+
+	body, err := json.Marshal(sendRequest{
+		PhoneNumberSet:   msg.Phones,
+		SmsSdkAppId:      s.opts.AppID,
+		SignName:         signName,
+		TemplateId:       msg.Template,
+		TemplateParamSet: msg.ParamValues(),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.opts.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Host", s.host)
+	req.Header.Set("X-TC-Action", "SendSms")
+	req.Header.Set("X-TC-Version", apiVersion)
+	req.Header.Set("X-TC-Timestamp", strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set("X-TC-Region", s.opts.Region)
+	req.Header.Set("Authorization", s.authorization(body, now))
+
+	resp, err := s.hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("tencent: %w", err)
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return fmt.Errorf("tencent: read response: %w", err)
+	}
+
+	var r sendResponse
+	if err := json.Unmarshal(b, &r); err != nil {
+		return fmt.Errorf("tencent: status %d: %s", resp.StatusCode, string(b))
+	}
+	if e := r.Response.Error; e != nil {
+		return &sms.ProviderError{Provider: "tencent", Code: e.Code, Message: e.Message, RequestID: r.Response.RequestId}
+	}
+	for _, st := range r.Response.SendStatusSet {
+		if st.Code != "Ok" {
+			return &sms.ProviderError{
+				Provider:  "tencent",
+				Code:      st.Code,
+				Message:   st.PhoneNumber + ": " + st.Message,
+				RequestID: r.Response.RequestId,
+			}
+		}
+	}
+	return nil
+}
+
+// authorization 计算 TC3-HMAC-SHA256 签名
+func (s *TencentSender) authorization(body []byte, now time.Time) string {
+	const signedHeaders = "content-type;host"
+	date := now.UTC().Format("2006-01-02")
+	scope := date + "/" + service + "/tc3_request"
+
+	canonicalRequest := "POST\n/\n\n" +
+		"content-type:application/json; charset=utf-8\n" +
+		"host:" + s.host + "\n\n" +
+		signedHeaders + "\n" +
+		sha256Hex(body)
+
+	stringToSign := "TC3-HMAC-SHA256\n" +
+		strconv.FormatInt(now.Unix(), 10) + "\n" +
+		scope + "\n" +
+		sha256Hex([]byte(canonicalRequest))
+
+	secretDate := hmacSHA256([]byte("TC3"+s.opts.SecretKey), date)
+	secretService := hmacSHA256(secretDate, service)
+	secretSigning := hmacSHA256(secretService, "tc3_request")
+	signature := hex.EncodeToString(hmacSHA256(secretSigning, stringToSign))
+
+	return fmt.Sprintf("TC3-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.opts.SecretID, scope, signedHeaders, signature)
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func (s *TencentSender) Close() error {
+	s.hc.CloseIdleConnections()
+	return nil
+}
+
+func init() {
+	sms.Register(sms.AdapterTencent, NewTencentSender)
+}
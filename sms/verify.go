@@ -0,0 +1,286 @@
+package sms
+
+import (
+	"context"
+	"crypto/subtle"
+	"time"
+
+	"github.com/jiajia556/tool-box/cache"
+	"github.com/jiajia556/tool-box/utils"
+)
+
+// VerifyConfig 验证码配置
+type VerifyConfig struct {
+	// 验证码短信模板
+	Template string
+
+	// 验证码在模板中的参数名
+	ParamKey string
+
+	// 验证码有效期，<=0 表示不过期（只在校验成功或尝试次数用尽时作废）
+	CodeTTL time.Duration
+
+	// 同一手机号两次发送的最小间隔
+	Cooldown time.Duration
+
+	// 同一手机号每日最多发送次数，<=0 表示不限制
+	DailyLimit int
+
+	// 单个验证码最多尝试次数，超过后作废
+	MaxAttempts int
+
+	// 缓存 key 前缀
+	KeyPrefix string
+}
+
+// VerifyOption 选项函数
+type VerifyOption func(*VerifyConfig)
+
+// WithCodeTTL 设置验证码有效期
+func WithCodeTTL(ttl time.Duration) VerifyOption {
+	return func(c *VerifyConfig) {
+		c.CodeTTL = ttl
+	}
+}
+
+// WithCooldown 设置发送间隔
+func WithCooldown(d time.Duration) VerifyOption {
+	return func(c *VerifyConfig) {
+		c.Cooldown = d
+	}
+}
+
+// WithDailyLimit 设置每日发送上限
+func WithDailyLimit(n int) VerifyOption {
+	return func(c *VerifyConfig) {
+		c.DailyLimit = n
+	}
+}
+
+// WithMaxAttempts 设置最多尝试次数
+func WithMaxAttempts(n int) VerifyOption {
+	return func(c *VerifyConfig) {
+		c.MaxAttempts = n
+	}
+}
+
+// WithParamKey 设置验证码参数名
+func WithParamKey(key string) VerifyOption {
+	return func(c *VerifyConfig) {
+		c.ParamKey = key
+	}
+}
+
+// WithKeyPrefix 设置缓存 key 前缀
+func WithKeyPrefix(prefix string) VerifyOption {
+	return func(c *VerifyConfig) {
+		c.KeyPrefix = prefix
+	}
+}
+
+// DefaultVerifyConfig 默认配置
+func DefaultVerifyConfig() VerifyConfig {
+	return VerifyConfig{
+		ParamKey:    "code",
+		CodeTTL:     5 * time.Minute,
+		Cooldown:    60 * time.Second,
+		DailyLimit:  10,
+		MaxAttempts: 5,
+		KeyPrefix:   "sms:",
+	}
+}
+
+// Verifier 验证码发送与校验，验证码及频率限制数据保存在缓存中
+type Verifier struct {
+	sender Sender
+	store  cache.Cache
+	config VerifyConfig
+}
+
+type codeRecord struct {
+	Code     string `json:"code"`
+	Attempts int    `json:"attempts"`
+}
+
+// NewVerifier 创建验证码校验器
+func NewVerifier(sender Sender, store cache.Cache, template string, opts ...VerifyOption) *Verifier {
+	config := DefaultVerifyConfig()
+	config.Template = template
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return &Verifier{sender: sender, store: store, config: config}
+}
+
+// SendCode 生成 6 位验证码并发送，受发送间隔与每日上限限制。
+// 发送间隔与每日计数在发送前通过 CompareAndSwap 原子占用，并发请求不会越过限制；发送失败时归还
+func (v *Verifier) SendCode(ctx context.Context, phone string) error {
+	if phone == "" {
+		return ErrNoPhone
+	}
+
+	cooldownKey := v.config.KeyPrefix + "cooldown:" + phone
+	if v.config.Cooldown > 0 {
+		// 相当于 SETNX：key 不存在时才写入
+		ok, err := v.store.CompareAndSwap(cooldownKey, nil, true, v.config.Cooldown)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrTooFrequent
+		}
+	}
+	release := func() {
+		if v.config.Cooldown > 0 {
+			v.store.Delete(cooldownKey)
+		}
+	}
+
+	dailyKey := v.config.KeyPrefix + "daily:" + phone + ":" + time.Now().Format("20060102")
+	if v.config.DailyLimit > 0 {
+		// 计数只需保留到当天结束
+		ok, err := v.incr(dailyKey, 1, v.config.DailyLimit, time.Until(endOfDay(time.Now())))
+		if err != nil || !ok {
+			release()
+			if err != nil {
+				return err
+			}
+			return ErrDailyLimit
+		}
+		prev := release
+		release = func() {
+			prev()
+			_, _ = v.incr(dailyKey, -1, 0, time.Until(endOfDay(time.Now())))
+		}
+	}
+
+	code, err := utils.GetRandCode6()
+	if err != nil {
+		release()
+		return err
+	}
+
+	err = v.sender.Send(ctx, &Message{
+		Phones:   []string{phone},
+		Template: v.config.Template,
+		Params:   []Param{{Key: v.config.ParamKey, Value: code}},
+	})
+	if err != nil {
+		release()
+		return err
+	}
+
+	v.store.Set(v.codeKey(phone), codeRecord{Code: code}, v.config.CodeTTL)
+	return nil
+}
+
+// casRetries CompareAndSwap 冲突时的最多重试次数
+const casRetries = 5
+
+// incr 以 CompareAndSwap 原子地将 key 的计数加 delta；limit > 0 且结果超过 limit 时不写入并返回 false
+func (v *Verifier) incr(key string, delta, limit int, ttl time.Duration) (bool, error) {
+	for i := 0; i < casRetries; i++ {
+		old, err := v.store.Get(key)
+		if err != nil {
+			old = nil
+		}
+		n := toInt(old) + delta
+		if limit > 0 && n > limit {
+			return false, nil
+		}
+		if n < 0 {
+			n = 0
+		}
+		ok, err := v.store.CompareAndSwap(key, old, n, ttl)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	// 冲突持续时按超限处理，宁可拒绝也不越过限制
+	return false, nil
+}
+
+// Verify 校验验证码，成功后验证码立即作废。
+// 每次校验先通过 CompareAndSwap 原子地计入尝试次数再比较验证码，并发猜测不会越过 MaxAttempts
+func (v *Verifier) Verify(phone, code string) error {
+	key := v.codeKey(phone)
+
+	for i := 0; i < casRetries; i++ {
+		raw, err := v.store.Get(key)
+		if err != nil {
+			return ErrCodeExpired
+		}
+		rec, ok := toRecord(raw)
+		if !ok {
+			v.store.Delete(key)
+			return ErrCodeExpired
+		}
+		// 尝试次数用尽的验证码保留到过期，期间一律视为已作废
+		if v.config.MaxAttempts > 0 && rec.Attempts >= v.config.MaxAttempts {
+			return ErrCodeExpired
+		}
+		// 没有过期时间（CodeTTL <= 0）时按不过期写回；key 已被删除时下面的 CompareAndSwap 会失败
+		ttl, ok := v.store.TTL(key)
+		if !ok {
+			ttl = 0
+		}
+
+		next := rec
+		next.Attempts++
+		swapped, err := v.store.CompareAndSwap(key, raw, next, ttl)
+		if err != nil {
+			return err
+		}
+		if !swapped {
+			continue
+		}
+
+		if subtle.ConstantTimeCompare([]byte(rec.Code), []byte(code)) != 1 {
+			return ErrCodeInvalid
+		}
+		// 并发的正确校验只有一个能取走验证码
+		if _, err := v.store.GetDel(key); err != nil {
+			return ErrCodeExpired
+		}
+		return nil
+	}
+	return ErrCodeInvalid
+}
+
+func (v *Verifier) codeKey(phone string) string {
+	return v.config.KeyPrefix + "code:" + phone
+}
+
+func toRecord(v any) (codeRecord, bool) {
+	switch r := v.(type) {
+	case codeRecord:
+		return r, true
+	case map[string]any:
+		code, ok := r["code"].(string)
+		if !ok {
+			return codeRecord{}, false
+		}
+		return codeRecord{Code: code, Attempts: toInt(r["attempts"])}, true
+	}
+	return codeRecord{}, false
+}
+
+func toInt(v any) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	}
+	return 0
+}
+
+func endOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, t.Location())
+}
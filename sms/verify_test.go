@@ -0,0 +1,138 @@
+package sms
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jiajia556/tool-box/cache/memory"
+)
+
+type fakeSender struct {
+	mu    sync.Mutex
+	last  *Message
+	sends int
+}
+
+func (f *fakeSender) Send(ctx context.Context, msg *Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.last = msg
+	f.sends++
+	return nil
+}
+
+func (f *fakeSender) Close() error { return nil }
+
+func TestVerifier(t *testing.T) {
+	s := &fakeSender{}
+	v := NewVerifier(s, memory.NewMemoryCache(), "SMS_001", WithMaxAttempts(2), WithDailyLimit(2), WithCooldown(0))
+
+	if err := v.SendCode(context.Background(), "13800000000"); err != nil {
+		t.Fatalf("SendCode: %v", err)
+	}
+	code := s.last.ParamMap()["code"]
+	if len(code) != 6 {
+		t.Fatalf("unexpected code %q", code)
+	}
+
+	if err := v.Verify("13800000000", "bad"); !errors.Is(err, ErrCodeInvalid) {
+		t.Fatalf("expected ErrCodeInvalid, got %v", err)
+	}
+	if err := v.Verify("13800000000", code); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if err := v.Verify("13800000000", code); !errors.Is(err, ErrCodeExpired) {
+		t.Fatalf("expected code to be consumed, got %v", err)
+	}
+
+	// 达到最大尝试次数后验证码作废
+	_ = v.SendCode(context.Background(), "13800000000")
+	code = s.last.ParamMap()["code"]
+	_ = v.Verify("13800000000", "bad")
+	_ = v.Verify("13800000000", "bad")
+	if err := v.Verify("13800000000", code); !errors.Is(err, ErrCodeExpired) {
+		t.Fatalf("expected ErrCodeExpired after max attempts, got %v", err)
+	}
+
+	if err := v.SendCode(context.Background(), "13800000000"); !errors.Is(err, ErrDailyLimit) {
+		t.Fatalf("expected ErrDailyLimit, got %v", err)
+	}
+}
+
+func TestVerifier_Concurrent(t *testing.T) {
+	const phone, workers = "13800000000", 50
+	run := func(fn func()) {
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				fn()
+			}()
+		}
+		wg.Wait()
+	}
+
+	s := &fakeSender{}
+	v := NewVerifier(s, memory.NewMemoryCache(), "SMS_001", WithMaxAttempts(3), WithDailyLimit(2))
+
+	// 并发发送只有一次能通过发送间隔
+	run(func() { _ = v.SendCode(context.Background(), phone) })
+	if s.sends != 1 {
+		t.Fatalf("expected 1 send within cooldown, got %d", s.sends)
+	}
+
+	// 并发猜测计入的次数不超过 MaxAttempts
+	var invalid int32
+	run(func() {
+		if errors.Is(v.Verify(phone, "bad"), ErrCodeInvalid) {
+			atomic.AddInt32(&invalid, 1)
+		}
+	})
+	if invalid > 3 {
+		t.Fatalf("expected at most 3 counted guesses, got %d", invalid)
+	}
+
+	// 并发提交正确的验证码只有一个成功
+	v = NewVerifier(s, memory.NewMemoryCache(), "SMS_001", WithCooldown(0), WithMaxAttempts(0))
+	if err := v.SendCode(context.Background(), phone); err != nil {
+		t.Fatalf("SendCode: %v", err)
+	}
+	code := s.last.ParamMap()["code"]
+	var ok int32
+	run(func() {
+		if v.Verify(phone, code) == nil {
+			atomic.AddInt32(&ok, 1)
+		}
+	})
+	if ok != 1 {
+		t.Fatalf("expected exactly one successful verify, got %d", ok)
+	}
+
+	// 并发发送不越过每日上限
+	s.sends = 0
+	v = NewVerifier(s, memory.NewMemoryCache(), "SMS_001", WithCooldown(0), WithDailyLimit(2))
+	run(func() { _ = v.SendCode(context.Background(), phone) })
+	if s.sends != 2 {
+		t.Fatalf("expected 2 sends within daily limit, got %d", s.sends)
+	}
+}
+
+func TestVerifier_NoCodeTTL(t *testing.T) {
+	s := &fakeSender{}
+	v := NewVerifier(s, memory.NewMemoryCache(), "SMS_001", WithCodeTTL(0), WithCooldown(0))
+
+	if err := v.SendCode(context.Background(), "13800000000"); err != nil {
+		t.Fatalf("SendCode: %v", err)
+	}
+	code := s.last.ParamMap()["code"]
+	if err := v.Verify("13800000000", "bad"); !errors.Is(err, ErrCodeInvalid) {
+		t.Fatalf("expected ErrCodeInvalid, got %v", err)
+	}
+	if err := v.Verify("13800000000", code); err != nil {
+		t.Fatalf("Verify without code ttl: %v", err)
+	}
+}
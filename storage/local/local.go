@@ -0,0 +1,343 @@
+package local
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jiajia556/tool-box/storage"
+)
+
+const multipartDir = ".multipart"
+
+// Options 本地文件存储配置
+type Options struct {
+	// 存储根目录
+	Dir string `json:"dir"`
+
+	// 对外访问的基础 URL（例如 https://static.example.com/files），用于生成 SignedURL
+	BaseURL string `json:"base_url"`
+
+	// SignedURL 签名密钥，为空时 SignedURL 不带签名
+	SignSecret string `json:"sign_secret"`
+}
+
+// LocalStorage 本地文件存储实现。
+// 只保存文件内容，不保存对象元信息：PutOption（Content-Type、Cache-Control）会被忽略，
+// Content-Type 始终按 key 的扩展名推断
+type LocalStorage struct {
+	opts Options
+}
+
+// NewLocalStorage 创建本地文件存储
+func NewLocalStorage(config any) (storage.Storage, error) {
+	opts := Options{Dir: "./storage"}
+	if config != nil {
+		o, ok := config.(Options)
+		if !ok {
+			return nil, fmt.Errorf("%w: expect local.Options", storage.ErrInvalidConfig)
+		}
+		opts = o
+	}
+	if opts.Dir == "" {
+		opts.Dir = "./storage"
+	}
+
+	if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("local storage: failed to create directory: %w", err)
+	}
+
+	return &LocalStorage{opts: opts}, nil
+}
+
+// path 将对象 key 转为文件路径，拒绝越出根目录的 key
+func (l *LocalStorage) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if clean == "/" || strings.HasPrefix(clean, "/"+multipartDir+"/") {
+		return "", storage.ErrInvalidKey
+	}
+	return filepath.Join(l.opts.Dir, filepath.FromSlash(clean)), nil
+}
+
+// Put 写入文件，opts 被忽略（本地存储不保存 Content-Type 等元信息）
+func (l *LocalStorage) Put(ctx context.Context, key string, r io.Reader, opts ...storage.PutOption) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	return writeFile(p, r)
+}
+
+func (l *LocalStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	p, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if os.IsNotExist(err) {
+		return nil, storage.ErrNotFound
+	}
+	return f, err
+}
+
+func (l *LocalStorage) Stat(ctx context.Context, key string) (storage.Object, error) {
+	p, err := l.path(key)
+	if err != nil {
+		return storage.Object{}, err
+	}
+	fi, err := os.Stat(p)
+	if os.IsNotExist(err) || (err == nil && fi.IsDir()) {
+		return storage.Object{}, storage.ErrNotFound
+	}
+	if err != nil {
+		return storage.Object{}, err
+	}
+	return l.object(key, fi), nil
+}
+
+func (l *LocalStorage) Delete(ctx context.Context, key string) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (l *LocalStorage) List(ctx context.Context, prefix string) ([]storage.Object, error) {
+	var objects []storage.Object
+	err := filepath.WalkDir(l.opts.Dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(l.opts.Dir, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if d.IsDir() {
+			if key == multipartDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, l.object(key, fi))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// SignedURL 生成访问 URL；配置了 SignSecret 时附带 expires/signature 参数，可由 Handler 校验
+func (l *LocalStorage) SignedURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	if _, err := l.path(key); err != nil {
+		return "", err
+	}
+	if l.opts.BaseURL == "" {
+		return "", fmt.Errorf("%w: local storage BaseURL is empty", storage.ErrNotSupported)
+	}
+
+	u := strings.TrimRight(l.opts.BaseURL, "/") + "/" + strings.TrimLeft(key, "/")
+	if l.opts.SignSecret == "" {
+		return u, nil
+	}
+
+	exp := strconv.FormatInt(time.Now().Add(expires).Unix(), 10)
+	q := url.Values{}
+	q.Set("expires", exp)
+	q.Set("signature", l.sign(key, exp))
+	return u + "?" + q.Encode(), nil
+}
+
+// Handler 返回静态文件服务 handler，挂载路径应与 BaseURL 一致（需配合 http.StripPrefix）。
+// 配置了 SignSecret 时会校验 SignedURL 的签名与有效期。
+func (l *LocalStorage) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimLeft(r.URL.Path, "/")
+		if l.opts.SignSecret != "" {
+			exp := r.URL.Query().Get("expires")
+			sig := r.URL.Query().Get("signature")
+			ts, err := strconv.ParseInt(exp, 10, 64)
+			if err != nil || time.Now().Unix() > ts || !hmac.Equal([]byte(sig), []byte(l.sign(key, exp))) {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+		}
+
+		p, err := l.path(key)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeFile(w, r, p)
+	})
+}
+
+func (l *LocalStorage) sign(key, expires string) string {
+	mac := hmac.New(sha256.New, []byte(l.opts.SignSecret))
+	mac.Write([]byte(strings.TrimLeft(key, "/") + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (l *LocalStorage) object(key string, fi os.FileInfo) storage.Object {
+	ct := mime.TypeByExtension(path.Ext(key))
+	if ct == "" {
+		ct = "application/octet-stream"
+	}
+	return storage.Object{
+		Key:          strings.TrimLeft(key, "/"),
+		Size:         fi.Size(),
+		ContentType:  ct,
+		LastModified: fi.ModTime(),
+	}
+}
+
+// CreateMultipart 初始化分片上传，opts 被忽略，见 Put
+func (l *LocalStorage) CreateMultipart(ctx context.Context, key string, opts ...storage.PutOption) (string, error) {
+	if _, err := l.path(key); err != nil {
+		return "", err
+	}
+	uploadID := uuid.New().String()
+	if err := os.MkdirAll(filepath.Join(l.opts.Dir, multipartDir, uploadID), 0755); err != nil {
+		return "", err
+	}
+	return uploadID, nil
+}
+
+// UploadPart 上传分片
+func (l *LocalStorage) UploadPart(ctx context.Context, key, uploadID string, partNumber int, r io.Reader) (storage.Part, error) {
+	if partNumber < 1 {
+		return storage.Part{}, fmt.Errorf("local storage: invalid part number %d", partNumber)
+	}
+	dir, err := l.uploadDir(uploadID)
+	if err != nil {
+		return storage.Part{}, err
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return storage.Part{}, storage.ErrNotFound
+	}
+
+	h := md5.New()
+	if err := writeFile(filepath.Join(dir, strconv.Itoa(partNumber)), io.TeeReader(r, h)); err != nil {
+		return storage.Part{}, err
+	}
+	return storage.Part{Number: partNumber, ETag: hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+// CompleteMultipart 按分片序号合并为最终对象
+func (l *LocalStorage) CompleteMultipart(ctx context.Context, key, uploadID string, parts []storage.Part) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	dir, err := l.uploadDir(uploadID)
+	if err != nil {
+		return err
+	}
+
+	sorted := append([]storage.Part(nil), parts...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Number < sorted[j].Number })
+
+	readers := make([]io.Reader, 0, len(sorted))
+	files := make([]*os.File, 0, len(sorted))
+	defer func() {
+		for _, f := range files {
+			_ = f.Close()
+		}
+	}()
+	for _, part := range sorted {
+		f, err := os.Open(filepath.Join(dir, strconv.Itoa(part.Number)))
+		if err != nil {
+			return fmt.Errorf("local storage: missing part %d: %w", part.Number, err)
+		}
+		files = append(files, f)
+		readers = append(readers, f)
+	}
+
+	if err := writeFile(p, io.MultiReader(readers...)); err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+// AbortMultipart 放弃分片上传并清理临时分片
+func (l *LocalStorage) AbortMultipart(ctx context.Context, key, uploadID string) error {
+	dir, err := l.uploadDir(uploadID)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+// uploadDir 返回分片的临时目录；uploadID 由 CreateMultipart 生成，不是标准格式的 UUID 时返回 ErrNotFound，
+// 避免 ".." 等值指向存储根目录
+func (l *LocalStorage) uploadDir(uploadID string) (string, error) {
+	id, err := uuid.Parse(uploadID)
+	if err != nil || id.String() != uploadID {
+		return "", fmt.Errorf("%w: invalid upload id %q", storage.ErrNotFound, uploadID)
+	}
+	return filepath.Join(l.opts.Dir, multipartDir, uploadID), nil
+}
+
+func (l *LocalStorage) Close() error {
+	return nil
+}
+
+// writeFile 先写临时文件再重命名，避免读到写了一半的对象
+func writeFile(p string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, r); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+func init() {
+	storage.Register("local", NewLocalStorage)
+}
@@ -0,0 +1,114 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jiajia556/tool-box/storage"
+)
+
+func newTestStorage(t *testing.T) *LocalStorage {
+	s, err := NewLocalStorage(Options{Dir: t.TempDir(), BaseURL: "http://files", SignSecret: "secret"})
+	if err != nil {
+		t.Fatalf("NewLocalStorage: %v", err)
+	}
+	return s.(*LocalStorage)
+}
+
+func TestLocalStorage_PutGetListDelete(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStorage(t)
+
+	if err := s.Put(ctx, "a/b.txt", strings.NewReader("hello")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	rc, err := s.Get(ctx, "a/b.txt")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	b, _ := io.ReadAll(rc)
+	_ = rc.Close()
+	if string(b) != "hello" {
+		t.Fatalf("unexpected content %q", b)
+	}
+
+	objs, err := s.List(ctx, "a/")
+	if err != nil || len(objs) != 1 || objs[0].Key != "a/b.txt" {
+		t.Fatalf("List: %v %+v", err, objs)
+	}
+
+	if err := s.Delete(ctx, "a/b.txt"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Get(ctx, "a/b.txt"); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := s.Get(ctx, "../../etc/passwd"); err == nil {
+		t.Fatalf("expected error for key escaping root")
+	}
+}
+
+func TestLocalStorage_Multipart(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStorage(t)
+
+	id, err := s.CreateMultipart(ctx, "big.bin")
+	if err != nil {
+		t.Fatalf("CreateMultipart: %v", err)
+	}
+	p2, _ := s.UploadPart(ctx, "big.bin", id, 2, strings.NewReader("world"))
+	p1, _ := s.UploadPart(ctx, "big.bin", id, 1, strings.NewReader("hello "))
+	if err := s.CompleteMultipart(ctx, "big.bin", id, []storage.Part{p2, p1}); err != nil {
+		t.Fatalf("CompleteMultipart: %v", err)
+	}
+
+	rc, _ := s.Get(ctx, "big.bin")
+	b, _ := io.ReadAll(rc)
+	_ = rc.Close()
+	if string(b) != "hello world" {
+		t.Fatalf("unexpected content %q", b)
+	}
+
+	for _, bad := range []string{"..", ".", "", "../x", "a/b"} {
+		if err := s.AbortMultipart(ctx, "big.bin", bad); !errors.Is(err, storage.ErrNotFound) {
+			t.Fatalf("AbortMultipart(%q): expected ErrNotFound, got %v", bad, err)
+		}
+		if _, err := s.UploadPart(ctx, "big.bin", bad, 1, strings.NewReader("x")); !errors.Is(err, storage.ErrNotFound) {
+			t.Fatalf("UploadPart(%q): expected ErrNotFound, got %v", bad, err)
+		}
+	}
+	if _, err := s.Get(ctx, "big.bin"); err != nil {
+		t.Fatalf("object removed by invalid upload id: %v", err)
+	}
+}
+
+func TestLocalStorage_SignedURL(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStorage(t)
+	_ = s.Put(ctx, "doc.txt", strings.NewReader("signed"))
+
+	raw, err := s.SignedURL(ctx, "doc.txt", time.Minute)
+	if err != nil {
+		t.Fatalf("SignedURL: %v", err)
+	}
+	u, _ := url.Parse(raw)
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, u.RequestURI(), nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "signed" {
+		t.Fatalf("expected signed request to succeed, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/doc.txt", nil))
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected unsigned request to be rejected, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,64 @@
+package oss
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jiajia556/tool-box/storage"
+	"github.com/jiajia556/tool-box/storage/s3"
+)
+
+// Options 阿里云 OSS 配置
+type Options struct {
+	// 地域，例如 cn-hangzhou
+	Region string `json:"region"`
+
+	Bucket          string `json:"bucket"`
+	AccessKeyID     string `json:"access_key_id"`
+	AccessKeySecret string `json:"access_key_secret"`
+	SecurityToken   string `json:"security_token"`
+
+	// 使用内网 endpoint（同地域 ECS 访问免流量费）
+	Internal bool `json:"internal"`
+
+	// 自定义 endpoint，非空时忽略 Region/Internal 推导
+	Endpoint string `json:"endpoint"`
+
+	// 请求超时
+	Timeout time.Duration `json:"timeout"`
+}
+
+// NewOSSStorage 创建阿里云 OSS 存储。
+// OSS 兼容 S3 协议（SigV4 签名、虚拟主机风格访问），这里复用 s3 适配器的实现。
+func NewOSSStorage(config any) (storage.Storage, error) {
+	opts, ok := config.(Options)
+	if !ok {
+		return nil, fmt.Errorf("%w: expect oss.Options", storage.ErrInvalidConfig)
+	}
+	if opts.Region == "" {
+		return nil, fmt.Errorf("%w: oss region is required", storage.ErrInvalidConfig)
+	}
+
+	endpoint := opts.Endpoint
+	if endpoint == "" {
+		host := "oss-" + opts.Region
+		if opts.Internal {
+			host += "-internal"
+		}
+		endpoint = "https://" + host + ".aliyuncs.com"
+	}
+
+	return s3.New(s3.Options{
+		Endpoint:        endpoint,
+		Region:          "oss-" + opts.Region,
+		Bucket:          opts.Bucket,
+		AccessKeyID:     opts.AccessKeyID,
+		SecretAccessKey: opts.AccessKeySecret,
+		SessionToken:    opts.SecurityToken,
+		Timeout:         opts.Timeout,
+	})
+}
+
+func init() {
+	storage.Register("oss", NewOSSStorage)
+}
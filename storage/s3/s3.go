@@ -0,0 +1,393 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+
+	"github.com/jiajia556/tool-box/storage"
+)
+
+const (
+	service          = "s3"
+	unsignedPayload  = "UNSIGNED-PAYLOAD"
+	maxErrorBodySize = 2048
+)
+
+// Options S3 兼容存储配置（AWS S3、MinIO、Cloudflare R2 等）
+type Options struct {
+	// 服务地址，例如 https://s3.us-east-1.amazonaws.com、http://127.0.0.1:9000
+	Endpoint string `json:"endpoint"`
+
+	Region          string `json:"region"`
+	Bucket          string `json:"bucket"`
+	AccessKeyID     string `json:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key"`
+	SessionToken    string `json:"session_token"`
+
+	// 使用路径风格（endpoint/bucket/key），MinIO 等通常需要开启
+	PathStyle bool `json:"path_style"`
+
+	// 请求超时
+	Timeout time.Duration `json:"timeout"`
+}
+
+// S3Storage S3 兼容对象存储实现，基于 SigV4 签名的 REST API
+type S3Storage struct {
+	opts     Options
+	endpoint *url.URL
+	hc       *http.Client
+	signer   *v4.Signer
+}
+
+// NewS3Storage 创建 S3 兼容存储
+func NewS3Storage(config any) (storage.Storage, error) {
+	opts, ok := config.(Options)
+	if !ok {
+		return nil, fmt.Errorf("%w: expect s3.Options", storage.ErrInvalidConfig)
+	}
+	return New(opts)
+}
+
+// New 使用配置创建 S3 兼容存储
+func New(opts Options) (*S3Storage, error) {
+	if opts.Endpoint == "" || opts.Bucket == "" {
+		return nil, fmt.Errorf("%w: endpoint and bucket are required", storage.ErrInvalidConfig)
+	}
+	if opts.Region == "" {
+		opts.Region = "us-east-1"
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 60 * time.Second
+	}
+
+	endpoint, err := url.Parse(strings.TrimRight(opts.Endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", storage.ErrInvalidConfig, err)
+	}
+
+	return &S3Storage{
+		opts:     opts,
+		endpoint: endpoint,
+		hc:       &http.Client{Timeout: opts.Timeout},
+		signer: v4.NewSigner(func(o *v4.SignerOptions) {
+			// S3 的签名规范要求不对 URI 做二次转义
+			o.DisableURIPathEscaping = true
+		}),
+	}, nil
+}
+
+func (s *S3Storage) credentials() aws.Credentials {
+	return aws.Credentials{
+		AccessKeyID:     s.opts.AccessKeyID,
+		SecretAccessKey: s.opts.SecretAccessKey,
+		SessionToken:    s.opts.SessionToken,
+	}
+}
+
+// objectURL 生成对象 URL；key 为空时返回 bucket URL
+func (s *S3Storage) objectURL(key string, query url.Values) *url.URL {
+	u := *s.endpoint
+	escaped := escapeKey(strings.TrimLeft(key, "/"))
+	if s.opts.PathStyle {
+		u.Path = "/" + s.opts.Bucket + "/" + strings.TrimLeft(key, "/")
+		u.RawPath = "/" + s.opts.Bucket + "/" + escaped
+	} else {
+		u.Host = s.opts.Bucket + "." + u.Host
+		u.Path = "/" + strings.TrimLeft(key, "/")
+		u.RawPath = "/" + escaped
+	}
+	if query != nil {
+		u.RawQuery = query.Encode()
+	}
+	return &u
+}
+
+func (s *S3Storage) do(ctx context.Context, method string, u *url.URL, body []byte, header http.Header) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reader)
+	if err != nil {
+		return nil, err
+	}
+	for k, vs := range header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+
+	sum := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(sum[:])
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	if err := s.signer.SignHTTP(ctx, s.credentials(), req, payloadHash, service, s.opts.Region, time.Now()); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, storage.ErrNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return nil, fmt.Errorf("s3: %s %s: status %d: %s", method, u.Path, resp.StatusCode, string(b))
+	}
+	return resp, nil
+}
+
+// Put 上传对象。数据会先读入内存以计算签名，大文件请使用分片上传。
+func (s *S3Storage) Put(ctx context.Context, key string, r io.Reader, opts ...storage.PutOption) error {
+	o := storage.ApplyPutOptions(opts...)
+	if o.ContentType == "" {
+		o.ContentType, r = storage.DetectContentType(key, r)
+	}
+
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(ctx, http.MethodPut, s.objectURL(key, nil), body, putHeader(o))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *S3Storage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, s.objectURL(key, nil), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s *S3Storage) Stat(ctx context.Context, key string) (storage.Object, error) {
+	resp, err := s.do(ctx, http.MethodHead, s.objectURL(key, nil), nil, nil)
+	if err != nil {
+		return storage.Object{}, err
+	}
+	resp.Body.Close()
+
+	obj := storage.Object{
+		Key:         strings.TrimLeft(key, "/"),
+		Size:        resp.ContentLength,
+		ContentType: resp.Header.Get("Content-Type"),
+		ETag:        strings.Trim(resp.Header.Get("ETag"), `"`),
+	}
+	if t, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		obj.LastModified = t
+	}
+	return obj, nil
+}
+
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, s.objectURL(key, nil), nil, nil)
+	if err == storage.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+type listResult struct {
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+	Contents              []struct {
+		Key          string    `xml:"Key"`
+		LastModified time.Time `xml:"LastModified"`
+		ETag         string    `xml:"ETag"`
+		Size         int64     `xml:"Size"`
+	} `xml:"Contents"`
+}
+
+func (s *S3Storage) List(ctx context.Context, prefix string) ([]storage.Object, error) {
+	var objects []storage.Object
+	token := ""
+	for {
+		q := url.Values{}
+		q.Set("list-type", "2")
+		if prefix != "" {
+			q.Set("prefix", prefix)
+		}
+		if token != "" {
+			q.Set("continuation-token", token)
+		}
+
+		resp, err := s.do(ctx, http.MethodGet, s.objectURL("", q), nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		var result listResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("s3: decode list result: %w", err)
+		}
+
+		for _, c := range result.Contents {
+			objects = append(objects, storage.Object{
+				Key:          c.Key,
+				Size:         c.Size,
+				ETag:         strings.Trim(c.ETag, `"`),
+				LastModified: c.LastModified,
+			})
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// SignedURL 生成预签名 GET URL
+func (s *S3Storage) SignedURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	q := url.Values{}
+	q.Set("X-Amz-Expires", strconv.FormatInt(int64(expires/time.Second), 10))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL(key, q).String(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	signed, _, err := s.signer.PresignHTTP(ctx, s.credentials(), req, unsignedPayload, service, s.opts.Region, time.Now())
+	return signed, err
+}
+
+// CreateMultipart 初始化分片上传
+func (s *S3Storage) CreateMultipart(ctx context.Context, key string, opts ...storage.PutOption) (string, error) {
+	o := storage.ApplyPutOptions(opts...)
+	if o.ContentType == "" {
+		o.ContentType = mime.TypeByExtension(path.Ext(key))
+	}
+
+	resp, err := s.do(ctx, http.MethodPost, s.objectURL(key, url.Values{"uploads": {""}}), nil, putHeader(o))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		UploadID string `xml:"UploadId"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("s3: decode multipart result: %w", err)
+	}
+	return result.UploadID, nil
+}
+
+// UploadPart 上传分片（除最后一片外每片至少 5MB）
+func (s *S3Storage) UploadPart(ctx context.Context, key, uploadID string, partNumber int, r io.Reader) (storage.Part, error) {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return storage.Part{}, err
+	}
+
+	q := url.Values{}
+	q.Set("partNumber", strconv.Itoa(partNumber))
+	q.Set("uploadId", uploadID)
+
+	resp, err := s.do(ctx, http.MethodPut, s.objectURL(key, q), body, nil)
+	if err != nil {
+		return storage.Part{}, err
+	}
+	resp.Body.Close()
+	return storage.Part{Number: partNumber, ETag: resp.Header.Get("ETag")}, nil
+}
+
+type completePart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+type completeMultipart struct {
+	XMLName xml.Name       `xml:"CompleteMultipartUpload"`
+	Parts   []completePart `xml:"Part"`
+}
+
+// CompleteMultipart 合并分片
+func (s *S3Storage) CompleteMultipart(ctx context.Context, key, uploadID string, parts []storage.Part) error {
+	payload := completeMultipart{Parts: make([]completePart, 0, len(parts))}
+	for _, p := range parts {
+		payload.Parts = append(payload.Parts, completePart{PartNumber: p.Number, ETag: p.ETag})
+	}
+	body, err := xml.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(ctx, http.MethodPost, s.objectURL(key, url.Values{"uploadId": {uploadID}}), body, http.Header{
+		"Content-Type": {"application/xml"},
+	})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// AbortMultipart 放弃分片上传
+func (s *S3Storage) AbortMultipart(ctx context.Context, key, uploadID string) error {
+	resp, err := s.do(ctx, http.MethodDelete, s.objectURL(key, url.Values{"uploadId": {uploadID}}), nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *S3Storage) Close() error {
+	s.hc.CloseIdleConnections()
+	return nil
+}
+
+func putHeader(o storage.PutOptions) http.Header {
+	h := make(http.Header)
+	if o.ContentType != "" {
+		h.Set("Content-Type", o.ContentType)
+	}
+	if o.CacheControl != "" {
+		h.Set("Cache-Control", o.CacheControl)
+	}
+	return h
+}
+
+// escapeKey 按路径段转义对象 key，保留分隔符 /
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, seg := range segments {
+		segments[i] = url.PathEscape(seg)
+	}
+	return strings.Join(segments, "/")
+}
+
+func init() {
+	storage.Register("s3", NewS3Storage)
+}
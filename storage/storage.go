@@ -0,0 +1,268 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNoGlobal      = errors.New("storage: global instance is nil")
+	ErrNotFound      = errors.New("storage: object not found")
+	ErrInvalidKey    = errors.New("storage: invalid object key")
+	ErrInvalidConfig = errors.New("storage: invalid config")
+	ErrNotSupported  = errors.New("storage: operation not supported")
+)
+
+// Object 对象元信息
+type Object struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	ETag         string
+	LastModified time.Time
+}
+
+// Storage 对象存储接口
+type Storage interface {
+	// 上传对象
+	Put(ctx context.Context, key string, r io.Reader, opts ...PutOption) error
+
+	// 读取对象，调用方负责关闭返回的 ReadCloser
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// 获取对象元信息
+	Stat(ctx context.Context, key string) (Object, error)
+
+	// 删除对象（不存在时不报错）
+	Delete(ctx context.Context, key string) error
+
+	// 按前缀列出对象
+	List(ctx context.Context, prefix string) ([]Object, error)
+
+	// 生成带有效期的访问 URL
+	SignedURL(ctx context.Context, key string, expires time.Duration) (string, error)
+
+	// 关闭（释放资源）
+	Close() error
+}
+
+// Part 分片上传中已完成的分片
+type Part struct {
+	Number int
+	ETag   string
+}
+
+// MultipartUploader 分片上传接口，适配器可选实现
+type MultipartUploader interface {
+	// 初始化分片上传，返回 uploadID
+	CreateMultipart(ctx context.Context, key string, opts ...PutOption) (string, error)
+
+	// 上传分片，partNumber 从 1 开始
+	UploadPart(ctx context.Context, key, uploadID string, partNumber int, r io.Reader) (Part, error)
+
+	// 合并分片
+	CompleteMultipart(ctx context.Context, key, uploadID string, parts []Part) error
+
+	// 放弃分片上传
+	AbortMultipart(ctx context.Context, key, uploadID string) error
+}
+
+// PutOptions 上传选项
+type PutOptions struct {
+	ContentType  string
+	CacheControl string
+}
+
+// PutOption 上传选项函数
+type PutOption func(*PutOptions)
+
+// WithContentType 指定 Content-Type（不指定时自动探测）
+func WithContentType(contentType string) PutOption {
+	return func(o *PutOptions) {
+		o.ContentType = contentType
+	}
+}
+
+// WithCacheControl 指定 Cache-Control
+func WithCacheControl(cacheControl string) PutOption {
+	return func(o *PutOptions) {
+		o.CacheControl = cacheControl
+	}
+}
+
+// ApplyPutOptions 合并上传选项，供适配器使用
+func ApplyPutOptions(opts ...PutOption) PutOptions {
+	var o PutOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+// DetectContentType 探测内容类型：优先按扩展名，其次嗅探数据头部。
+// 返回的 Reader 包含完整数据（已嗅探的部分不会丢失），调用方应使用它代替 r。
+func DetectContentType(key string, r io.Reader) (string, io.Reader) {
+	if ct := mime.TypeByExtension(path.Ext(key)); ct != "" {
+		return ct, r
+	}
+
+	br := bufio.NewReaderSize(r, 512)
+	head, _ := br.Peek(512)
+	return http.DetectContentType(head), br
+}
+
+// Instance 适配器工厂函数
+type Instance func(config any) (Storage, error)
+
+var (
+	adaptersMu sync.RWMutex
+	adapters   = make(map[string]Instance)
+)
+
+const (
+	AdapterLocal = "local"
+	AdapterS3    = "s3"
+	AdapterOSS   = "oss"
+)
+
+var (
+	globalMu sync.RWMutex
+	global   Storage
+)
+
+// Register 注册存储适配器
+func Register(name string, adapter Instance) {
+	adaptersMu.Lock()
+	defer adaptersMu.Unlock()
+
+	if adapter == nil {
+		panic("storage: Register adapter is nil")
+	}
+	if _, ok := adapters[name]; ok {
+		panic("storage: Register called twice for adapter " + name)
+	}
+	adapters[name] = adapter
+}
+
+// New 按适配器名称创建存储实例（不影响全局实例）
+func New(adapterName string, config ...any) (Storage, error) {
+	adaptersMu.RLock()
+	instanceFunc, ok := adapters[adapterName]
+	adaptersMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("storage: unknown adapter name %q (forgot to import?)", adapterName)
+	}
+
+	var cfg any
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	return instanceFunc(cfg)
+}
+
+// Init 初始化全局存储实例
+// 参数 config 是可选的，不同的适配器接受不同的配置类型：
+// - "local": 接受 local.Options 结构体
+// - "s3": 接受 s3.Options 结构体
+// - "oss": 接受 oss.Options 结构体
+func Init(adapterName string, config ...any) error {
+	s, err := New(adapterName, config...)
+	if err != nil {
+		return err
+	}
+	SetGlobal(s)
+	return nil
+}
+
+// SetGlobal 设置全局存储实例
+func SetGlobal(s Storage) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	global = s
+}
+
+func getGlobal() (Storage, error) {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+	if global == nil {
+		return nil, ErrNoGlobal
+	}
+	return global, nil
+}
+
+// Put 上传对象（使用全局实例）
+func Put(ctx context.Context, key string, r io.Reader, opts ...PutOption) error {
+	s, err := getGlobal()
+	if err != nil {
+		return err
+	}
+	return s.Put(ctx, key, r, opts...)
+}
+
+// Get 读取对象（使用全局实例）
+func Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	s, err := getGlobal()
+	if err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, key)
+}
+
+// Stat 获取对象元信息（使用全局实例）
+func Stat(ctx context.Context, key string) (Object, error) {
+	s, err := getGlobal()
+	if err != nil {
+		return Object{}, err
+	}
+	return s.Stat(ctx, key)
+}
+
+// Delete 删除对象（使用全局实例）
+func Delete(ctx context.Context, key string) error {
+	s, err := getGlobal()
+	if err != nil {
+		return err
+	}
+	return s.Delete(ctx, key)
+}
+
+// List 按前缀列出对象（使用全局实例）
+func List(ctx context.Context, prefix string) ([]Object, error) {
+	s, err := getGlobal()
+	if err != nil {
+		return nil, err
+	}
+	return s.List(ctx, prefix)
+}
+
+// SignedURL 生成带有效期的访问 URL（使用全局实例）
+func SignedURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	s, err := getGlobal()
+	if err != nil {
+		return "", err
+	}
+	return s.SignedURL(ctx, key, expires)
+}
+
+// Close 关闭全局存储实例
+func Close() error {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	if global == nil {
+		return nil
+	}
+	err := global.Close()
+	global = nil
+	return err
+}
@@ -0,0 +1,108 @@
+package timex
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidDateTime 无法识别的日期时间格式
+var ErrInvalidDateTime = errors.New("timex: unrecognized datetime format")
+
+// autoLayouts ParseDateTimeAuto 依次尝试的格式，排在前面的优先；DateTimeFormat 总是最先尝试。
+// 解析时秒后的小数部分可省略，因此 RFC3339 与 MySQL datetime 均同时覆盖带毫秒、微秒的写法
+var autoLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-1-2 15:04:05",
+	"2006-1-2",
+	"2006/1/2 15:04:05",
+	"2006/1/2 15:04",
+	"2006/1/2",
+	"2006.1.2 15:04:05",
+	"2006.1.2",
+	"2006年1月2日 15:04:05",
+	"2006年1月2日 15时4分5秒",
+	"2006年1月2日15时4分5秒",
+	"2006年1月2日 15:04",
+	"2006年1月2日",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// ParseDateTimeAuto 自动识别常见格式解析日期时间：
+//
+//   - DateTimeFormat 与 RFC3339（可带小数秒与时区）
+//   - MySQL datetime：2006-01-02 15:04:05[.000000]、2006-01-02 15:04、2006-01-02
+//   - 斜杠与点分隔：2006/01/02 15:04:05、2006/1/2、2006.01.02
+//   - 中文：2006年01月02日 15:04:05、2006年1月2日 15时04分05秒、2006年1月2日
+//   - 纯数字：8 位按 20060102、14 位按 20060102150405 解析，失败或其他长度按 Unix 时间戳，
+//     根据位数区分秒（≤10 位）、毫秒（≤13 位）、微秒（≤16 位）与纳秒（≤19 位）
+//
+// 不带时区的格式按 UTC 解析（与 time.Parse 一致），时间戳转换为本地时间
+func ParseDateTimeAuto(s string) (DateTime, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return DateTime{}, fmt.Errorf("%w: empty string", ErrInvalidDateTime)
+	}
+
+	if isDigits(s) {
+		if t, ok := parseNumeric(s); ok {
+			return DateTime{t}, nil
+		}
+		return DateTime{}, fmt.Errorf("%w: %q", ErrInvalidDateTime, s)
+	}
+
+	if t, err := time.Parse(DateTimeFormat, s); err == nil {
+		return DateTime{t}, nil
+	}
+	for _, layout := range autoLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return DateTime{t}, nil
+		}
+	}
+	return DateTime{}, fmt.Errorf("%w: %q", ErrInvalidDateTime, s)
+}
+
+// parseNumeric 解析纯数字的紧凑日期或 Unix 时间戳
+func parseNumeric(s string) (time.Time, bool) {
+	switch len(s) {
+	case 8:
+		if t, err := time.Parse("20060102", s); err == nil {
+			return t, true
+		}
+	case 14:
+		if t, err := time.Parse("20060102150405", s); err == nil {
+			return t, true
+		}
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	switch {
+	case len(s) <= 10:
+		return time.Unix(n, 0), true
+	case len(s) <= 13:
+		return time.UnixMilli(n), true
+	case len(s) <= 16:
+		return time.UnixMicro(n), true
+	default:
+		return time.Unix(0, n), true
+	}
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
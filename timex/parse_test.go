@@ -0,0 +1,144 @@
+package timex
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseDateTimeAuto(t *testing.T) {
+	utc := func(y int, mo time.Month, d, h, mi, s, ns int) time.Time {
+		return time.Date(y, mo, d, h, mi, s, ns, time.UTC)
+	}
+	want := utc(2024, 5, 1, 8, 30, 15, 0)
+	day := utc(2024, 5, 1, 0, 0, 0, 0)
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2024-05-01 08:30:15", want},
+		{" 2024-05-01 08:30:15 ", want},
+		{"2024-05-01 08:30:15.123456", utc(2024, 5, 1, 8, 30, 15, 123456000)},
+		{"2024-05-01T08:30:15Z", want},
+		{"2024-05-01T16:30:15+08:00", want},
+		{"2024-05-01T08:30:15.5Z", utc(2024, 5, 1, 8, 30, 15, 500000000)},
+		{"2024-05-01T08:30:15", want},
+		{"2024-05-01 08:30", utc(2024, 5, 1, 8, 30, 0, 0)},
+		{"2024-05-01", day},
+		{"2024-5-1", day},
+		{"2024/05/01 08:30:15", want},
+		{"2024/5/1", day},
+		{"2024.05.01", day},
+		{"2024年05月01日 08:30:15", want},
+		{"2024年5月1日 8时30分15秒", want},
+		{"2024年5月1日", day},
+		{"20240501", day},
+		{"20240501083015", want},
+		{"Wed, 01 May 2024 08:30:15 +0000", want},
+	}
+	for _, tt := range tests {
+		got, err := ParseDateTimeAuto(tt.in)
+		if err != nil {
+			t.Errorf("ParseDateTimeAuto(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseDateTimeAuto(%q) = %v, want %v", tt.in, got.Time, tt.want)
+		}
+	}
+
+	epochs := map[string]time.Time{
+		"1714552215":          want,
+		"1714552215123":       want.Add(123 * time.Millisecond),
+		"1714552215123456":    want.Add(123456 * time.Microsecond),
+		"1714552215123456789": want.Add(123456789),
+	}
+	for in, w := range epochs {
+		got, err := ParseDateTimeAuto(in)
+		if err != nil || !got.Equal(w) {
+			t.Errorf("ParseDateTimeAuto(%q) = %v, %v; want %v", in, got.Time, err, w)
+		}
+	}
+
+	for _, in := range []string{"", "  ", "not a date", "2024-13-01", "2024-05-01 25:00:00", "12345678901234567890"} {
+		if _, err := ParseDateTimeAuto(in); !errors.Is(err, ErrInvalidDateTime) {
+			t.Errorf("ParseDateTimeAuto(%q) error = %v, want ErrInvalidDateTime", in, err)
+		}
+	}
+}
+
+func TestDateTimeUnmarshalJSON(t *testing.T) {
+	var v struct {
+		A DateTime `json:"a"`
+		B DateTime `json:"b"`
+		C DateTime `json:"c"`
+	}
+	data := `{"a":"2024-05-01T08:30:15Z","b":1714552215,"c":null}`
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	want := time.Date(2024, 5, 1, 8, 30, 15, 0, time.UTC)
+	if !v.A.Equal(want) || !v.B.Equal(want) || !v.C.IsZero() {
+		t.Fatalf("got %v %v %v", v.A.Time, v.B.Time, v.C.Time)
+	}
+
+	for _, data := range []string{`"x"`, `true`, `1.5`, `""`} {
+		var d DateTime
+		if err := d.UnmarshalJSON([]byte(data)); !errors.Is(err, ErrInvalidDateTime) {
+			t.Errorf("UnmarshalJSON(%s) error = %v, want ErrInvalidDateTime", data, err)
+		}
+	}
+}
+
+func FuzzParseDateTimeAuto(f *testing.F) {
+	for _, s := range []string{
+		"2024-05-01 08:30:15", "2024-05-01T08:30:15.123+08:00", "2024/5/1", "2024年5月1日 8时30分15秒",
+		"20240501", "20240501083015", "1714552215", "1714552215123", "", "年月日", "9999999999999999999",
+	} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		got, err := ParseDateTimeAuto(s)
+		if err != nil {
+			if !errors.Is(err, ErrInvalidDateTime) {
+				t.Fatalf("ParseDateTimeAuto(%q) error %v does not wrap ErrInvalidDateTime", s, err)
+			}
+			return
+		}
+		// 解析成功的结果以 RFC3339Nano 格式化后可以再次解析为同一时刻
+		if got.Year() < 0 || got.Year() > 9999 {
+			return
+		}
+		text := got.Format(time.RFC3339Nano)
+		again, err := ParseDateTimeAuto(text)
+		if err != nil {
+			t.Fatalf("ParseDateTimeAuto(%q) = %s, reparse: %v", s, text, err)
+		}
+		if !again.Equal(got.Time) {
+			t.Fatalf("ParseDateTimeAuto(%q) = %s, reparse = %s", s, text, again.Format(time.RFC3339Nano))
+		}
+	})
+}
+
+func FuzzDateTimeUnmarshalJSON(f *testing.F) {
+	for _, s := range []string{`"2024-05-01 08:30:15"`, `1714552215`, `null`, `"`, `""`, ``, `"2024"`} {
+		f.Add([]byte(s))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var d DateTime
+		if err := d.UnmarshalJSON(data); err != nil || d.Year() < 0 || d.Year() > 9999 {
+			return
+		}
+		// 成功解析的值序列化后可以再次反序列化
+		b, err := d.MarshalJSON()
+		if err != nil {
+			t.Fatalf("MarshalJSON: %v", err)
+		}
+		var again DateTime
+		if err := again.UnmarshalJSON(b); err != nil {
+			t.Fatalf("UnmarshalJSON(%s) ok, round trip %s: %v", data, b, err)
+		}
+	})
+}
@@ -103,33 +103,26 @@ func (t DateTime) MarshalJSON() ([]byte, error) {
 	return []byte(`"` + t.Format(DateTimeFormat) + `"`), nil
 }
 
+// UnmarshalJSON 支持 null、ParseDateTimeAuto 可识别的字符串以及数字形式的 Unix 时间戳
 func (t *DateTime) UnmarshalJSON(data []byte) error {
 	if string(data) == "null" {
 		t.Time = time.Time{}
 		return nil
 	}
 
-	str := string(data[1 : len(data)-1])
-
-	formats := []string{
-		DateTimeFormat,
-		"2006-01-02T15:04:05",
-		time.RFC3339,
-		"2006-01-02",
+	str := string(data)
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		str = string(data[1 : len(data)-1])
+	} else if !isDigits(str) {
+		return fmt.Errorf("invalid datetime format: %w: %s", ErrInvalidDateTime, data)
 	}
 
-	var parsed time.Time
-	var err error
-
-	for _, format := range formats {
-		parsed, err = time.Parse(format, str)
-		if err == nil {
-			t.Time = parsed
-			return nil
-		}
+	parsed, err := ParseDateTimeAuto(str)
+	if err != nil {
+		return fmt.Errorf("invalid datetime format: %w", err)
 	}
-
-	return fmt.Errorf("invalid datetime format: %w", err)
+	*t = parsed
+	return nil
 }
 
 func (t *DateTime) Scan(value interface{}) error {
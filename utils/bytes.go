@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var ErrInvalidByteSize = errors.New("utils: invalid byte size")
+
+var (
+	binaryUnits = []string{"B", "KB", "MB", "GB", "TB", "PB", "EB"}
+	iecUnits    = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	siUnits     = []string{"B", "kB", "MB", "GB", "TB", "PB", "EB"}
+)
+
+// FormatBytes 按 1024 进制格式化字节数，单位写作 KB / MB，如 FormatBytes(1536) = "1.5 KB"
+func FormatBytes(n int64) string {
+	return formatBytes(n, 1024, binaryUnits)
+}
+
+// FormatBytesIEC 按 1024 进制格式化字节数，使用 IEC 单位，如 "1.5 KiB"
+func FormatBytesIEC(n int64) string {
+	return formatBytes(n, 1024, iecUnits)
+}
+
+// FormatBytesSI 按 1000 进制格式化字节数，使用 SI 单位，如 FormatBytesSI(1500) = "1.5 kB"
+func FormatBytesSI(n int64) string {
+	return formatBytes(n, 1000, siUnits)
+}
+
+func formatBytes(n int64, base float64, units []string) string {
+	sign := ""
+	f := float64(n)
+	if n < 0 {
+		sign = "-"
+		f = -f
+	}
+	if f < base {
+		return sign + strconv.FormatInt(int64(f), 10) + " B"
+	}
+
+	i := 0
+	for f >= base && i < len(units)-1 {
+		f /= base
+		i++
+	}
+	// 最多保留一位小数，去掉多余的 0；进位到 base 时升一级单位
+	f = math.Round(f*10) / 10
+	if f >= base && i < len(units)-1 {
+		f /= base
+		i++
+	}
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	return sign + s + " " + units[i]
+}
+
+// ParseBytes 解析字节数，KB / MB 等与 KiB / MiB 等均按 1024 进制，如 ParseBytes("10MB") = 10485760；
+// 支持小数（"1.5GB"）、省略 B（"10M"）、大小写不敏感，纯数字按字节处理
+func ParseBytes(s string) (int64, error) {
+	return parseBytes(s, 1024)
+}
+
+// ParseBytesSI 解析字节数，KB / MB 等按 1000 进制，KiB / MiB 等仍按 1024 进制
+func ParseBytesSI(s string) (int64, error) {
+	return parseBytes(s, 1000)
+}
+
+func parseBytes(s string, base float64) (int64, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return !unicode.IsDigit(r) && r != '.' && r != '-' && r != '+'
+	})
+	num, unit := s, ""
+	if i >= 0 {
+		num, unit = s[:i], strings.TrimSpace(s[i:])
+	}
+
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil || num == "" {
+		return 0, ErrInvalidByteSize
+	}
+
+	unit = strings.ToUpper(unit)
+	if strings.HasSuffix(unit, "IB") {
+		base = 1024
+		unit = strings.TrimSuffix(unit, "IB")
+	} else {
+		unit = strings.TrimSuffix(unit, "B")
+	}
+
+	exp := strings.Index("KMGTPE", unit) + 1
+	if unit == "" {
+		exp = 0
+	} else if exp == 0 || len(unit) != 1 {
+		return 0, ErrInvalidByteSize
+	}
+
+	v := f * math.Pow(base, float64(exp))
+	if v >= math.MaxInt64 || v <= math.MinInt64 {
+		return 0, ErrInvalidByteSize
+	}
+	return int64(v), nil
+}
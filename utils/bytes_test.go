@@ -0,0 +1,55 @@
+package utils
+
+import "testing"
+
+func TestFormatBytes(t *testing.T) {
+	cases := []struct {
+		got, want string
+	}{
+		{FormatBytes(0), "0 B"},
+		{FormatBytes(1023), "1023 B"},
+		{FormatBytes(1536), "1.5 KB"},
+		{FormatBytes(10 << 20), "10 MB"},
+		{FormatBytes(-2048), "-2 KB"},
+		{FormatBytesIEC(1536), "1.5 KiB"},
+		{FormatBytesSI(1500), "1.5 kB"},
+		{FormatBytesSI(2_000_000_000), "2 GB"},
+	}
+	for _, c := range cases {
+		if c.got != c.want {
+			t.Errorf("got %q, want %q", c.got, c.want)
+		}
+	}
+}
+
+func TestParseBytes(t *testing.T) {
+	cases := []struct {
+		in   string
+		si   bool
+		want int64
+	}{
+		{"10MB", false, 10485760},
+		{"10 mb", false, 10485760},
+		{"10M", false, 10485760},
+		{"1.5KiB", false, 1536},
+		{"512", false, 512},
+		{"10MB", true, 10_000_000},
+		{"1KiB", true, 1024},
+	}
+	for _, c := range cases {
+		parse := ParseBytes
+		if c.si {
+			parse = ParseBytesSI
+		}
+		got, err := parse(c.in)
+		if err != nil || got != c.want {
+			t.Errorf("parse(%q, si=%v) = %d, %v; want %d", c.in, c.si, got, err, c.want)
+		}
+	}
+
+	for _, in := range []string{"", "MB", "10XB", "1.2.3KB", "10KBB"} {
+		if _, err := ParseBytes(in); err == nil {
+			t.Errorf("ParseBytes(%q) expected error", in)
+		}
+	}
+}
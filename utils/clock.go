@@ -0,0 +1,175 @@
+package utils
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock 时间源抽象，测试中可替换为 FakeClock 以精确控制过期、超时等逻辑
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker 对应 time.Ticker
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+	Reset(d time.Duration)
+}
+
+// RealClock 使用系统时间的 Clock
+var RealClock Clock = realClock{}
+
+// ClockOrReal 返回 c，c 为 nil 时返回 RealClock
+func ClockOrReal(c Clock) Clock {
+	if c == nil {
+		return RealClock
+	}
+	return c
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) NewTicker(d time.Duration) Ticker       { return realTicker{time.NewTicker(d)} }
+
+type realTicker struct{ t *time.Ticker }
+
+func (r realTicker) C() <-chan time.Time   { return r.t.C }
+func (r realTicker) Stop()                 { r.t.Stop() }
+func (r realTicker) Reset(d time.Duration) { r.t.Reset(d) }
+
+// FakeClock 手动推进的 Clock：Now 只在 Advance / Set 时变化，到期的 After 与 Ticker 在推进时触发
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+type fakeWaiter struct {
+	at     time.Time
+	period time.Duration // >0 表示 Ticker
+	ch     chan time.Time
+}
+
+// NewFakeClock 创建起始时间为 t 的 FakeClock，t 为零值时使用当前时间
+func NewFakeClock(t time.Time) *FakeClock {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return &FakeClock{now: t}
+}
+
+func (f *FakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *FakeClock) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w := &fakeWaiter{at: f.now.Add(d), ch: make(chan time.Time, 1)}
+	if d <= 0 {
+		w.ch <- f.now
+		return w.ch
+	}
+	f.waiters = append(f.waiters, w)
+	return w.ch
+}
+
+// NewTicker 创建 Ticker，d 必须大于 0
+func (f *FakeClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("utils: non-positive interval for FakeClock.NewTicker")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w := &fakeWaiter{at: f.now.Add(d), period: d, ch: make(chan time.Time, 1)}
+	f.waiters = append(f.waiters, w)
+	return &fakeTicker{clock: f, w: w}
+}
+
+// Advance 将时间推进 d，并按时间顺序触发到期的 After 与 Ticker
+func (f *FakeClock) Advance(d time.Duration) {
+	f.Set(f.Now().Add(d))
+}
+
+// Set 将时间设置为 t（不能早于当前时间），并触发到期的 After 与 Ticker
+func (f *FakeClock) Set(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if t.Before(f.now) {
+		return
+	}
+	f.now = t
+
+	sort.Slice(f.waiters, func(i, j int) bool { return f.waiters[i].at.Before(f.waiters[j].at) })
+	kept := f.waiters[:0]
+	for _, w := range f.waiters {
+		if w.at.After(t) {
+			kept = append(kept, w)
+			continue
+		}
+		// 与 time.Ticker 一致：接收方来不及读取时丢弃
+		select {
+		case w.ch <- w.at:
+		default:
+		}
+		if w.period > 0 {
+			for !w.at.After(t) {
+				w.at = w.at.Add(w.period)
+			}
+			kept = append(kept, w)
+		}
+	}
+	f.waiters = kept
+}
+
+// Waiters 返回尚未触发的 After 与活动 Ticker 数量，便于测试等待被测代码进入等待状态
+func (f *FakeClock) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.waiters)
+}
+
+type fakeTicker struct {
+	clock *FakeClock
+	w     *fakeWaiter
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.w.ch
+}
+
+func (t *fakeTicker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	for i, w := range t.clock.waiters {
+		if w == t.w {
+			t.clock.waiters = append(t.clock.waiters[:i], t.clock.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+func (t *fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("utils: non-positive interval for FakeClock ticker Reset")
+	}
+	t.Stop()
+
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.w.period = d
+	t.w.at = t.clock.now.Add(d)
+	t.clock.waiters = append(t.clock.waiters, t.w)
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFakeClock(start)
+
+	after := c.After(time.Second)
+	ticker := c.NewTicker(400 * time.Millisecond)
+	defer ticker.Stop()
+
+	c.Advance(500 * time.Millisecond)
+	select {
+	case <-after:
+		t.Fatalf("After fired too early")
+	default:
+	}
+	if got := <-ticker.C(); !got.Equal(start.Add(400 * time.Millisecond)) {
+		t.Fatalf("unexpected tick time %v", got)
+	}
+
+	c.Advance(500 * time.Millisecond)
+	if got := <-after; !got.Equal(start.Add(time.Second)) {
+		t.Fatalf("unexpected After time %v", got)
+	}
+	if got := <-ticker.C(); !got.Equal(start.Add(800 * time.Millisecond)) {
+		t.Fatalf("unexpected tick time %v", got)
+	}
+	if !c.Now().Equal(start.Add(time.Second)) || c.Waiters() != 1 {
+		t.Fatalf("unexpected state: now=%v waiters=%d", c.Now(), c.Waiters())
+	}
+}
@@ -0,0 +1,225 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var ErrConvert = errors.New("utils: cannot convert value")
+
+// ConvertByJSONTags 按 json tag 将 src 的字段复制到 dst（必须是非 nil 指针）指向的值中，
+// 用于字段名不同但 json 名称一致的结构体之间转换，如外部 API 的 DTO 与内部模型。
+//   - 字段名取 json tag（没有 tag 时取字段名），先精确匹配，再与 encoding/json 一样忽略大小写匹配
+//   - 递归处理嵌套结构体、指针、切片、数组与 map，匿名嵌入的结构体按 encoding/json 的规则展开
+//   - 类型相同时直接赋值（切片、map 与指针会共享底层数据），数值之间按 Go 的类型转换规则转换
+//   - dst 中没有对应字段的 src 字段被忽略，src 中没有的 dst 字段保持不变
+//
+// 类型无法转换时返回包含字段路径的 ErrConvert，转换过程中的 panic 也作为错误返回。
+func ConvertByJSONTags(src, dst any) (err error) {
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() {
+		return errors.New("utils: ConvertByJSONTags dst must be a non-nil pointer")
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("utils: ConvertByJSONTags panic: %v", r)
+		}
+	}()
+
+	sv := reflect.ValueOf(src)
+	if !sv.IsValid() {
+		return nil
+	}
+	return convertValue(sv, dv.Elem(), "")
+}
+
+func convertValue(src, dst reflect.Value, path string) error {
+	for src.Kind() == reflect.Pointer || src.Kind() == reflect.Interface {
+		if src.IsNil() {
+			dst.SetZero()
+			return nil
+		}
+		src = src.Elem()
+	}
+	if src.Type().AssignableTo(dst.Type()) {
+		dst.Set(src)
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Pointer:
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return convertValue(src, dst.Elem(), path)
+
+	case reflect.Struct:
+		if src.Kind() == reflect.Struct {
+			return convertStruct(src, dst, path)
+		}
+
+	case reflect.Slice:
+		if src.Kind() == reflect.Slice || src.Kind() == reflect.Array {
+			if src.Kind() == reflect.Slice && src.IsNil() {
+				dst.SetZero()
+				return nil
+			}
+			out := reflect.MakeSlice(dst.Type(), src.Len(), src.Len())
+			for i := 0; i < src.Len(); i++ {
+				if err := convertValue(src.Index(i), out.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+			dst.Set(out)
+			return nil
+		}
+
+	case reflect.Array:
+		if src.Kind() == reflect.Slice || src.Kind() == reflect.Array {
+			for i := 0; i < src.Len() && i < dst.Len(); i++ {
+				if err := convertValue(src.Index(i), dst.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+	case reflect.Map:
+		if src.Kind() == reflect.Map {
+			if src.IsNil() {
+				dst.SetZero()
+				return nil
+			}
+			out := reflect.MakeMapWithSize(dst.Type(), src.Len())
+			iter := src.MapRange()
+			for iter.Next() {
+				k := reflect.New(dst.Type().Key()).Elem()
+				v := reflect.New(dst.Type().Elem()).Elem()
+				elemPath := fmt.Sprintf("%s[%v]", path, iter.Key())
+				if err := convertValue(iter.Key(), k, elemPath); err != nil {
+					return err
+				}
+				if err := convertValue(iter.Value(), v, elemPath); err != nil {
+					return err
+				}
+				out.SetMapIndex(k, v)
+			}
+			dst.Set(out)
+			return nil
+		}
+
+	default:
+		// 整数可以转换为 string（得到对应的字符），这里不允许
+		if src.Type().ConvertibleTo(dst.Type()) && (dst.Kind() != reflect.String || src.Kind() == reflect.String) {
+			dst.Set(src.Convert(dst.Type()))
+			return nil
+		}
+	}
+
+	if path == "" {
+		path = "."
+	}
+	return fmt.Errorf("%w: %s: %s to %s", ErrConvert, path, src.Type(), dst.Type())
+}
+
+func convertStruct(src, dst reflect.Value, path string) error {
+	srcFields := jsonFields(src.Type())
+	byName := make(map[string]jsonField, len(srcFields))
+	for _, f := range srcFields {
+		byName[f.name] = f
+	}
+
+	for _, df := range jsonFields(dst.Type()) {
+		sf, ok := byName[df.name]
+		if !ok {
+			for _, f := range srcFields {
+				if strings.EqualFold(f.name, df.name) {
+					sf, ok = f, true
+					break
+				}
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		sv, err := src.FieldByIndexErr(sf.index)
+		if err != nil {
+			// 嵌入的指针为 nil，没有可复制的值
+			continue
+		}
+		if err := convertValue(sv, fieldByIndexAlloc(dst, df.index), path+"."+df.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type jsonField struct {
+	name  string
+	index []int
+}
+
+// jsonFields 按 encoding/json 的规则列出可导出字段：外层字段优先于嵌入结构体中的同名字段
+func jsonFields(t reflect.Type) []jsonField {
+	var fields []jsonField
+	seen := make(map[string]bool)
+	var embedded []reflect.StructField
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			embedded = append(embedded, f)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, jsonField{name: name, index: []int{i}})
+		seen[name] = true
+	}
+
+	for _, f := range embedded {
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		for _, inner := range jsonFields(ft) {
+			if seen[inner.name] {
+				continue
+			}
+			seen[inner.name] = true
+			fields = append(fields, jsonField{name: inner.name, index: append([]int{f.Index[0]}, inner.index...)})
+		}
+	}
+	return fields
+}
+
+// fieldByIndexAlloc 同 FieldByIndex，遇到 nil 的嵌入指针时分配
+func fieldByIndexAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
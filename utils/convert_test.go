@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConvertByJSONTags(t *testing.T) {
+	type apiItem struct {
+		SKU string `json:"sku"`
+		Qty int32  `json:"qty"`
+	}
+	type apiMeta struct {
+		Source string `json:"source"`
+	}
+	type apiOrder struct {
+		apiMeta
+		OrderNo string            `json:"order_no"`
+		Items   []apiItem         `json:"items"`
+		Buyer   *apiItem          `json:"buyer"`
+		Tags    map[string]string `json:"tags"`
+		Secret  string            `json:"-"`
+	}
+
+	type item struct {
+		Code     string `json:"sku"`
+		Quantity int64  `json:"qty"`
+	}
+	type order struct {
+		Number string            `json:"order_no"`
+		Lines  []item            `json:"items"`
+		Buyer  item              `json:"buyer"`
+		Tags   map[string]string `json:"tags"`
+		Source string
+		Secret string `json:"secret"`
+	}
+
+	src := apiOrder{
+		apiMeta: apiMeta{Source: "shop"},
+		OrderNo: "A1",
+		Items:   []apiItem{{SKU: "x", Qty: 2}, {SKU: "y", Qty: 3}},
+		Buyer:   &apiItem{SKU: "u1"},
+		Tags:    map[string]string{"k": "v"},
+		Secret:  "s",
+	}
+	var dst order
+	if err := ConvertByJSONTags(&src, &dst); err != nil {
+		t.Fatalf("ConvertByJSONTags: %v", err)
+	}
+	if dst.Number != "A1" || len(dst.Lines) != 2 || dst.Lines[1].Code != "y" || dst.Lines[1].Quantity != 3 ||
+		dst.Buyer.Code != "u1" || dst.Tags["k"] != "v" || dst.Source != "shop" || dst.Secret != "" {
+		t.Fatalf("unexpected result: %+v", dst)
+	}
+
+	// 无法转换时返回带字段路径的错误
+	type bad struct {
+		Items string `json:"items"`
+	}
+	err := ConvertByJSONTags(src, &bad{})
+	if !errors.Is(err, ErrConvert) {
+		t.Fatalf("expected ErrConvert, got %v", err)
+	}
+
+	if err := ConvertByJSONTags(src, dst); err == nil {
+		t.Fatal("expected error for non-pointer dst")
+	}
+}
@@ -0,0 +1,291 @@
+package fieldcrypt
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/jiajia556/tool-box/cryptox"
+)
+
+var (
+	ErrInvalidKey = errors.New("fieldcrypt: key must be 32 bytes")
+	ErrNoKey      = errors.New("fieldcrypt: default key not set")
+	ErrDecrypt    = errors.New("fieldcrypt: decrypt failed")
+	ErrNotPointer = errors.New("fieldcrypt: target must be a non-nil pointer to struct")
+)
+
+// Cipher 基于 cryptox 的 AES-256-GCM 字段加解密，密文格式见 cryptox.EncryptWithKey
+type Cipher struct {
+	key []byte
+}
+
+// New 使用 32 字节的 key 创建 Cipher
+func New(key []byte) (*Cipher, error) {
+	if len(key) != 32 {
+		return nil, ErrInvalidKey
+	}
+	return &Cipher{key: bytes.Clone(key)}, nil
+}
+
+// Encrypt 加密，每次使用随机 nonce，相同明文的密文不同
+func (c *Cipher) Encrypt(plain []byte) ([]byte, error) {
+	return cryptox.EncryptWithKey(cryptox.AESGCM, c.key, plain)
+}
+
+// Decrypt 解密 Encrypt 的结果，密文被篡改或密钥不匹配时返回 ErrDecrypt
+func (c *Cipher) Decrypt(data []byte) ([]byte, error) {
+	plain, err := cryptox.DecryptWithKey(c.key, data)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plain, nil
+}
+
+// EncryptString 加密字符串，结果为 base64 编码
+func (c *Cipher) EncryptString(s string) (string, error) {
+	b, err := c.Encrypt([]byte(s))
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// DecryptString 解密 EncryptString 的结果
+func (c *Cipher) DecryptString(s string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return "", ErrDecrypt
+	}
+	plain, err := c.Decrypt(b)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// EncryptFields 加密 v（结构体指针）中带 `encrypt:"true"` 标签的 string / []byte 字段，
+// string 字段替换为 base64 密文，[]byte 字段替换为原始密文；嵌套结构体与结构体指针递归处理
+func (c *Cipher) EncryptFields(v any) error {
+	return c.walk(v, func(f reflect.Value) error {
+		switch f.Kind() {
+		case reflect.String:
+			s, err := c.EncryptString(f.String())
+			if err != nil {
+				return err
+			}
+			f.SetString(s)
+		case reflect.Slice:
+			b, err := c.Encrypt(f.Bytes())
+			if err != nil {
+				return err
+			}
+			f.SetBytes(b)
+		}
+		return nil
+	})
+}
+
+// DecryptFields 解密 EncryptFields 加密的字段
+func (c *Cipher) DecryptFields(v any) error {
+	return c.walk(v, func(f reflect.Value) error {
+		switch f.Kind() {
+		case reflect.String:
+			s, err := c.DecryptString(f.String())
+			if err != nil {
+				return err
+			}
+			f.SetString(s)
+		case reflect.Slice:
+			b, err := c.Decrypt(f.Bytes())
+			if err != nil {
+				return err
+			}
+			f.SetBytes(b)
+		}
+		return nil
+	})
+}
+
+func (c *Cipher) walk(v any, fn func(reflect.Value) error) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrNotPointer
+	}
+	return walkStruct(rv.Elem(), fn)
+}
+
+var bytesType = reflect.TypeOf([]byte(nil))
+
+func walkStruct(rv reflect.Value, fn func(reflect.Value) error) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		f := rv.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		if sf.Tag.Get("encrypt") == "true" {
+			// 空值不加密，便于区分未填写的字段
+			if f.Kind() == reflect.String && f.Len() > 0 || f.Type() == bytesType && f.Len() > 0 {
+				if err := fn(f); err != nil {
+					return fmt.Errorf("fieldcrypt: field %s: %w", sf.Name, err)
+				}
+			}
+			continue
+		}
+
+		switch {
+		case f.Kind() == reflect.Struct:
+			if err := walkStruct(f, fn); err != nil {
+				return err
+			}
+		case f.Kind() == reflect.Pointer && !f.IsNil() && f.Elem().Kind() == reflect.Struct:
+			if err := walkStruct(f.Elem(), fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+var (
+	defaultMu     sync.RWMutex
+	defaultCipher *Cipher
+)
+
+// SetKey 设置默认密钥，供 EncryptStruct / DecryptStruct 与 String / Bytes 的数据库读写使用
+func SetKey(key []byte) error {
+	c, err := New(key)
+	if err != nil {
+		return err
+	}
+	defaultMu.Lock()
+	defaultCipher = c
+	defaultMu.Unlock()
+	return nil
+}
+
+func getDefault() (*Cipher, error) {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	if defaultCipher == nil {
+		return nil, ErrNoKey
+	}
+	return defaultCipher, nil
+}
+
+// EncryptStruct 使用默认密钥加密 v 中带 `encrypt:"true"` 标签的字段
+func EncryptStruct(v any) error {
+	c, err := getDefault()
+	if err != nil {
+		return err
+	}
+	return c.EncryptFields(v)
+}
+
+// DecryptStruct 使用默认密钥解密 v 中带 `encrypt:"true"` 标签的字段
+func DecryptStruct(v any) error {
+	c, err := getDefault()
+	if err != nil {
+		return err
+	}
+	return c.DecryptFields(v)
+}
+
+// String 存入数据库时使用默认密钥加密的字符串列（保存为 base64 文本），读出时自动解密
+type String string
+
+func (s *String) Scan(value interface{}) error {
+	if value == nil {
+		*s = ""
+		return nil
+	}
+
+	var raw string
+	switch v := value.(type) {
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into fieldcrypt.String", value)
+	}
+	if raw == "" {
+		*s = ""
+		return nil
+	}
+
+	c, err := getDefault()
+	if err != nil {
+		return err
+	}
+	plain, err := c.DecryptString(raw)
+	if err != nil {
+		return err
+	}
+	*s = String(plain)
+	return nil
+}
+
+func (s String) Value() (driver.Value, error) {
+	if s == "" {
+		return "", nil
+	}
+	c, err := getDefault()
+	if err != nil {
+		return nil, err
+	}
+	return c.EncryptString(string(s))
+}
+
+// Bytes 存入数据库时使用默认密钥加密的二进制列，读出时自动解密
+type Bytes []byte
+
+func (b *Bytes) Scan(value interface{}) error {
+	if value == nil {
+		*b = nil
+		return nil
+	}
+
+	var raw []byte
+	switch v := value.(type) {
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into fieldcrypt.Bytes", value)
+	}
+	if len(raw) == 0 {
+		*b = Bytes{}
+		return nil
+	}
+
+	c, err := getDefault()
+	if err != nil {
+		return err
+	}
+	plain, err := c.Decrypt(raw)
+	if err != nil {
+		return err
+	}
+	*b = plain
+	return nil
+}
+
+func (b Bytes) Value() (driver.Value, error) {
+	if b == nil {
+		return nil, nil
+	}
+	c, err := getDefault()
+	if err != nil {
+		return nil, err
+	}
+	return c.Encrypt(b)
+}
@@ -0,0 +1,85 @@
+package fieldcrypt
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestFields(t *testing.T) {
+	c, err := New([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type profile struct {
+		IDCard string `encrypt:"true"`
+	}
+	type user struct {
+		Name    string
+		Phone   string `encrypt:"true"`
+		Secret  []byte `encrypt:"true"`
+		Empty   string `encrypt:"true"`
+		Profile *profile
+	}
+
+	u := user{Name: "alice", Phone: "13800000000", Secret: []byte("s3"), Profile: &profile{IDCard: "110101"}}
+	if err := c.EncryptFields(&u); err != nil {
+		t.Fatal(err)
+	}
+	if u.Name != "alice" || u.Phone == "13800000000" || bytes.Equal(u.Secret, []byte("s3")) ||
+		u.Empty != "" || u.Profile.IDCard == "110101" {
+		t.Fatalf("unexpected encrypted struct: %+v", u)
+	}
+
+	if err := c.DecryptFields(&u); err != nil {
+		t.Fatal(err)
+	}
+	if u.Phone != "13800000000" || string(u.Secret) != "s3" || u.Profile.IDCard != "110101" {
+		t.Fatalf("unexpected decrypted struct: %+v", u)
+	}
+
+	if err := c.EncryptFields(u); !errors.Is(err, ErrNotPointer) {
+		t.Fatalf("want ErrNotPointer, got %v", err)
+	}
+
+	other, _ := New([]byte("fedcba9876543210fedcba9876543210"))
+	s, _ := c.EncryptString("x")
+	if _, err := other.DecryptString(s); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("want ErrDecrypt, got %v", err)
+	}
+}
+
+func TestScannerValuer(t *testing.T) {
+	if err := SetKey([]byte("0123456789abcdef0123456789abcdef")); err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := String("secret").Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v == "secret" {
+		t.Fatal("value not encrypted")
+	}
+
+	var s String
+	if err := s.Scan(v); err != nil {
+		t.Fatal(err)
+	}
+	if s != "secret" {
+		t.Fatalf("got %q", s)
+	}
+
+	bv, err := Bytes("raw").Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b Bytes
+	if err := b.Scan(bv); err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "raw" {
+		t.Fatalf("got %q", b)
+	}
+}
@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+type jsonOptions struct {
+	disallowUnknown bool
+}
+
+// JSONOption DecodeJSON 的选项
+type JSONOption func(*jsonOptions)
+
+// DisallowUnknownFields 解码到结构体时遇到未定义的字段返回错误
+func DisallowUnknownFields() JSONOption {
+	return func(o *jsonOptions) { o.disallowUnknown = true }
+}
+
+// DecodeJSON 将 data 解码到 out（必须是非 nil 指针），与 json.Unmarshal 不同，
+// 解码到 any（包括 map[string]any、[]any 及结构体中的 any 字段）的数字保留整数类型：
+//   - 整数优先解码为 int64，超出 int64 的正整数解码为 uint64
+//   - 带小数点、指数或超出 uint64 的数字解码为 float64
+//
+// 避免大整数（如雪花 ID）经 float64 丢失精度，读回后也可以直接断言为整数类型。
+// data 中只能有一个 JSON 值，其后出现多余内容时返回错误。
+func DecodeJSON(data []byte, out any, opts ...JSONOption) error {
+	var o jsonOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+
+	ov := reflect.ValueOf(out)
+	if ov.Kind() != reflect.Pointer || ov.IsNil() {
+		return errors.New("utils: DecodeJSON out must be a non-nil pointer")
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if o.disallowUnknown {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(out); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("utils: DecodeJSON unexpected data after top-level value")
+	}
+
+	normalizeNumbers(ov.Elem())
+	return nil
+}
+
+// normalizeNumbers 将 v 中 any 位置上的 json.Number 转换为 int64 / uint64 / float64，
+// 类型为 json.Number 的字段保持不变
+func normalizeNumbers(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() || v.NumMethod() != 0 || !v.CanSet() {
+			return
+		}
+		v.Set(reflect.ValueOf(normalizeAny(v.Interface())))
+	case reflect.Pointer:
+		if !v.IsNil() {
+			normalizeNumbers(v.Elem())
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				normalizeNumbers(v.Field(i))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			normalizeNumbers(v.Index(i))
+		}
+	case reflect.Map:
+		if v.IsNil() || !mayHoldNumber(v.Type().Elem()) {
+			return
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			// map 的值不可寻址，复制后处理再写回
+			ev := reflect.New(v.Type().Elem()).Elem()
+			ev.Set(iter.Value())
+			normalizeNumbers(ev)
+			v.SetMapIndex(iter.Key(), ev)
+		}
+	}
+}
+
+// mayHoldNumber 判断类型 t 的值中是否可能存在需要转换的 json.Number
+func mayHoldNumber(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface, reflect.Struct:
+		return true
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return mayHoldNumber(t.Elem())
+	}
+	return false
+}
+
+// normalizeAny 转换 encoding/json 解码到 any 的结果
+func normalizeAny(x any) any {
+	switch t := x.(type) {
+	case json.Number:
+		return parseNumber(t)
+	case map[string]any:
+		for k, v := range t {
+			t[k] = normalizeAny(v)
+		}
+	case []any:
+		for i, v := range t {
+			t[i] = normalizeAny(v)
+		}
+	}
+	return x
+}
+
+func parseNumber(n json.Number) any {
+	s := string(n)
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i
+	}
+	if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return u
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	// 超出 float64 范围时保留原始文本
+	return n
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	var m map[string]any
+	data := []byte(`{"id":9007199254740993,"big":18446744073709551615,"ratio":1.5,"exp":1e3,"list":[1,{"n":-2}]}`)
+	if err := DecodeJSON(data, &m); err != nil {
+		t.Fatalf("DecodeJSON: %v", err)
+	}
+	if v, ok := m["id"].(int64); !ok || v != 9007199254740993 {
+		t.Fatalf("id = %#v, want int64 9007199254740993", m["id"])
+	}
+	if v, ok := m["big"].(uint64); !ok || v != math.MaxUint64 {
+		t.Fatalf("big = %#v, want uint64 max", m["big"])
+	}
+	if v, ok := m["ratio"].(float64); !ok || v != 1.5 {
+		t.Fatalf("ratio = %#v, want float64 1.5", m["ratio"])
+	}
+	if v, ok := m["exp"].(float64); !ok || v != 1000 {
+		t.Fatalf("exp = %#v, want float64 1000", m["exp"])
+	}
+	list := m["list"].([]any)
+	if v, ok := list[0].(int64); !ok || v != 1 {
+		t.Fatalf("list[0] = %#v, want int64 1", list[0])
+	}
+	if v, ok := list[1].(map[string]any)["n"].(int64); !ok || v != -2 {
+		t.Fatalf("list[1].n = %#v, want int64 -2", list[1])
+	}
+
+	type payload struct {
+		Count int64            `json:"count"`
+		Extra any              `json:"extra"`
+		Raw   json.Number      `json:"raw"`
+		Attrs map[string]any   `json:"attrs"`
+		Items []map[string]any `json:"items"`
+	}
+	var p payload
+	data = []byte(`{"count":3,"extra":42,"raw":7,"attrs":{"a":1},"items":[{"b":2.5}]}`)
+	if err := DecodeJSON(data, &p); err != nil {
+		t.Fatalf("DecodeJSON struct: %v", err)
+	}
+	if p.Count != 3 || p.Extra != int64(42) || p.Raw != "7" {
+		t.Fatalf("payload = %+v", p)
+	}
+	if p.Attrs["a"] != int64(1) || p.Items[0]["b"] != 2.5 {
+		t.Fatalf("nested = %+v %+v", p.Attrs, p.Items)
+	}
+
+	var v any
+	if err := DecodeJSON([]byte(`12`), &v); err != nil || v != int64(12) {
+		t.Fatalf("scalar = %#v, %v", v, err)
+	}
+}
+
+func TestDecodeJSON_Errors(t *testing.T) {
+	type strict struct {
+		Name string `json:"name"`
+	}
+	var s strict
+	if err := DecodeJSON([]byte(`{"name":"a","age":1}`), &s); err != nil {
+		t.Fatalf("unknown fields should be ignored by default: %v", err)
+	}
+	if err := DecodeJSON([]byte(`{"name":"a","age":1}`), &s, DisallowUnknownFields()); err == nil {
+		t.Fatal("expected error for unknown field")
+	}
+	if err := DecodeJSON([]byte(`{"name":"a"} {}`), &s); err == nil {
+		t.Fatal("expected error for trailing data")
+	}
+	if err := DecodeJSON([]byte(`{}`), s); err == nil {
+		t.Fatal("expected error for non-pointer out")
+	}
+}
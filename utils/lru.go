@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"container/list"
+	"sync"
+)
+
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// LRUMap 并发安全的定长 LRU 映射，超出容量时淘汰最久未访问的元素
+type LRUMap[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[K]*list.Element
+	onEvict  func(key K, value V)
+}
+
+// NewLRUMap 创建 LRU 映射，capacity 小于 1 时按 1 处理；
+// onEvict 在元素因容量被淘汰时调用（不含 Delete / Clear），可为 nil
+func NewLRUMap[K comparable, V any](capacity int, onEvict func(key K, value V)) *LRUMap[K, V] {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &LRUMap[K, V]{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[K]*list.Element),
+		onEvict:  onEvict,
+	}
+}
+
+// Get 获取值并标记为最近访问
+func (m *LRUMap[K, V]) Get(k K) (V, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if e, ok := m.items[k]; ok {
+		m.ll.MoveToFront(e)
+		return e.Value.(*lruEntry[K, V]).value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Peek 获取值，不影响访问顺序
+func (m *LRUMap[K, V]) Peek(k K) (V, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if e, ok := m.items[k]; ok {
+		return e.Value.(*lruEntry[K, V]).value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Set 写入值，返回是否发生了淘汰
+func (m *LRUMap[K, V]) Set(k K, v V) bool {
+	m.mu.Lock()
+	if e, ok := m.items[k]; ok {
+		m.ll.MoveToFront(e)
+		e.Value.(*lruEntry[K, V]).value = v
+		m.mu.Unlock()
+		return false
+	}
+
+	m.items[k] = m.ll.PushFront(&lruEntry[K, V]{key: k, value: v})
+
+	var evicted *lruEntry[K, V]
+	if m.ll.Len() > m.capacity {
+		oldest := m.ll.Back()
+		m.ll.Remove(oldest)
+		evicted = oldest.Value.(*lruEntry[K, V])
+		delete(m.items, evicted.key)
+	}
+	m.mu.Unlock()
+
+	if evicted == nil {
+		return false
+	}
+	if m.onEvict != nil {
+		m.onEvict(evicted.key, evicted.value)
+	}
+	return true
+}
+
+// Delete 删除元素，返回是否存在
+func (m *LRUMap[K, V]) Delete(k K) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.items[k]
+	if ok {
+		m.ll.Remove(e)
+		delete(m.items, k)
+	}
+	return ok
+}
+
+// Len 返回元素个数
+func (m *LRUMap[K, V]) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.ll.Len()
+}
+
+// Keys 按最近访问到最久未访问的顺序返回所有 key
+func (m *LRUMap[K, V]) Keys() []K {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([]K, 0, m.ll.Len())
+	for e := m.ll.Front(); e != nil; e = e.Next() {
+		keys = append(keys, e.Value.(*lruEntry[K, V]).key)
+	}
+	return keys
+}
+
+// Clear 清空所有元素
+func (m *LRUMap[K, V]) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.ll.Init()
+	m.items = make(map[K]*list.Element)
+}
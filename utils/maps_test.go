@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLRUMap(t *testing.T) {
+	var evicted []string
+	m := NewLRUMap[string, int](2, func(k string, v int) { evicted = append(evicted, k) })
+
+	m.Set("a", 1)
+	m.Set("b", 2)
+	m.Get("a") // a 变为最近访问
+	if !m.Set("c", 3) {
+		t.Fatalf("expected eviction")
+	}
+	if _, ok := m.Get("b"); ok || len(evicted) != 1 || evicted[0] != "b" {
+		t.Fatalf("expected b to be evicted, evicted=%v", evicted)
+	}
+	if keys := m.Keys(); len(keys) != 2 || keys[0] != "c" || keys[1] != "a" {
+		t.Fatalf("unexpected order %v", keys)
+	}
+}
+
+func TestTTLMap(t *testing.T) {
+	var expired int32
+	m := NewTTLMap[string, int](20*time.Millisecond, 0, func(k string, v int) { atomic.AddInt32(&expired, 1) })
+	defer m.Close()
+
+	m.Set("a", 1)
+	m.SetWithTTL("b", 2, 0)
+	if v, ok := m.Get("a"); !ok || v != 1 {
+		t.Fatalf("expected a=1")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	m.Cleanup()
+	if _, ok := m.Get("a"); ok {
+		t.Fatalf("expected a to expire")
+	}
+	if _, ok := m.Get("b"); !ok {
+		t.Fatalf("expected b to never expire")
+	}
+	if atomic.LoadInt32(&expired) != 1 {
+		t.Fatalf("expected one expire callback, got %d", expired)
+	}
+}
@@ -0,0 +1,67 @@
+package optionbuilder
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type retryConfig struct {
+	Attempts int
+}
+
+type testConfig struct {
+	TTL    time.Duration
+	Name   string `option:"Label"`
+	Tags   []string
+	Retry  retryConfig
+	Secret string `option:"-"`
+	hidden int
+}
+
+type testOption func(*testConfig)
+
+var (
+	withTTL      = Field[testOption, time.Duration]("TTL")
+	withName     = Field[testOption, string]("Name")
+	withAttempts = Field[testOption, int]("Retry.Attempts")
+)
+
+func TestField(t *testing.T) {
+	var c testConfig
+	for _, opt := range []testOption{withTTL(time.Second), withName("a"), withAttempts(3)} {
+		opt(&c)
+	}
+	if c.TTL != time.Second || c.Name != "a" || c.Retry.Attempts != 3 {
+		t.Fatalf("unexpected config: %+v", c)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic on type mismatch")
+		}
+	}()
+	Field[testOption, int]("TTL")
+}
+
+func TestGenerate(t *testing.T) {
+	src, err := Generate(testConfig{}, "demo", "testOption", "time")
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	s := string(src)
+	for _, want := range []string{
+		"func WithTTL(v time.Duration) testOption",
+		"func WithLabel(v string) testOption",
+		"func WithTags(v []string) testOption",
+		"func WithRetry(v retryConfig) testOption",
+		"t.Name = v",
+	} {
+		if !strings.Contains(s, want) {
+			t.Fatalf("generated source missing %q:\n%s", want, s)
+		}
+	}
+	if strings.Contains(s, "WithSecret") || strings.Contains(s, "hidden") {
+		t.Fatalf("generated source contains skipped fields:\n%s", s)
+	}
+}
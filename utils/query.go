@@ -0,0 +1,293 @@
+package utils
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrBindTarget = errors.New("bind query: out must be a non-nil pointer to struct")
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+)
+
+// BindQuery 将 url.Values 绑定到结构体，字段名取 `form` 标签（"-" 表示忽略），未设置时使用字段名。
+// 支持基础类型、指针、切片（同名多值）、time.Time（RFC3339）、time.Duration，
+// 以及实现 encoding.TextUnmarshaler 或 json.Unmarshaler 的类型（如 timex.Date）；嵌入结构体会被展开。
+func BindQuery(values url.Values, out any) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrBindTarget
+	}
+	return bindStruct(values, rv.Elem())
+}
+
+func bindStruct(values url.Values, rv reflect.Value) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name, _, skip := formTag(sf)
+		if skip {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if sf.Anonymous && sf.Tag.Get("form") == "" && indirectType(sf.Type).Kind() == reflect.Struct && !isScalar(indirectType(sf.Type)) {
+			if fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					fv.Set(reflect.New(sf.Type.Elem()))
+				}
+				fv = fv.Elem()
+			}
+			if err := bindStruct(values, fv); err != nil {
+				return err
+			}
+			continue
+		}
+
+		vals, ok := values[name]
+		if !ok || len(vals) == 0 {
+			continue
+		}
+		if err := setField(fv, vals); err != nil {
+			return fmt.Errorf("bind query: field %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func setField(fv reflect.Value, vals []string) error {
+	if fv.Kind() == reflect.Slice && !isScalar(fv.Type()) {
+		slice := reflect.MakeSlice(fv.Type(), len(vals), len(vals))
+		for i, s := range vals {
+			if err := setValue(slice.Index(i), s); err != nil {
+				return err
+			}
+		}
+		fv.Set(slice)
+		return nil
+	}
+	return setValue(fv, vals[0])
+}
+
+func setValue(fv reflect.Value, s string) error {
+	if fv.Kind() == reflect.Pointer {
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		return setValue(fv.Elem(), s)
+	}
+
+	if fv.CanAddr() {
+		pt := fv.Addr().Type()
+		switch {
+		// 优先使用 json.Unmarshaler：timex.Date 等嵌入 time.Time 的类型只重写了 JSON 编解码
+		case pt.Implements(jsonUnmarshalerType):
+			return fv.Addr().Interface().(json.Unmarshaler).UnmarshalJSON([]byte(strconv.Quote(s)))
+		case pt.Implements(textUnmarshalerType):
+			return fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+		}
+	}
+
+	switch fv.Type() {
+	case durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(int64(d))
+		return nil
+	case timeType:
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported type %s", fv.Type())
+	}
+	return nil
+}
+
+// ToQuery 将结构体转换为 url.Values，规则与 BindQuery 对应；
+// 标签带 omitempty（如 `form:"page,omitempty"`）时忽略零值，nil 指针与无法转换的字段总是忽略。
+// v 不是结构体时返回空的 url.Values。
+func ToQuery(v any) url.Values {
+	values := url.Values{}
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return values
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() == reflect.Struct {
+		encodeStruct(values, rv)
+	}
+	return values
+}
+
+func encodeStruct(values url.Values, rv reflect.Value) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name, omitempty, skip := formTag(sf)
+		if skip {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if sf.Anonymous && sf.Tag.Get("form") == "" && indirectType(sf.Type).Kind() == reflect.Struct && !isScalar(indirectType(sf.Type)) {
+			if fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			encodeStruct(values, fv)
+			continue
+		}
+
+		if fv.Kind() == reflect.Pointer && fv.IsNil() {
+			continue
+		}
+		if omitempty && fv.IsZero() {
+			continue
+		}
+
+		if fv.Kind() == reflect.Slice && !isScalar(fv.Type()) {
+			for j := 0; j < fv.Len(); j++ {
+				if s, ok := formatValue(fv.Index(j)); ok {
+					values.Add(name, s)
+				}
+			}
+			continue
+		}
+
+		if s, ok := formatValue(fv); ok {
+			values.Set(name, s)
+		}
+	}
+}
+
+func formatValue(fv reflect.Value) (string, bool) {
+	for fv.Kind() == reflect.Pointer {
+		if fv.IsNil() {
+			return "", false
+		}
+		fv = fv.Elem()
+	}
+
+	switch fv.Type() {
+	case durationType:
+		return time.Duration(fv.Int()).String(), true
+	case timeType:
+		return fv.Interface().(time.Time).Format(time.RFC3339), true
+	}
+
+	switch x := fv.Interface().(type) {
+	case json.Marshaler:
+		b, err := x.MarshalJSON()
+		if err != nil || string(b) == "null" {
+			return "", false
+		}
+		if s, err := strconv.Unquote(string(b)); err == nil {
+			return s, true
+		}
+		return string(b), true
+	case encoding.TextMarshaler:
+		b, err := x.MarshalText()
+		return string(b), err == nil
+	case fmt.Stringer:
+		return x.String(), true
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		return fv.String(), true
+	case reflect.Bool:
+		return strconv.FormatBool(fv.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(fv.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(fv.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(fv.Float(), 'f', -1, fv.Type().Bits()), true
+	}
+	return "", false
+}
+
+// formTag 解析 form 标签，返回字段名、是否 omitempty、是否忽略
+func formTag(sf reflect.StructField) (name string, omitempty, skip bool) {
+	tag := sf.Tag.Get("form")
+	if tag == "-" {
+		return "", false, true
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = sf.Name
+	}
+	return name, opts == "omitempty", false
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// isScalar 判断类型是否作为单个值处理（time.Time、实现了解码接口的结构体、[]byte 等）
+func isScalar(t reflect.Type) bool {
+	if t == timeType || t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+		return true
+	}
+	pt := reflect.PointerTo(t)
+	return pt.Implements(textUnmarshalerType) || pt.Implements(jsonUnmarshalerType)
+}
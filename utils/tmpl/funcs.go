@@ -0,0 +1,196 @@
+package tmpl
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	"github.com/jiajia556/tool-box/timex"
+)
+
+// FuncMap 返回内置模板函数：
+//   - 日期：date（格式化 time.Time / timex.Date / timex.DateTime，未指定格式时使用 timex 的默认格式）、now
+//   - 字符串：upper、lower、title、trim、camel、snake、kebab、replace、contains、hasPrefix、hasSuffix、join、split
+//   - 取值：default（值为空时使用默认值）、coalesce（返回第一个非空值）、ternary
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"date":      date,
+		"now":       time.Now,
+		"upper":     strings.ToUpper,
+		"lower":     strings.ToLower,
+		"title":     title,
+		"trim":      strings.TrimSpace,
+		"camel":     camel,
+		"snake":     func(s string) string { return delimited(s, '_') },
+		"kebab":     func(s string) string { return delimited(s, '-') },
+		"replace":   func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		"contains":  func(substr, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix": func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix": func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"join":      join,
+		"split":     func(sep, s string) []string { return strings.Split(s, sep) },
+		"default":   dfault,
+		"coalesce":  coalesce,
+		"ternary":   ternary,
+	}
+}
+
+// date 格式化时间：{{date .CreatedAt}}、{{date .Birthday "2006/01/02"}}
+func date(v any, layout ...string) (string, error) {
+	var t time.Time
+	var def string
+	switch x := v.(type) {
+	case timex.Date:
+		t, def = x.Time, timex.DateFormat
+	case *timex.Date:
+		if x == nil {
+			return "", nil
+		}
+		t, def = x.Time, timex.DateFormat
+	case timex.DateTime:
+		t, def = x.Time, timex.DateTimeFormat
+	case *timex.DateTime:
+		if x == nil {
+			return "", nil
+		}
+		t, def = x.Time, timex.DateTimeFormat
+	case time.Time:
+		t, def = x, timex.DateTimeFormat
+	case *time.Time:
+		if x == nil {
+			return "", nil
+		}
+		t, def = *x, timex.DateTimeFormat
+	case int64:
+		t, def = time.Unix(x, 0), timex.DateTimeFormat
+	case int:
+		t, def = time.Unix(int64(x), 0), timex.DateTimeFormat
+	default:
+		return "", fmt.Errorf("tmpl: date: unsupported type %T", v)
+	}
+
+	if t.IsZero() {
+		return "", nil
+	}
+	if len(layout) > 0 && layout[0] != "" {
+		def = layout[0]
+	}
+	return t.Format(def), nil
+}
+
+func title(s string) string {
+	rs := []rune(s)
+	for i, r := range rs {
+		if i == 0 || unicode.IsSpace(rs[i-1]) || rs[i-1] == '-' || rs[i-1] == '_' {
+			rs[i] = unicode.ToTitle(r)
+		}
+	}
+	return string(rs)
+}
+
+// words 按空白、分隔符与大小写边界拆分单词
+func words(s string) []string {
+	var out []string
+	var cur []rune
+	flush := func() {
+		if len(cur) > 0 {
+			out = append(out, string(cur))
+			cur = cur[:0]
+		}
+	}
+
+	rs := []rune(s)
+	for i, r := range rs {
+		switch {
+		case unicode.IsSpace(r) || r == '_' || r == '-' || r == '.':
+			flush()
+		case unicode.IsUpper(r):
+			// "userID" -> user ID，"HTTPServer" -> HTTP Server
+			if i > 0 && (unicode.IsLower(rs[i-1]) || unicode.IsDigit(rs[i-1]) ||
+				unicode.IsUpper(rs[i-1]) && i+1 < len(rs) && unicode.IsLower(rs[i+1])) {
+				flush()
+			}
+			cur = append(cur, r)
+		default:
+			cur = append(cur, r)
+		}
+	}
+	flush()
+	return out
+}
+
+func camel(s string) string {
+	var b strings.Builder
+	for i, w := range words(s) {
+		w = strings.ToLower(w)
+		if i > 0 {
+			rs := []rune(w)
+			rs[0] = unicode.ToUpper(rs[0])
+			w = string(rs)
+		}
+		b.WriteString(w)
+	}
+	return b.String()
+}
+
+func delimited(s string, sep rune) string {
+	ws := words(s)
+	for i, w := range ws {
+		ws[i] = strings.ToLower(w)
+	}
+	return strings.Join(ws, string(sep))
+}
+
+func join(sep string, v any) string {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return fmt.Sprint(v)
+	}
+	parts := make([]string, rv.Len())
+	for i := range parts {
+		parts[i] = fmt.Sprint(rv.Index(i).Interface())
+	}
+	return strings.Join(parts, sep)
+}
+
+// dfault 用法与 sprig 一致：{{.Name | default "匿名"}}
+func dfault(def any, v ...any) any {
+	if len(v) == 0 || empty(v[0]) {
+		return def
+	}
+	return v[0]
+}
+
+func coalesce(v ...any) any {
+	for _, x := range v {
+		if !empty(x) {
+			return x
+		}
+	}
+	return nil
+}
+
+// ternary 条件为真返回 a，否则返回 b：{{ternary "是" "否" .OK}}
+func ternary(a, b any, cond bool) any {
+	if cond {
+		return a
+	}
+	return b
+}
+
+func empty(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array, reflect.String:
+		return rv.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return rv.IsNil()
+	}
+	return rv.IsZero()
+}
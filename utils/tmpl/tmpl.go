@@ -0,0 +1,186 @@
+package tmpl
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sync"
+	"text/template"
+)
+
+var ErrNoFS = errors.New("tmpl: file system is nil")
+
+// Config 模板引擎配置
+type Config struct {
+	// 额外的模板函数，与内置函数同名时覆盖内置函数
+	Funcs template.FuncMap
+
+	// 布局与公共片段的匹配模式，与每个模板文件一起解析
+	Layouts []string
+
+	// 不缓存解析结果，每次渲染重新读取文件（开发环境热更新）
+	NoCache bool
+}
+
+// Option 选项函数
+type Option func(*Config)
+
+// WithFuncs 添加模板函数
+func WithFuncs(funcs template.FuncMap) Option {
+	return func(c *Config) {
+		if c.Funcs == nil {
+			c.Funcs = template.FuncMap{}
+		}
+		for k, v := range funcs {
+			c.Funcs[k] = v
+		}
+	}
+}
+
+// WithLayouts 设置布局与公共片段的匹配模式
+func WithLayouts(patterns ...string) Option {
+	return func(c *Config) {
+		c.Layouts = append(c.Layouts, patterns...)
+	}
+}
+
+// WithNoCache 设置是否禁用解析缓存
+func WithNoCache(noCache bool) Option {
+	return func(c *Config) {
+		c.NoCache = noCache
+	}
+}
+
+// Engine 基于 text/template 的模板引擎，模板来自 fs.FS（目录或 embed.FS），解析结果按文件缓存
+type Engine struct {
+	fsys   fs.FS
+	config Config
+	funcs  template.FuncMap
+
+	mu    sync.RWMutex
+	cache map[string]*template.Template
+}
+
+// New 创建模板引擎
+func New(fsys fs.FS, opts ...Option) *Engine {
+	var config Config
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	funcs := FuncMap()
+	for k, v := range config.Funcs {
+		funcs[k] = v
+	}
+	return &Engine{
+		fsys:   fsys,
+		config: config,
+		funcs:  funcs,
+		cache:  make(map[string]*template.Template),
+	}
+}
+
+// NewDir 创建从目录加载模板的引擎
+func NewDir(dir string, opts ...Option) *Engine {
+	return New(os.DirFS(dir), opts...)
+}
+
+// Lookup 返回已解析的模板 name（文件路径），首次调用时解析并缓存
+func (e *Engine) Lookup(name string) (*template.Template, error) {
+	if e.fsys == nil {
+		return nil, ErrNoFS
+	}
+
+	if !e.config.NoCache {
+		e.mu.RLock()
+		t, ok := e.cache[name]
+		e.mu.RUnlock()
+		if ok {
+			return t, nil
+		}
+	}
+
+	t, err := e.parse(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !e.config.NoCache {
+		e.mu.Lock()
+		e.cache[name] = t
+		e.mu.Unlock()
+	}
+	return t, nil
+}
+
+func (e *Engine) parse(name string) (*template.Template, error) {
+	data, err := fs.ReadFile(e.fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("tmpl: read %q: %w", name, err)
+	}
+
+	t := template.New(name).Funcs(e.funcs)
+	if len(e.config.Layouts) > 0 {
+		if t, err = t.ParseFS(e.fsys, e.config.Layouts...); err != nil {
+			return nil, fmt.Errorf("tmpl: parse layouts: %w", err)
+		}
+	}
+	if _, err := t.New(name).Parse(string(data)); err != nil {
+		return nil, fmt.Errorf("tmpl: parse %q: %w", name, err)
+	}
+	return t.Lookup(name), nil
+}
+
+// Execute 渲染模板 name 到 w
+func (e *Engine) Execute(w io.Writer, name string, data any) error {
+	t, err := e.Lookup(name)
+	if err != nil {
+		return err
+	}
+	if err := t.Execute(w, data); err != nil {
+		return fmt.Errorf("tmpl: render %q: %w", name, err)
+	}
+	return nil
+}
+
+// Render 渲染模板 name 为字符串
+func (e *Engine) Render(name string, data any) (string, error) {
+	var buf bytes.Buffer
+	if err := e.Execute(&buf, name, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Reset 清空解析缓存
+func (e *Engine) Reset() {
+	e.mu.Lock()
+	e.cache = make(map[string]*template.Template)
+	e.mu.Unlock()
+}
+
+var textCache sync.Map // map[string]*template.Template
+
+// RenderString 渲染模板字符串，使用内置函数；相同的模板文本只解析一次
+func RenderString(text string, data any) (string, error) {
+	var t *template.Template
+	if v, ok := textCache.Load(text); ok {
+		t = v.(*template.Template)
+	} else {
+		parsed, err := template.New("").Funcs(FuncMap()).Parse(text)
+		if err != nil {
+			return "", fmt.Errorf("tmpl: parse: %w", err)
+		}
+		v, _ := textCache.LoadOrStore(text, parsed)
+		t = v.(*template.Template)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("tmpl: render: %w", err)
+	}
+	return buf.String(), nil
+}
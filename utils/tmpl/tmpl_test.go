@@ -0,0 +1,41 @@
+package tmpl
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/jiajia556/tool-box/timex"
+)
+
+func TestRenderString(t *testing.T) {
+	data := map[string]any{
+		"Name":  "",
+		"Nick":  "bob",
+		"Day":   timex.NewDate(2024, time.May, 6),
+		"Field": "userID",
+	}
+	out, err := RenderString(`{{.Name | default "anon"}} {{coalesce .Name .Nick}} {{date .Day}} {{snake .Field}} {{camel "user_name"}} {{upper "x"}}`, data)
+	if err != nil {
+		t.Fatalf("RenderString: %v", err)
+	}
+	if want := "anon bob 2024-05-06 user_id userName X"; out != want {
+		t.Fatalf("expected %q, got %q", want, out)
+	}
+}
+
+func TestEngine_Layouts(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layouts/base.tmpl": {Data: []byte(`{{define "header"}}[{{.Title | upper}}]{{end}}`)},
+		"pages/home.tmpl":   {Data: []byte(`{{template "header" .}} hello {{.Title}}`)},
+	}
+	e := New(fsys, WithLayouts("layouts/*.tmpl"))
+
+	out, err := e.Render("pages/home.tmpl", map[string]string{"Title": "home"})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if want := "[HOME] hello home"; out != want {
+		t.Fatalf("expected %q, got %q", want, out)
+	}
+}
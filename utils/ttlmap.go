@@ -0,0 +1,185 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+type ttlEntry[V any] struct {
+	value    V
+	expireAt time.Time
+}
+
+// TTLMap 并发安全的带过期时间的映射。
+// 读取时惰性判断过期，另可通过 cleanupInterval 定期清理；过期元素被移除时调用 onExpire。
+type TTLMap[K comparable, V any] struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	items    map[K]ttlEntry[V]
+	onExpire func(key K, value V)
+
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewTTLMap 创建 TTL 映射，ttl 为默认过期时间（小于等于 0 表示不过期）；
+// cleanupInterval 大于 0 时启动后台清理，需调用 Close 停止
+func NewTTLMap[K comparable, V any](ttl, cleanupInterval time.Duration, onExpire func(key K, value V)) *TTLMap[K, V] {
+	m := &TTLMap[K, V]{
+		ttl:      ttl,
+		items:    make(map[K]ttlEntry[V]),
+		onExpire: onExpire,
+		stop:     make(chan struct{}),
+	}
+	if cleanupInterval > 0 {
+		go m.janitor(cleanupInterval)
+	}
+	return m
+}
+
+// Set 使用默认过期时间写入
+func (m *TTLMap[K, V]) Set(k K, v V) {
+	m.SetWithTTL(k, v, m.ttl)
+}
+
+// SetWithTTL 使用指定过期时间写入，ttl 小于等于 0 表示不过期
+func (m *TTLMap[K, V]) SetWithTTL(k K, v V, ttl time.Duration) {
+	var expireAt time.Time
+	if ttl > 0 {
+		expireAt = time.Now().Add(ttl)
+	}
+
+	m.mu.Lock()
+	m.items[k] = ttlEntry[V]{value: v, expireAt: expireAt}
+	m.mu.Unlock()
+}
+
+// Get 获取未过期的值
+func (m *TTLMap[K, V]) Get(k K) (V, bool) {
+	m.mu.Lock()
+	e, ok := m.items[k]
+	expired := ok && e.expired(time.Now())
+	if expired {
+		delete(m.items, k)
+	}
+	m.mu.Unlock()
+
+	var zero V
+	if !ok {
+		return zero, false
+	}
+	if expired {
+		if m.onExpire != nil {
+			m.onExpire(k, e.value)
+		}
+		return zero, false
+	}
+	return e.value, true
+}
+
+// TTL 返回剩余有效期，不过期的元素返回 0, true
+func (m *TTLMap[K, V]) TTL(k K) (time.Duration, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.items[k]
+	if !ok || e.expired(time.Now()) {
+		return 0, false
+	}
+	if e.expireAt.IsZero() {
+		return 0, true
+	}
+	return time.Until(e.expireAt), true
+}
+
+// Delete 删除元素，返回是否存在
+func (m *TTLMap[K, V]) Delete(k K) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.items[k]
+	delete(m.items, k)
+	return ok
+}
+
+// Len 返回元素个数（可能包含尚未清理的过期元素）
+func (m *TTLMap[K, V]) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.items)
+}
+
+// Range 遍历未过期的元素，f 返回 false 时停止；遍历基于快照，f 中可安全修改映射
+func (m *TTLMap[K, V]) Range(f func(key K, value V) bool) {
+	now := time.Now()
+	m.mu.Lock()
+	snapshot := make(map[K]V, len(m.items))
+	for k, e := range m.items {
+		if !e.expired(now) {
+			snapshot[k] = e.value
+		}
+	}
+	m.mu.Unlock()
+
+	for k, v := range snapshot {
+		if !f(k, v) {
+			return
+		}
+	}
+}
+
+// Cleanup 移除所有过期元素
+func (m *TTLMap[K, V]) Cleanup() {
+	now := time.Now()
+	var expired map[K]V
+
+	m.mu.Lock()
+	for k, e := range m.items {
+		if e.expired(now) {
+			if expired == nil {
+				expired = make(map[K]V)
+			}
+			expired[k] = e.value
+			delete(m.items, k)
+		}
+	}
+	m.mu.Unlock()
+
+	if m.onExpire != nil {
+		for k, v := range expired {
+			m.onExpire(k, v)
+		}
+	}
+}
+
+// Clear 清空所有元素
+func (m *TTLMap[K, V]) Clear() {
+	m.mu.Lock()
+	m.items = make(map[K]ttlEntry[V])
+	m.mu.Unlock()
+}
+
+// Close 停止后台清理
+func (m *TTLMap[K, V]) Close() {
+	m.closeOnce.Do(func() {
+		close(m.stop)
+	})
+}
+
+func (m *TTLMap[K, V]) janitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			m.Cleanup()
+		}
+	}
+}
+
+func (e ttlEntry[V]) expired(now time.Time) bool {
+	return !e.expireAt.IsZero() && now.After(e.expireAt)
+}